@@ -0,0 +1,68 @@
+// Package loglevel provides an admin endpoint for changing module log levels at runtime.
+//
+// Requests are authenticated by middleware.AdminAuthMiddleware, which guards all /admin/* routes.
+package loglevel
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/shared/logging"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// Handler handles log level requests.
+type Handler struct {
+	logger *slog.Logger
+}
+
+// SetLevelRequest is the body of PUT /admin/log-level.
+type SetLevelRequest struct {
+	Module string `json:"module"` // e.g., "mind", "brain", "nvmw"
+	Level  string `json:"level"`  // DEBUG, INFO, WARN, ERROR (case-insensitive)
+}
+
+// SetLevelResponse reports the level now in effect for a module.
+type SetLevelResponse struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+}
+
+// NewHandler creates a new log level handler.
+func NewHandler(logger *slog.Logger) *Handler {
+	return &Handler{
+		logger: logger.With("component", "admin-log-level"),
+	}
+}
+
+// RegisterRoutes registers log level routes on the Echo instance.
+func (h *Handler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/admin")
+	admin.PUT("/log-level", h.HandleSetLevel)
+}
+
+// HandleSetLevel updates the log level of a module without restarting the server.
+func (h *Handler) HandleSetLevel(c echo.Context) error {
+	var req SetLevelRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if req.Module == "" || req.Level == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "module and level are required")
+	}
+
+	if err := logging.SetModuleLevel(req.Module, req.Level); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	level, _ := logging.ModuleLevel(req.Module)
+	h.logger.Info("log level changed",
+		"module", req.Module,
+		"level", level.String(),
+		"actor_id", middleware.GetActorID(c.Request().Context()),
+	)
+
+	return c.JSON(http.StatusOK, SetLevelResponse{Module: req.Module, Level: level.String()})
+}
@@ -0,0 +1,121 @@
+package loglevel
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/shared/logging"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+const testSecret = "test-admin-secret"
+
+// setupTestServer returns an Echo instance with the admin routes and a /probe route
+// that writes a debug line to a module logger backed by the returned buffer.
+func setupTestServer(t *testing.T) (*echo.Echo, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := logging.NewModuleLoggerWithWriter(&buf, logging.ModuleMind, "INFO", "json")
+	t.Cleanup(func() {
+		if err := logging.SetModuleLevel(logging.ModuleMind, "INFO"); err != nil {
+			t.Errorf("failed to reset log level: %v", err)
+		}
+	})
+
+	e := echo.New()
+	e.Use(middleware.AdminAuthMiddleware(testSecret))
+	NewHandler(slog.New(slog.NewTextHandler(io.Discard, nil))).RegisterRoutes(e)
+	e.GET("/probe", func(c echo.Context) error {
+		logger.Debug("probe handled")
+		return c.NoContent(http.StatusOK)
+	})
+
+	return e, &buf
+}
+
+// setLevel sends PUT /admin/log-level and returns the response status.
+func setLevel(t *testing.T, e *echo.Echo, token, body string) int {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func probe(t *testing.T, e *echo.Echo) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/probe", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected probe status 200, got %d", rec.Code)
+	}
+}
+
+func TestHandleSetLevel_EnablesDebugLogging(t *testing.T) {
+	e, buf := setupTestServer(t)
+	token, err := middleware.SignAdminToken(testSecret, "ops", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	probe(t, e)
+	if strings.Contains(buf.String(), "probe handled") {
+		t.Fatalf("expected no debug output at INFO, got %q", buf.String())
+	}
+
+	if code := setLevel(t, e, token, `{"module": "mind", "level": "debug"}`); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+
+	probe(t, e)
+	if !strings.Contains(buf.String(), `"level":"DEBUG","msg":"probe handled"`) {
+		t.Fatalf("expected debug line after level change, got %q", buf.String())
+	}
+}
+
+func TestHandleSetLevel_Rejections(t *testing.T) {
+	e, _ := setupTestServer(t)
+	token, err := middleware.SignAdminToken(testSecret, "ops", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		body           string
+		expectedStatus int
+	}{
+		{"unauthenticated", "", `{"module": "mind", "level": "debug"}`, http.StatusUnauthorized},
+		{"unknown level", token, `{"module": "mind", "level": "verbose"}`, http.StatusBadRequest},
+		{"unknown module", token, `{"module": "nope", "level": "debug"}`, http.StatusBadRequest},
+		{"missing fields", token, `{}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := setLevel(t, e, tt.token, tt.body); code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, code)
+			}
+		})
+	}
+
+	if level, _ := logging.ModuleLevel(logging.ModuleMind); level != slog.LevelInfo {
+		t.Errorf("expected rejected requests to leave level at INFO, got %s", level)
+	}
+}
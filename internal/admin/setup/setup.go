@@ -281,7 +281,7 @@ security:
 }
 
 // SetupRequiredMiddleware redirects to /admin/setup if config.yaml doesn't exist.
-// It skips redirection for health checks and setup routes themselves.
+// It skips redirection for health checks, metrics and setup routes themselves.
 func SetupRequiredMiddleware(dataDir string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -289,6 +289,7 @@ func SetupRequiredMiddleware(dataDir string) echo.MiddlewareFunc {
 
 			// Always allow these paths without config
 			if path == "/health" ||
+				path == "/metrics" ||
 				strings.HasPrefix(path, "/admin/setup") {
 				return next(c)
 			}
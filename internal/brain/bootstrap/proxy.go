@@ -0,0 +1,49 @@
+package bootstrap
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// mindAPIPrefix is the path prefix of the Mind API.
+const mindAPIPrefix = "/api/mind"
+
+// MindProxyMiddleware forwards /api/mind/* requests that match no local route to the
+// Mind instance at upstreamURL, so a standalone Brain can serve clients that expect both APIs.
+// Forwarded requests carry X-Forwarded-By: brain; upstream failures are logged and answered with 502.
+func MindProxyMiddleware(upstreamURL string, logger *slog.Logger) (echo.MiddlewareFunc, error) {
+	target, err := url.Parse(upstreamURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("invalid upstream mind url %q", upstreamURL)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		req.Host = target.Host
+		req.Header.Set("X-Forwarded-By", "brain")
+	}
+	proxy.ErrorHandler = func(w http.ResponseWriter, req *http.Request, err error) {
+		logger.Error("mind proxy request failed", "method", req.Method, "path", req.URL.Path, "upstream", upstreamURL, "error", err)
+		w.WriteHeader(http.StatusBadGateway)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// c.Path() is empty when the router found no route for the request
+			path := c.Request().URL.Path
+			if c.Path() != "" || (path != mindAPIPrefix && !strings.HasPrefix(path, mindAPIPrefix+"/")) {
+				return next(c)
+			}
+			proxy.ServeHTTP(c.Response(), c.Request())
+			return nil
+		}
+	}, nil
+}
@@ -0,0 +1,111 @@
+package bootstrap
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// setupProxy returns a Brain Echo instance with a local /api/brain route, proxying to a
+// mock Mind that echoes method, path, body and X-Forwarded-By with status 201.
+func setupProxy(t *testing.T) *echo.Echo {
+	t.Helper()
+
+	mind := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Forwarded-By-Seen", r.Header.Get("X-Forwarded-By"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+	}))
+	t.Cleanup(mind.Close)
+
+	proxy, err := MindProxyMiddleware(mind.URL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+
+	e := echo.New()
+	e.Use(proxy)
+	e.GET("/api/brain/status", func(c echo.Context) error {
+		return c.String(http.StatusOK, "brain")
+	})
+	return e
+}
+
+func TestMindProxy_ForwardsUnknownMindRoutes(t *testing.T) {
+	e := setupProxy(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/mind/mind.v3.NotesService/CreateNote?x=1", strings.NewReader(`{"title":"Hi"}`))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected upstream status 201, got %d", rec.Code)
+	}
+	if want := `POST /api/mind/mind.v3.NotesService/CreateNote?x=1 {"title":"Hi"}`; rec.Body.String() != want {
+		t.Errorf("expected body %q, got %q", want, rec.Body.String())
+	}
+	if got := rec.Header().Get("X-Forwarded-By-Seen"); got != "brain" {
+		t.Errorf("expected upstream to see X-Forwarded-By: brain, got %q", got)
+	}
+}
+
+func TestMindProxy_LeavesOtherRoutesAlone(t *testing.T) {
+	e := setupProxy(t)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"local brain route", "/api/brain/status", http.StatusOK, "brain"},
+		{"unknown non-mind route", "/api/other", http.StatusNotFound, ""},
+		{"prefix lookalike", "/api/mindful", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if tt.expectedBody != "" && rec.Body.String() != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMindProxy_UpstreamDown(t *testing.T) {
+	mind := httptest.NewServer(http.NotFoundHandler())
+	mind.Close()
+
+	proxy, err := MindProxyMiddleware(mind.URL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("failed to create proxy: %v", err)
+	}
+	e := echo.New()
+	e.Use(proxy)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/mind/notes", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected status 502, got %d", rec.Code)
+	}
+}
+
+func TestMindProxyMiddleware_InvalidURL(t *testing.T) {
+	for _, raw := range []string{"", "localhost:9421", "ftp://mind"} {
+		if _, err := MindProxyMiddleware(raw, slog.New(slog.NewTextHandler(io.Discard, nil))); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
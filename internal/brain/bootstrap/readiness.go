@@ -0,0 +1,58 @@
+// Package bootstrap wires up the Brain service.
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// readinessPollInterval is how often BrainReadinessProbe polls Mind (overridden in tests).
+var readinessPollInterval = time.Second
+
+// BrainReadinessProbe blocks until the Mind service at mindURL answers /health with 200.
+// It polls every second and returns an error once timeout elapses or ctx is done.
+func BrainReadinessProbe(ctx context.Context, mindURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(mindURL, "/") + "/health"
+	client := &http.Client{Timeout: readinessPollInterval}
+
+	ticker := time.NewTicker(readinessPollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		if lastErr = checkHealth(ctx, client, endpoint); lastErr == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("mind service not ready after %s: %w", timeout, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkHealth performs a single GET against the health endpoint.
+func checkHealth(ctx context.Context, client *http.Client, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach mind: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mind health returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowMind returns a test server whose /health fails until readyAfter has passed.
+func slowMind(t *testing.T, readyAfter time.Duration) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	start := time.Now()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+		if time.Since(start) < readyAfter {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func withPollInterval(t *testing.T, d time.Duration) {
+	t.Helper()
+	prev := readinessPollInterval
+	readinessPollInterval = d
+	t.Cleanup(func() { readinessPollInterval = prev })
+}
+
+func TestBrainReadinessProbe_WaitsForSlowMind(t *testing.T) {
+	withPollInterval(t, 20*time.Millisecond)
+	srv, calls := slowMind(t, 150*time.Millisecond)
+
+	start := time.Now()
+	if err := BrainReadinessProbe(context.Background(), srv.URL, 2*time.Second); err != nil {
+		t.Fatalf("expected mind to become ready, got: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("probe returned after %s, before mind was ready", elapsed)
+	}
+	if calls.Load() < 2 {
+		t.Fatalf("expected repeated polling, got %d calls", calls.Load())
+	}
+}
+
+func TestBrainReadinessProbe_TimesOut(t *testing.T) {
+	withPollInterval(t, 20*time.Millisecond)
+	srv, _ := slowMind(t, time.Hour)
+
+	start := time.Now()
+	if err := BrainReadinessProbe(context.Background(), srv.URL, 100*time.Millisecond); err == nil {
+		t.Fatal("expected timeout error")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("probe kept polling for %s past its timeout", elapsed)
+	}
+}
+
+func TestBrainReadinessProbe_UnreachableMind(t *testing.T) {
+	withPollInterval(t, 20*time.Millisecond)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	if err := BrainReadinessProbe(context.Background(), url, 100*time.Millisecond); err == nil {
+		t.Fatal("expected error for unreachable mind")
+	}
+}
@@ -0,0 +1,128 @@
+package apikeys
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// AdminHandler serves API key management endpoints under /admin.
+// Requests are authenticated by middleware.AdminAuthMiddleware, which guards all /admin/* routes.
+type AdminHandler struct {
+	service *ApiKeyService
+}
+
+// CreateKeyRequest is the JSON body of POST /admin/api-keys.
+type CreateKeyRequest struct {
+	ActorID   string     `json:"actor_id"`
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // RFC 3339; omitted = never expires
+}
+
+// ApiKeyResponse describes a stored key. The plaintext key is never included.
+type ApiKeyResponse struct {
+	ID         int64   `json:"id"`
+	ActorID    string  `json:"actor_id"`
+	Name       string  `json:"name"`
+	CreatedAt  *string `json:"created_at,omitempty"`
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	ExpiresAt  *string `json:"expires_at,omitempty"`
+	RevokedAt  *string `json:"revoked_at,omitempty"`
+}
+
+// CreateKeyResponse is the body of POST /admin/api-keys.
+// Key holds the plaintext key; it is returned only here and cannot be recovered later.
+type CreateKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+// ListKeysResponse is the body of GET /admin/api-keys.
+type ListKeysResponse struct {
+	Keys []ApiKeyResponse `json:"keys"`
+}
+
+// NewAdminHandler creates a new API keys admin handler.
+func NewAdminHandler(service *ApiKeyService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// RegisterRoutes registers API key admin routes on the Echo instance.
+func (h *AdminHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/admin/api-keys")
+	admin.POST("", h.HandleCreate)
+	admin.GET("", h.HandleList)
+	admin.DELETE("/:id", h.HandleRevoke)
+}
+
+// HandleCreate creates a key and returns its plaintext once.
+func (h *AdminHandler) HandleCreate(c echo.Context) error {
+	var req CreateKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	key, plaintext, err := h.service.CreateKey(c.Request().Context(), req.ActorID, req.Name, expiresAt)
+	if err != nil {
+		if errors.Is(err, ErrInvalidApiKeyParams) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to create api key")
+	}
+
+	return c.JSON(http.StatusCreated, CreateKeyResponse{ApiKeyResponse: keyResponse(key), Key: plaintext})
+}
+
+// HandleList lists keys, newest first; ?actor_id= limits the list to one actor.
+func (h *AdminHandler) HandleList(c echo.Context) error {
+	keys, err := h.service.ListKeys(c.Request().Context(), c.QueryParam("actor_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list api keys")
+	}
+
+	resp := ListKeysResponse{Keys: make([]ApiKeyResponse, len(keys))}
+	for i, key := range keys {
+		resp.Keys[i] = keyResponse(key)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleRevoke revokes a key so it can no longer authenticate.
+func (h *AdminHandler) HandleRevoke(c echo.Context) error {
+	id, err := utils.ParseIDParam(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid api key id")
+	}
+
+	if err := h.service.RevokeKey(c.Request().Context(), id); err != nil {
+		if errors.Is(err, ErrApiKeyNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "api key not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to revoke api key")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// keyResponse converts a stored key to its JSON form.
+func keyResponse(key store.ApiKey) ApiKeyResponse {
+	return ApiKeyResponse{
+		ID:         key.ID,
+		ActorID:    key.ActorID,
+		Name:       key.Name,
+		CreatedAt:  utils.FormatNullTime(key.CreatedAt),
+		LastUsedAt: utils.FormatNullTime(key.LastUsedAt),
+		ExpiresAt:  utils.FormatNullTime(key.ExpiresAt),
+		RevokedAt:  utils.FormatNullTime(key.RevokedAt),
+	}
+}
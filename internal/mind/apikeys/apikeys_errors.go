@@ -0,0 +1,23 @@
+package apikeys
+
+// API Keys Domain Errors
+// Domain-specific errors for the API key service layer
+
+import (
+	"errors"
+)
+
+// Domain errors for API key service
+var (
+	// ErrApiKeyNotFound indicates an API key was not found (or is already revoked)
+	ErrApiKeyNotFound = errors.New("api key not found")
+
+	// ErrInvalidApiKey indicates a presented key does not match any active key
+	ErrInvalidApiKey = errors.New("invalid api key")
+
+	// ErrApiKeyExpired indicates a presented key is past its expiry
+	ErrApiKeyExpired = errors.New("api key expired")
+
+	// ErrInvalidApiKeyParams indicates a missing actor ID or name on creation
+	ErrInvalidApiKeyParams = errors.New("api key requires an actor id and a name")
+)
@@ -0,0 +1,40 @@
+package apikeys
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// APIKeyHeader is the HTTP header carrying the API key.
+const APIKeyHeader = "X-API-Key"
+
+// ApiKeyMiddleware authenticates requests carrying an X-API-Key header.
+// On a match, the key's actor ID is injected into the request context (middleware.GetActorID).
+// Requests with an unknown, revoked, or expired key are rejected with 401.
+// Requests without the header pass through unchanged, so other auth strategies can apply.
+func ApiKeyMiddleware(service *ApiKeyService) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			plaintext := c.Request().Header.Get(APIKeyHeader)
+			if plaintext == "" {
+				return next(c)
+			}
+
+			req := c.Request()
+			key, err := service.Authenticate(req.Context(), plaintext)
+			if err != nil {
+				if errors.Is(err, ErrInvalidApiKey) || errors.Is(err, ErrApiKeyExpired) {
+					return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+				}
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to authenticate api key")
+			}
+
+			c.SetRequest(req.WithContext(middleware.WithActorID(req.Context(), key.ActorID)))
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Package apikeys manages API keys for the Mind HTTP API.
+//
+// Keys are generated server-side and returned in plaintext exactly once, on creation.
+// Only their sha256 hash is stored; ApiKeyMiddleware hashes the X-API-Key header
+// and looks the hash up to authenticate the request as the key's actor.
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// keyPrefix marks Mindweaver API keys so they are recognizable in configs and secret scanners.
+const keyPrefix = "mwk_"
+
+// keyBytes is the number of random bytes in a generated key.
+const keyBytes = 32
+
+// ApiKeyService provides business logic for API key management and authentication.
+type ApiKeyService struct {
+	db     *sql.DB
+	store  store.Querier
+	logger *slog.Logger
+	now    func() time.Time // Overridable clock for expiry checks
+}
+
+// NewApiKeyService creates a new ApiKeyService.
+func NewApiKeyService(db *sql.DB, store store.Querier, logger *slog.Logger, serviceName string) *ApiKeyService {
+	return &ApiKeyService{
+		db:     db,
+		store:  store,
+		logger: logger.With("service", serviceName),
+		now:    time.Now,
+	}
+}
+
+// HashKey returns the hex-encoded sha256 of key, as stored in api_keys.key_hash.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateKey generates a new API key for actorID.
+// Returns the stored key record and the plaintext key; the plaintext cannot be recovered later.
+// expiresAt is optional (invalid = never expires).
+func (s *ApiKeyService) CreateKey(ctx context.Context, actorID, name string, expiresAt sql.NullTime) (store.ApiKey, string, error) {
+	if actorID == "" || name == "" {
+		return store.ApiKey{}, "", ErrInvalidApiKeyParams
+	}
+
+	raw := make([]byte, keyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.Error("failed to generate api key", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.ApiKey{}, "", err
+	}
+	plaintext := keyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	id, err := s.store.CreateApiKey(ctx, store.CreateApiKeyParams{
+		ActorID:   actorID,
+		KeyHash:   HashKey(plaintext),
+		Name:      name,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		s.logger.Error("failed to create api key", "actor_id", actorID, "name", name, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.ApiKey{}, "", err
+	}
+
+	key, err := s.store.GetApiKeyByID(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to get created api key", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.ApiKey{}, "", err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("api key created", "api_key_id", id, "key_actor_id", actorID, "name", name)
+	return key, plaintext, nil
+}
+
+// ListKeys returns the API keys of actorID, newest first, including revoked and expired keys.
+// An empty actorID lists keys of all actors.
+func (s *ApiKeyService) ListKeys(ctx context.Context, actorID string) ([]store.ApiKey, error) {
+	var (
+		keys []store.ApiKey
+		err  error
+	)
+	if actorID == "" {
+		keys, err = s.store.ListApiKeys(ctx)
+	} else {
+		keys, err = s.store.ListApiKeysByActor(ctx, actorID)
+	}
+	if err != nil {
+		s.logger.Error("failed to list api keys", "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+	}
+	return keys, err
+}
+
+// RevokeKey revokes an API key so it can no longer authenticate.
+// Returns ErrApiKeyNotFound if the key doesn't exist or is already revoked.
+func (s *ApiKeyService) RevokeKey(ctx context.Context, id int64) error {
+	result, err := s.store.RevokeApiKey(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to revoke api key", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrApiKeyNotFound
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("api key revoked", "api_key_id", id)
+	return nil
+}
+
+// Authenticate looks up a plaintext key by its hash and returns the matching active key.
+// Returns ErrInvalidApiKey for unknown or revoked keys and ErrApiKeyExpired for expired ones.
+// The key's last_used_at is updated on success.
+func (s *ApiKeyService) Authenticate(ctx context.Context, plaintext string) (store.ApiKey, error) {
+	key, err := s.store.GetActiveApiKeyByHash(ctx, HashKey(plaintext))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.ApiKey{}, ErrInvalidApiKey
+		}
+		s.logger.Error("failed to look up api key", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.ApiKey{}, err
+	}
+
+	if key.ExpiresAt.Valid && !s.now().Before(key.ExpiresAt.Time) {
+		return store.ApiKey{}, ErrApiKeyExpired
+	}
+
+	// Usage tracking is best effort and must not fail the request
+	if err := s.store.TouchApiKeyLastUsed(ctx, key.ID); err != nil {
+		s.logger.Warn("failed to update api key last used", "api_key_id", key.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+	}
+
+	return key, nil
+}
@@ -0,0 +1,187 @@
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+)
+
+// setupTestService creates an ApiKeyService with a test database for testing.
+func setupTestService(t *testing.T) (*ApiKeyService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	logger := testdb.NewTestLogger(t)
+	service := NewApiKeyService(db, queries, logger, "apikeys-test")
+
+	return service, queries
+}
+
+// setupTestServer returns an Echo instance with ApiKeyMiddleware and a /whoami route
+// that echoes the authenticated actor ID.
+func setupTestServer(service *ApiKeyService) *echo.Echo {
+	e := echo.New()
+	e.GET("/whoami", func(c echo.Context) error {
+		return c.String(http.StatusOK, middleware.GetActorID(c.Request().Context()))
+	}, ApiKeyMiddleware(service))
+	return e
+}
+
+// whoami sends GET /whoami with the given API key (empty = no header).
+func whoami(e *echo.Echo, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	if key != "" {
+		req.Header.Set(APIKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateKey_StoresOnlyHash(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	key, plaintext, err := service.CreateKey(ctx, "alice", "cli", sql.NullTime{})
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(plaintext, keyPrefix))
+	require.Equal(t, "alice", key.ActorID)
+	require.Equal(t, "cli", key.Name)
+	require.Equal(t, HashKey(plaintext), key.KeyHash)
+	require.NotContains(t, key.KeyHash, plaintext)
+
+	stored, err := queries.GetApiKeyByID(ctx, key.ID)
+	require.NoError(t, err)
+	require.Equal(t, HashKey(plaintext), stored.KeyHash)
+
+	_, other, err := service.CreateKey(ctx, "alice", "ci", sql.NullTime{})
+	require.NoError(t, err)
+	require.NotEqual(t, plaintext, other, "generated keys must be unique")
+
+	keys, err := service.ListKeys(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	_, _, err = service.CreateKey(ctx, "", "cli", sql.NullTime{})
+	require.ErrorIs(t, err, ErrInvalidApiKeyParams)
+}
+
+func TestApiKeyMiddleware_Authenticates(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	key, plaintext, err := service.CreateKey(ctx, "alice", "cli", sql.NullTime{})
+	require.NoError(t, err)
+	require.False(t, key.LastUsedAt.Valid)
+
+	e := setupTestServer(service)
+	rec := whoami(e, plaintext)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "alice", rec.Body.String())
+
+	stored, err := queries.GetApiKeyByID(ctx, key.ID)
+	require.NoError(t, err)
+	require.True(t, stored.LastUsedAt.Valid, "last_used_at should be set after authentication")
+
+	// No header passes through unauthenticated
+	rec = whoami(e, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Body.String())
+
+	rec = whoami(e, keyPrefix+"not-a-real-key")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestApiKeyMiddleware_RejectsExpiredKey(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(time.Hour)
+	_, plaintext, err := service.CreateKey(ctx, "alice", "temp", sql.NullTime{Time: expiresAt, Valid: true})
+	require.NoError(t, err)
+
+	e := setupTestServer(service)
+	require.Equal(t, http.StatusOK, whoami(e, plaintext).Code)
+
+	service.now = func() time.Time { return expiresAt.Add(time.Second) }
+
+	_, err = service.Authenticate(ctx, plaintext)
+	require.ErrorIs(t, err, ErrApiKeyExpired)
+	require.Equal(t, http.StatusUnauthorized, whoami(e, plaintext).Code)
+}
+
+func TestApiKeyMiddleware_RejectsRevokedKey(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	key, plaintext, err := service.CreateKey(ctx, "alice", "cli", sql.NullTime{})
+	require.NoError(t, err)
+
+	e := setupTestServer(service)
+	require.Equal(t, http.StatusOK, whoami(e, plaintext).Code)
+
+	require.NoError(t, service.RevokeKey(ctx, key.ID))
+	require.ErrorIs(t, service.RevokeKey(ctx, key.ID), ErrApiKeyNotFound)
+
+	_, err = service.Authenticate(ctx, plaintext)
+	require.ErrorIs(t, err, ErrInvalidApiKey)
+	require.Equal(t, http.StatusUnauthorized, whoami(e, plaintext).Code)
+}
+
+func TestAdminHandler_CreateListRevoke(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	e := setupTestServer(service)
+	NewAdminHandler(service).RegisterRoutes(e)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/admin/api-keys", `{"actor_id": "alice", "name": "cli"}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created CreateKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	require.True(t, strings.HasPrefix(created.Key, keyPrefix))
+	require.Equal(t, "alice", created.ActorID)
+	require.Equal(t, http.StatusOK, whoami(e, created.Key).Code)
+
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/admin/api-keys", `{"actor_id": "alice"}`).Code)
+
+	// Listing never exposes the plaintext or the hash
+	rec = send(http.MethodGet, "/admin/api-keys?actor_id=alice", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), created.Key)
+	require.NotContains(t, rec.Body.String(), HashKey(created.Key))
+	var listed ListKeysResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Keys, 1)
+	require.Equal(t, created.ID, listed.Keys[0].ID)
+
+	path := "/admin/api-keys/" + strconv.FormatInt(created.ID, 10)
+	require.Equal(t, http.StatusNoContent, send(http.MethodDelete, path, "").Code)
+	require.Equal(t, http.StatusNotFound, send(http.MethodDelete, path, "").Code)
+	require.Equal(t, http.StatusUnauthorized, whoami(e, created.Key).Code)
+}
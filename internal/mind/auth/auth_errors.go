@@ -0,0 +1,27 @@
+package auth
+
+// Auth Domain Errors
+// Domain-specific errors for the auth service layer
+
+import (
+	"errors"
+)
+
+// Domain errors for auth service
+var (
+	// ErrInvalidRefreshToken indicates a presented refresh token is unknown or revoked
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+	// ErrRefreshTokenExpired indicates a presented refresh token is past its expiry
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+
+	// ErrRefreshTokenReused indicates an already rotated refresh token was presented again;
+	// all refresh tokens of the actor are revoked in response
+	ErrRefreshTokenReused = errors.New("refresh token already used")
+
+	// ErrAuthDisabled indicates no JWT secret is configured, so access tokens cannot be issued
+	ErrAuthDisabled = errors.New("token auth is disabled (security.jwt_secret not set)")
+
+	// ErrMissingActorID indicates a refresh token was requested without an actor ID
+	ErrMissingActorID = errors.New("refresh token requires an actor id")
+)
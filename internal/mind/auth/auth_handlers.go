@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler serves the token endpoints under /auth.
+// The refresh token in the request body is the credential, so no other auth is required.
+type Handler struct {
+	service *AuthService
+}
+
+// RefreshRequest is the body of POST /auth/refresh.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// TokenResponse is the body of a successful POST /auth/refresh.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // Access token lifetime in seconds
+}
+
+// NewHandler creates a new auth handler.
+func NewHandler(service *AuthService) *Handler {
+	return &Handler{service: service}
+}
+
+// RegisterRoutes registers auth routes on the Echo instance.
+func (h *Handler) RegisterRoutes(e *echo.Echo) {
+	e.POST("/auth/refresh", h.HandleRefresh)
+}
+
+// HandleRefresh rotates a refresh token into a new access/refresh token pair.
+func (h *Handler) HandleRefresh(c echo.Context) error {
+	var req RefreshRequest
+	if err := c.Bind(&req); err != nil || req.RefreshToken == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "refresh_token is required")
+	}
+
+	accessToken, refreshToken, err := h.service.RotateRefreshToken(c.Request().Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAuthDisabled):
+			return echo.NewHTTPError(http.StatusForbidden, err.Error())
+		case errors.Is(err, ErrInvalidRefreshToken),
+			errors.Is(err, ErrRefreshTokenExpired),
+			errors.Is(err, ErrRefreshTokenReused):
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to refresh token")
+		}
+	}
+
+	return c.JSON(http.StatusOK, TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(h.service.AccessTokenTTL().Seconds()),
+	})
+}
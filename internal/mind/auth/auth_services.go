@@ -0,0 +1,193 @@
+// Package auth issues bearer tokens for the Mind API.
+//
+// Access tokens are short-lived HS256 JWTs (see middleware.SignToken) accepted by the
+// JWT interceptor. Refresh tokens are opaque, long-lived and one-time use: each
+// rotation marks the presented token used and hands out a fresh pair. Presenting a
+// used token again is treated as theft and revokes every refresh token of the actor.
+// Like API keys, only the sha256 hash of a refresh token is stored.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// tokenPrefix marks Mindweaver refresh tokens so they are recognizable in configs and secret scanners.
+const tokenPrefix = "mwr_"
+
+// tokenBytes is the number of random bytes in a generated refresh token.
+const tokenBytes = 32
+
+const (
+	// DefaultAccessTokenTTL is the lifetime of access JWTs issued on rotation.
+	DefaultAccessTokenTTL = 15 * time.Minute
+
+	// DefaultRefreshTokenTTL is the lifetime of refresh tokens.
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// AuthService issues and rotates refresh tokens.
+type AuthService struct {
+	db         *sql.DB
+	store      store.Querier
+	logger     *slog.Logger
+	secret     string           // HS256 secret for access tokens (empty = rotation disabled)
+	accessTTL  time.Duration    // Lifetime of issued access tokens
+	refreshTTL time.Duration    // Lifetime of issued refresh tokens
+	now        func() time.Time // Overridable clock for expiry checks
+}
+
+// NewAuthService creates a new AuthService signing access tokens with secret.
+func NewAuthService(db *sql.DB, store store.Querier, logger *slog.Logger, serviceName, secret string) *AuthService {
+	return &AuthService{
+		db:         db,
+		store:      store,
+		logger:     logger.With("service", serviceName),
+		secret:     secret,
+		accessTTL:  DefaultAccessTokenTTL,
+		refreshTTL: DefaultRefreshTokenTTL,
+		now:        time.Now,
+	}
+}
+
+// AccessTokenTTL returns the lifetime of access tokens issued by RotateRefreshToken.
+func (s *AuthService) AccessTokenTTL() time.Duration {
+	return s.accessTTL
+}
+
+// HashToken returns the hex-encoded sha256 of token, as stored in refresh_tokens.token_hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateRefreshToken issues a new refresh token for actorID.
+// Returns the plaintext token; it cannot be recovered later.
+func (s *AuthService) GenerateRefreshToken(ctx context.Context, actorID string) (string, error) {
+	if actorID == "" {
+		return "", ErrMissingActorID
+	}
+
+	token, err := s.createRefreshToken(ctx, s.store, actorID)
+	if err != nil {
+		return "", err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("refresh token issued", "token_actor_id", actorID)
+	return token, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new access token and a new refresh token.
+// The presented token is marked used. Returns ErrInvalidRefreshToken for unknown or revoked
+// tokens, ErrRefreshTokenExpired for expired ones, and ErrRefreshTokenReused when the token
+// was already rotated, in which case all refresh tokens of its actor are revoked.
+func (s *AuthService) RotateRefreshToken(ctx context.Context, token string) (accessToken, refreshToken string, err error) {
+	if s.secret == "" {
+		return "", "", ErrAuthDisabled
+	}
+
+	current, err := s.store.GetRefreshTokenByHash(ctx, HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", ErrInvalidRefreshToken
+		}
+		s.logger.Error("failed to look up refresh token", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", "", err
+	}
+
+	if current.RevokedAt.Valid {
+		return "", "", ErrInvalidRefreshToken
+	}
+	if current.UsedAt.Valid {
+		return "", "", s.revokeOnReuse(ctx, current)
+	}
+	if !s.now().Before(current.ExpiresAt) {
+		return "", "", ErrRefreshTokenExpired
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", "", err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	result, err := txStore.MarkRefreshTokenUsed(ctx, current.ID)
+	if err != nil {
+		s.logger.Error("failed to mark refresh token used", "id", current.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", "", err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", "", err
+	}
+	if rowsAffected == 0 {
+		// Another request rotated the same token since the lookup
+		tx.Rollback()
+		return "", "", s.revokeOnReuse(ctx, current)
+	}
+
+	refreshToken, err = s.createRefreshToken(ctx, txStore, current.ActorID)
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, err = middleware.SignToken(s.secret, current.ActorID, s.accessTTL)
+	if err != nil {
+		s.logger.Error("failed to sign access token", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", "", err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "id", current.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", "", err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("refresh token rotated", "refresh_token_id", current.ID, "token_actor_id", current.ActorID)
+	return accessToken, refreshToken, nil
+}
+
+// revokeOnReuse revokes all refresh tokens of the actor owning a reused token and
+// returns ErrRefreshTokenReused.
+func (s *AuthService) revokeOnReuse(ctx context.Context, reused store.RefreshToken) error {
+	middleware.ContextLogger(ctx, s.logger).Warn("refresh token reuse detected, revoking all tokens of actor", "refresh_token_id", reused.ID, "token_actor_id", reused.ActorID)
+
+	if err := s.store.RevokeRefreshTokensByActor(ctx, reused.ActorID); err != nil {
+		s.logger.Error("failed to revoke refresh tokens", "actor_id", reused.ActorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// createRefreshToken generates a refresh token for actorID and stores its hash via querier.
+func (s *AuthService) createRefreshToken(ctx context.Context, querier store.Querier, actorID string) (string, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.Error("failed to generate refresh token", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", err
+	}
+	plaintext := tokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	_, err := querier.CreateRefreshToken(ctx, store.CreateRefreshTokenParams{
+		ActorID:   actorID,
+		TokenHash: HashToken(plaintext),
+		ExpiresAt: s.now().Add(s.refreshTTL),
+	})
+	if err != nil {
+		s.logger.Error("failed to create refresh token", "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", err
+	}
+	return plaintext, nil
+}
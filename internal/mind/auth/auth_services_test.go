@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+)
+
+const testSecret = "test-secret"
+
+// setupTestService creates an AuthService with a test database for testing.
+func setupTestService(t *testing.T) (*AuthService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	logger := testdb.NewTestLogger(t)
+	service := NewAuthService(db, queries, logger, "auth-test", testSecret)
+
+	return service, queries
+}
+
+// refresh sends POST /auth/refresh with the given refresh token.
+func refresh(e *echo.Echo, token string) *httptest.ResponseRecorder {
+	body := `{"refresh_token":"` + token + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGenerateRefreshToken_StoresOnlyHash(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	token, err := service.GenerateRefreshToken(ctx, "alice")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, tokenPrefix))
+
+	stored, err := queries.GetRefreshTokenByHash(ctx, HashToken(token))
+	require.NoError(t, err)
+	require.Equal(t, "alice", stored.ActorID)
+	require.False(t, stored.UsedAt.Valid)
+
+	_, err = service.GenerateRefreshToken(ctx, "")
+	require.ErrorIs(t, err, ErrMissingActorID)
+}
+
+func TestRotateRefreshToken_IssuesNewPair(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	token, err := service.GenerateRefreshToken(ctx, "alice")
+	require.NoError(t, err)
+
+	access, next, err := service.RotateRefreshToken(ctx, token)
+	require.NoError(t, err)
+	require.NotEqual(t, token, next)
+
+	actorID, err := middleware.VerifyToken(access, testSecret)
+	require.NoError(t, err)
+	require.Equal(t, "alice", actorID)
+
+	old, err := queries.GetRefreshTokenByHash(ctx, HashToken(token))
+	require.NoError(t, err)
+	require.True(t, old.UsedAt.Valid, "rotated token must be marked used")
+
+	// The new token rotates in turn
+	_, _, err = service.RotateRefreshToken(ctx, next)
+	require.NoError(t, err)
+}
+
+func TestRotateRefreshToken_RejectsExpired(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	token, err := service.GenerateRefreshToken(ctx, "alice")
+	require.NoError(t, err)
+
+	service.now = func() time.Time { return time.Now().Add(DefaultRefreshTokenTTL + time.Minute) }
+	_, _, err = service.RotateRefreshToken(ctx, token)
+	require.ErrorIs(t, err, ErrRefreshTokenExpired)
+
+	_, _, err = service.RotateRefreshToken(ctx, "mwr_unknown")
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestRotateRefreshToken_ReuseRevokesAllTokens(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	token, err := service.GenerateRefreshToken(ctx, "alice")
+	require.NoError(t, err)
+	other, err := service.GenerateRefreshToken(ctx, "bob")
+	require.NoError(t, err)
+
+	_, next, err := service.RotateRefreshToken(ctx, token)
+	require.NoError(t, err)
+
+	_, _, err = service.RotateRefreshToken(ctx, token)
+	require.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	// The legitimately rotated token is revoked along with the reused one
+	_, _, err = service.RotateRefreshToken(ctx, next)
+	require.ErrorIs(t, err, ErrInvalidRefreshToken)
+	revoked, err := queries.GetRefreshTokenByHash(ctx, HashToken(next))
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+
+	// Other actors are unaffected
+	_, _, err = service.RotateRefreshToken(ctx, other)
+	require.NoError(t, err)
+}
+
+func TestRotateRefreshToken_DisabledWithoutSecret(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	token, err := service.GenerateRefreshToken(ctx, "alice")
+	require.NoError(t, err)
+
+	service.secret = ""
+	_, _, err = service.RotateRefreshToken(ctx, token)
+	require.ErrorIs(t, err, ErrAuthDisabled)
+}
+
+func TestHandleRefresh(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	e := echo.New()
+	NewHandler(service).RegisterRoutes(e)
+
+	token, err := service.GenerateRefreshToken(ctx, "alice")
+	require.NoError(t, err)
+
+	rec := refresh(e, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp TokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "Bearer", resp.TokenType)
+	require.Equal(t, int64(DefaultAccessTokenTTL.Seconds()), resp.ExpiresIn)
+	require.NotEmpty(t, resp.AccessToken)
+	require.NotEmpty(t, resp.RefreshToken)
+
+	require.Equal(t, http.StatusUnauthorized, refresh(e, token).Code, "reused token")
+	require.Equal(t, http.StatusBadRequest, refresh(e, "").Code)
+}
@@ -7,27 +7,37 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 
 	"connectrpc.com/connect"
 	"github.com/labstack/echo/v4"
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
-	_ "modernc.org/sqlite"
 
 	"github.com/nkapatos/mindweaver/gen/proto/mind/v3/mindv3connect"
+	"github.com/nkapatos/mindweaver/internal/mind/apikeys"
+	"github.com/nkapatos/mindweaver/internal/mind/auth"
 	"github.com/nkapatos/mindweaver/internal/mind/collections"
 	"github.com/nkapatos/mindweaver/internal/mind/events"
 	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/internal/mind/graph"
 	"github.com/nkapatos/mindweaver/internal/mind/links"
 	"github.com/nkapatos/mindweaver/internal/mind/meta"
 	"github.com/nkapatos/mindweaver/internal/mind/notes"
 	"github.com/nkapatos/mindweaver/internal/mind/notetypes"
+	"github.com/nkapatos/mindweaver/internal/mind/permissions"
+	"github.com/nkapatos/mindweaver/internal/mind/presence"
+	"github.com/nkapatos/mindweaver/internal/mind/readprogress"
 	"github.com/nkapatos/mindweaver/internal/mind/search"
 	"github.com/nkapatos/mindweaver/internal/mind/tags"
 	"github.com/nkapatos/mindweaver/internal/mind/templates"
+	"github.com/nkapatos/mindweaver/internal/mind/webhooks"
 	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/database"
+	apierrors "github.com/nkapatos/mindweaver/shared/errors"
 	"github.com/nkapatos/mindweaver/shared/interceptors"
+	"github.com/nkapatos/mindweaver/shared/middleware"
 )
 
 // Initialize sets up the Mind service on the given API group.
@@ -37,37 +47,24 @@ import (
 //   - e: Echo instance (needed for Connect-RPC V3 routes)
 //   - apiGroup: Echo API group to register routes under (will create /mind subgroup)
 //   - dbPath: Path to the SQLite database file
+//   - jwtSecret: HS256 secret for bearer tokens on Connect-RPC routes (empty = not required)
 //   - logger: Structured logger
 //
 // Returns the database connection, notes service, event hub, and error if initialization fails.
 // The caller is responsible for closing the returned database connection and event hub.
 // The notes service is returned for scheduler integration in combined mode.
 // The event hub is returned for graceful shutdown and can be used by other services to publish events.
-func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath string, logger *slog.Logger) (*sql.DB, *notes.NotesService, events.Hub, error) {
+func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath, jwtSecret string, logger *slog.Logger) (*sql.DB, *notes.NotesService, events.Hub, error) {
 	logger.Info("🧠 Initializing Mind service (Notes/PKM)")
 
-	// Open database connection
-	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?cache=shared&mode=rwc", dbPath))
+	// Open database connection (foreign keys and WAL are enabled on every pooled connection)
+	db, err := database.OpenSQLite(dbPath)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to open notes database: %w", err)
 	}
 
-	// Configure WAL mode for better concurrency
-	if _, err := db.Exec("PRAGMA journal_mode=WAL;"); err != nil {
-		db.Close()
-		return nil, nil, nil, fmt.Errorf("failed to enable WAL mode for notes: %w", err)
-	}
-	if _, err := db.Exec("PRAGMA synchronous=NORMAL;"); err != nil {
-		db.Close()
-		return nil, nil, nil, fmt.Errorf("failed to enable WAL synchronous mode for notes: %w", err)
-	}
-	if _, err := db.Exec("PRAGMA wal_autocheckpoint=100;"); err != nil {
-		db.Close()
-		return nil, nil, nil, fmt.Errorf("failed to enable WAL checkpoint for notes: %w", err)
-	}
-
 	// Run migrations
-	if err := mindmigrations.RunMigrations(db, logger); err != nil {
+	if err := mindmigrations.NewMigrator(db, logger).Up(context.Background()); err != nil {
 		db.Close()
 		return nil, nil, nil, fmt.Errorf("failed to run notes DB migrations: %w", err)
 	}
@@ -97,12 +94,20 @@ func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath string, logger *slog.
 	notesService := notes.NewNotesService(db, querier, logger, "Notes Service")
 	notesService.SetEventHub(eventHub) // Wire event hub for SSE notifications
 
-	tagService := tags.NewTagsService(querier, logger, "Tags Service")
+	tagService := tags.NewTagsService(db, querier, logger, "Tags Service")
 	templateService := templates.NewTemplatesService(querier, logger, "Templates Service")
 	linksService := links.NewLinksService(querier, logger, "Links Service")
 	noteTypesService := notetypes.NewNoteTypesService(querier, logger, "NoteTypes Service")
+	schemaService := notetypes.NewSchemaService(querier, logger, "Schema Service")
 	collectionsService := collections.NewCollectionsService(db, querier, logger, "Collections Service")
 	searchService := search.NewSearchService(db, querier, logger)
+	apiKeyService := apikeys.NewApiKeyService(db, querier, logger, "API Keys Service")
+	authService := auth.NewAuthService(db, querier, logger, "Auth Service", jwtSecret)
+	webhookService := webhooks.NewWebhookService(db, querier, logger, "Webhook Service")
+	webhookService.Start(eventHub) // Delivers note events until the hub is closed
+	graphService := graph.NewGraphService(db, querier, logger, "Graph Service")
+	permissionsService := permissions.NewPermissionsService(querier, logger, "Permissions Service")
+	readProgressService := readprogress.NewReadProgressService(querier, logger, "Read Progress Service")
 
 	// Wire event hub for SSE notifications on all services
 	noteMetaService.SetEventHub(eventHub)
@@ -112,6 +117,13 @@ func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath string, logger *slog.
 	noteTypesService.SetEventHub(eventHub)
 	collectionsService.SetEventHub(eventHub)
 
+	// Note writes invalidate cached per-collection note counts
+	notesService.SetCollectionCache(collectionsService)
+	collectionsService.SetNotesBatchCreator(notesService)
+
+	// Notes of a type with a frontmatter schema must match it on create
+	notesService.SetSchemaService(schemaService)
+
 	// Initialize handlers
 	tagsHandler := tags.NewTagsHandler(tagService)
 	templatesHandler := templates.NewTemplatesHandler(templateService)
@@ -123,7 +135,30 @@ func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath string, logger *slog.
 
 	// Register V3 routes (Connect-RPC with protobuf) - supports gRPC + HTTP/JSON
 	// Connect-RPC requires registration at Echo root level (not in a group)
-	validationOpt := connect.WithInterceptors(interceptors.ValidationInterceptor)
+	// Tracing runs first so requests rejected by validation still produce a span.
+	// Bearer tokens then set the actor ID for requests not already authenticated by API key
+	jwtAuth := interceptors.NewJWTInterceptor(apierrors.Mind, jwtSecret)
+	interceptorOpt := connect.WithInterceptors(interceptors.OTelInterceptor, jwtAuth, interceptors.ValidationInterceptor)
+
+	// Replace/Delete RPCs honor If-Match against the resource's current ETag
+	etagResources := notes.ETagResources(notesService)
+	maps.Copy(etagResources, collections.ETagResources(collectionsService))
+
+	// Note, collection, and tag writes additionally require the editor role on the target collection
+	writeInterceptorOpt := connect.WithInterceptors(
+		interceptors.OTelInterceptor,
+		jwtAuth,
+		interceptors.ValidationInterceptor,
+		permissionsService.RequireCollectionPermission(permissions.RoleEditor),
+		interceptors.NewETagInterceptor(apierrors.Mind, etagResources),
+	)
+
+	// API keys authenticate Mind routes as route-level middleware, so they run after the
+	// global middleware chain (including admin JWT auth) and only for Mind endpoints
+	apiKeyAuth := apikeys.ApiKeyMiddleware(apiKeyService)
+
+	// Plain Echo routes get the same bearer JWT check the Connect interceptor applies
+	bearerAuth := middleware.JWTAuthMiddleware(jwtSecret)
 
 	type serviceReg struct {
 		name    string
@@ -131,13 +166,13 @@ func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath string, logger *slog.
 		handler http.Handler
 	}
 
-	tagsPath, tagsConnHandler := mindv3connect.NewTagsServiceHandler(tagsHandler, validationOpt)
-	templatesPath, templatesConnHandler := mindv3connect.NewTemplatesServiceHandler(templatesHandler, validationOpt)
-	noteTypesPath, noteTypesConnHandler := mindv3connect.NewNoteTypesServiceHandler(noteTypesHandler, validationOpt)
-	collectionsPath, collectionsConnHandler := mindv3connect.NewCollectionsServiceHandler(collectionsHandler, validationOpt)
-	notesPath, notesConnHandler := mindv3connect.NewNotesServiceHandler(notesHandler, validationOpt)
-	noteMetaPath, noteMetaConnHandler := mindv3connect.NewNoteMetaServiceHandler(noteMetaHandler, validationOpt)
-	searchPath, searchConnHandler := mindv3connect.NewSearchServiceHandler(searchHandlerV3, validationOpt)
+	tagsPath, tagsConnHandler := mindv3connect.NewTagsServiceHandler(tagsHandler, writeInterceptorOpt)
+	templatesPath, templatesConnHandler := mindv3connect.NewTemplatesServiceHandler(templatesHandler, interceptorOpt)
+	noteTypesPath, noteTypesConnHandler := mindv3connect.NewNoteTypesServiceHandler(noteTypesHandler, interceptorOpt)
+	collectionsPath, collectionsConnHandler := mindv3connect.NewCollectionsServiceHandler(collectionsHandler, writeInterceptorOpt)
+	notesPath, notesConnHandler := mindv3connect.NewNotesServiceHandler(notesHandler, writeInterceptorOpt)
+	noteMetaPath, noteMetaConnHandler := mindv3connect.NewNoteMetaServiceHandler(noteMetaHandler, interceptorOpt)
+	searchPath, searchConnHandler := mindv3connect.NewSearchServiceHandler(searchHandlerV3, interceptorOpt)
 
 	services := []serviceReg{
 		{"Tags", tagsPath, tagsConnHandler},
@@ -150,16 +185,62 @@ func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath string, logger *slog.
 	}
 
 	for _, svc := range services {
-		registerConnectService(e, logger, svc.name, svc.path, svc.handler)
+		registerConnectService(e, logger, svc.name, svc.path, svc.handler, apiKeyAuth)
 	}
 
 	// Initialize SSE handler for real-time events
 	sseHandler := events.NewSSEHandler(eventHub, logger)
 
 	// Register SSE endpoint for real-time events
-	e.GET("/events/stream", sseHandler.HandleStream)
+	e.GET("/events/stream", sseHandler.HandleStream, apiKeyAuth, bearerAuth)
 	logger.Info("Registered SSE endpoint", "path", "/events/stream")
 
+	// Presence WebSocket: who is editing a note
+	presenceHandler := presence.NewHandler(presence.NewPresenceHub(logger))
+	e.GET("/ws/mind/notes/:id/presence", presenceHandler.HandleConnect, apiKeyAuth, bearerAuth)
+	logger.Info("Registered presence endpoint", "path", "/ws/mind/notes/{id}/presence")
+
+	// Refresh token rotation; the refresh token in the body is the credential
+	auth.NewHandler(authService).RegisterRoutes(e)
+	logger.Info("Registered auth endpoint", "path", "/auth/refresh")
+
+	// Collection maintenance, grants, API keys, and webhooks (admin JWT required via the global /admin/* middleware)
+	collections.NewAdminHandler(collectionsService).RegisterRoutes(e)
+	permissions.NewAdminHandler(permissionsService).RegisterRoutes(e)
+	logger.Info("Registered permission admin endpoints", "path", "/admin/mind/collections/{id}/permissions")
+	apikeys.NewAdminHandler(apiKeyService).RegisterRoutes(e)
+	logger.Info("Registered API key admin endpoints", "path", "/admin/api-keys")
+	webhooks.NewAdminHandler(webhookService).RegisterRoutes(e)
+	logger.Info("Registered webhook admin endpoints", "path", "/admin/webhooks")
+
+	// HTML export is served as plain Echo route since Connect-RPC has no text/html responses.
+	// Every /api/mind route requires an API key or bearer JWT (when a JWT secret is configured)
+	mindGroup := apiGroup.Group("/mind", apiKeyAuth, bearerAuth)
+	mindGroup.GET("/notes/:id", notes.ExportHTMLHandler(notesService))
+	logger.Info("Registered note export endpoint", "path", "/api/mind/notes/{id}:exportHTML")
+	mindGroup.GET("/notes\\:findDuplicates", notes.FindDuplicatesHandler(notesService))
+	logger.Info("Registered duplicate detection endpoint", "path", "/api/mind/notes:findDuplicates")
+	mindGroup.GET("/notes\\:suggest", search.SuggestHandler(searchService))
+	logger.Info("Registered title suggestion endpoint", "path", "/api/mind/notes:suggest")
+	mindGroup.GET("/notes\\:recent", notes.RecentNotesHandler(notesService))
+	logger.Info("Registered recent notes endpoint", "path", "/api/mind/notes:recent")
+	mindGroup.GET("/notes/:id/word-frequency", notes.WordFrequencyHandler(notesService))
+	logger.Info("Registered word frequency endpoint", "path", "/api/mind/notes/{id}/word-frequency")
+	mindGroup.GET("/notes/:id/read-position", readprogress.GetReadPositionHandler(readProgressService))
+	mindGroup.PUT("/notes/:id/read-position", readprogress.UpdateReadPositionHandler(readProgressService))
+	logger.Info("Registered read position endpoint", "path", "/api/mind/notes/{id}/read-position")
+	mindGroup.GET("/actors/:id/mentions", notes.MentionsHandler(notesService))
+	logger.Info("Registered mentions endpoint", "path", "/api/mind/actors/{id}/mentions")
+	mindGroup.GET("/actors/:id/favorites", notes.ListFavoritesHandler(notesService))
+	mindGroup.PUT("/actors/:id/favorites\\:reorder", notes.ReorderFavoritesHandler(notesService))
+	mindGroup.PUT("/actors/:id/favorites/:note_id", notes.FavoriteNoteHandler(notesService))
+	mindGroup.DELETE("/actors/:id/favorites/:note_id", notes.UnfavoriteNoteHandler(notesService))
+	logger.Info("Registered favorites endpoints", "path", "/api/mind/actors/{id}/favorites")
+	mindGroup.GET("/collections/:id", graph.ExportGraphHandler(graphService))
+	logger.Info("Registered graph export endpoint", "path", "/api/mind/collections/{id}:exportGraph")
+	mindGroup.PUT("/collections\\:reorder", collections.ReorderCollectionsHandler(collectionsService, permissionsService))
+	logger.Info("Registered collection reorder endpoint", "path", "/api/mind/collections:reorder")
+
 	// Note: Import service registration removed - See issue #37 for decision on restoration
 
 	logger.Info("✅ Mind service ready")
@@ -170,12 +251,13 @@ func Initialize(e *echo.Echo, apiGroup *echo.Group, dbPath string, logger *slog.
 // registerConnectService registers a Connect-RPC service handler with Echo.
 // Connect-RPC supports gRPC (binary protobuf over HTTP/2), gRPC-Web (for browsers),
 // and Connect protocol (JSON or binary over HTTP/1.1 or HTTP/2).
-func registerConnectService(e *echo.Echo, logger *slog.Logger, serviceName, path string, handler http.Handler) {
+// Route-level middleware (e.g., API key auth) runs before the Connect handler.
+func registerConnectService(e *echo.Echo, logger *slog.Logger, serviceName, path string, handler http.Handler, middleware ...echo.MiddlewareFunc) {
 	// Wrap in h2c handler for HTTP/2 without TLS (needed for gRPC)
 	h2cHandler := h2c.NewHandler(handler, &http2.Server{})
 
 	// Register with Echo - Match all methods and let Connect handle routing
-	e.Match([]string{"GET", "POST", "PUT", "DELETE", "PATCH"}, path+"*", echo.WrapHandler(h2cHandler))
+	e.Match([]string{"GET", "POST", "PUT", "DELETE", "PATCH"}, path+"*", echo.WrapHandler(h2cHandler), middleware...)
 
 	logger.Info("Registered V3 routes", "service", serviceName, "path", path)
 }
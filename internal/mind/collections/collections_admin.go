@@ -0,0 +1,82 @@
+package collections
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminHandler serves collection maintenance endpoints under /admin.
+// Requests are authenticated by middleware.AdminAuthMiddleware, which guards all /admin/* routes.
+type AdminHandler struct {
+	service *CollectionsService
+}
+
+// OrphanedCollection is a collection reported by GET /admin/mind/collections:orphaned.
+type OrphanedCollection struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	ParentID *int64 `json:"parent_id,omitempty"`
+	Depth    int    `json:"depth"`
+}
+
+// OrphanedCollectionsResponse is the body of GET /admin/mind/collections:orphaned.
+type OrphanedCollectionsResponse struct {
+	Collections []OrphanedCollection `json:"collections"`
+}
+
+// PruneOrphanedResponse is the body of POST /admin/mind/collections:pruneOrphaned.
+type PruneOrphanedResponse struct {
+	Count  int  `json:"count"`   // Collections deleted (or that would be, with dry_run)
+	DryRun bool `json:"dry_run"` // True if nothing was deleted
+}
+
+// NewAdminHandler creates a new collections admin handler.
+func NewAdminHandler(service *CollectionsService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// RegisterRoutes registers collection admin routes on the Echo instance.
+// Colons are escaped so Echo matches the AIP-136 style suffixes literally.
+func (h *AdminHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/admin/mind")
+	admin.GET("/collections\\:orphaned", h.HandleListOrphaned)
+	admin.POST("/collections\\:pruneOrphaned", h.HandlePruneOrphaned)
+}
+
+// HandleListOrphaned lists collections with no notes and no children.
+func (h *AdminHandler) HandleListOrphaned(c echo.Context) error {
+	orphans, err := h.service.GetOrphanedCollections(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list orphaned collections")
+	}
+
+	resp := OrphanedCollectionsResponse{Collections: make([]OrphanedCollection, len(orphans))}
+	for i, o := range orphans {
+		resp.Collections[i] = OrphanedCollection{ID: o.ID, Name: o.Name, Path: o.Path, Depth: o.Depth}
+		if o.ParentID.Valid {
+			resp.Collections[i].ParentID = &o.ParentID.Int64
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandlePruneOrphaned deletes orphaned collections; ?dry_run=true only counts them.
+func (h *AdminHandler) HandlePruneOrphaned(c echo.Context) error {
+	dryRun := false
+	if raw := c.QueryParam("dry_run"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "dry_run must be a boolean")
+		}
+		dryRun = parsed
+	}
+
+	count, err := h.service.PruneOrphanedCollections(c.Request().Context(), dryRun)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to prune orphaned collections")
+	}
+	return c.JSON(http.StatusOK, PruneOrphanedResponse{Count: count, DryRun: dryRun})
+}
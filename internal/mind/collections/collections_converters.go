@@ -17,6 +17,7 @@ func StoreCollectionToProto(c store.Collection) *mindv3.Collection {
 		DisplayName: c.Name,
 		Path:        c.Path,
 		IsSystem:    c.IsSystem,
+		Etag:        utils.ComputeHashedETag(c.Version),
 	}
 
 	if parentID := utils.FromInterface(c.ParentID); parentID != nil {
@@ -110,3 +111,38 @@ func CollectionTreeRowsToProto(rows []sqlcext.CollectionTreeRow) []*mindv3.Colle
 	}
 	return result
 }
+
+// BreadcrumbsToProto converts collection breadcrumbs (from the ancestors CTE query) to proto.
+func BreadcrumbsToProto(crumbs []sqlcext.CollectionBreadcrumb) []*mindv3.Breadcrumb {
+	result := make([]*mindv3.Breadcrumb, len(crumbs))
+	for i, c := range crumbs {
+		result[i] = &mindv3.Breadcrumb{
+			Id:    c.ID,
+			Name:  c.Name,
+			Path:  c.Path,
+			Depth: int32(c.Depth),
+		}
+	}
+	return result
+}
+
+// CollectionStatsToProto converts collection statistics to proto.
+func CollectionStatsToProto(stats *CollectionStats) *mindv3.CollectionStats {
+	proto := &mindv3.CollectionStats{
+		NoteCount:           stats.NoteCount,
+		TemplateCount:       stats.TemplateCount,
+		TopTags:             make([]*mindv3.TagCount, len(stats.TopTags)),
+		LinkCount:           stats.LinkCount,
+		DescendantNoteCount: stats.DescendantNoteCount,
+	}
+
+	if !stats.LastModifiedAt.IsZero() {
+		proto.LastModifiedTime = timestamppb.New(stats.LastModifiedAt)
+	}
+
+	for i, tag := range stats.TopTags {
+		proto.TopTags[i] = &mindv3.TagCount{Name: tag.Name, Count: int32(tag.Count)}
+	}
+
+	return proto
+}
@@ -15,4 +15,30 @@ var (
 
 	// ErrInvalidParentCollection is returned when parent_id references a non-existent collection.
 	ErrInvalidParentCollection = errors.New("invalid parent collection")
+
+	// ErrInvalidMergeTarget is returned when a collection is merged into itself or one of its descendants.
+	ErrInvalidMergeTarget = errors.New("invalid merge target")
+
+	// ErrCollectionPathConflict is returned when a merge would place a sub-collection or
+	// note next to an existing one with the same name.
+	ErrCollectionPathConflict = errors.New("collection path conflict")
+
+	// ErrInvalidReorder is returned when a reorder lists no collections or the same collection twice.
+	ErrInvalidReorder = errors.New("collection_ids must be non-empty and unique")
+
+	// ErrReorderParentMismatch is returned when a reordered collection does not belong to the given parent.
+	ErrReorderParentMismatch = errors.New("collection does not belong to the given parent")
+
+	// ErrImportRootNotDirectory is returned when a filesystem import does not start at a directory.
+	ErrImportRootNotDirectory = errors.New("import root is not a directory")
+
+	// ErrImportUnavailable is returned when importing notes without a notes batch creator configured.
+	ErrImportUnavailable = errors.New("note import is not configured")
+
+	// ErrImportNoteConflict is reported for an imported file whose title is already used by
+	// another note in the target collection.
+	ErrImportNoteConflict = errors.New("a note with this title already exists in the collection")
+
+	// ErrImportNoteTrashed is reported for an imported file whose earlier import is in the trash.
+	ErrImportNoteTrashed = errors.New("a previous import of this note is in the trash")
 )
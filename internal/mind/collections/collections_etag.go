@@ -0,0 +1,41 @@
+package collections
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/gen/proto/mind/v3/mindv3connect"
+	"github.com/nkapatos/mindweaver/shared/interceptors"
+)
+
+// collectionVersions resolves collection versions for interceptors.NewETagInterceptor.
+type collectionVersions struct {
+	service *CollectionsService
+}
+
+// ResourceVersion returns the version of the collection targeted by a Delete request.
+func (v collectionVersions) ResourceVersion(ctx context.Context, req connect.AnyRequest) (int64, bool, error) {
+	msg, ok := req.Any().(*mindv3.DeleteCollectionRequest)
+	if !ok {
+		return 0, false, nil
+	}
+
+	collection, err := v.service.GetCollectionByID(ctx, msg.Id)
+	if err != nil {
+		if errors.Is(err, ErrCollectionNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return collection.Version, true, nil
+}
+
+// ETagResources returns the collection procedures guarded by If-Match, keyed by procedure.
+func ETagResources(service *CollectionsService) map[string]interceptors.VersionedResource {
+	return map[string]interceptors.VersionedResource{
+		mindv3connect.CollectionsServiceDeleteCollectionProcedure: collectionVersions{service: service},
+	}
+}
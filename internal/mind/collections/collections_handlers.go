@@ -40,7 +40,7 @@ func (h *CollectionsHandler) CreateCollection(
 		if errors.Is(err, ErrInvalidParentCollection) {
 			return nil, apierrors.NewInvalidArgumentError("parent_id", ErrInvalidParentCollection.Error())
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to generate collection path", err)
+		return nil, apierrors.Mind.Internal("failed to generate collection path", err)
 	}
 
 	params := ProtoCreateCollectionToStore(req.Msg, path)
@@ -48,12 +48,12 @@ func (h *CollectionsHandler) CreateCollection(
 	collection, err := h.service.CreateCollection(ctx, params)
 	if err != nil {
 		if errors.Is(err, ErrCollectionAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "collection", "path", path)
+			return nil, apierrors.Mind.AlreadyExists("collection", "path", path)
 		}
 		if errors.Is(err, ErrInvalidParentCollection) {
 			return nil, apierrors.NewInvalidArgumentError("parent_id", ErrInvalidParentCollection.Error())
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to create collection", err)
+		return nil, apierrors.Mind.Internal("failed to create collection", err)
 	}
 
 	return connect.NewResponse(StoreCollectionToProto(collection)), nil
@@ -66,9 +66,9 @@ func (h *CollectionsHandler) GetCollection(
 	collection, err := h.service.GetCollectionByID(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, ErrCollectionNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "collection", strconv.FormatInt(req.Msg.Id, 10))
+			return nil, apierrors.Mind.NotFound("collection", strconv.FormatInt(req.Msg.Id, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get collection", err)
+		return nil, apierrors.Mind.Internal("failed to get collection", err)
 	}
 
 	return connect.NewResponse(StoreCollectionToProto(collection)), nil
@@ -81,13 +81,13 @@ func (h *CollectionsHandler) UpdateCollection(
 	current, err := h.service.GetCollectionByID(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, ErrCollectionNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "collection", strconv.FormatInt(req.Msg.Id, 10))
+			return nil, apierrors.Mind.NotFound("collection", strconv.FormatInt(req.Msg.Id, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get collection", err)
+		return nil, apierrors.Mind.Internal("failed to get collection", err)
 	}
 
 	if current.IsSystem {
-		return nil, apierrors.NewPermissionDeniedError(apierrors.MindDomain, ErrCollectionIsSystem.Error())
+		return nil, apierrors.Mind.PermissionDenied(ErrCollectionIsSystem.Error())
 	}
 
 	// Regenerate path if name or parent changed
@@ -101,7 +101,7 @@ func (h *CollectionsHandler) UpdateCollection(
 		if errors.Is(err, ErrInvalidParentCollection) {
 			return nil, apierrors.NewInvalidArgumentError("parent_id", ErrInvalidParentCollection.Error())
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to generate collection path", err)
+		return nil, apierrors.Mind.Internal("failed to generate collection path", err)
 	}
 
 	params := ProtoUpdateCollectionToStore(req.Msg, path, current.IsSystem)
@@ -109,25 +109,17 @@ func (h *CollectionsHandler) UpdateCollection(
 	err = h.service.UpdateCollection(ctx, params)
 	if err != nil {
 		if errors.Is(err, ErrCollectionAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "collection", "path", path)
+			return nil, apierrors.Mind.AlreadyExists("collection", "path", path)
 		}
 		if errors.Is(err, ErrInvalidParentCollection) {
 			return nil, apierrors.NewInvalidArgumentError("parent_id", ErrInvalidParentCollection.Error())
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to update collection", err)
-	}
-
-	// If path changed, update descendant paths
-	if path != current.Path {
-		err = h.service.UpdateDescendantPaths(ctx, req.Msg.Id, path)
-		if err != nil {
-			return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to update descendant paths", err)
-		}
+		return nil, apierrors.Mind.Internal("failed to update collection", err)
 	}
 
 	updated, err := h.service.GetCollectionByID(ctx, req.Msg.Id)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve updated collection", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve updated collection", err)
 	}
 
 	return connect.NewResponse(StoreCollectionToProto(updated)), nil
@@ -140,13 +132,13 @@ func (h *CollectionsHandler) DeleteCollection(
 	collection, err := h.service.GetCollectionByID(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, ErrCollectionNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "collection", strconv.FormatInt(req.Msg.Id, 10))
+			return nil, apierrors.Mind.NotFound("collection", strconv.FormatInt(req.Msg.Id, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get collection", err)
+		return nil, apierrors.Mind.Internal("failed to get collection", err)
 	}
 
 	if collection.IsSystem {
-		return nil, apierrors.NewPermissionDeniedError(apierrors.MindDomain, ErrCollectionIsSystem.Error())
+		return nil, apierrors.Mind.PermissionDenied(ErrCollectionIsSystem.Error())
 	}
 
 	err = h.service.DeleteCollection(ctx, req.Msg.Id)
@@ -156,9 +148,9 @@ func (h *CollectionsHandler) DeleteCollection(
 			metadata := map[string]string{
 				"collection_id": strconv.FormatInt(req.Msg.Id, 10),
 			}
-			return nil, apierrors.NewFailedPreconditionError(apierrors.MindDomain, "COLLECTION_HAS_NOTES", metadata)
+			return nil, apierrors.Mind.FailedPrecondition("COLLECTION_HAS_NOTES", metadata)
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to delete collection", err)
+		return nil, apierrors.Mind.Internal("failed to delete collection", err)
 	}
 
 	return connect.NewResponse(&emptypb.Empty{}), nil
@@ -191,7 +183,7 @@ func (h *CollectionsHandler) ListCollections(
 	}
 
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list collections", err)
+		return nil, apierrors.Mind.Internal("failed to list collections", err)
 	}
 
 	// Count errors are logged in service but don't fail the request
@@ -226,7 +218,7 @@ func (h *CollectionsHandler) ListCollectionChildren(
 	parentID := utils.NullInt64(req.Msg.ParentId)
 	collections, err := h.service.ListCollectionsByParentPaginated(ctx, parentID, params.Limit, params.Offset)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list collection children", err)
+		return nil, apierrors.Mind.Internal("failed to list collection children", err)
 	}
 
 	var totalCount int64
@@ -262,15 +254,15 @@ func (h *CollectionsHandler) GetCollectionTree(
 	root, err := h.service.GetCollectionByID(ctx, req.Msg.RootId)
 	if err != nil {
 		if errors.Is(err, ErrCollectionNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "collection", strconv.FormatInt(req.Msg.RootId, 10))
+			return nil, apierrors.Mind.NotFound("collection", strconv.FormatInt(req.Msg.RootId, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get collection", err)
+		return nil, apierrors.Mind.Internal("failed to get collection", err)
 	}
 
 	maxDepth := int(req.Msg.MaxDepth)
 	descendants, err := h.service.GetCollectionSubtree(ctx, req.Msg.RootId, maxDepth)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get collection subtree", err)
+		return nil, apierrors.Mind.Internal("failed to get collection subtree", err)
 	}
 
 	resp := &mindv3.GetCollectionTreeResponse{
@@ -280,3 +272,37 @@ func (h *CollectionsHandler) GetCollectionTree(
 
 	return connect.NewResponse(resp), nil
 }
+
+// GetCollectionStats implements the AIP-136 :stats custom method for collections.
+func (h *CollectionsHandler) GetCollectionStats(
+	ctx context.Context,
+	req *connect.Request[mindv3.GetCollectionStatsRequest],
+) (*connect.Response[mindv3.CollectionStats], error) {
+	stats, err := h.service.GetCollectionStats(ctx, req.Msg.Id)
+	if err != nil {
+		if errors.Is(err, ErrCollectionNotFound) {
+			return nil, apierrors.Mind.NotFound("collection", strconv.FormatInt(req.Msg.Id, 10))
+		}
+		return nil, apierrors.Mind.Internal("failed to get collection stats", err)
+	}
+
+	return connect.NewResponse(CollectionStatsToProto(stats)), nil
+}
+
+// ListBreadcrumbs returns the ancestors of a collection, root first.
+func (h *CollectionsHandler) ListBreadcrumbs(
+	ctx context.Context,
+	req *connect.Request[mindv3.ListBreadcrumbsRequest],
+) (*connect.Response[mindv3.ListBreadcrumbsResponse], error) {
+	crumbs, err := h.service.ListBreadcrumbs(ctx, req.Msg.Id)
+	if err != nil {
+		if errors.Is(err, ErrCollectionNotFound) {
+			return nil, apierrors.Mind.NotFound("collection", strconv.FormatInt(req.Msg.Id, 10))
+		}
+		return nil, apierrors.Mind.Internal("failed to list breadcrumbs", err)
+	}
+
+	return connect.NewResponse(&mindv3.ListBreadcrumbsResponse{
+		Breadcrumbs: BreadcrumbsToProto(crumbs),
+	}), nil
+}
@@ -0,0 +1,238 @@
+package collections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// NotesBatchCreator creates notes in bulk. It is implemented by notes.NotesService and kept
+// as an interface so this package does not import notes.
+type NotesBatchCreator interface {
+	CreateNotesBatch(ctx context.Context, notes []store.CreateNoteParams) ([]int64, error)
+}
+
+// ImportOptions controls ImportFromFilesystem.
+type ImportOptions struct {
+	DryRun bool // Report what would be created without writing anything
+}
+
+// ImportError is a file or directory that could not be imported.
+type ImportError struct {
+	Path string `json:"path"` // Relative to the import root
+	Err  string `json:"error"`
+}
+
+// CollectionImportResult summarizes an ImportFromFilesystem run.
+type CollectionImportResult struct {
+	CollectionsCreated int           `json:"collections_created"`
+	NotesCreated       int           `json:"notes_created"`
+	NotesUpdated       int           `json:"notes_updated"` // Notes from an earlier import of the same tree
+	Errors             []ImportError `json:"errors,omitempty"`
+}
+
+// SetNotesBatchCreator sets the notes service used by ImportFromFilesystem.
+func (s *CollectionsService) SetNotesBatchCreator(creator NotesBatchCreator) {
+	s.notesCreator = creator
+}
+
+// ImportFromFilesystem mirrors the directory tree at rootPath under parentCollectionID:
+// every directory becomes a collection named after it, and every markdown file becomes a
+// note titled after the file name. See importFS.
+func (s *CollectionsService) ImportFromFilesystem(ctx context.Context, rootPath string, parentCollectionID int64, opts ImportOptions) (CollectionImportResult, error) {
+	info, err := os.Stat(rootPath)
+	if err != nil {
+		return CollectionImportResult{}, fmt.Errorf("import root %s: %w", rootPath, err)
+	}
+	if !info.IsDir() {
+		return CollectionImportResult{}, fmt.Errorf("import root %s: %w", rootPath, ErrImportRootNotDirectory)
+	}
+	return s.importFS(ctx, os.DirFS(rootPath), parentCollectionID, opts)
+}
+
+// importFS imports the tree of fsys under parentCollectionID.
+//
+// Directories that already exist as collections are reused, and note UUIDs are derived from
+// the target collection path and file path, so importing the same tree again updates the
+// notes instead of duplicating them. Hidden files and directories (such as .git) and
+// non-markdown files are skipped. Failures of single files or directories are collected in
+// the result; their subtree is skipped and the import carries on.
+//
+// Collections are created as the tree is walked; the notes are created at the end in one
+// CreateNotesBatch call, which is all-or-nothing.
+func (s *CollectionsService) importFS(ctx context.Context, fsys fs.FS, parentCollectionID int64, opts ImportOptions) (CollectionImportResult, error) {
+	var result CollectionImportResult
+
+	if !opts.DryRun && s.notesCreator == nil {
+		return result, ErrImportUnavailable
+	}
+
+	parent, err := s.store.GetCollectionByID(ctx, parentCollectionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return result, ErrInvalidParentCollection
+		}
+		s.logger.Error("failed to get import parent collection", "id", parentCollectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return result, err
+	}
+
+	// Directory (relative to the root) -> target collection. New collections in a dry run
+	// have ID 0, which is never used for a lookup.
+	type target struct {
+		id      int64
+		path    string
+		created bool
+	}
+	targets := map[string]target{".": {id: parent.ID, path: parent.Path}}
+
+	var pending []store.CreateNoteParams
+	fail := func(p string, err error) {
+		result.Errors = append(result.Errors, ImportError{Path: p, Err: err.Error()})
+	}
+
+	err = fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			fail(p, walkErr)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if p == "." {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		dir, ok := targets[path.Dir(p)]
+		if !ok {
+			// The parent directory failed and was skipped
+			return fs.SkipDir
+		}
+
+		if d.IsDir() {
+			t := target{path: fmt.Sprintf("%s/%s", dir.path, utils.GenerateSlug(d.Name()))}
+			existing, err := s.store.GetCollectionByPath(ctx, t.path)
+			switch {
+			case err == nil:
+				t.id = existing.ID
+			case !errors.Is(err, sql.ErrNoRows):
+				fail(p, err)
+				return fs.SkipDir
+			case opts.DryRun:
+				t.created = true
+				result.CollectionsCreated++
+			default:
+				collection, err := s.CreateCollection(ctx, store.CreateCollectionParams{
+					Name:     d.Name(),
+					ParentID: dir.id,
+					Path:     t.path,
+				})
+				if err != nil {
+					fail(p, err)
+					return fs.SkipDir
+				}
+				t.id = collection.ID
+				t.created = true
+				result.CollectionsCreated++
+			}
+			targets[p] = t
+			return nil
+		}
+
+		ext := path.Ext(d.Name())
+		if !strings.EqualFold(ext, ".md") {
+			return nil
+		}
+		title := strings.TrimSuffix(d.Name(), ext)
+		noteUUID := uuid.NewSHA1(uuid.NameSpaceURL, []byte("mindweaver-import:"+dir.path+"/"+d.Name()))
+
+		exists := false
+		if !dir.created {
+			if existing, err := s.store.GetNoteByUUIDWithTrashed(ctx, noteUUID); err == nil {
+				if existing.DeletedAt.Valid {
+					// Its UUID is still taken, so the whole batch would fail on it
+					fail(p, ErrImportNoteTrashed)
+					return nil
+				}
+				exists = true
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				fail(p, err)
+				return nil
+			}
+			if !exists {
+				// A note created outside the import would fail the whole batch on its title
+				if _, err := s.store.GetNoteByTitle(ctx, store.GetNoteByTitleParams{Title: title, CollectionID: dir.id}); err == nil {
+					fail(p, ErrImportNoteConflict)
+					return nil
+				} else if !errors.Is(err, sql.ErrNoRows) {
+					fail(p, err)
+					return nil
+				}
+			}
+		}
+		var body []byte
+		if !opts.DryRun {
+			data, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				fail(p, err)
+				return nil
+			}
+			body = data
+		}
+		if exists {
+			result.NotesUpdated++
+		} else {
+			result.NotesCreated++
+		}
+		if opts.DryRun {
+			return nil
+		}
+
+		pending = append(pending, store.CreateNoteParams{
+			Uuid:         noteUUID,
+			Title:        title,
+			Body:         utils.NullString(string(body)),
+			CollectionID: dir.id,
+		})
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	if len(pending) > 0 {
+		if _, err := s.notesCreator.CreateNotesBatch(ctx, pending); err != nil {
+			s.logger.Error("failed to create imported notes", "count", len(pending), "err", err, "request_id", middleware.GetRequestID(ctx))
+			result.NotesCreated, result.NotesUpdated = 0, 0
+			return result, err
+		}
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("imported directory tree",
+		"parent_id", parentCollectionID,
+		"dry_run", opts.DryRun,
+		"collections_created", result.CollectionsCreated,
+		"notes_created", result.NotesCreated,
+		"notes_updated", result.NotesUpdated,
+		"errors", len(result.Errors))
+	return result, nil
+}
@@ -0,0 +1,175 @@
+package collections
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/internal/mind/permissions"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// ReorderCollections sets the position of the children of parentID (invalid = top level)
+// to their index in orderedIDs, in one transaction. Every ID must be a child of parentID.
+// Children left out keep their position. The parent's version is bumped so its ETag changes.
+func (s *CollectionsService) ReorderCollections(ctx context.Context, parentID sql.NullInt64, orderedIDs []int64) error {
+	if len(orderedIDs) == 0 {
+		return ErrInvalidReorder
+	}
+	seen := make(map[int64]bool, len(orderedIDs))
+	for _, id := range orderedIDs {
+		if seen[id] {
+			return ErrInvalidReorder
+		}
+		seen[id] = true
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	if parentID.Valid {
+		if _, err := txStore.GetCollectionByID(ctx, parentID.Int64); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrCollectionNotFound
+			}
+			s.logger.Error("failed to get parent collection for reorder", "id", parentID.Int64, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+	}
+
+	for position, id := range orderedIDs {
+		collection, err := txStore.GetCollectionByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrCollectionNotFound
+			}
+			s.logger.Error("failed to get collection for reorder", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+		if collection.ParentID != parentID {
+			return ErrReorderParentMismatch
+		}
+
+		if err := txStore.UpdateCollectionPosition(ctx, store.UpdateCollectionPositionParams{
+			ID:       id,
+			Position: utils.NullInt64(int64(position)),
+		}); err != nil {
+			s.logger.Error("failed to update collection position", "id", id, "position", position, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+	}
+
+	if parentID.Valid {
+		if err := txStore.BumpCollectionVersion(ctx, parentID.Int64); err != nil {
+			s.logger.Error("failed to bump parent collection version", "id", parentID.Int64, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "parent_id", parentID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("collections reordered", "parent_id", parentID, "count", len(orderedIDs))
+
+	if s.eventHub != nil {
+		for _, id := range orderedIDs {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_UPDATED, id)
+		}
+		if parentID.Valid {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_UPDATED, parentID.Int64)
+		}
+	}
+
+	return nil
+}
+
+// ReorderCollectionsRequest is the JSON body of PUT /collections:reorder.
+// A missing parent_id reorders top-level collections.
+type ReorderCollectionsRequest struct {
+	ParentID      *int64  `json:"parent_id"`
+	CollectionIDs []int64 `json:"collection_ids"`
+}
+
+// ReorderCollectionsHandler serves PUT /collections:reorder.
+// The caller needs the editor role on the parent, or on every reordered collection when
+// reordering the top level.
+// When reordering the children of a collection, If-Match may carry the parent's ETag;
+// a stale ETag is rejected with 412 so concurrent reorders don't silently overwrite each other.
+func ReorderCollectionsHandler(service *CollectionsService, perms *permissions.PermissionsService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req ReorderCollectionsRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		// Positions belong to the parent; top-level collections have none, so each one is checked
+		checkIDs := req.CollectionIDs
+		if req.ParentID != nil {
+			checkIDs = []int64{*req.ParentID}
+		}
+		actorID := middleware.GetActorID(ctx)
+		for _, id := range checkIDs {
+			err := perms.CheckPermission(ctx, actorID, id, permissions.RoleEditor)
+			if errors.Is(err, permissions.ErrPermissionDenied) {
+				return echo.NewHTTPError(http.StatusForbidden, "requires editor role on collection")
+			}
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to check permission")
+			}
+		}
+
+		var parentID sql.NullInt64
+		if req.ParentID != nil {
+			parentID = utils.NullInt64(*req.ParentID)
+
+			if ifMatch := c.Request().Header.Get("If-Match"); ifMatch != "" && ifMatch != "*" {
+				parent, err := service.GetCollectionByID(ctx, *req.ParentID)
+				if err != nil {
+					if errors.Is(err, ErrCollectionNotFound) {
+						return echo.NewHTTPError(http.StatusNotFound, "parent collection not found")
+					}
+					return echo.NewHTTPError(http.StatusInternalServerError, "failed to get parent collection")
+				}
+				if ifMatch != utils.ComputeHashedETag(parent.Version) {
+					return echo.NewHTTPError(http.StatusPreconditionFailed, "parent collection was modified")
+				}
+			}
+		}
+
+		err := service.ReorderCollections(ctx, parentID, req.CollectionIDs)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrCollectionNotFound):
+				return echo.NewHTTPError(http.StatusNotFound, "collection not found")
+			case errors.Is(err, ErrInvalidReorder), errors.Is(err, ErrReorderParentMismatch):
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			default:
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to reorder collections")
+			}
+		}
+
+		if parentID.Valid {
+			parent, err := service.GetCollectionByID(ctx, parentID.Int64)
+			if err == nil {
+				c.Response().Header().Set("ETag", utils.ComputeHashedETag(parent.Version))
+			}
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
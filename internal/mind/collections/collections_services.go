@@ -6,6 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
 	"github.com/nkapatos/mindweaver/internal/mind/events"
@@ -16,16 +20,49 @@ import (
 	"github.com/nkapatos/mindweaver/shared/utils"
 )
 
+// topTagsLimit is the number of tags reported in CollectionStats.TopTags.
+const topTagsLimit = 10
+
+// CollectionStats holds aggregate statistics for the notes directly in a collection.
+// DescendantNoteCount is the only field that includes descendant collections.
+type CollectionStats struct {
+	NoteCount      int64     // Live notes, excluding templates
+	TemplateCount  int64     // Live template notes
+	LastModifiedAt time.Time // Most recent note update (zero if the collection is empty)
+	TopTags        []TagCount
+	LinkCount      int64 // Outgoing links from notes in the collection
+
+	DescendantNoteCount int64 // Live notes, including templates, in the collection and its descendants
+}
+
+// TagCount is a tag and the number of notes in a collection using it.
+type TagCount struct {
+	Name  string
+	Count int
+}
+
 type CollectionsService struct {
 	store      store.Querier
+	db         *sql.DB
 	cteQuerier *sqlcext.CTEQuerier
 	logger     *slog.Logger
 	eventHub   events.Hub
+
+	// notesCreator creates the notes of ImportFromFilesystem; set by SetNotesBatchCreator.
+	notesCreator NotesBatchCreator
+
+	// noteCounts caches live note counts per collection (collection ID -> count).
+	// Entries are dropped by InvalidateCacheForCollection after note writes.
+	noteCounts sync.Map
+	// cacheGen is bumped on every invalidation so a count read concurrently
+	// with a write is not left behind in the cache.
+	cacheGen atomic.Uint64
 }
 
-func NewCollectionsService(db sqlcext.DB, store store.Querier, logger *slog.Logger, serviceName string) *CollectionsService {
+func NewCollectionsService(db *sql.DB, store store.Querier, logger *slog.Logger, serviceName string) *CollectionsService {
 	return &CollectionsService{
 		store:      store,
+		db:         db,
 		cteQuerier: sqlcext.NewCTEQuerier(db),
 		logger:     logger.With("service", serviceName),
 	}
@@ -93,8 +130,18 @@ func (s *CollectionsService) GetCollectionByPath(ctx context.Context, path strin
 	return collection, nil
 }
 
+// CreateCollection creates a collection together with its full-text search table.
 func (s *CollectionsService) CreateCollection(ctx context.Context, params store.CreateCollectionParams) (store.Collection, error) {
-	id, err := s.store.CreateCollection(ctx, params)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Collection{}, err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	id, err := txStore.CreateCollection(ctx, params)
 	if err != nil {
 		if sharederrors.IsUniqueConstraintError(err) {
 			return store.Collection{}, ErrCollectionAlreadyExists
@@ -106,13 +153,23 @@ func (s *CollectionsService) CreateCollection(ctx context.Context, params store.
 		return store.Collection{}, err
 	}
 
-	collection, err := s.store.GetCollectionByID(ctx, id)
+	if err := sqlcext.NewFTSManager(tx).CreateCollectionFTSTable(ctx, id); err != nil {
+		s.logger.Error("failed to create collection fts table", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Collection{}, err
+	}
+
+	collection, err := txStore.GetCollectionByID(ctx, id)
 	if err != nil {
 		s.logger.Error("failed to fetch created collection", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return store.Collection{}, err
 	}
 
-	s.logger.Info("collection created", "id", id, "path", params.Path, "request_id", middleware.GetRequestID(ctx))
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Collection{}, err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("collection created", "collection_id", id, "path", params.Path)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_CREATED, id)
@@ -122,8 +179,27 @@ func (s *CollectionsService) CreateCollection(ctx context.Context, params store.
 }
 
 // UpdateCollection updates an existing collection.
+// If the path changed (rename or move), descendant paths are rewritten in the same transaction.
 func (s *CollectionsService) UpdateCollection(ctx context.Context, params store.UpdateCollectionParams) error {
-	err := s.store.UpdateCollection(ctx, params)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	current, err := txStore.GetCollectionByID(ctx, params.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCollectionNotFound
+		}
+		s.logger.Error("failed to get collection for update", "id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	err = txStore.UpdateCollection(ctx, params)
 	if err != nil {
 		if sharederrors.IsUniqueConstraintError(err) {
 			return ErrCollectionAlreadyExists
@@ -134,7 +210,19 @@ func (s *CollectionsService) UpdateCollection(ctx context.Context, params store.
 		s.logger.Error("failed to update collection", "id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("collection updated", "id", params.ID, "request_id", middleware.GetRequestID(ctx))
+
+	if params.Path != current.Path {
+		if err := s.updateDescendantPathsWithStore(ctx, txStore, params.ID, current.Path, params.Path); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("collection updated", "collection_id", params.ID)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_UPDATED, params.ID)
@@ -143,6 +231,220 @@ func (s *CollectionsService) UpdateCollection(ctx context.Context, params store.
 	return nil
 }
 
+// MoveCollection moves a collection under a new parent (or to the root when newParentID is not valid).
+// The collection's parent_id and path, and the paths of all its descendants, are updated atomically.
+func (s *CollectionsService) MoveCollection(ctx context.Context, collectionID int64, newParentID sql.NullInt64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	collection, err := txStore.GetCollectionByID(ctx, collectionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCollectionNotFound
+		}
+		s.logger.Error("failed to get collection for move", "id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	newPath := utils.GenerateSlug(collection.Name)
+	var parentID interface{}
+	if newParentID.Valid {
+		// A collection cannot become its own ancestor
+		if newParentID.Int64 == collectionID {
+			return ErrInvalidParentCollection
+		}
+		descendants, err := txStore.GetCollectionDescendants(ctx, collectionID)
+		if err != nil {
+			s.logger.Error("failed to get collection descendants", "id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+		for _, d := range descendants {
+			if d.ID == newParentID.Int64 {
+				return ErrInvalidParentCollection
+			}
+		}
+
+		parent, err := txStore.GetCollectionByID(ctx, newParentID.Int64)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidParentCollection
+			}
+			s.logger.Error("failed to get parent collection", "parent_id", newParentID.Int64, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+		newPath = fmt.Sprintf("%s/%s", parent.Path, newPath)
+		parentID = newParentID.Int64
+	}
+
+	err = txStore.UpdateCollection(ctx, store.UpdateCollectionParams{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		ParentID:    parentID,
+		Path:        newPath,
+		Description: collection.Description,
+		Position:    collection.Position,
+		IsSystem:    collection.IsSystem,
+	})
+	if err != nil {
+		if sharederrors.IsUniqueConstraintError(err) {
+			return ErrCollectionAlreadyExists
+		}
+		if sharederrors.IsForeignKeyConstraintError(err) {
+			return ErrInvalidParentCollection
+		}
+		s.logger.Error("failed to move collection", "id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := s.updateDescendantPathsWithStore(ctx, txStore, collectionID, collection.Path, newPath); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("collection moved", "collection_id", collectionID, "old_path", collection.Path, "new_path", newPath)
+
+	if s.eventHub != nil {
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_UPDATED, collectionID)
+	}
+
+	return nil
+}
+
+// MergeCollections folds sourceID into targetID: its notes move to the target, its
+// sub-collections are re-parented under the target (paths rewritten), and the source
+// is deleted. Everything happens in one transaction; a name clash with an existing
+// child or note of the target aborts the merge with ErrCollectionPathConflict.
+func (s *CollectionsService) MergeCollections(ctx context.Context, sourceID, targetID int64) error {
+	if sourceID == targetID {
+		return ErrInvalidMergeTarget
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	source, err := txStore.GetCollectionByID(ctx, sourceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCollectionNotFound
+		}
+		s.logger.Error("failed to get source collection for merge", "id", sourceID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if source.IsSystem {
+		return ErrCollectionIsSystem
+	}
+
+	target, err := txStore.GetCollectionByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCollectionNotFound
+		}
+		s.logger.Error("failed to get target collection for merge", "id", targetID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	// The source is deleted at the end, which would cascade into a target living under it
+	descendants, err := txStore.GetCollectionDescendants(ctx, sourceID)
+	if err != nil {
+		s.logger.Error("failed to get collection descendants", "id", sourceID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	for _, d := range descendants {
+		if d.ID == targetID {
+			return ErrInvalidMergeTarget
+		}
+	}
+
+	if _, err := txStore.MoveNotesBetweenCollections(ctx, store.MoveNotesBetweenCollectionsParams{
+		TargetID: targetID,
+		SourceID: sourceID,
+	}); err != nil {
+		if sharederrors.IsUniqueConstraintError(err) {
+			return ErrCollectionPathConflict
+		}
+		s.logger.Error("failed to move notes for merge", "source_id", sourceID, "target_id", targetID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	children, err := txStore.GetCollectionChildren(ctx, utils.NullInt64(sourceID))
+	if err != nil {
+		s.logger.Error("failed to get collection children", "id", sourceID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	for _, child := range children {
+		// source/child/... -> target/child/...
+		newPath := target.Path + strings.TrimPrefix(child.Path, source.Path)
+
+		err := txStore.UpdateCollection(ctx, store.UpdateCollectionParams{
+			ID:          child.ID,
+			Name:        child.Name,
+			ParentID:    targetID,
+			Path:        newPath,
+			Description: child.Description,
+			Position:    child.Position,
+			IsSystem:    child.IsSystem,
+		})
+		if err != nil {
+			if sharederrors.IsUniqueConstraintError(err) {
+				return ErrCollectionPathConflict
+			}
+			s.logger.Error("failed to re-parent collection for merge", "id", child.ID, "target_id", targetID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+
+		if err := s.updateDescendantPathsWithStore(ctx, txStore, child.ID, child.Path, newPath); err != nil {
+			if errors.Is(err, ErrCollectionAlreadyExists) {
+				return ErrCollectionPathConflict
+			}
+			return err
+		}
+	}
+
+	if err := txStore.DeleteCollection(ctx, sourceID); err != nil {
+		s.logger.Error("failed to delete merged collection", "id", sourceID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if err := sqlcext.NewFTSManager(tx).DropCollectionFTSTable(ctx, sourceID); err != nil {
+		s.logger.Error("failed to drop collection fts table", "id", sourceID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "source_id", sourceID, "target_id", targetID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("collections merged", "source_id", sourceID, "target_id", targetID, "children", len(children))
+
+	s.invalidateNoteCountCache()
+
+	if s.eventHub != nil {
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_DELETED, sourceID)
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_UPDATED, targetID)
+		for _, child := range children {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_UPDATED, child.ID)
+		}
+	}
+
+	return nil
+}
+
 func (s *CollectionsService) GetCollectionTree(ctx context.Context, maxDepth int) ([]sqlcext.CollectionTreeRow, error) {
 	tree, err := s.cteQuerier.GetCollectionTree(ctx, maxDepth)
 	if err != nil {
@@ -163,15 +465,64 @@ func (s *CollectionsService) GetCollectionSubtree(ctx context.Context, collectio
 	return subtree, nil
 }
 
-// DeleteCollection deletes a collection by ID.
+// ListBreadcrumbs returns the collections from the root down to collectionID (included),
+// for navigation breadcrumbs.
+func (s *CollectionsService) ListBreadcrumbs(ctx context.Context, collectionID int64) ([]sqlcext.CollectionBreadcrumb, error) {
+	crumbs, err := s.cteQuerier.GetCollectionPath(ctx, collectionID)
+	if err != nil {
+		s.logger.Error("failed to get collection path", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	if len(crumbs) == 0 {
+		return nil, ErrCollectionNotFound
+	}
+	return crumbs, nil
+}
+
+// DeleteCollection deletes a collection by ID, along with the full-text search tables of
+// the collection and of the descendants removed with it.
 // Note: This may fail if there are notes in the collection (FK constraint).
 func (s *CollectionsService) DeleteCollection(ctx context.Context, id int64) error {
-	err := s.store.DeleteCollection(ctx, id)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	descendants, err := txStore.GetCollectionDescendants(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to get collection descendants", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := txStore.DeleteCollection(ctx, id); err != nil {
 		s.logger.Error("failed to delete collection", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("collection deleted", "id", id, "request_id", middleware.GetRequestID(ctx))
+
+	ftsManager := sqlcext.NewFTSManager(tx)
+	dropped := []int64{id}
+	for _, d := range descendants {
+		dropped = append(dropped, d.ID)
+	}
+	for _, collectionID := range dropped {
+		if err := ftsManager.DropCollectionFTSTable(ctx, collectionID); err != nil {
+			s.logger.Error("failed to drop collection fts table", "id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	middleware.ContextLogger(ctx, s.logger).Info("collection deleted", "collection_id", id)
+
+	// Notes of the collection and its descendants fall back to the default collection
+	s.invalidateNoteCountCache()
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_DELETED, id)
@@ -180,6 +531,84 @@ func (s *CollectionsService) DeleteCollection(ctx context.Context, id int64) err
 	return nil
 }
 
+// SearchInCollectionFTS runs a full-text search over the live notes directly in a collection,
+// using the collection's own FTS table so other collections never affect ranking.
+func (s *CollectionsService) SearchInCollectionFTS(ctx context.Context, collectionID int64, params sqlcext.FTSSearchParams) ([]sqlcext.FTSSearchResult, error) {
+	if _, err := s.store.GetCollectionByID(ctx, collectionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCollectionNotFound
+		}
+		s.logger.Error("failed to get collection for search", "id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	results, err := sqlcext.NewFTSManager(s.db).SearchInCollectionFTS(ctx, collectionID, params)
+	if err != nil {
+		s.logger.Error("failed to search collection", "collection_id", collectionID, "query", params.Query, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetOrphanedCollections returns non-system collections that have no notes and no children.
+func (s *CollectionsService) GetOrphanedCollections(ctx context.Context) ([]sqlcext.CollectionTreeRow, error) {
+	orphans, err := s.cteQuerier.GetOrphanedCollections(ctx)
+	if err != nil {
+		s.logger.Error("failed to get orphaned collections", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	return orphans, nil
+}
+
+// PruneOrphanedCollections deletes all orphaned collections in one transaction and
+// returns how many there were. With dryRun nothing is deleted.
+// Parents left empty by the prune become orphans themselves and go on the next run.
+func (s *CollectionsService) PruneOrphanedCollections(ctx context.Context, dryRun bool) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	orphans, err := sqlcext.NewCTEQuerier(tx).GetOrphanedCollections(ctx)
+	if err != nil {
+		s.logger.Error("failed to get orphaned collections", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	if dryRun || len(orphans) == 0 {
+		return len(orphans), nil
+	}
+
+	txStore := store.New(tx)
+	ftsManager := sqlcext.NewFTSManager(tx)
+	for _, orphan := range orphans {
+		if err := txStore.DeleteCollection(ctx, orphan.ID); err != nil {
+			s.logger.Error("failed to delete orphaned collection", "id", orphan.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return 0, err
+		}
+		if err := ftsManager.DropCollectionFTSTable(ctx, orphan.ID); err != nil {
+			s.logger.Error("failed to drop collection fts table", "id", orphan.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("orphaned collections pruned", "count", len(orphans))
+
+	if s.eventHub != nil {
+		for _, orphan := range orphans {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_DELETED, orphan.ID)
+		}
+	}
+
+	return len(orphans), nil
+}
+
 // GetCollectionAncestors returns all ancestors of a collection (parent, grandparent, etc).
 func (s *CollectionsService) GetCollectionAncestors(ctx context.Context, id int64) ([]store.GetCollectionAncestorsRow, error) {
 	ancestors, err := s.store.GetCollectionAncestors(ctx, id)
@@ -239,6 +668,105 @@ func (s *CollectionsService) CountNotesInCollection(ctx context.Context, id int6
 	return count, nil
 }
 
+// CountDescendantNotes returns the number of notes in a collection and all of its
+// descendants. CountNotesInCollection counts the collection's own notes only.
+func (s *CollectionsService) CountDescendantNotes(ctx context.Context, id int64) (int64, error) {
+	count, err := s.store.CountDescendantNotes(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to count descendant notes", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetCollectionStats returns aggregate statistics for the notes directly in a collection.
+// Notes in descendant collections are only included in DescendantNoteCount.
+func (s *CollectionsService) GetCollectionStats(ctx context.Context, id int64) (*CollectionStats, error) {
+	if _, err := s.GetCollectionByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.store.CountNotesAndTemplatesInCollection(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to count notes for collection stats", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	stats := &CollectionStats{
+		NoteCount:     counts.NoteCount,
+		TemplateCount: counts.TemplateCount,
+		TopTags:       []TagCount{},
+	}
+
+	lastModified, err := s.store.GetCollectionLastModified(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		s.logger.Error("failed to get last modified for collection stats", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	if lastModified.Valid {
+		stats.LastModifiedAt = lastModified.Time
+	}
+
+	tags, err := s.store.ListTopTagsInCollection(ctx, store.ListTopTagsInCollectionParams{
+		CollectionID: id,
+		Limit:        topTagsLimit,
+	})
+	if err != nil {
+		s.logger.Error("failed to list top tags for collection stats", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	for _, tag := range tags {
+		stats.TopTags = append(stats.TopTags, TagCount{Name: tag.Name, Count: int(tag.Count)})
+	}
+
+	stats.LinkCount, err = s.store.CountLinksInCollection(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to count links for collection stats", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	stats.DescendantNoteCount, err = s.CountDescendantNotes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetCachedNoteCount returns the number of notes in a collection, serving it from
+// the cache when present and falling back to CountNotesInCollection otherwise.
+func (s *CollectionsService) GetCachedNoteCount(ctx context.Context, collectionID int64) (int64, error) {
+	if count, ok := s.noteCounts.Load(collectionID); ok {
+		return count.(int64), nil
+	}
+
+	gen := s.cacheGen.Load()
+	count, err := s.CountNotesInCollection(ctx, collectionID)
+	if err != nil {
+		return 0, err
+	}
+
+	s.noteCounts.Store(collectionID, count)
+	// An invalidation raced with the query; the stored count may predate the write
+	if s.cacheGen.Load() != gen {
+		s.noteCounts.CompareAndDelete(collectionID, count)
+	}
+	return count, nil
+}
+
+// InvalidateCacheForCollection drops the cached note count of a collection.
+// Called by NotesService after notes are created, updated, moved, or deleted.
+func (s *CollectionsService) InvalidateCacheForCollection(collectionID int64) {
+	s.cacheGen.Add(1)
+	s.noteCounts.Delete(collectionID)
+}
+
+// invalidateNoteCountCache drops all cached note counts.
+func (s *CollectionsService) invalidateNoteCountCache() {
+	s.cacheGen.Add(1)
+	s.noteCounts.Clear()
+}
+
 // ============================================================================
 // Path Management
 // ============================================================================
@@ -282,38 +810,29 @@ func (s *CollectionsService) GenerateCollectionPath(ctx context.Context, name st
 	return fmt.Sprintf("%s/%s", parent.Path, slug), nil
 }
 
-// UpdateDescendantPaths recursively updates paths for all descendants when a collection is moved or renamed.
-// This is called after updating a collection's path to keep the tree consistent.
-func (s *CollectionsService) UpdateDescendantPaths(ctx context.Context, collectionID int64, newPath string) error {
-	// Get all direct children
-	children, err := s.store.GetCollectionChildren(ctx, utils.NullInt64(collectionID))
+// updateDescendantPathsWithStore rewrites the paths of all descendants after a collection's
+// path changed from oldPath to newPath. Uses the recursive GetCollectionDescendants query,
+// so it runs against the given querier (typically a transaction store).
+func (s *CollectionsService) updateDescendantPathsWithStore(ctx context.Context, querier store.Querier, collectionID int64, oldPath, newPath string) error {
+	descendants, err := querier.GetCollectionDescendants(ctx, collectionID)
 	if err != nil {
-		s.logger.Error("failed to get children for path update", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		s.logger.Error("failed to get descendants for path update", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
 
-	// Update each child's path
-	for _, child := range children {
-		// Generate new path for child: parent_path/child_slug
-		childSlug := utils.GenerateSlug(child.Name)
-		newChildPath := fmt.Sprintf("%s/%s", newPath, childSlug)
+	for _, d := range descendants {
+		// Descendant paths share the old prefix: old_path/child/... -> new_path/child/...
+		newDescendantPath := newPath + strings.TrimPrefix(d.Path, oldPath)
 
-		// Update child's path
-		err := s.store.UpdateCollection(ctx, store.UpdateCollectionParams{
-			ID:          child.ID,
-			Name:        child.Name,
-			ParentID:    child.ParentID,
-			Path:        newChildPath,
-			Description: child.Description,
-			Position:    child.Position,
+		err := querier.UpdateCollectionPath(ctx, store.UpdateCollectionPathParams{
+			ID:   d.ID,
+			Path: newDescendantPath,
 		})
 		if err != nil {
-			s.logger.Error("failed to update child path", "child_id", child.ID, "new_path", newChildPath, "err", err, "request_id", middleware.GetRequestID(ctx))
-			return err
-		}
-
-		// Recursively update descendants
-		if err := s.UpdateDescendantPaths(ctx, child.ID, newChildPath); err != nil {
+			if sharederrors.IsUniqueConstraintError(err) {
+				return ErrCollectionAlreadyExists
+			}
+			s.logger.Error("failed to update descendant path", "descendant_id", d.ID, "new_path", newDescendantPath, "err", err, "request_id", middleware.GetRequestID(ctx))
 			return err
 		}
 	}
@@ -0,0 +1,952 @@
+package collections
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/internal/mind/permissions"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/sqlcext"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// setupTestService creates a CollectionsService with a test database for testing.
+func setupTestService(t *testing.T) (*CollectionsService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	logger := testdb.NewTestLogger(t)
+	service := NewCollectionsService(db, queries, logger, "collections-test")
+
+	return service, queries
+}
+
+// createTestCollection creates a collection under parentID (0 = root) with a generated path.
+func createTestCollection(t *testing.T, service *CollectionsService, name string, parentID int64) store.Collection {
+	t.Helper()
+	ctx := context.Background()
+
+	var parent interface{}
+	if parentID != 0 {
+		parent = parentID
+	}
+
+	path, err := service.GenerateCollectionPath(ctx, name, parent)
+	require.NoError(t, err)
+
+	collection, err := service.CreateCollection(ctx, store.CreateCollectionParams{
+		Name:     name,
+		ParentID: parent,
+		Path:     path,
+	})
+	require.NoError(t, err)
+	return collection
+}
+
+// requirePath asserts the stored path of a collection.
+func requirePath(t *testing.T, queries *store.Queries, id int64, expected string) {
+	t.Helper()
+
+	collection, err := queries.GetCollectionByID(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, expected, collection.Path)
+}
+
+// ============================================================================
+// MoveCollection Tests
+// ============================================================================
+
+func TestMoveCollection_SubtreeThreeLevelsDeep(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	// work/projects/alpha/notes, moved under archive
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+	alpha := createTestCollection(t, service, "Alpha", projects.ID)
+	alphaNotes := createTestCollection(t, service, "Notes", alpha.ID)
+	archive := createTestCollection(t, service, "Archive", 0)
+
+	requirePath(t, queries, alphaNotes.ID, "work/projects/alpha/notes")
+
+	err := service.MoveCollection(ctx, projects.ID, utils.NullInt64(archive.ID))
+	require.NoError(t, err)
+
+	requirePath(t, queries, projects.ID, "archive/projects")
+	requirePath(t, queries, alpha.ID, "archive/projects/alpha")
+	requirePath(t, queries, alphaNotes.ID, "archive/projects/alpha/notes")
+	requirePath(t, queries, work.ID, "work")
+
+	moved, err := queries.GetCollectionByID(ctx, projects.ID)
+	require.NoError(t, err)
+	require.True(t, moved.ParentID.Valid)
+	require.Equal(t, archive.ID, moved.ParentID.Int64)
+}
+
+func TestMoveCollection_ToRoot(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+	alpha := createTestCollection(t, service, "Alpha", projects.ID)
+
+	err := service.MoveCollection(ctx, projects.ID, utils.NullInt64Empty())
+	require.NoError(t, err)
+
+	requirePath(t, queries, projects.ID, "projects")
+	requirePath(t, queries, alpha.ID, "projects/alpha")
+
+	moved, err := queries.GetCollectionByID(ctx, projects.ID)
+	require.NoError(t, err)
+	require.False(t, moved.ParentID.Valid)
+}
+
+func TestMoveCollection_BumpsVersions(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+	alpha := createTestCollection(t, service, "Alpha", projects.ID)
+	require.Equal(t, int64(1), alpha.Version)
+
+	require.NoError(t, service.MoveCollection(ctx, projects.ID, utils.NullInt64Empty()))
+
+	// Descendant path rewrites change the representation, so their ETags change too
+	for _, id := range []int64{projects.ID, alpha.ID} {
+		moved, err := queries.GetCollectionByID(ctx, id)
+		require.NoError(t, err)
+		require.Greater(t, moved.Version, int64(1))
+	}
+}
+
+func TestMoveCollection_IntoOwnDescendantFails(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+	alpha := createTestCollection(t, service, "Alpha", projects.ID)
+
+	err := service.MoveCollection(ctx, work.ID, utils.NullInt64(alpha.ID))
+	require.ErrorIs(t, err, ErrInvalidParentCollection)
+
+	err = service.MoveCollection(ctx, work.ID, utils.NullInt64(work.ID))
+	require.ErrorIs(t, err, ErrInvalidParentCollection)
+
+	requirePath(t, queries, alpha.ID, "work/projects/alpha")
+}
+
+func TestMoveCollection_PathConflictRollsBack(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+	alpha := createTestCollection(t, service, "Alpha", projects.ID)
+	archive := createTestCollection(t, service, "Archive", 0)
+	createTestCollection(t, service, "Projects", archive.ID)
+
+	err := service.MoveCollection(ctx, projects.ID, utils.NullInt64(archive.ID))
+	require.ErrorIs(t, err, ErrCollectionAlreadyExists)
+
+	requirePath(t, queries, projects.ID, "work/projects")
+	requirePath(t, queries, alpha.ID, "work/projects/alpha")
+}
+
+func TestMoveCollection_MissingParent(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	work := createTestCollection(t, service, "Work", 0)
+
+	err := service.MoveCollection(context.Background(), work.ID, utils.NullInt64(9999))
+	require.ErrorIs(t, err, ErrInvalidParentCollection)
+	requirePath(t, queries, work.ID, "work")
+}
+
+func TestMoveCollection_NotFound(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	err := service.MoveCollection(context.Background(), 9999, utils.NullInt64Empty())
+	require.ErrorIs(t, err, ErrCollectionNotFound)
+}
+
+// ============================================================================
+// Note Count Cache Tests
+// ============================================================================
+
+// createTestNote inserts a note directly, bypassing any cache invalidation.
+func createTestNote(ctx context.Context, queries *store.Queries, title string, collectionID int64) error {
+	_, err := queries.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        title,
+		CollectionID: collectionID,
+	})
+	return err
+}
+
+func TestGetCachedNoteCount_CachesUntilInvalidated(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	require.NoError(t, createTestNote(ctx, queries, "First", work.ID))
+	require.NoError(t, createTestNote(ctx, queries, "Second", work.ID))
+
+	count, err := service.GetCachedNoteCount(ctx, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	// Without invalidation the cached count is served
+	require.NoError(t, createTestNote(ctx, queries, "Third", work.ID))
+	count, err = service.GetCachedNoteCount(ctx, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	service.InvalidateCacheForCollection(work.ID)
+	count, err = service.GetCachedNoteCount(ctx, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+}
+
+func TestGetCachedNoteCount_InvalidationIsPerCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	home := createTestCollection(t, service, "Home", 0)
+
+	for _, id := range []int64{work.ID, home.ID} {
+		count, err := service.GetCachedNoteCount(ctx, id)
+		require.NoError(t, err)
+		require.Zero(t, count)
+	}
+
+	require.NoError(t, createTestNote(ctx, queries, "Errand", home.ID))
+	require.NoError(t, createTestNote(ctx, queries, "Standup", work.ID))
+	service.InvalidateCacheForCollection(home.ID)
+
+	count, err := service.GetCachedNoteCount(ctx, home.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	count, err = service.GetCachedNoteCount(ctx, work.ID)
+	require.NoError(t, err)
+	require.Zero(t, count, "work was not invalidated")
+}
+
+// Run with -race to check the cache for data races.
+func TestGetCachedNoteCount_ConcurrentReadsAndInvalidations(t *testing.T) {
+	service, queries := setupTestService(t)
+	// Every connection to :memory: opens a separate database; share one
+	service.db.SetMaxOpenConns(1)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+
+	const (
+		readers = 8
+		writes  = 50
+	)
+
+	var g errgroup.Group
+	for range readers {
+		g.Go(func() error {
+			for range writes {
+				count, err := service.GetCachedNoteCount(ctx, work.ID)
+				if err != nil {
+					return err
+				}
+				if count < 0 || count > writes {
+					return fmt.Errorf("unexpected count %d", count)
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		for i := range writes {
+			if err := createTestNote(ctx, queries, fmt.Sprintf("Note %d", i), work.ID); err != nil {
+				return err
+			}
+			service.InvalidateCacheForCollection(work.ID)
+		}
+		return nil
+	})
+	require.NoError(t, g.Wait())
+
+	// Once writers are done no stale count may remain cached
+	count, err := service.GetCachedNoteCount(ctx, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(writes), count)
+}
+
+// ============================================================================
+// GetCollectionStats Tests
+// ============================================================================
+
+func TestGetCollectionStats(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	other := createTestCollection(t, service, "Other", 0)
+
+	noteIDs := make([]int64, 0, 5)
+	for i := range 5 {
+		id, err := queries.CreateNote(ctx, store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        fmt.Sprintf("Note %d", i),
+			CollectionID: work.ID,
+		})
+		require.NoError(t, err)
+		noteIDs = append(noteIDs, id)
+	}
+	for i := range 2 {
+		_, err := queries.CreateNote(ctx, store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        fmt.Sprintf("Template %d", i),
+			CollectionID: work.ID,
+			IsTemplate:   sql.NullBool{Bool: true, Valid: true},
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, createTestNote(ctx, queries, "Elsewhere", other.ID))
+
+	// "go" on all five notes, "sql" on three, "ops" on one
+	tagNotes := map[string][]int64{
+		"go":  noteIDs,
+		"sql": noteIDs[:3],
+		"ops": noteIDs[4:],
+	}
+	for name, ids := range tagNotes {
+		tagID, err := queries.CreateTag(ctx, name)
+		require.NoError(t, err)
+		for _, noteID := range ids {
+			require.NoError(t, queries.CreateNoteTag(ctx, store.CreateNoteTagParams{NoteID: noteID, TagID: tagID}))
+		}
+	}
+
+	_, err := queries.CreateLink(ctx, store.CreateLinkParams{
+		SrcID:  noteIDs[0],
+		DestID: sql.NullInt64{Int64: noteIDs[1], Valid: true},
+	})
+	require.NoError(t, err)
+
+	stats, err := service.GetCollectionStats(ctx, work.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), stats.NoteCount)
+	require.Equal(t, int64(2), stats.TemplateCount)
+	require.Equal(t, int64(1), stats.LinkCount)
+	require.False(t, stats.LastModifiedAt.IsZero())
+	require.Equal(t, []TagCount{{"go", 5}, {"sql", 3}, {"ops", 1}}, stats.TopTags)
+	require.Equal(t, int64(7), stats.DescendantNoteCount)
+}
+
+func TestCountDescendantNotes(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	parent := createTestCollection(t, service, "Parent", 0)
+	child := createTestCollection(t, service, "Child", parent.ID)
+	sibling := createTestCollection(t, service, "Sibling", 0)
+	for i := range 2 {
+		require.NoError(t, createTestNote(ctx, queries, fmt.Sprintf("Parent %d", i), parent.ID))
+	}
+	for i := range 3 {
+		require.NoError(t, createTestNote(ctx, queries, fmt.Sprintf("Child %d", i), child.ID))
+	}
+	require.NoError(t, createTestNote(ctx, queries, "Elsewhere", sibling.ID))
+
+	count, err := service.CountDescendantNotes(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+
+	count, err = service.CountNotesInCollection(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	count, err = service.CountDescendantNotes(ctx, child.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+
+	stats, err := service.GetCollectionStats(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), stats.NoteCount)
+	require.Equal(t, int64(5), stats.DescendantNoteCount)
+}
+
+func TestGetCollectionStats_EmptyAndMissing(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	empty := createTestCollection(t, service, "Empty", 0)
+	stats, err := service.GetCollectionStats(ctx, empty.ID)
+	require.NoError(t, err)
+	require.Zero(t, stats.NoteCount)
+	require.True(t, stats.LastModifiedAt.IsZero())
+	require.Empty(t, stats.TopTags)
+
+	_, err = service.GetCollectionStats(ctx, 9999)
+	require.ErrorIs(t, err, ErrCollectionNotFound)
+}
+
+// ============================================================================
+// Orphaned Collection Tests
+// ============================================================================
+
+func TestPruneOrphanedCollections_OnlyEmptyLeaves(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	// Work -> Projects -> (Alpha with a note, Beta empty); Archive empty at root
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+	alpha := createTestCollection(t, service, "Alpha", projects.ID)
+	beta := createTestCollection(t, service, "Beta", projects.ID)
+	archive := createTestCollection(t, service, "Archive", 0)
+	require.NoError(t, createTestNote(ctx, queries, "Plan", alpha.ID))
+
+	orphans, err := service.GetOrphanedCollections(ctx)
+	require.NoError(t, err)
+	ids := make([]int64, len(orphans))
+	for i, o := range orphans {
+		ids[i] = o.ID
+	}
+	require.ElementsMatch(t, []int64{beta.ID, archive.ID}, ids)
+
+	count, err := service.PruneOrphanedCollections(ctx, true)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	_, err = queries.GetCollectionByID(ctx, beta.ID)
+	require.NoError(t, err, "dry run must not delete")
+
+	count, err = service.PruneOrphanedCollections(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	for _, id := range []int64{beta.ID, archive.ID} {
+		_, err := queries.GetCollectionByID(ctx, id)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	}
+	for _, id := range []int64{work.ID, projects.ID, alpha.ID} {
+		_, err := queries.GetCollectionByID(ctx, id)
+		require.NoError(t, err)
+	}
+
+	count, err = service.PruneOrphanedCollections(ctx, false)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+// ============================================================================
+// MergeCollections Tests
+// ============================================================================
+
+func TestMergeCollections_NestedSubCollections(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	// work-projects/{alpha/drafts, beta} + notes merged into projects (which has gamma)
+	source := createTestCollection(t, service, "Work Projects", 0)
+	alpha := createTestCollection(t, service, "Alpha", source.ID)
+	drafts := createTestCollection(t, service, "Drafts", alpha.ID)
+	beta := createTestCollection(t, service, "Beta", source.ID)
+	target := createTestCollection(t, service, "Projects", 0)
+	gamma := createTestCollection(t, service, "Gamma", target.ID)
+	require.NoError(t, createTestNote(ctx, queries, "Roadmap", source.ID))
+	require.NoError(t, createTestNote(ctx, queries, "Spec", alpha.ID))
+
+	require.NoError(t, service.MergeCollections(ctx, source.ID, target.ID))
+
+	_, err := queries.GetCollectionByID(ctx, source.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	requirePath(t, queries, alpha.ID, "projects/alpha")
+	requirePath(t, queries, drafts.ID, "projects/alpha/drafts")
+	requirePath(t, queries, beta.ID, "projects/beta")
+	requirePath(t, queries, gamma.ID, "projects/gamma")
+
+	for _, id := range []int64{alpha.ID, beta.ID} {
+		child, err := queries.GetCollectionByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, target.ID, child.ParentID.Int64)
+	}
+
+	count, err := queries.CountNotesInCollection(ctx, target.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+	count, err = queries.CountNotesInCollection(ctx, alpha.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count, "notes of sub-collections stay with them")
+}
+
+func TestMergeCollections_PathConflictRollsBack(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	source := createTestCollection(t, service, "Work Projects", 0)
+	sourceAlpha := createTestCollection(t, service, "Alpha", source.ID)
+	target := createTestCollection(t, service, "Projects", 0)
+	createTestCollection(t, service, "Alpha", target.ID)
+	require.NoError(t, createTestNote(ctx, queries, "Roadmap", source.ID))
+
+	err := service.MergeCollections(ctx, source.ID, target.ID)
+	require.ErrorIs(t, err, ErrCollectionPathConflict)
+
+	_, err = queries.GetCollectionByID(ctx, source.ID)
+	require.NoError(t, err)
+	requirePath(t, queries, sourceAlpha.ID, "work-projects/alpha")
+
+	count, err := queries.CountNotesInCollection(ctx, source.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count, "note move must roll back")
+}
+
+func TestMergeCollections_NoteTitleConflict(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	source := createTestCollection(t, service, "Work Projects", 0)
+	target := createTestCollection(t, service, "Projects", 0)
+	require.NoError(t, createTestNote(ctx, queries, "Roadmap", source.ID))
+	require.NoError(t, createTestNote(ctx, queries, "Roadmap", target.ID))
+
+	err := service.MergeCollections(ctx, source.ID, target.ID)
+	require.ErrorIs(t, err, ErrCollectionPathConflict)
+}
+
+func TestMergeCollections_InvalidTargets(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+
+	require.ErrorIs(t, service.MergeCollections(ctx, work.ID, work.ID), ErrInvalidMergeTarget)
+	require.ErrorIs(t, service.MergeCollections(ctx, work.ID, projects.ID), ErrInvalidMergeTarget)
+	require.ErrorIs(t, service.MergeCollections(ctx, work.ID, 9999), ErrCollectionNotFound)
+
+	system, err := service.CreateCollection(ctx, store.CreateCollectionParams{
+		Name:     "Inbox",
+		Path:     "inbox",
+		IsSystem: true,
+	})
+	require.NoError(t, err)
+	require.ErrorIs(t, service.MergeCollections(ctx, system.ID, work.ID), ErrCollectionIsSystem)
+}
+
+// ============================================================================
+// Per-collection full-text search
+// ============================================================================
+
+// ftsTableExists reports whether the FTS table of a collection exists.
+func ftsTableExists(t *testing.T, service *CollectionsService, collectionID int64) bool {
+	t.Helper()
+
+	var count int
+	err := service.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`,
+		sqlcext.CollectionFTSTable(collectionID)).Scan(&count)
+	require.NoError(t, err)
+	return count == 1
+}
+
+func TestSearchInCollectionFTS_IsolatedPerCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	home := createTestCollection(t, service, "Home", 0)
+	require.True(t, ftsTableExists(t, service, work.ID))
+	require.True(t, ftsTableExists(t, service, home.ID))
+
+	require.NoError(t, createTestNote(ctx, queries, "Golang roadmap", work.ID))
+	require.NoError(t, createTestNote(ctx, queries, "Golang side project", home.ID))
+
+	params := sqlcext.FTSSearchParams{Query: "golang", LimitCount: 10}
+
+	results, err := service.SearchInCollectionFTS(ctx, work.ID, params)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Golang roadmap", results[0].Title)
+
+	results, err = service.SearchInCollectionFTS(ctx, home.ID, params)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "Golang side project", results[0].Title)
+
+	_, err = service.SearchInCollectionFTS(ctx, 9999, params)
+	require.ErrorIs(t, err, ErrCollectionNotFound)
+}
+
+func TestDeleteCollection_DropsFTSTables(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+
+	require.NoError(t, service.DeleteCollection(ctx, work.ID))
+	require.False(t, ftsTableExists(t, service, work.ID))
+	require.False(t, ftsTableExists(t, service, projects.ID))
+}
+
+func TestDeleteCollection_NotesFallBackToDefaultCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	// Notes of deleted collections move to collection 1 (notes.collection_id ON DELETE SET DEFAULT)
+	fallback := createTestCollection(t, service, "Default", 0)
+	require.Equal(t, int64(1), fallback.ID)
+	work := createTestCollection(t, service, "Work", 0)
+	projects := createTestCollection(t, service, "Projects", work.ID)
+	require.NoError(t, createTestNote(ctx, queries, "Plan", work.ID))
+	require.NoError(t, createTestNote(ctx, queries, "Roadmap", projects.ID))
+
+	require.NoError(t, service.DeleteCollection(ctx, work.ID))
+
+	_, err := queries.GetCollectionByID(ctx, projects.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows, "sub-collections cascade")
+	for _, title := range []string{"Plan", "Roadmap"} {
+		note, err := queries.GetNoteByTitle(ctx, store.GetNoteByTitleParams{Title: title, CollectionID: fallback.ID})
+		require.NoError(t, err)
+		require.Equal(t, fallback.ID, note.CollectionID)
+	}
+}
+
+// ============================================================================
+// Reordering
+// ============================================================================
+
+func TestReorderCollections_SetsPositions(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	a := createTestCollection(t, service, "A", work.ID)
+	b := createTestCollection(t, service, "B", work.ID)
+	c := createTestCollection(t, service, "C", work.ID)
+
+	require.NoError(t, service.ReorderCollections(ctx, utils.NullInt64(work.ID), []int64{c.ID, a.ID, b.ID}))
+
+	for want, id := range []int64{c.ID, a.ID, b.ID} {
+		collection, err := queries.GetCollectionByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, int64(want), collection.Position.Int64)
+	}
+
+	children, err := queries.ListCollectionsByParent(ctx, utils.NullInt64(work.ID))
+	require.NoError(t, err)
+	require.Equal(t, []string{"C", "A", "B"}, []string{children[0].Name, children[1].Name, children[2].Name})
+
+	parent, err := queries.GetCollectionByID(ctx, work.ID)
+	require.NoError(t, err)
+	require.Greater(t, parent.Version, work.Version, "reordering bumps the parent version")
+}
+
+func TestReorderCollections_RejectsForeignAndDuplicateIDs(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	home := createTestCollection(t, service, "Home", 0)
+	a := createTestCollection(t, service, "A", work.ID)
+	b := createTestCollection(t, service, "B", work.ID)
+	foreign := createTestCollection(t, service, "Garden", home.ID)
+
+	err := service.ReorderCollections(ctx, utils.NullInt64(work.ID), []int64{b.ID, foreign.ID, a.ID})
+	require.ErrorIs(t, err, ErrReorderParentMismatch)
+
+	// Nothing was written
+	collection, err := queries.GetCollectionByID(ctx, b.ID)
+	require.NoError(t, err)
+	require.Equal(t, b.Position, collection.Position)
+
+	require.ErrorIs(t, service.ReorderCollections(ctx, utils.NullInt64(work.ID), []int64{a.ID, a.ID}), ErrInvalidReorder)
+	require.ErrorIs(t, service.ReorderCollections(ctx, utils.NullInt64(work.ID), nil), ErrInvalidReorder)
+	require.ErrorIs(t, service.ReorderCollections(ctx, utils.NullInt64(work.ID), []int64{9999}), ErrCollectionNotFound)
+
+	// Top-level collections are reordered with an invalid parent ID
+	require.ErrorIs(t, service.ReorderCollections(ctx, sql.NullInt64{}, []int64{a.ID}), ErrReorderParentMismatch)
+	require.NoError(t, service.ReorderCollections(ctx, sql.NullInt64{}, []int64{home.ID, work.ID}))
+}
+
+func TestReorderCollectionsHandler_ETag(t *testing.T) {
+	service, queries := setupTestService(t)
+	perms := permissions.NewPermissionsService(queries, testdb.NewTestLogger(t), "permissions-test")
+
+	work := createTestCollection(t, service, "Work", 0)
+	a := createTestCollection(t, service, "A", work.ID)
+	b := createTestCollection(t, service, "B", work.ID)
+
+	e := echo.New()
+	e.PUT("/collections\\:reorder", ReorderCollectionsHandler(service, perms))
+
+	put := func(body, ifMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/collections:reorder", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	body := fmt.Sprintf(`{"parent_id": %d, "collection_ids": [%d, %d]}`, work.ID, b.ID, a.ID)
+	etag := utils.ComputeHashedETag(work.Version)
+
+	rec := put(body, etag)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	newETag := rec.Header().Get("ETag")
+	require.NotEqual(t, etag, newETag)
+
+	// The old ETag is stale after the first reorder
+	require.Equal(t, http.StatusPreconditionFailed, put(body, etag).Code)
+	require.Equal(t, http.StatusNoContent, put(body, newETag).Code)
+
+	foreign := createTestCollection(t, service, "Other", 0)
+	badBody := fmt.Sprintf(`{"parent_id": %d, "collection_ids": [%d]}`, work.ID, foreign.ID)
+	require.Equal(t, http.StatusBadRequest, put(badBody, "").Code)
+}
+
+func TestReorderCollectionsHandler_RequiresEditor(t *testing.T) {
+	service, queries := setupTestService(t)
+	perms := permissions.NewPermissionsService(queries, testdb.NewTestLogger(t), "permissions-test")
+	ctx := context.Background()
+
+	work := createTestCollection(t, service, "Work", 0)
+	home := createTestCollection(t, service, "Home", 0)
+	a := createTestCollection(t, service, "A", work.ID)
+	b := createTestCollection(t, service, "B", work.ID)
+
+	require.NoError(t, perms.GrantPermission(ctx, work.ID, "alice", permissions.RoleEditor))
+	require.NoError(t, perms.GrantPermission(ctx, work.ID, "bob", permissions.RoleViewer))
+
+	e := echo.New()
+	e.PUT("/collections\\:reorder", ReorderCollectionsHandler(service, perms))
+
+	put := func(body, actorID string) int {
+		req := httptest.NewRequest(http.MethodPut, "/collections:reorder", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req = req.WithContext(middleware.WithActorID(req.Context(), actorID))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	children := fmt.Sprintf(`{"parent_id": %d, "collection_ids": [%d, %d]}`, work.ID, b.ID, a.ID)
+	require.Equal(t, http.StatusForbidden, put(children, "bob"))
+	require.Equal(t, http.StatusForbidden, put(children, ""))
+	require.Equal(t, http.StatusNoContent, put(children, "alice"))
+
+	// Top-level reorders need the role on every collection that moves
+	roots := fmt.Sprintf(`{"collection_ids": [%d, %d]}`, home.ID, work.ID)
+	require.Equal(t, http.StatusForbidden, put(roots, "bob"))
+	require.Equal(t, http.StatusNoContent, put(roots, "alice"))
+}
+
+// storeNotesCreator is a NotesBatchCreator that inserts notes directly and skips UUIDs
+// that already exist, standing in for notes.NotesService.
+type storeNotesCreator struct {
+	queries *store.Queries
+}
+
+func (c storeNotesCreator) CreateNotesBatch(ctx context.Context, notes []store.CreateNoteParams) ([]int64, error) {
+	ids := make([]int64, len(notes))
+	for i, params := range notes {
+		if existing, err := c.queries.GetNoteByUUID(ctx, params.Uuid); err == nil {
+			ids[i] = existing.ID
+			continue
+		}
+		id, err := c.queries.CreateNote(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// writeImportTree creates files (relative path -> content) under a temporary directory.
+func writeImportTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+	return root
+}
+
+func TestImportFromFilesystem_MirrorsTree(t *testing.T) {
+	service, queries := setupTestService(t)
+	service.SetNotesBatchCreator(storeNotesCreator{queries: queries})
+	ctx := context.Background()
+
+	parent := createTestCollection(t, service, "Vault", 0)
+	root := writeImportTree(t, map[string]string{
+		"Index.md":                "# Index",
+		"Projects/Alpha.md":       "alpha",
+		"Projects/Archive/Old.md": "old",
+		"Projects/notes.txt":      "not markdown",
+		".obsidian/workspace.md":  "hidden",
+		"Reading/Book List.md":    "books",
+		"Reading/.draft.md":       "hidden",
+	})
+
+	result, err := service.ImportFromFilesystem(ctx, root, parent.ID, ImportOptions{})
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	require.Equal(t, 3, result.CollectionsCreated)
+	require.Equal(t, 4, result.NotesCreated)
+
+	archive, err := queries.GetCollectionByPath(ctx, parent.Path+"/projects/archive")
+	require.NoError(t, err)
+	require.Equal(t, "Archive", archive.Name)
+
+	old, err := queries.GetNoteByTitle(ctx, store.GetNoteByTitleParams{Title: "Old", CollectionID: archive.ID})
+	require.NoError(t, err)
+	require.Equal(t, "old", old.Body.String)
+
+	reading, err := queries.GetCollectionByPath(ctx, parent.Path+"/reading")
+	require.NoError(t, err)
+	_, err = queries.GetNoteByTitle(ctx, store.GetNoteByTitleParams{Title: "Book List", CollectionID: reading.ID})
+	require.NoError(t, err)
+
+	_, err = queries.GetCollectionByPath(ctx, parent.Path+"/.obsidian")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Importing the same tree again reuses the collections and notes
+	again, err := service.ImportFromFilesystem(ctx, root, parent.ID, ImportOptions{})
+	require.NoError(t, err)
+	require.Empty(t, again.Errors)
+	require.Zero(t, again.CollectionsCreated)
+	require.Zero(t, again.NotesCreated)
+	require.Equal(t, 4, again.NotesUpdated)
+}
+
+func TestImportFromFilesystem_DryRunWritesNothing(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	parent := createTestCollection(t, service, "Vault", 0)
+	root := writeImportTree(t, map[string]string{
+		"Top.md":       "top",
+		"Sub/Child.md": "child",
+	})
+
+	// A dry run does not need a notes creator
+	result, err := service.ImportFromFilesystem(ctx, root, parent.ID, ImportOptions{DryRun: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.CollectionsCreated)
+	require.Equal(t, 2, result.NotesCreated)
+
+	_, err = queries.GetCollectionByPath(ctx, parent.Path+"/sub")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	count, err := queries.CountNotesByCollectionID(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	_, err = service.ImportFromFilesystem(ctx, root, parent.ID, ImportOptions{})
+	require.ErrorIs(t, err, ErrImportUnavailable)
+}
+
+func TestImportFromFilesystem_ReportsTitleConflicts(t *testing.T) {
+	service, queries := setupTestService(t)
+	service.SetNotesBatchCreator(storeNotesCreator{queries: queries})
+	ctx := context.Background()
+
+	parent := createTestCollection(t, service, "Vault", 0)
+	_, err := queries.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        "Taken",
+		CollectionID: parent.ID,
+	})
+	require.NoError(t, err)
+
+	root := writeImportTree(t, map[string]string{
+		"Taken.md": "conflicts",
+		"Free.md":  "imported",
+	})
+
+	result, err := service.ImportFromFilesystem(ctx, root, parent.ID, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.NotesCreated)
+	require.Equal(t, []ImportError{{Path: "Taken.md", Err: ErrImportNoteConflict.Error()}}, result.Errors)
+
+	_, err = service.ImportFromFilesystem(ctx, filepath.Join(root, "Free.md"), parent.ID, ImportOptions{})
+	require.ErrorIs(t, err, ErrImportRootNotDirectory)
+	_, err = service.ImportFromFilesystem(ctx, root, 999999, ImportOptions{DryRun: true})
+	require.ErrorIs(t, err, ErrInvalidParentCollection)
+}
+
+func TestImportFromFilesystem_ReportsTrashedNotes(t *testing.T) {
+	service, queries := setupTestService(t)
+	service.SetNotesBatchCreator(storeNotesCreator{queries: queries})
+	ctx := context.Background()
+
+	parent := createTestCollection(t, service, "Vault", 0)
+	root := writeImportTree(t, map[string]string{
+		"Trashed.md": "first import",
+		"Kept.md":    "first import",
+	})
+	_, err := service.ImportFromFilesystem(ctx, root, parent.ID, ImportOptions{})
+	require.NoError(t, err)
+
+	trashed, err := queries.GetNoteByTitle(ctx, store.GetNoteByTitleParams{Title: "Trashed", CollectionID: parent.ID})
+	require.NoError(t, err)
+	_, err = queries.SoftDeleteNoteByID(ctx, trashed.ID)
+	require.NoError(t, err)
+
+	// The trashed note is reported instead of failing the whole batch on its UUID
+	result, err := service.ImportFromFilesystem(ctx, root, parent.ID, ImportOptions{})
+	require.NoError(t, err)
+	require.Equal(t, 1, result.NotesUpdated)
+	require.Equal(t, []ImportError{{Path: "Trashed.md", Err: ErrImportNoteTrashed.Error()}}, result.Errors)
+}
+
+func TestListBreadcrumbs_FourLevels(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	area := createTestCollection(t, service, "Area", 0)
+	project := createTestCollection(t, service, "Project", area.ID)
+	phase := createTestCollection(t, service, "Phase", project.ID)
+	task := createTestCollection(t, service, "Task", phase.ID)
+
+	crumbs, err := service.ListBreadcrumbs(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, crumbs, 4)
+
+	var names []string
+	for i, crumb := range crumbs {
+		require.Equal(t, i, crumb.Depth)
+		names = append(names, crumb.Name)
+	}
+	require.Equal(t, []string{"Area", "Project", "Phase", "Task"}, names)
+	require.Equal(t, task.Path, crumbs[3].Path)
+
+	_, err = service.ListBreadcrumbs(ctx, 999999)
+	require.ErrorIs(t, err, ErrCollectionNotFound)
+}
@@ -0,0 +1,12 @@
+package graph
+
+import "errors"
+
+// Domain errors for Graph
+var (
+	// ErrCollectionNotFound is returned when the root collection of a graph does not exist.
+	ErrCollectionNotFound = errors.New("collection not found")
+
+	// ErrInvalidMaxDepth is returned when a negative collection depth is requested.
+	ErrInvalidMaxDepth = errors.New("max depth must not be negative")
+)
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// exportGraphAction is the AIP-136 style suffix of the export route (/collections/{id}:exportGraph).
+const exportGraphAction = ":exportGraph"
+
+// DefaultMaxDepth is the number of sub-collection levels included when max_depth is not given.
+const DefaultMaxDepth = 10
+
+// dotContentType is the media type of Graphviz DOT documents.
+const dotContentType = "text/vnd.graphviz; charset=utf-8"
+
+// ExportGraphHandler serves GET /collections/{id}:exportGraph?format=dot&max_depth=.
+// format defaults to dot, the only format supported so far.
+func ExportGraphHandler(service *GraphService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rawID, ok := strings.CutSuffix(c.Param("id"), exportGraphAction)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "unknown collection action")
+		}
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil || id <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid collection id")
+		}
+
+		if format := c.QueryParam("format"); format != "" && format != "dot" {
+			return echo.NewHTTPError(http.StatusBadRequest, "unsupported format: "+format)
+		}
+
+		maxDepth := DefaultMaxDepth
+		if raw := c.QueryParam("max_depth"); raw != "" {
+			maxDepth, err = strconv.Atoi(raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "max_depth must be an integer")
+			}
+		}
+
+		dot, err := service.ExportLinkGraphDOT(c.Request().Context(), id, maxDepth)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrCollectionNotFound):
+				return echo.NewHTTPError(http.StatusNotFound, "collection not found")
+			case errors.Is(err, ErrInvalidMaxDepth):
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			default:
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to export graph")
+			}
+		}
+
+		return c.Blob(http.StatusOK, dotContentType, []byte(dot))
+	}
+}
@@ -0,0 +1,184 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// dotStatement matches the node and edge statements emitted by ExportLinkGraphDOT.
+var dotStatement = regexp.MustCompile(`^  (n\d+ \[label="([^"\\]|\\.)*"\]|n\d+ -> n\d+( \[style=dashed\])?);$`)
+
+// setupTestService creates a GraphService with a test database for testing.
+func setupTestService(t *testing.T) (*GraphService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	logger := testdb.NewTestLogger(t)
+	service := NewGraphService(db, queries, logger, "graph-test")
+
+	return service, queries
+}
+
+// createTestCollection creates a collection under parentID (0 = root).
+func createTestCollection(t *testing.T, queries *store.Queries, name string, parentID int64) int64 {
+	t.Helper()
+
+	var parent interface{}
+	path := utils.GenerateSlug(name)
+	if parentID != 0 {
+		parent = parentID
+		path = "nested/" + path
+	}
+
+	id, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{
+		Name:     name,
+		ParentID: parent,
+		Path:     path,
+	})
+	require.NoError(t, err)
+	return id
+}
+
+// createTestNote creates a note and returns its ID.
+func createTestNote(t *testing.T, queries *store.Queries, title string, collectionID int64) int64 {
+	t.Helper()
+
+	id, err := queries.CreateNote(context.Background(), store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        title,
+		CollectionID: collectionID,
+	})
+	require.NoError(t, err)
+	return id
+}
+
+// createTestLink creates a resolved link from src to dest.
+func createTestLink(t *testing.T, queries *store.Queries, src, dest int64, embed bool) {
+	t.Helper()
+
+	_, err := queries.CreateLink(context.Background(), store.CreateLinkParams{
+		SrcID:   src,
+		DestID:  utils.NullInt64(dest),
+		IsEmbed: utils.NullBool(embed),
+	})
+	require.NoError(t, err)
+}
+
+// requireValidDOT asserts dot is a digraph made only of node and edge statements.
+func requireValidDOT(t *testing.T, dot string) []string {
+	t.Helper()
+
+	lines := strings.Split(strings.TrimSuffix(dot, "\n"), "\n")
+	require.GreaterOrEqual(t, len(lines), 2)
+	require.Equal(t, "digraph {", lines[0])
+	require.Equal(t, "}", lines[len(lines)-1])
+
+	body := lines[1 : len(lines)-1]
+	for _, line := range body {
+		require.Regexp(t, dotStatement, line)
+	}
+	return body
+}
+
+func TestExportLinkGraphDOT_ChainAndIsolatedNote(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work", 0)
+	a := createTestNote(t, queries, "Note A", collectionID)
+	b := createTestNote(t, queries, "Note B", collectionID)
+	c := createTestNote(t, queries, `Note "C"`, collectionID)
+	d := createTestNote(t, queries, "Lonely", collectionID)
+	createTestLink(t, queries, a, b, false)
+	createTestLink(t, queries, b, c, true)
+
+	dot, err := service.ExportLinkGraphDOT(ctx, collectionID, 0)
+	require.NoError(t, err)
+	body := requireValidDOT(t, dot)
+
+	require.Contains(t, body, "  n"+itoa(a)+` [label="Note A"];`)
+	require.Contains(t, body, "  n"+itoa(c)+` [label="Note \"C\""];`)
+	require.Contains(t, body, "  n"+itoa(d)+` [label="Lonely"];`)
+	require.Contains(t, body, "  n"+itoa(a)+" -> n"+itoa(b)+";")
+	require.Contains(t, body, "  n"+itoa(b)+" -> n"+itoa(c)+" [style=dashed];")
+	require.NotContains(t, dot, "n"+itoa(d)+" ->")
+	require.NotContains(t, dot, "-> n"+itoa(d)+";")
+	require.Len(t, body, 6, "4 nodes and 2 edges")
+}
+
+func TestExportLinkGraphDOT_MaxDepth(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, queries, "Work", 0)
+	projects := createTestCollection(t, queries, "Projects", work)
+	other := createTestCollection(t, queries, "Other", 0)
+	top := createTestNote(t, queries, "Top", work)
+	nested := createTestNote(t, queries, "Nested", projects)
+	outside := createTestNote(t, queries, "Outside", other)
+	createTestLink(t, queries, top, nested, false)
+	createTestLink(t, queries, top, outside, false)
+
+	dot, err := service.ExportLinkGraphDOT(ctx, work, 0)
+	require.NoError(t, err)
+	require.NotContains(t, dot, "Nested")
+	require.NotContains(t, dot, "->", "edges into excluded collections are dropped")
+
+	dot, err = service.ExportLinkGraphDOT(ctx, work, 1)
+	require.NoError(t, err)
+	requireValidDOT(t, dot)
+	require.Contains(t, dot, "n"+itoa(top)+" -> n"+itoa(nested)+";")
+	require.NotContains(t, dot, "Outside")
+
+	_, err = service.ExportLinkGraphDOT(ctx, 9999, 0)
+	require.ErrorIs(t, err, ErrCollectionNotFound)
+	_, err = service.ExportLinkGraphDOT(ctx, work, -1)
+	require.ErrorIs(t, err, ErrInvalidMaxDepth)
+}
+
+func TestExportGraphHandler(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	collectionID := createTestCollection(t, queries, "Work", 0)
+	createTestNote(t, queries, "Note A", collectionID)
+
+	e := echo.New()
+	e.GET("/collections/:id", ExportGraphHandler(service))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/collections/" + itoa(collectionID) + ":exportGraph?format=dot")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, dotContentType, rec.Header().Get(echo.HeaderContentType))
+	require.True(t, strings.HasPrefix(rec.Body.String(), "digraph {"))
+
+	require.Equal(t, http.StatusBadRequest, get("/collections/"+itoa(collectionID)+":exportGraph?format=svg").Code)
+	require.Equal(t, http.StatusNotFound, get("/collections/9999:exportGraph").Code)
+	require.Equal(t, http.StatusNotFound, get("/collections/"+itoa(collectionID)).Code)
+}
+
+// itoa formats an ID for DOT node names and URLs.
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
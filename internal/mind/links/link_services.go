@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"log/slog"
+	"time"
 
 	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
 	"github.com/nkapatos/mindweaver/internal/mind/events"
 	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	sharederrors "github.com/nkapatos/mindweaver/shared/errors"
 	"github.com/nkapatos/mindweaver/shared/middleware"
 	"github.com/nkapatos/mindweaver/shared/utils"
 )
@@ -21,16 +23,31 @@ type LinksService struct {
 	store    store.Querier
 	logger   *slog.Logger
 	eventHub events.Hub
+
+	// retryWindow is how long a pending link may stay unresolved before BulkResolveLinks marks it broken.
+	retryWindow time.Duration
 }
 
+// DefaultResolveRetryWindow is the retry window used until SetResolveRetryWindow is called.
+const DefaultResolveRetryWindow = 24 * time.Hour
+
+// bulkResolveBatchSize is the number of unresolved links BulkResolveLinks loads at a time.
+const bulkResolveBatchSize = 500
+
 // NewLinksService creates a new LinksService.
 func NewLinksService(store store.Querier, logger *slog.Logger, serviceName string) *LinksService {
 	return &LinksService{
-		store:  store,
-		logger: logger.With("service", serviceName),
+		store:       store,
+		logger:      logger.With("service", serviceName),
+		retryWindow: DefaultResolveRetryWindow,
 	}
 }
 
+// SetResolveRetryWindow sets how long pending links are retried before being marked broken.
+func (s *LinksService) SetResolveRetryWindow(window time.Duration) {
+	s.retryWindow = window
+}
+
 // SetEventHub sets the event hub for SSE notifications.
 func (s *LinksService) SetEventHub(hub events.Hub) {
 	s.eventHub = hub
@@ -48,7 +65,7 @@ func (s *LinksService) CreateLink(ctx context.Context, params store.CreateLinkPa
 		s.logger.Error("failed to create link", "src_id", params.SrcID, "dest_id", params.DestID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return 0, err
 	}
-	s.logger.Info("link created", "id", id, "src_id", params.SrcID, "dest_id", params.DestID, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("link created", "link_id", id, "src_id", params.SrcID, "dest_id", params.DestID)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_LINK, mindv3.EventType_EVENT_TYPE_CREATED, id)
@@ -64,7 +81,7 @@ func (s *LinksService) CreateUnresolvedLink(ctx context.Context, params store.Cr
 		s.logger.Error("failed to create unresolved link", "src_id", params.SrcID, "dest_title", params.DestTitle, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return 0, err
 	}
-	s.logger.Info("unresolved link created", "id", id, "src_id", params.SrcID, "dest_title", params.DestTitle, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("unresolved link created", "link_id", id, "src_id", params.SrcID, "dest_title", params.DestTitle)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_LINK, mindv3.EventType_EVENT_TYPE_CREATED, id)
@@ -104,7 +121,7 @@ func (s *LinksService) DeleteLinksBySrcID(ctx context.Context, srcID int64) erro
 		s.logger.Error("failed to delete links by src_id", "src_id", srcID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("links deleted by src_id", "src_id", srcID, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("links deleted by src_id", "src_id", srcID)
 
 	// Note: We publish with srcID as entity_id since we're deleting all links from a source
 	if s.eventHub != nil {
@@ -189,7 +206,7 @@ func (s *LinksService) ResolveLink(ctx context.Context, params store.ResolveLink
 		s.logger.Error("failed to resolve link", "link_id", params.ID, "dest_id", params.DestID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("link resolved", "link_id", params.ID, "dest_id", params.DestID, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("link resolved", "link_id", params.ID, "dest_id", params.DestID)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_LINK, mindv3.EventType_EVENT_TYPE_UPDATED, params.ID)
@@ -205,7 +222,7 @@ func (s *LinksService) MarkLinkBroken(ctx context.Context, id int64) error {
 		s.logger.Error("failed to mark link broken", "link_id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("link marked as broken", "link_id", id, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("link marked as broken", "link_id", id)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_LINK, mindv3.EventType_EVENT_TYPE_UPDATED, id)
@@ -214,6 +231,63 @@ func (s *LinksService) MarkLinkBroken(ctx context.Context, id int64) error {
 	return nil
 }
 
+// BulkResolveLinks re-processes all pending and broken links (e.g., after a bulk import
+// created the notes they point to). Links whose destination title now matches a live note
+// are resolved; pending links still unmatched after the retry window are marked broken.
+// Broken links stay broken until their note appears. Returns how many links were resolved
+// and how many were newly marked broken.
+func (s *LinksService) BulkResolveLinks(ctx context.Context) (resolved, broken int, err error) {
+	var afterID int64
+	for {
+		links, err := s.store.ListUnresolvedLinksAfterID(ctx, store.ListUnresolvedLinksAfterIDParams{
+			AfterID: afterID,
+			Limit:   bulkResolveBatchSize,
+		})
+		if err != nil {
+			s.logger.Error("failed to list unresolved links", "after_id", afterID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return resolved, broken, err
+		}
+
+		for _, link := range links {
+			afterID = link.ID
+
+			if link.DestTitle.Valid {
+				note, err := s.store.GetNoteByTitleGlobal(ctx, link.DestTitle.String)
+				if err == nil {
+					err = s.ResolveLink(ctx, store.ResolveLinkParams{ID: link.ID, DestID: utils.NullInt64(note.ID)})
+					if err == nil {
+						resolved++
+						continue
+					}
+					// An identical resolved link already exists; leave this one for cleanup
+					if sharederrors.IsUniqueConstraintError(err) {
+						continue
+					}
+					return resolved, broken, err
+				}
+				if !errors.Is(err, sql.ErrNoRows) {
+					s.logger.Error("failed to look up link destination", "link_id", link.ID, "dest_title", link.DestTitle.String, "err", err, "request_id", middleware.GetRequestID(ctx))
+					return resolved, broken, err
+				}
+			}
+
+			if link.Resolved.Int64 == 0 && link.CreatedAt.Valid && time.Since(link.CreatedAt.Time) >= s.retryWindow {
+				if err := s.MarkLinkBroken(ctx, link.ID); err != nil {
+					return resolved, broken, err
+				}
+				broken++
+			}
+		}
+
+		if len(links) < bulkResolveBatchSize {
+			break
+		}
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("bulk link resolution finished", "resolved", resolved, "broken", broken)
+	return resolved, broken, nil
+}
+
 // ============================================================================
 // Broken/Orphaned Links Operations
 // ============================================================================
@@ -247,3 +321,46 @@ func (s *LinksService) ListOrphanedLinks(ctx context.Context) ([]store.Link, err
 	}
 	return links, nil
 }
+
+// GetLinkGraph returns the resolved links between noteIDs as an adjacency list
+// keyed by source note ID. Only edges with both ends in noteIDs are included,
+// every requested ID has an entry (empty for notes without outgoing edges), and
+// a note linking the same target several times yields a single edge.
+func (s *LinksService) GetLinkGraph(ctx context.Context, noteIDs []int64) (map[int64][]int64, error) {
+	graph := make(map[int64][]int64, len(noteIDs))
+	for _, id := range noteIDs {
+		graph[id] = []int64{}
+	}
+	if len(noteIDs) == 0 {
+		return graph, nil
+	}
+
+	edges, err := s.store.ListLinkGraphEdges(ctx, store.ListLinkGraphEdgesParams{
+		SrcIds:  noteIDs,
+		DestIds: noteIDs,
+	})
+	if err != nil {
+		s.logger.Error("failed to list link graph edges", "notes", len(noteIDs), "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	for _, e := range edges {
+		graph[e.SrcID] = append(graph[e.SrcID], e.DestID.Int64)
+	}
+	return graph, nil
+}
+
+// GetLinkGraphForCollection returns the link graph of the live notes directly in collectionID.
+// See GetLinkGraph.
+func (s *LinksService) GetLinkGraphForCollection(ctx context.Context, collectionID int64) (map[int64][]int64, error) {
+	notes, err := s.store.ListNotesByCollectionID(ctx, collectionID)
+	if err != nil {
+		s.logger.Error("failed to list notes for link graph", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	noteIDs := make([]int64, len(notes))
+	for i, n := range notes {
+		noteIDs[i] = n.ID
+	}
+	return s.GetLinkGraph(ctx, noteIDs)
+}
@@ -14,7 +14,7 @@ import (
 	"github.com/nkapatos/mindweaver/shared/utils"
 )
 
-// setupTestService creates a LinksService with in-memory database for testing.
+// setupTestService creates a LinksService with a test database for testing.
 func setupTestService(t *testing.T) (*LinksService, *store.Queries) {
 	t.Helper()
 
@@ -22,12 +22,24 @@ func setupTestService(t *testing.T) (*LinksService, *store.Queries) {
 	t.Cleanup(func() { db.Close() })
 
 	queries := store.New(db)
+	createDefaultCollection(t, queries)
 	logger := testdb.NewTestLogger(t)
 	service := NewLinksService(queries, logger, "links-test")
 
 	return service, queries
 }
 
+// createDefaultCollection creates collection 1, which notes default to (the server creates it on startup).
+func createDefaultCollection(t *testing.T, queries *store.Queries) {
+	t.Helper()
+
+	_, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{
+		Name: "default",
+		Path: "default",
+	})
+	require.NoError(t, err)
+}
+
 // createTestNote creates a note for testing links.
 func createTestNote(t *testing.T, queries *store.Queries, title string) int64 {
 	t.Helper()
@@ -509,3 +521,134 @@ func TestListOrphanedLinks(t *testing.T) {
 	require.Len(t, links, 1)
 	require.False(t, links[0].DestID.Valid) // NULL dest_id
 }
+
+// ============================================================================
+// Bulk Resolution Tests
+// ============================================================================
+
+// createPendingLink creates an unresolved link from srcID to destTitle.
+func createPendingLink(t *testing.T, queries *store.Queries, srcID int64, destTitle string) int64 {
+	t.Helper()
+
+	id, err := queries.CreateUnresolvedLink(context.Background(), store.CreateUnresolvedLinkParams{
+		SrcID:       srcID,
+		DestTitle:   utils.NullString(destTitle),
+		DisplayText: utils.NullString(destTitle),
+		IsEmbed:     utils.NullBool(false),
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestBulkResolveLinks_ResolvesMatchingTitles(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	srcID := createTestNote(t, queries, "Index")
+	for _, title := range []string{"Alpha", "Beta", "Gamma", "Delta", "Epsilon"} {
+		createPendingLink(t, queries, srcID, title)
+	}
+
+	// Three of the five targets show up later, as after a bulk import
+	alphaID := createTestNote(t, queries, "Alpha")
+	createTestNote(t, queries, "Beta")
+	createTestNote(t, queries, "Gamma")
+
+	resolved, broken, err := service.BulkResolveLinks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, resolved)
+	require.Equal(t, 0, broken, "links within the retry window stay pending")
+
+	count, err := queries.CountUnresolvedLinks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	backlinks, err := queries.ListLinksByDestID(ctx, utils.NullInt64(alphaID))
+	require.NoError(t, err)
+	require.Len(t, backlinks, 1)
+
+	// Once the retry window has passed the remaining links are marked broken
+	service.SetResolveRetryWindow(0)
+	resolved, broken, err = service.BulkResolveLinks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, resolved)
+	require.Equal(t, 2, broken)
+
+	// Broken links are not counted again, but still resolve when their note appears
+	createTestNote(t, queries, "Delta")
+	resolved, broken, err = service.BulkResolveLinks(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, resolved)
+	require.Equal(t, 0, broken)
+}
+
+// ============================================================================
+// Link Graph Tests
+// ============================================================================
+
+func TestGetLinkGraph(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	// Diamond: A→B, A→C, B→D, C→D
+	a := createTestNote(t, queries, "A")
+	b := createTestNote(t, queries, "B")
+	c := createTestNote(t, queries, "C")
+	d := createTestNote(t, queries, "D")
+	outside := createTestNote(t, queries, "Outside")
+
+	for _, edge := range [][2]int64{{a, b}, {a, c}, {b, d}, {c, d}, {a, b}, {d, outside}, {outside, a}} {
+		_, err := service.CreateLink(ctx, store.CreateLinkParams{
+			SrcID:   edge[0],
+			DestID:  utils.NullInt64(edge[1]),
+			IsEmbed: utils.NullBool(false),
+		})
+		require.NoError(t, err)
+	}
+	_, err := service.CreateUnresolvedLink(ctx, store.CreateUnresolvedLinkParams{
+		SrcID:     b,
+		DestTitle: utils.NullString("Missing"),
+		IsEmbed:   utils.NullBool(false),
+	})
+	require.NoError(t, err)
+
+	graph, err := service.GetLinkGraph(ctx, []int64{a, b, c, d})
+	require.NoError(t, err)
+	require.Equal(t, map[int64][]int64{
+		a: {b, c},
+		b: {d},
+		c: {d},
+		d: {},
+	}, graph)
+
+	// A subset keeps only the edges inside it
+	graph, err = service.GetLinkGraph(ctx, []int64{a, d})
+	require.NoError(t, err)
+	require.Equal(t, map[int64][]int64{a: {}, d: {}}, graph)
+
+	graph, err = service.GetLinkGraph(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, graph)
+}
+
+func TestGetLinkGraphForCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	a := createTestNote(t, queries, "A")
+	b := createTestNote(t, queries, "B")
+	_, err := service.CreateLink(ctx, store.CreateLinkParams{
+		SrcID:   a,
+		DestID:  utils.NullInt64(b),
+		IsEmbed: utils.NullBool(false),
+	})
+	require.NoError(t, err)
+
+	graph, err := service.GetLinkGraphForCollection(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, map[int64][]int64{a: {b}, b: {}}, graph)
+
+	graph, err = service.GetLinkGraphForCollection(ctx, 9999)
+	require.NoError(t, err)
+	require.Empty(t, graph)
+}
@@ -31,7 +31,7 @@ func (h *LinksHandler) ListLinks(
 	// For now, just get all links (pagination not yet implemented in service)
 	links, err := h.service.ListLinks(ctx)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list links", err)
+		return nil, apierrors.Mind.Internal("failed to list links", err)
 	}
 
 	// Apply pagination to results
@@ -25,7 +25,7 @@ func (h *NoteMetaHandler) ListMeta(
 ) (*connect.Response[mindv3.ListMetaResponse], error) {
 	metaItems, err := h.service.GetNoteMetaByNoteID(ctx, req.Msg.NoteId)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve note metadata", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve note metadata", err)
 	}
 
 	protoItems := StoreNoteMetasToProto(metaItems)
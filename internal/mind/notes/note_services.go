@@ -3,9 +3,17 @@ package notes
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
@@ -13,12 +21,16 @@ import (
 	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
 	"github.com/nkapatos/mindweaver/internal/mind/links"
 	"github.com/nkapatos/mindweaver/internal/mind/meta"
+	"github.com/nkapatos/mindweaver/internal/mind/notetypes"
 	"github.com/nkapatos/mindweaver/internal/mind/scheduler"
 	"github.com/nkapatos/mindweaver/internal/mind/tags"
 	sharederrors "github.com/nkapatos/mindweaver/shared/errors"
 	"github.com/nkapatos/mindweaver/shared/markdown"
+	"github.com/nkapatos/mindweaver/shared/metrics"
 	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/sqlcext"
 	"github.com/nkapatos/mindweaver/shared/utils"
+	"golang.org/x/sync/errgroup"
 )
 
 // NotesService provides business logic for notes CRUD operations.
@@ -30,19 +42,123 @@ type NotesService struct {
 	logger    *slog.Logger
 	scheduler *scheduler.ChangeAccumulator // Optional: notifies Brain of note changes
 	eventHub  events.Hub                   // Optional: publishes events for SSE clients
+	metrics   *metrics.Metrics             // Optional: records Prometheus note counters
 	parser    *markdown.Parser
+
+	cteQuerier      *sqlcext.CTEQuerier      // Recursive link graph queries
+	collectionCache CollectionCache          // Optional: invalidated when a collection's notes change
+	frontmatterSync bool                     // Rewrite frontmatter title/updated on UpdateNote
+	maxBodySize     int                      // Maximum note body size in bytes (0 = unlimited)
+	schemaService   *notetypes.SchemaService // Optional: validates frontmatter against note type schemas on create
+}
+
+// CollectionCache is notified when the set of live notes in a collection changes.
+// Implemented by collections.CollectionsService.
+type CollectionCache interface {
+	InvalidateCacheForCollection(collectionID int64)
 }
 
 var untitledCounter int64 = 0
 
+// copySuffixPattern matches a trailing " (copy)" or " (copy N)" title suffix.
+var copySuffixPattern = regexp.MustCompile(`\s\(copy(?: \d+)?\)$`)
+
+// Reserved template variables populated by NewNoteFromTemplate.
+const (
+	templateVarDate  = "date"  // Creation date, e.g., 2025-01-31
+	templateVarUUID  = "uuid"  // UUID of the new note
+	templateVarTitle = "title" // Title of the new note
+)
+
+// Bulk inserters used by CreateNotesBatch.
+var (
+	noteBulkInserter = sqlcext.NewBulkInserter("notes",
+		[]string{"uuid", "title", "body", "description", "frontmatter", "note_type_id", "is_template", "collection_id"}, sqlcext.DefaultBatchSize)
+	noteTagBulkInserter  = sqlcext.NewBulkInserter("note_tags", []string{"note_id", "tag_id"}, sqlcext.DefaultBatchSize)
+	linkBulkInserter     = sqlcext.NewBulkInserter("links", []string{"src_id", "dest_id", "display_text", "is_embed"}, sqlcext.DefaultBatchSize)
+	noteMetaBulkInserter = sqlcext.NewBulkInserter("note_meta", []string{"note_id", "key", "value"}, sqlcext.DefaultBatchSize)
+	tagBulkInserter      = sqlcext.NewBulkInserter("tags", []string{"name"}, sqlcext.DefaultBatchSize)
+)
+
+// Bounds for GetReachableNotes.
+const (
+	defaultReachableDepth = 3
+	maxReachableDepth     = 10
+	defaultReachableLimit = 20
+	maxReachableLimit     = 100
+)
+
+// NoteWithDistance is a note reachable over links with its minimum hop distance.
+type NoteWithDistance struct {
+	store.Note
+	Distance int
+}
+
+// NoteStats holds reading statistics derived from a note body (frontmatter excluded).
+// They are stored in note_meta under word_count and reading_time_minutes.
+type NoteStats struct {
+	WordCount          int
+	ReadingTimeMinutes int // WordCount / wordsPerMinute, rounded up
+}
+
+// wordsPerMinute is the reading speed used for NoteStats.ReadingTimeMinutes.
+const wordsPerMinute = 200
+
+// ComputeNoteStats counts words in a markdown body and estimates its reading time.
+// Tokens without any letter or digit (e.g., "#", "-", "```") are not counted as words.
+func ComputeNoteStats(body string) NoteStats {
+	words := 0
+	for _, field := range strings.Fields(body) {
+		if strings.IndexFunc(field, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }) >= 0 {
+			words++
+		}
+	}
+	return NoteStats{
+		WordCount:          words,
+		ReadingTimeMinutes: (words + wordsPerMinute - 1) / wordsPerMinute,
+	}
+}
+
+// Sort orders accepted by ListNotesByCollectionIDPaginated.
+const (
+	NoteSortDefault     = ""             // Insertion order (id)
+	NoteSortUpdatedAt   = "updated_at"   // Most recently updated first
+	NoteSortPinnedFirst = "pinned_first" // Pinned notes by pin position, then by updated_at
+)
+
+// externalLinksMetaKey is the note_meta key holding the JSON array of external links.
+const externalLinksMetaKey = "external_links"
+
+// imageURLsMetaKey is the note_meta key holding the JSON array of external image URLs.
+const imageURLsMetaKey = "image_urls"
+
+// openTasksMetaKey is the note_meta key holding the number of unchecked task list items.
+const openTasksMetaKey = "open_tasks"
+
+// calloutCountMetaKey is the note_meta key holding the number of callout blocks.
+const calloutCountMetaKey = "callout_count"
+
+// titleFromHeadingMetaKey is the note_meta key holding the first H1 of notes without a frontmatter title.
+const titleFromHeadingMetaKey = "title_from_heading"
+
+// topWordsMetaKey is the note_meta key holding the JSON array of the most frequent body words.
+const topWordsMetaKey = "top_words"
+
+// Note_meta keys holding NoteStats.
+const (
+	wordCountMetaKey   = "word_count"
+	readingTimeMetaKey = "reading_time_minutes"
+)
+
 // NewNotesService creates a new NotesService.
 func NewNotesService(db *sql.DB, store store.Querier, logger *slog.Logger, serviceName string) *NotesService {
 	return &NotesService{
-		store:     store,
-		db:        db,
-		logger:    logger.With("service", serviceName),
-		scheduler: nil,
-		parser:    markdown.NewParser(),
+		store:      store,
+		db:         db,
+		logger:     logger.With("service", serviceName),
+		scheduler:  nil,
+		parser:     markdown.NewParser(),
+		cteQuerier: sqlcext.NewCTEQuerier(db),
 	}
 }
 
@@ -58,6 +174,78 @@ func (s *NotesService) SetEventHub(hub events.Hub) {
 	s.logger.Info("event hub enabled for note service")
 }
 
+// SetMetrics sets the Prometheus metrics updated on note changes.
+func (s *NotesService) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+	s.logger.Info("metrics enabled for note service")
+}
+
+// SetCollectionCache sets the cache invalidated after note writes.
+func (s *NotesService) SetCollectionCache(cache CollectionCache) {
+	s.collectionCache = cache
+	s.logger.Info("collection cache enabled for note service")
+}
+
+// SetFrontmatterSync enables rewriting the title and updated frontmatter keys of note bodies
+// on UpdateNote so they match the note. Disabled by default; bodies are stored as sent.
+func (s *NotesService) SetFrontmatterSync(enabled bool) {
+	s.frontmatterSync = enabled
+	if enabled {
+		s.logger.Info("frontmatter sync enabled for note service")
+	}
+}
+
+// SetSchemaService sets the service CreateNote validates frontmatter with.
+// Without it, notes are created regardless of their note type's schema.
+func (s *NotesService) SetSchemaService(schemaService *notetypes.SchemaService) {
+	s.schemaService = schemaService
+	s.logger.Info("frontmatter schema validation enabled for note service")
+}
+
+// validateFrontmatter checks the frontmatter of a note of type noteTypeID against the type's schema.
+// Returns ErrSchemaValidationFailed wrapping notetypes.ValidationErrors when it doesn't match.
+func (s *NotesService) validateFrontmatter(ctx context.Context, noteTypeID sql.NullInt64, parsed *markdown.ParseResult) error {
+	if s.schemaService == nil || !noteTypeID.Valid {
+		return nil
+	}
+
+	var metadata map[string]any
+	if parsed != nil {
+		metadata = parsed.Metadata
+	}
+	if errs := s.schemaService.ValidateFrontmatter(ctx, noteTypeID.Int64, metadata); len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrSchemaValidationFailed, notetypes.ValidationErrors(errs))
+	}
+	return nil
+}
+
+// SetMaxBodySize sets the largest note body, in bytes, accepted by CreateNote and UpdateNote.
+// Zero (the default) disables the limit.
+func (s *NotesService) SetMaxBodySize(n int) {
+	s.maxBodySize = n
+	if n > 0 {
+		s.logger.Info("note body size limit enabled", "max_bytes", n)
+	}
+}
+
+// checkBodySize returns ErrBodyTooLarge if body exceeds the configured size limit.
+func (s *NotesService) checkBodySize(body sql.NullString) error {
+	if s.maxBodySize > 0 && len(body.String) > s.maxBodySize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d", ErrBodyTooLarge, len(body.String), s.maxBodySize)
+	}
+	return nil
+}
+
+// invalidateCollections drops cached note counts for the given collections.
+func (s *NotesService) invalidateCollections(collectionIDs ...int64) {
+	if s.collectionCache == nil {
+		return
+	}
+	for _, id := range collectionIDs {
+		s.collectionCache.InvalidateCacheForCollection(id)
+	}
+}
+
 // GetMarkdownParser returns the markdown parser instance.
 func (s *NotesService) GetMarkdownParser() *markdown.Parser {
 	return s.parser
@@ -97,9 +285,135 @@ func (s *NotesService) GetNoteByID(ctx context.Context, id int64) (store.Note, e
 	return note, nil
 }
 
+// GetNoteByTitle returns the live note with the given title in a collection.
+func (s *NotesService) GetNoteByTitle(ctx context.Context, collectionID int64, title string) (store.Note, error) {
+	note, err := s.store.GetLiveNoteByTitleInCollection(ctx, store.GetLiveNoteByTitleInCollectionParams{
+		CollectionID: collectionID,
+		Title:        title,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.Note{}, ErrNoteNotFound
+		}
+		s.logger.Error("failed to get note by title", "collection_id", collectionID, "title", title, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Note{}, err
+	}
+	return note, nil
+}
+
+// GetNoteByUUID returns a live note by UUID (e.g., to recognize notes seen by an earlier import).
+func (s *NotesService) GetNoteByUUID(ctx context.Context, id uuid.UUID) (store.Note, error) {
+	note, err := s.store.GetNoteByUUID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.Note{}, ErrNoteNotFound
+		}
+		s.logger.Error("failed to get note by uuid", "uuid", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Note{}, err
+	}
+	return note, nil
+}
+
 // CreateNote creates a new note with derived data (links, tags) atomically.
 // All operations are wrapped in a transaction to ensure consistency.
+// Returns ErrBodyTooLarge if the body exceeds the limit set by SetMaxBodySize.
 func (s *NotesService) CreateNote(ctx context.Context, params store.CreateNoteParams) (int64, error) {
+	return s.createNote(ctx, params, nil)
+}
+
+// UpsertNote creates the note if no note has its UUID, otherwise updates that note in place,
+// so importing the same notes twice doesn't duplicate them. Returns the note ID and whether it was created.
+// An existing note whose content already matches params is left untouched (its version doesn't change).
+// Returns ErrNoteTrashed if the UUID belongs to a trashed note.
+func (s *NotesService) UpsertNote(ctx context.Context, params store.CreateNoteParams) (int64, bool, error) {
+	current, err := s.findUpsertTarget(ctx, params.Uuid)
+	if errors.Is(err, ErrNoteNotFound) {
+		id, err := s.CreateNote(ctx, params)
+		if err != nil {
+			return 0, false, err
+		}
+		return id, true, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if noteMatches(current, params) {
+		return current.ID, false, nil
+	}
+
+	if err := s.UpdateNote(ctx, upsertUpdateParams(current, params)); err != nil {
+		return 0, false, err
+	}
+	return current.ID, false, nil
+}
+
+// findUpsertTarget returns the note an upsert of the given UUID would update.
+// Trashed notes are looked up too: inserting their UUID again would violate its uniqueness,
+// so they are reported as ErrNoteTrashed. Returns ErrNoteNotFound if no note has the UUID.
+func (s *NotesService) findUpsertTarget(ctx context.Context, id uuid.UUID) (store.Note, error) {
+	note, err := s.store.GetNoteByUUIDWithTrashed(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.Note{}, ErrNoteNotFound
+		}
+		s.logger.Error("failed to get note by uuid", "uuid", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Note{}, err
+	}
+	if note.DeletedAt.Valid {
+		return store.Note{}, ErrNoteTrashed
+	}
+	return note, nil
+}
+
+// noteMatches reports whether an upsert of params would leave current unchanged.
+func noteMatches(current store.Note, params store.CreateNoteParams) bool {
+	return current.Title == params.Title &&
+		current.Body == params.Body &&
+		current.Description == params.Description &&
+		current.Frontmatter == params.Frontmatter &&
+		current.NoteTypeID == params.NoteTypeID &&
+		current.IsTemplate == params.IsTemplate &&
+		current.CollectionID == params.CollectionID
+}
+
+// upsertUpdateParams builds the update that brings current in line with params.
+func upsertUpdateParams(current store.Note, params store.CreateNoteParams) store.UpdateNoteByIDParams {
+	return store.UpdateNoteByIDParams{
+		ID:           current.ID,
+		Uuid:         params.Uuid,
+		Title:        params.Title,
+		Body:         params.Body,
+		Description:  params.Description,
+		Frontmatter:  params.Frontmatter,
+		NoteTypeID:   params.NoteTypeID,
+		IsTemplate:   params.IsTemplate,
+		CollectionID: params.CollectionID,
+		Version:      current.Version,
+	}
+}
+
+// createNote creates a note and its derived data in one transaction.
+// systemMeta is stored alongside frontmatter metadata (frontmatter wins on conflicts).
+func (s *NotesService) createNote(ctx context.Context, params store.CreateNoteParams, systemMeta map[string]string) (int64, error) {
+	if err := s.checkBodySize(params.Body); err != nil {
+		return 0, err
+	}
+
+	// Parse up front so the frontmatter is validated before anything is written
+	var parsed *markdown.ParseResult
+	if params.Body.Valid && params.Body.String != "" {
+		var err error
+		parsed, err = s.parser.Parse([]byte(params.Body.String))
+		if err != nil {
+			s.logger.Error("failed to parse note body", "title", params.Title, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return 0, err
+		}
+	}
+	if err := s.validateFrontmatter(ctx, params.NoteTypeID, parsed); err != nil {
+		return 0, err
+	}
+
 	// Begin transaction
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -120,13 +434,7 @@ func (s *NotesService) CreateNote(ctx context.Context, params store.CreateNotePa
 	}
 
 	// Extract and store derived data from note body (wiki-links, tags, metadata)
-	if params.Body.Valid && params.Body.String != "" {
-		parsed, err := s.parser.Parse([]byte(params.Body.String))
-		if err != nil {
-			s.logger.Error("failed to parse note body", "note_id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
-			return 0, err
-		}
-
+	if parsed != nil {
 		allTags := s.extractAndMergeTags(parsed)
 
 		if err := s.insertWikiLinksWithStore(ctx, txStore, id, parsed); err != nil {
@@ -140,7 +448,17 @@ func (s *NotesService) CreateNote(ctx context.Context, params store.CreateNotePa
 		}
 
 		// Note: 'tags'/'tag' frontmatter keys are filtered out here (handled above)
-		if err := s.insertMetadataWithStore(ctx, txStore, id, parsed, nil); err != nil {
+		if err := s.insertMetadataWithStore(ctx, txStore, id, parsed, systemMeta); err != nil {
+			s.logger.Error("failed to insert metadata", "note_id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return 0, err
+		}
+
+		if err := s.insertMentionsWithStore(ctx, txStore, id, parsed.Mentions); err != nil {
+			s.logger.Error("failed to insert mentions", "note_id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return 0, err
+		}
+	} else if len(systemMeta) > 0 {
+		if err := s.insertMetadataWithStore(ctx, txStore, id, &markdown.ParseResult{}, systemMeta); err != nil {
 			s.logger.Error("failed to insert metadata", "note_id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
 			return 0, err
 		}
@@ -151,12 +469,19 @@ func (s *NotesService) CreateNote(ctx context.Context, params store.CreateNotePa
 		return 0, err
 	}
 
-	s.logger.Info("note created", "id", id, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("note created", "note_id", id)
+
+	s.invalidateCollections(params.CollectionID)
 
 	if s.scheduler != nil {
 		s.scheduler.TrackChange("note_created", id)
 	}
 
+	if s.metrics != nil {
+		s.metrics.NotesCreated.Inc()
+		s.metrics.NotesCount.Inc()
+	}
+
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, id)
 	}
@@ -165,10 +490,22 @@ func (s *NotesService) CreateNote(ctx context.Context, params store.CreateNotePa
 }
 
 // NewNoteCreation creates a new note with auto-generated title and optional template content.
+// Template placeholders are filled with the reserved variables only.
 func (s *NotesService) NewNoteCreation(ctx context.Context, collectionID, templateID int64) (int64, error) {
-	// Generate auto-incremented title
-	untitledCounter++
-	title := fmt.Sprintf("Untitled %d", untitledCounter)
+	return s.NewNoteFromTemplate(ctx, collectionID, templateID, nil)
+}
+
+// NewNoteFromTemplate creates a new note from a template note, replacing {{key}} placeholders
+// in the template body with values from vars. Values are inserted verbatim (no escaping).
+// Reserved keys {{date}}, {{uuid}} and {{title}} are populated automatically unless set in vars;
+// vars["title"] also becomes the note title. Unknown placeholders are left as-is.
+func (s *NotesService) NewNoteFromTemplate(ctx context.Context, collectionID, templateID int64, vars map[string]string) (int64, error) {
+	title := vars[templateVarTitle]
+	if title == "" {
+		// Generate auto-incremented title
+		untitledCounter++
+		title = fmt.Sprintf("Untitled %d", untitledCounter)
+	}
 
 	// Get template body (template_id 1 is empty by default)
 	body := ""
@@ -183,9 +520,20 @@ func (s *NotesService) NewNoteCreation(ctx context.Context, collectionID, templa
 		}
 	}
 
+	noteUUID := uuid.New()
+	resolved := map[string]string{
+		templateVarDate:  time.Now().Format(time.DateOnly),
+		templateVarUUID:  noteUUID.String(),
+		templateVarTitle: title,
+	}
+	for key, value := range vars {
+		resolved[key] = value
+	}
+	body = substituteTemplateVars(body, resolved)
+
 	// Build params for CreateNote
 	params := store.CreateNoteParams{
-		Uuid:         uuid.New(),
+		Uuid:         noteUUID,
 		Title:        title,
 		Body:         utils.NullStringFrom(body, true),
 		CollectionID: collectionID,
@@ -194,17 +542,530 @@ func (s *NotesService) NewNoteCreation(ctx context.Context, collectionID, templa
 	// Delegate to existing CreateNote logic
 	noteID, err := s.CreateNote(ctx, params)
 	if err != nil {
-		s.logger.Error("failed to create new note", "title", title, "err", err, "request_id", middleware.GetRequestID(ctx))
+		s.logger.Error("failed to create new note", "title", title, "template_id", templateID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return 0, err
 	}
 
 	return noteID, nil
 }
 
+// substituteTemplateVars replaces every {{key}} in body with vars[key].
+func substituteTemplateVars(body string, vars map[string]string) string {
+	if body == "" || len(vars) == 0 {
+		return body
+	}
+
+	// Sort keys so replacement is deterministic
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys)*2)
+	for _, key := range keys {
+		pairs = append(pairs, "{{"+key+"}}", vars[key])
+	}
+	return strings.NewReplacer(pairs...).Replace(body)
+}
+
+// DuplicateNote creates a copy of a note, including its body and metadata.
+// The copy goes into targetCollectionID when valid, otherwise into the source note's collection.
+// Its title is the source title with a " (copy)" suffix, numbered " (copy N)" on conflicts.
+func (s *NotesService) DuplicateNote(ctx context.Context, sourceID int64, targetCollectionID sql.NullInt64) (int64, error) {
+	source, err := s.GetNoteByID(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+
+	collectionID := source.CollectionID
+	if targetCollectionID.Valid {
+		collectionID = targetCollectionID.Int64
+	}
+
+	title, err := s.generateCopyTitle(ctx, source.Title, collectionID)
+	if err != nil {
+		return 0, err
+	}
+
+	metaItems, err := s.store.GetNoteMetaByNoteID(ctx, sourceID)
+	if err != nil {
+		s.logger.Error("failed to get note metadata for duplication", "note_id", sourceID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	systemMeta := make(map[string]string, len(metaItems))
+	for _, item := range metaItems {
+		if item.Value.Valid {
+			systemMeta[item.Key] = item.Value.String
+		}
+	}
+
+	params := store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        title,
+		Body:         source.Body,
+		Description:  source.Description,
+		Frontmatter:  source.Frontmatter,
+		NoteTypeID:   source.NoteTypeID,
+		IsTemplate:   source.IsTemplate,
+		CollectionID: collectionID,
+	}
+
+	noteID, err := s.createNote(ctx, params, systemMeta)
+	if err != nil {
+		s.logger.Error("failed to duplicate note", "source_id", sourceID, "title", title, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	return noteID, nil
+}
+
+// generateCopyTitle returns the first free title of the form "<base> (copy)", "<base> (copy 2)", ...
+// within the collection. An existing copy suffix on the source title is stripped first,
+// so duplicating "Note (copy)" yields "Note (copy 2)" rather than "Note (copy) (copy)".
+func (s *NotesService) generateCopyTitle(ctx context.Context, title string, collectionID int64) (string, error) {
+	base := copySuffixPattern.ReplaceAllString(title, "")
+
+	for n := 1; ; n++ {
+		candidate := base + " (copy)"
+		if n > 1 {
+			candidate = fmt.Sprintf("%s (copy %d)", base, n)
+		}
+
+		_, err := s.store.GetNoteByTitle(ctx, store.GetNoteByTitleParams{
+			Title:        candidate,
+			CollectionID: collectionID,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate, nil
+		}
+		if err != nil {
+			s.logger.Error("failed to check title availability", "title", candidate, "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return "", err
+		}
+	}
+}
+
+// CreateNotesBatch creates many notes (e.g., a vault import) in a single transaction.
+// Notes are bulk-inserted first so wiki-links between notes of the same batch resolve;
+// bodies are then parsed in parallel and tags, links, and metadata are bulk-inserted.
+// Frontmatter is checked against note type schemas (see SetSchemaService) before the
+// transaction opens; one invalid note rejects the whole batch.
+// Notes whose UUID already exists (e.g., a re-import) are updated in the same transaction,
+// after the insert so their links to new notes resolve; notes that already match are left
+// untouched. Any failure rolls back the whole batch, and a UUID that belongs to a trashed
+// note rejects it with ErrNoteTrashed. Returns the note IDs in the same order as the input.
+func (s *NotesService) CreateNotesBatch(ctx context.Context, notes []store.CreateNoteParams) ([]int64, error) {
+	if len(notes) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(notes))
+	var created []int
+	var updates []store.UpdateNoteByIDParams
+	for i, note := range notes {
+		current, err := s.findUpsertTarget(ctx, note.Uuid)
+		switch {
+		case errors.Is(err, ErrNoteNotFound):
+			created = append(created, i)
+		case err != nil:
+			return nil, fmt.Errorf("note %q: %w", note.Title, err)
+		default:
+			ids[i] = current.ID
+			if !noteMatches(current, note) {
+				updates = append(updates, upsertUpdateParams(current, note))
+			}
+		}
+	}
+
+	newNotes := make([]store.CreateNoteParams, len(created))
+	for j, i := range created {
+		newNotes[j] = notes[i]
+	}
+	newIDs, err := s.writeNotesBatch(ctx, newNotes, updates)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range created {
+		ids[i] = newIDs[j]
+	}
+
+	return ids, nil
+}
+
+// writeNotesBatch bulk-creates notes that don't exist yet and applies updates to existing
+// notes in one transaction. Returns the IDs of the created notes in input order.
+func (s *NotesService) writeNotesBatch(ctx context.Context, notes []store.CreateNoteParams, updates []store.UpdateNoteByIDParams) ([]int64, error) {
+	for _, note := range notes {
+		if err := s.checkBodySize(note.Body); err != nil {
+			return nil, fmt.Errorf("note %q: %w", note.Title, err)
+		}
+	}
+	for _, update := range updates {
+		if err := s.checkBodySize(update.Body); err != nil {
+			return nil, fmt.Errorf("note %q: %w", update.Title, err)
+		}
+	}
+
+	// Parse bodies in parallel up front so frontmatter is validated before anything is written
+	parsed := make([]*markdown.ParseResult, len(notes))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, note := range notes {
+		if !note.Body.Valid || note.Body.String == "" {
+			continue
+		}
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			result, err := s.parser.Parse([]byte(note.Body.String))
+			if err != nil {
+				return fmt.Errorf("parse note %q: %w", note.Title, err)
+			}
+			parsed[i] = result
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		s.logger.Error("failed to parse notes batch", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	for i, note := range notes {
+		if err := s.validateFrontmatter(ctx, note.NoteTypeID, parsed[i]); err != nil {
+			return nil, fmt.Errorf("note %q: %w", note.Title, err)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	var ids []int64
+	if len(notes) > 0 {
+		// Pass 1: insert all notes and collect their IDs
+		ids, err = s.insertNotesBatch(ctx, tx, txStore, notes)
+		if err != nil {
+			if sharederrors.IsUniqueConstraintError(err) {
+				return nil, ErrNoteAlreadyExists
+			}
+			s.logger.Error("failed to insert notes batch", "count", len(notes), "err", err, "request_id", middleware.GetRequestID(ctx))
+			return nil, err
+		}
+
+		// Pass 2: bulk-insert derived data from the parsed bodies
+		if err := s.insertDerivedDataBatch(ctx, tx, txStore, ids, parsed); err != nil {
+			s.logger.Error("failed to insert derived data for notes batch", "count", len(notes), "err", err, "request_id", middleware.GetRequestID(ctx))
+			return nil, err
+		}
+	}
+
+	// Pass 3: update existing notes now that links to the new ones resolve
+	previousCollectionIDs := make([]int64, len(updates))
+	for i, update := range updates {
+		previousCollectionIDs[i], err = s.updateNoteWithStore(ctx, txStore, update)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "count", len(notes)+len(updates), "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("notes batch written", "created", len(ids), "updated", len(updates))
+
+	invalidated := make(map[int64]bool)
+	for _, note := range notes {
+		if !invalidated[note.CollectionID] {
+			invalidated[note.CollectionID] = true
+			s.invalidateCollections(note.CollectionID)
+		}
+	}
+
+	for _, id := range ids {
+		if s.scheduler != nil {
+			s.scheduler.TrackChange("note_created", id)
+		}
+		if s.eventHub != nil {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, id)
+		}
+	}
+
+	if s.metrics != nil {
+		s.metrics.NotesCreated.Add(float64(len(ids)))
+		s.metrics.NotesCount.Add(float64(len(ids)))
+	}
+
+	for i, update := range updates {
+		s.noteUpdated(ctx, update, previousCollectionIDs[i])
+	}
+
+	return ids, nil
+}
+
+// insertNotesBatch bulk-inserts notes and returns their IDs in input order.
+func (s *NotesService) insertNotesBatch(ctx context.Context, db sqlcext.DBTX, querier store.Querier, notes []store.CreateNoteParams) ([]int64, error) {
+	rows := make([][]any, len(notes))
+	uuids := make([]uuid.UUID, len(notes))
+	for i, n := range notes {
+		rows[i] = []any{n.Uuid, n.Title, n.Body, n.Description, n.Frontmatter, n.NoteTypeID, n.IsTemplate, n.CollectionID}
+		uuids[i] = n.Uuid
+	}
+
+	if err := noteBulkInserter.Insert(ctx, db, rows); err != nil {
+		return nil, err
+	}
+
+	idByUUID := make(map[uuid.UUID]int64, len(notes))
+	for start := 0; start < len(uuids); start += sqlcext.MaxBatchSize {
+		end := min(start+sqlcext.MaxBatchSize, len(uuids))
+		created, err := querier.ListNoteIDsByUUIDs(ctx, uuids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range created {
+			idByUUID[row.Uuid] = row.ID
+		}
+	}
+
+	ids := make([]int64, len(notes))
+	for i, u := range uuids {
+		id, ok := idByUUID[u]
+		if !ok {
+			return nil, fmt.Errorf("note %s missing after bulk insert", u)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// insertDerivedDataBatch bulk-inserts tags, wiki-links, and metadata for a batch of notes,
+// and records their mentions like CreateNote does.
+// parsed[i] belongs to ids[i]; nil entries (empty bodies) are skipped.
+func (s *NotesService) insertDerivedDataBatch(ctx context.Context, db sqlcext.DBTX, querier store.Querier, ids []int64, parsed []*markdown.ParseResult) error {
+	var noteTagRows, linkRows, metaRows [][]any
+	tagIDs := make(map[string]int64)
+	targetIDs := make(map[string]sql.NullInt64) // Cache of wiki-link target lookups (invalid = not found)
+
+	for i, result := range parsed {
+		if result == nil {
+			continue
+		}
+		noteID := ids[i]
+
+		for _, tagName := range s.extractAndMergeTags(result) {
+			tagID, ok := tagIDs[tagName]
+			if !ok {
+				var err error
+				tagID, err = getOrCreateTagID(ctx, querier, tagName)
+				if err != nil {
+					return err
+				}
+				tagIDs[tagName] = tagID
+			}
+			noteTagRows = append(noteTagRows, []any{noteID, tagID})
+		}
+
+		for _, link := range result.WikiLinks {
+			target, ok := targetIDs[link.Target]
+			if !ok {
+				targetNote, err := resolveWikiLinkTarget(ctx, querier, link.Target)
+				if err != nil && !errors.Is(err, sql.ErrNoRows) {
+					return err
+				}
+				if err == nil {
+					target = utils.NullInt64(targetNote.ID)
+				}
+				targetIDs[link.Target] = target
+			}
+			if !target.Valid {
+				s.logger.Debug("wiki-link target not found", "title", link.Target, "source_note_id", noteID)
+				continue
+			}
+
+			displayText := sql.NullString{}
+			if link.DisplayText != "" && link.DisplayText != link.Target {
+				displayText = utils.NullString(link.DisplayText)
+			}
+			linkRows = append(linkRows, []any{noteID, target, displayText, utils.NullBool(link.Embed)})
+		}
+
+		meta, err := buildNoteMetadata(result, nil)
+		if err != nil {
+			return err
+		}
+		for key, value := range meta {
+			metaRows = append(metaRows, []any{noteID, key, utils.NullString(value)})
+		}
+
+		if err := s.insertMentionsWithStore(ctx, querier, noteID, result.Mentions); err != nil {
+			return err
+		}
+	}
+
+	if err := noteTagBulkInserter.Insert(ctx, db, noteTagRows); err != nil {
+		return err
+	}
+	if err := linkBulkInserter.Insert(ctx, db, linkRows); err != nil {
+		return err
+	}
+	return noteMetaBulkInserter.Insert(ctx, db, metaRows)
+}
+
+// getOrCreateTagID returns the ID of the named tag, creating it if needed.
+func getOrCreateTagID(ctx context.Context, querier store.Querier, name string) (int64, error) {
+	tag, err := querier.GetTagByName(ctx, name)
+	if err == nil {
+		return tag.ID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	return querier.CreateTag(ctx, name)
+}
+
+// BulkAssignTags assigns every tag in tagNames to every note in noteIDs in a single transaction.
+// Missing tags are created; existing assignments are left as is, so repeating a call is a no-op.
+// Returns ErrNoteNotFound if any note doesn't exist or is trashed; nothing is assigned then.
+//
+// Tags are assigned directly, not written into note bodies, so the next body update
+// (which re-extracts tags from the body) drops assignments the body doesn't mention.
+func (s *NotesService) BulkAssignTags(ctx context.Context, noteIDs []int64, tagNames []string) error {
+	noteIDs = uniqueIDs(noteIDs)
+	tagNames = uniqueTagNames(tagNames)
+	if len(noteIDs) == 0 || len(tagNames) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	notes, err := txStore.ListNotesByIDs(ctx, noteIDs)
+	if err != nil {
+		s.logger.Error("failed to load notes for bulk tag", "count", len(noteIDs), "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if len(notes) != len(noteIDs) {
+		return ErrNoteNotFound
+	}
+
+	tagIDs, err := s.resolveTagIDs(ctx, tx, txStore, tagNames)
+	if err != nil {
+		s.logger.Error("failed to resolve tags for bulk tag", "tags", tagNames, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	rows := make([][]any, 0, len(noteIDs)*len(tagIDs))
+	for _, noteID := range noteIDs {
+		for _, tagID := range tagIDs {
+			rows = append(rows, []any{noteID, tagID})
+		}
+	}
+	if err := noteTagBulkInserter.InsertOrIgnore(ctx, tx, rows); err != nil {
+		s.logger.Error("failed to insert note tags", "count", len(rows), "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("tags assigned in bulk", "notes", len(noteIDs), "tags", len(tagIDs))
+
+	if s.eventHub != nil {
+		for _, noteID := range noteIDs {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, noteID)
+		}
+	}
+
+	return nil
+}
+
+// resolveTagIDs returns the IDs of the named tags, bulk-creating the ones that don't exist yet.
+func (s *NotesService) resolveTagIDs(ctx context.Context, db sqlcext.DBTX, querier store.Querier, names []string) ([]int64, error) {
+	tags, err := querier.ListTagsByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tags) < len(names) {
+		existing := make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			existing[tag.Name] = true
+		}
+		var missing [][]any
+		for _, name := range names {
+			if !existing[name] {
+				missing = append(missing, []any{name})
+			}
+		}
+		if err := tagBulkInserter.InsertOrIgnore(ctx, db, missing); err != nil {
+			return nil, err
+		}
+		if tags, err = querier.ListTagsByNames(ctx, names); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]int64, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	return ids, nil
+}
+
+// uniqueIDs returns ids without duplicates, keeping first-seen order.
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// uniqueTagNames trims tag names and drops empty and duplicate ones, keeping first-seen order.
+func uniqueTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
 // UpdateNote updates an existing note and re-extracts all derived data.
 // Replaces all links, tags, and metadata from the new note body.
-// Returns ErrStaleNote if the version doesn't match (optimistic locking failure).
+// Returns ErrNoteNotFound if the note doesn't exist or is trashed, ErrStaleNote if the version
+// doesn't match (optimistic locking failure) and ErrBodyTooLarge if the body exceeds the limit
+// set by SetMaxBodySize.
 func (s *NotesService) UpdateNote(ctx context.Context, params store.UpdateNoteByIDParams) error {
+	if err := s.checkBodySize(params.Body); err != nil {
+		return err
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
@@ -214,82 +1075,155 @@ func (s *NotesService) UpdateNote(ctx context.Context, params store.UpdateNoteBy
 
 	txStore := store.New(tx)
 
+	previousCollectionID, err := s.updateNoteWithStore(ctx, txStore, params)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("note updated", "note_id", params.ID)
+
+	s.noteUpdated(ctx, params, previousCollectionID)
+
+	return nil
+}
+
+// updateNoteWithStore updates a note and replaces its derived data within the caller's transaction.
+// Returns the collection the note was in before the update.
+func (s *NotesService) updateNoteWithStore(ctx context.Context, querier store.Querier, params store.UpdateNoteByIDParams) (int64, error) {
+	if s.frontmatterSync && params.Body.Valid {
+		synced, syncErr := markdown.SyncFrontmatter(params.Body.String, params.Title, time.Now())
+		if syncErr != nil {
+			// Malformed frontmatter is saved as written rather than rejecting the edit
+			s.logger.Warn("failed to sync frontmatter", "note_id", params.ID, "err", syncErr, "request_id", middleware.GetRequestID(ctx))
+		} else {
+			params.Body = utils.NullString(synced)
+		}
+	}
+
+	// Remember the current collection so both sides of a move are invalidated
+	current, err := querier.GetNoteByID(ctx, params.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNoteNotFound
+		}
+		s.logger.Error("failed to get note", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	previousCollectionID := current.CollectionID
+
 	// Clear existing derived data before re-extracting from updated body
-	if delErr := txStore.DeleteLinksBySrcID(ctx, params.ID); delErr != nil {
-		s.logger.Error("failed to delete existing links", "note_id", params.ID, "err", delErr, "request_id", middleware.GetRequestID(ctx))
-		return delErr
+	if err := s.deleteDerivedDataWithStore(ctx, querier, params.ID); err != nil {
+		return 0, err
+	}
+
+	result, err := querier.UpdateNoteByID(ctx, params)
+	if err != nil {
+		if sharederrors.IsUniqueConstraintError(err) {
+			return 0, ErrNoteAlreadyExists
+		}
+		s.logger.Error("failed to update note", "params", params, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	// Check for version mismatch (optimistic locking failure)
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		s.logger.Error("failed to get rows affected", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	if rowsAffected == 0 {
+		s.logger.Warn("stale note detected", "note_id", params.ID, "version", params.Version, "request_id", middleware.GetRequestID(ctx))
+		return 0, ErrStaleNote
+	}
+
+	// Re-extract derived data from updated body
+	if params.Body.Valid && params.Body.String != "" {
+		if err := s.insertDerivedDataWithStore(ctx, querier, params.ID, params.Body.String); err != nil {
+			return 0, err
+		}
+	}
+
+	return previousCollectionID, nil
+}
+
+// noteUpdated invalidates caches and notifies the scheduler, metrics and subscribers
+// after an update has been committed.
+func (s *NotesService) noteUpdated(ctx context.Context, params store.UpdateNoteByIDParams, previousCollectionID int64) {
+	s.invalidateCollections(previousCollectionID)
+	if params.CollectionID != previousCollectionID {
+		s.invalidateCollections(params.CollectionID)
+	}
+
+	if s.scheduler != nil {
+		s.scheduler.TrackChange("note_updated", params.ID)
 	}
 
-	if delErr := txStore.DeleteNoteTagsByNoteID(ctx, params.ID); delErr != nil {
-		s.logger.Error("failed to delete existing tags", "note_id", params.ID, "err", delErr, "request_id", middleware.GetRequestID(ctx))
-		return delErr
+	if s.metrics != nil {
+		s.metrics.NotesUpdated.Inc()
 	}
 
-	if delErr := txStore.DeleteNoteMetaByNoteID(ctx, params.ID); delErr != nil {
-		s.logger.Error("failed to delete existing metadata", "note_id", params.ID, "err", delErr, "request_id", middleware.GetRequestID(ctx))
-		return delErr
+	if s.eventHub != nil {
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, params.ID)
 	}
+}
 
-	result, err := txStore.UpdateNoteByID(ctx, params)
-	if err != nil {
-		if sharederrors.IsUniqueConstraintError(err) {
-			return ErrNoteAlreadyExists
-		}
-		s.logger.Error("failed to update note", "params", params, "err", err, "request_id", middleware.GetRequestID(ctx))
+// deleteDerivedDataWithStore removes the links, tags, metadata and mentions extracted from a note's body.
+func (s *NotesService) deleteDerivedDataWithStore(ctx context.Context, querier store.Querier, noteID int64) error {
+	if err := querier.DeleteLinksBySrcID(ctx, noteID); err != nil {
+		s.logger.Error("failed to delete existing links", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
 
-	// Check for version mismatch (optimistic locking failure)
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		s.logger.Error("failed to get rows affected", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+	if err := querier.DeleteNoteTagsByNoteID(ctx, noteID); err != nil {
+		s.logger.Error("failed to delete existing tags", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	if rowsAffected == 0 {
-		s.logger.Warn("stale note detected", "note_id", params.ID, "version", params.Version, "request_id", middleware.GetRequestID(ctx))
-		return ErrStaleNote
-	}
-
-	// Re-extract derived data from updated body
-	if params.Body.Valid && params.Body.String != "" {
-		parsed, err := s.parser.Parse([]byte(params.Body.String))
-		if err != nil {
-			s.logger.Error("failed to parse note body", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
-			return err
-		}
-
-		if err := s.insertWikiLinksWithStore(ctx, txStore, params.ID, parsed); err != nil {
-			s.logger.Error("failed to insert wiki-links", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
-			return err
-		}
 
-		allTags := s.extractAndMergeTags(parsed)
-		if err := s.insertTagsWithStore(ctx, txStore, params.ID, allTags); err != nil {
-			s.logger.Error("failed to insert tags", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
-			return err
-		}
+	if err := querier.DeleteNoteMetaByNoteID(ctx, noteID); err != nil {
+		s.logger.Error("failed to delete existing metadata", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
 
-		if err := s.insertMetadataWithStore(ctx, txStore, params.ID, parsed, nil); err != nil {
-			s.logger.Error("failed to insert metadata", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
-			return err
-		}
+	if err := querier.DeleteNoteMentionsByNoteID(ctx, noteID); err != nil {
+		s.logger.Error("failed to delete existing mentions", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
 	}
+	return nil
+}
 
-	if err := tx.Commit(); err != nil {
-		s.logger.Error("failed to commit transaction", "note_id", params.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+// insertDerivedDataWithStore parses body and inserts the note's links, tags, metadata and mentions.
+func (s *NotesService) insertDerivedDataWithStore(ctx context.Context, querier store.Querier, noteID int64, body string) error {
+	parsed, err := s.parser.Parse([]byte(body))
+	if err != nil {
+		s.logger.Error("failed to parse note body", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
 
-	s.logger.Info("note updated", "id", params.ID, "request_id", middleware.GetRequestID(ctx))
+	if err := s.insertWikiLinksWithStore(ctx, querier, noteID, parsed); err != nil {
+		s.logger.Error("failed to insert wiki-links", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
 
-	if s.scheduler != nil {
-		s.scheduler.TrackChange("note_updated", params.ID)
+	allTags := s.extractAndMergeTags(parsed)
+	if err := s.insertTagsWithStore(ctx, querier, noteID, allTags); err != nil {
+		s.logger.Error("failed to insert tags", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
 	}
 
-	if s.eventHub != nil {
-		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, params.ID)
+	if err := s.insertMetadataWithStore(ctx, querier, noteID, parsed, nil); err != nil {
+		s.logger.Error("failed to insert metadata", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
 	}
 
+	if err := s.insertMentionsWithStore(ctx, querier, noteID, parsed.Mentions); err != nil {
+		s.logger.Error("failed to insert mentions", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
 	return nil
 }
 
@@ -306,12 +1240,16 @@ func (s *NotesService) UpdateNoteMetadata(ctx context.Context, params store.Upda
 		return err
 	}
 
-	s.logger.Info("note metadata updated", "id", params.ID, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("note metadata updated", "note_id", params.ID)
 
 	// Detect relocation (title or collection_id changed)
 	titleChanged := params.Title != current.Title
 	collectionChanged := params.CollectionID != current.CollectionID
 
+	if collectionChanged {
+		s.invalidateCollections(current.CollectionID, params.CollectionID)
+	}
+
 	if s.eventHub != nil && (titleChanged || collectionChanged) {
 		payload := &mindv3.RelocatedPayload{}
 		if titleChanged {
@@ -328,18 +1266,28 @@ func (s *NotesService) UpdateNoteMetadata(ctx context.Context, params store.Upda
 	return nil
 }
 
-// DeleteNote deletes a note by ID.
-// Associated links, tags, and metadata are cascade-deleted by database constraints.
+// DeleteNote moves a note to the trash by setting deleted_at.
+// Links, tags, and metadata are kept so the note can be restored with RestoreNote.
+// Returns ErrNoteNotFound if the note doesn't exist or is already trashed.
 func (s *NotesService) DeleteNote(ctx context.Context, id int64) error {
-	err := s.store.DeleteNoteByID(ctx, id)
+	collectionID, err := s.store.SoftDeleteNoteByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoteNotFound
+		}
 		s.logger.Error("failed to delete note", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("note deleted", "id", id, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("note deleted", "note_id", id)
+
+	s.invalidateCollections(collectionID)
 
 	if s.scheduler != nil {
-		s.scheduler.TrackChange("note_deleted", id)
+		s.scheduler.TrackCriticalChange("note_deleted", id)
+	}
+
+	if s.metrics != nil {
+		s.metrics.NotesCount.Dec()
 	}
 
 	if s.eventHub != nil {
@@ -349,10 +1297,207 @@ func (s *NotesService) DeleteNote(ctx context.Context, id int64) error {
 	return nil
 }
 
+// RestoreNote moves a trashed note back out of the trash.
+// Returns ErrNoteNotFound if the note doesn't exist or is not trashed.
+func (s *NotesService) RestoreNote(ctx context.Context, id int64) error {
+	collectionID, err := s.store.RestoreNoteByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoteNotFound
+		}
+		s.logger.Error("failed to restore note", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	middleware.ContextLogger(ctx, s.logger).Info("note restored", "note_id", id)
+
+	s.invalidateCollections(collectionID)
+
+	// Brain dropped the note on delete, so a restore is a new note from its perspective
+	if s.scheduler != nil {
+		s.scheduler.TrackChange("note_created", id)
+	}
+
+	if s.metrics != nil {
+		s.metrics.NotesCount.Inc()
+	}
+
+	if s.eventHub != nil {
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, id)
+	}
+
+	return nil
+}
+
+// PinNote pins a note to the top of its collection when listed with NoteSortPinnedFirst.
+// Pinned notes are ordered by position (ascending). Re-pinning a note moves it to the new position.
+// Returns ErrNoteNotFound if the note doesn't exist or is trashed.
+func (s *NotesService) PinNote(ctx context.Context, id int64, position int) error {
+	if position < 0 {
+		return ErrInvalidPinPosition
+	}
+
+	result, err := s.store.PinNoteByID(ctx, store.PinNoteByIDParams{
+		ID:          id,
+		PinPosition: utils.NullInt64(int64(position)),
+	})
+	if err != nil {
+		s.logger.Error("failed to pin note", "id", id, "position", position, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return s.afterPinChange(ctx, id, result, "note pinned", "position", position)
+}
+
+// UnpinNote removes a note's pin. Unpinning a note that isn't pinned is a no-op.
+// Returns ErrNoteNotFound if the note doesn't exist or is trashed.
+func (s *NotesService) UnpinNote(ctx context.Context, id int64) error {
+	result, err := s.store.UnpinNoteByID(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to unpin note", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return s.afterPinChange(ctx, id, result, "note unpinned")
+}
+
+// afterPinChange maps an unmatched pin update to ErrNoteNotFound, then logs and publishes the change.
+func (s *NotesService) afterPinChange(ctx context.Context, id int64, result sql.Result, msg string, attrs ...any) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNoteNotFound
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info(msg, append([]any{"note_id", id}, attrs...)...)
+
+	if s.eventHub != nil {
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, id)
+	}
+
+	return nil
+}
+
+// PermanentlyDeleteNote deletes a note (live or trashed) for good.
+// Associated links, tags, and metadata are cascade-deleted by database constraints.
+// Returns ErrTemplateStarterNote if a template still starts from the note.
+func (s *NotesService) PermanentlyDeleteNote(ctx context.Context, id int64) error {
+	// Look up trash state first: live notes still count towards NotesCount and need events
+	note, lookupErr := s.store.GetNoteByID(ctx, id)
+	wasLive := lookupErr == nil
+	if lookupErr != nil && !errors.Is(lookupErr, sql.ErrNoRows) {
+		s.logger.Error("failed to get note", "id", id, "err", lookupErr, "request_id", middleware.GetRequestID(ctx))
+		return lookupErr
+	}
+
+	result, err := s.store.DeleteNoteByID(ctx, id)
+	if err != nil {
+		if sharederrors.IsForeignKeyConstraintError(err) {
+			return ErrTemplateStarterNote
+		}
+		s.logger.Error("failed to permanently delete note", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if err := checkNoteRowsAffected(result); err != nil {
+		return err
+	}
+	middleware.ContextLogger(ctx, s.logger).Info("note permanently deleted", "note_id", id)
+
+	if wasLive {
+		s.invalidateCollections(note.CollectionID)
+
+		if s.scheduler != nil {
+			s.scheduler.TrackCriticalChange("note_deleted", id)
+		}
+
+		if s.metrics != nil {
+			s.metrics.NotesCount.Dec()
+		}
+
+		if s.eventHub != nil {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_DELETED, id)
+		}
+	}
+
+	return nil
+}
+
+// PurgeDeletedNotes permanently deletes notes that have been in the trash longer than retention.
+// Starter notes of templates stay in the trash. Returns the number of notes purged.
+func (s *NotesService) PurgeDeletedNotes(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := fmt.Sprintf("-%d seconds", int64(retention.Seconds()))
+	result, err := s.store.PurgeDeletedNotes(ctx, cutoff)
+	if err != nil {
+		s.logger.Error("failed to purge deleted notes", "retention", retention, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		s.logger.Error("failed to get rows affected", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	if purged > 0 {
+		middleware.ContextLogger(ctx, s.logger).Info("purged deleted notes", "count", purged, "retention", retention)
+	}
+	return purged, nil
+}
+
+// ListDeletedNotesPaginated returns trashed notes, most recently deleted first.
+func (s *NotesService) ListDeletedNotesPaginated(ctx context.Context, limit, offset int32) ([]store.Note, error) {
+	notes, err := s.store.ListDeletedNotesPaginated(ctx, store.ListDeletedNotesPaginatedParams{
+		Limit:  int64(limit),
+		Offset: int64(offset),
+	})
+	if err != nil {
+		s.logger.Error("failed to list deleted notes", "err", err, "request_id", middleware.GetRequestID(ctx))
+	}
+	return notes, err
+}
+
+// CountDeletedNotes returns the total number of trashed notes.
+func (s *NotesService) CountDeletedNotes(ctx context.Context) (int64, error) {
+	count, err := s.store.CountDeletedNotes(ctx)
+	if err != nil {
+		s.logger.Error("failed to count deleted notes", "err", err, "request_id", middleware.GetRequestID(ctx))
+	}
+	return count, err
+}
+
+// checkNoteRowsAffected returns ErrNoteNotFound when a single-note write matched no rows.
+func checkNoteRowsAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
 // ============================================================================
 // Query Methods - List and Count with Filters
 // ============================================================================
 
+// GetNoteCollectionIDs maps noteIDs to the collection each note is in, trashed notes included.
+// IDs of notes that no longer exist are left out. Implements scheduler.NoteReader.
+func (s *NotesService) GetNoteCollectionIDs(ctx context.Context, noteIDs []int64) (map[int64]int64, error) {
+	result := make(map[int64]int64, len(noteIDs))
+	if len(noteIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := s.store.ListNoteCollectionIDs(ctx, noteIDs)
+	if err != nil {
+		s.logger.Error("failed to list note collection ids", "count", len(noteIDs), "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.ID] = row.CollectionID
+	}
+	return result, nil
+}
+
 func (s *NotesService) ListNotesByCollectionID(ctx context.Context, collectionID int64) ([]store.Note, error) {
 	notes, err := s.store.ListNotesByCollectionID(ctx, collectionID)
 	if err != nil {
@@ -362,14 +1507,23 @@ func (s *NotesService) ListNotesByCollectionID(ctx context.Context, collectionID
 }
 
 // ListNotesByCollectionIDPaginated returns notes in a collection with pagination.
-func (s *NotesService) ListNotesByCollectionIDPaginated(ctx context.Context, collectionID int64, limit, offset int32) ([]store.Note, error) {
+// sortBy is one of NoteSortDefault, NoteSortUpdatedAt, or NoteSortPinnedFirst;
+// anything else returns ErrInvalidSortBy.
+func (s *NotesService) ListNotesByCollectionIDPaginated(ctx context.Context, collectionID int64, sortBy string, limit, offset int32) ([]store.Note, error) {
+	switch sortBy {
+	case NoteSortDefault, NoteSortUpdatedAt, NoteSortPinnedFirst:
+	default:
+		return nil, ErrInvalidSortBy
+	}
+
 	notes, err := s.store.ListNotesByCollectionIDPaginated(ctx, store.ListNotesByCollectionIDPaginatedParams{
 		CollectionID: collectionID,
+		SortBy:       sortBy,
 		Limit:        int64(limit),
 		Offset:       int64(offset),
 	})
 	if err != nil {
-		s.logger.Error("failed to list notes by collection paginated", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		s.logger.Error("failed to list notes by collection paginated", "collection_id", collectionID, "sort_by", sortBy, "err", err, "request_id", middleware.GetRequestID(ctx))
 	}
 	return notes, err
 }
@@ -512,7 +1666,7 @@ func (s *NotesService) insertWikiLinksWithStore(ctx context.Context, querier sto
 	}
 
 	for _, link := range parsed.WikiLinks {
-		targetNote, err := querier.GetNoteByTitleGlobal(ctx, link.Target)
+		targetNote, err := resolveWikiLinkTarget(ctx, querier, link.Target)
 		if err != nil {
 			s.logger.Debug("wiki-link target not found", "title", link.Target, "source_note_id", sourceNoteID)
 			continue
@@ -536,6 +1690,53 @@ func (s *NotesService) insertWikiLinksWithStore(ctx context.Context, querier sto
 	return nil
 }
 
+// resolveWikiLinkTarget returns the live note a wiki-link target refers to. A target with a
+// '/' is first read as a collection path and title (docs/A is the note titled A in the
+// collection at path docs); otherwise, or if that finds nothing, the note with the whole
+// target as its title in any collection is used. Returns sql.ErrNoRows if there is none.
+func resolveWikiLinkTarget(ctx context.Context, querier store.Querier, target string) (store.Note, error) {
+	if i := strings.LastIndex(target, "/"); i > 0 && i < len(target)-1 {
+		collection, err := querier.GetCollectionByPath(ctx, target[:i])
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return store.Note{}, err
+		}
+		if err == nil {
+			note, err := querier.GetLiveNoteByTitleInCollection(ctx, store.GetLiveNoteByTitleInCollectionParams{
+				CollectionID: collection.ID,
+				Title:        target[i+1:],
+			})
+			if !errors.Is(err, sql.ErrNoRows) {
+				return note, err
+			}
+		}
+	}
+	return querier.GetNoteByTitleGlobal(ctx, target)
+}
+
+// insertMentionsWithStore records the @mentions of a note that name known actors.
+// Handles that match no actor are ignored.
+func (s *NotesService) insertMentionsWithStore(ctx context.Context, querier store.Querier, noteID int64, mentions []string) error {
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	actorIDs, err := querier.ListKnownActorIDs(ctx, mentions)
+	if err != nil {
+		return err
+	}
+
+	for _, actorID := range actorIDs {
+		if err := querier.CreateNoteMention(ctx, store.CreateNoteMentionParams{
+			NoteID:  noteID,
+			ActorID: actorID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // insertTagsWithStore creates or reuses tags and associates them with the note.
 // Creates new tags if they don't exist. Tags are already deduplicated by extractAndMergeTags.
 func (s *NotesService) insertTagsWithStore(ctx context.Context, querier store.Querier, noteID int64, tags []string) error {
@@ -571,10 +1772,35 @@ func (s *NotesService) insertTagsWithStore(ctx context.Context, querier store.Qu
 	return nil
 }
 
-// insertMetadataWithStore stores metadata key-value pairs from frontmatter.
-// Merges with optional system metadata (frontmatter wins on conflicts).
-// Filters out 'tags'/'tag' keys which are handled separately.
+// insertMetadataWithStore stores metadata key-value pairs built by buildNoteMetadata.
 func (s *NotesService) insertMetadataWithStore(ctx context.Context, querier store.Querier, noteID int64, parsed *markdown.ParseResult, systemMeta map[string]string) error {
+	mergedMeta, err := buildNoteMetadata(parsed, systemMeta)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range mergedMeta {
+		params := store.CreateNoteMetaParams{
+			NoteID: noteID,
+			Key:    key,
+			Value:  utils.NullString(value),
+		}
+		_, err := querier.CreateNoteMeta(ctx, params)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildNoteMetadata merges frontmatter metadata with optional system metadata (frontmatter wins on conflicts).
+// Filters out 'tags'/'tag' keys which are handled separately.
+// External links found in the body are stored as a JSON array under the 'external_links' key,
+// external (non-relative) image URLs as a JSON array under 'image_urls', and notes with task lists get their unchecked item count under 'open_tasks'.
+// Notes with a body get NoteStats under 'word_count' and 'reading_time_minutes', and notes
+// without a frontmatter title get their first H1 under 'title_from_heading'.
+func buildNoteMetadata(parsed *markdown.ParseResult, systemMeta map[string]string) (map[string]string, error) {
 	mergedMeta := make(map[string]string)
 
 	for k, v := range systemMeta {
@@ -590,19 +1816,59 @@ func (s *NotesService) insertMetadataWithStore(ctx context.Context, querier stor
 		}
 	}
 
-	for key, value := range mergedMeta {
-		params := store.CreateNoteMetaParams{
-			NoteID: noteID,
-			Key:    key,
-			Value:  utils.NullString(value),
+	if len(parsed.ExternalLinks) > 0 {
+		linksJSON, err := json.Marshal(parsed.ExternalLinks)
+		if err != nil {
+			return nil, err
 		}
-		_, err := querier.CreateNoteMeta(ctx, params)
+		mergedMeta[externalLinksMetaKey] = string(linksJSON)
+	}
+
+	var imageURLs []string
+	for _, img := range parsed.Images {
+		if !img.IsLocal {
+			imageURLs = append(imageURLs, img.URL)
+		}
+	}
+	if len(imageURLs) > 0 {
+		urlsJSON, err := json.Marshal(imageURLs)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		mergedMeta[imageURLsMetaKey] = string(urlsJSON)
 	}
 
-	return nil
+	if len(parsed.TaskListItems) > 0 {
+		mergedMeta[openTasksMetaKey] = strconv.Itoa(parsed.OpenTaskCount())
+	}
+
+	if len(parsed.Callouts) > 0 {
+		mergedMeta[calloutCountMetaKey] = strconv.Itoa(len(parsed.Callouts))
+	}
+
+	if _, hasTitle := parsed.Metadata["title"]; !hasTitle {
+		for _, heading := range parsed.Headings {
+			if heading.Level == 1 {
+				mergedMeta[titleFromHeadingMetaKey] = heading.Text
+				break
+			}
+		}
+	}
+
+	if stats := ComputeNoteStats(parsed.BodyWithoutFrontmatter); stats.WordCount > 0 {
+		mergedMeta[wordCountMetaKey] = strconv.Itoa(stats.WordCount)
+		mergedMeta[readingTimeMetaKey] = strconv.Itoa(stats.ReadingTimeMinutes)
+	}
+
+	topWords, err := topWordsJSON(parsed.BodyWithoutFrontmatter)
+	if err != nil {
+		return nil, err
+	}
+	if topWords != "" {
+		mergedMeta[topWordsMetaKey] = topWords
+	}
+
+	return mergedMeta, nil
 }
 
 // ============================================================================
@@ -680,3 +1946,54 @@ func (s *NotesService) GetNoteRelationships(ctx context.Context, noteID int64, l
 
 	return outgoingLinks, incomingLinks, tagIDs, nil
 }
+
+// GetReachableNotes performs a BFS over outgoing links from noteID and returns every note
+// reachable within maxDepth hops with its minimum hop distance, nearest first.
+// maxDepth defaults to 3 (max 10) and limit to 20 (max 100). Trashed notes are not traversed.
+func (s *NotesService) GetReachableNotes(ctx context.Context, noteID int64, maxDepth int, limit int) ([]NoteWithDistance, error) {
+	if _, err := s.GetNoteByID(ctx, noteID); err != nil {
+		return nil, err
+	}
+
+	if maxDepth <= 0 {
+		maxDepth = defaultReachableDepth
+	}
+	maxDepth = min(maxDepth, maxReachableDepth)
+	if limit <= 0 {
+		limit = defaultReachableLimit
+	}
+	limit = min(limit, maxReachableLimit)
+
+	rows, err := s.cteQuerier.GetReachableNotes(ctx, noteID, maxDepth, limit)
+	if err != nil {
+		s.logger.Error("failed to traverse link graph", "note_id", noteID, "max_depth", maxDepth, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return []NoteWithDistance{}, nil
+	}
+
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	notes, err := s.store.ListNotesByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Error("failed to load reachable notes", "note_id", noteID, "count", len(ids), "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	byID := make(map[int64]store.Note, len(notes))
+	for _, note := range notes {
+		byID[note.ID] = note
+	}
+
+	// Keep the CTE's distance order
+	results := make([]NoteWithDistance, 0, len(rows))
+	for _, row := range rows {
+		if note, ok := byID[row.ID]; ok {
+			results = append(results, NoteWithDistance{Note: note, Distance: row.Distance})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,1303 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/internal/mind/links"
+	"github.com/nkapatos/mindweaver/internal/mind/tags"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/database"
+	sharederrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/metrics"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// setupTestService creates a NotesService with a test database for testing.
+func setupTestService(t *testing.T) (*NotesService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	logger := testdb.NewTestLogger(t)
+	service := NewNotesService(db, queries, logger, "notes-test")
+
+	return service, queries
+}
+
+// createTestCollection creates a root collection for testing.
+func createTestCollection(t *testing.T, queries *store.Queries, name string) int64 {
+	t.Helper()
+
+	id, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{
+		Name: name,
+		Path: utils.GenerateSlug(name),
+	})
+	require.NoError(t, err)
+	return id
+}
+
+// createTestNote creates a note through the service so derived data is extracted.
+func createTestNote(t *testing.T, service *NotesService, title, body string, collectionID int64) int64 {
+	t.Helper()
+
+	id, err := service.CreateNote(context.Background(), store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        title,
+		Body:         utils.NullString(body),
+		CollectionID: collectionID,
+	})
+	require.NoError(t, err)
+	return id
+}
+
+// noteMeta returns the stored note_meta rows of a note as a map.
+func noteMeta(t *testing.T, queries *store.Queries, noteID int64) map[string]string {
+	t.Helper()
+
+	items, err := queries.GetNoteMetaByNoteID(context.Background(), noteID)
+	require.NoError(t, err)
+
+	metadata := make(map[string]string)
+	for _, item := range items {
+		metadata[item.Key] = item.Value.String
+	}
+	return metadata
+}
+
+// ============================================================================
+// DuplicateNote Tests
+// ============================================================================
+
+func TestDuplicateNote_SameCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	body := "---\nauthor: Jane\n---\n# Plan\n\nSee [docs](https://example.com)."
+	sourceID := createTestNote(t, service, "Plan", body, collectionID)
+
+	copyID, err := service.DuplicateNote(ctx, sourceID, utils.NullInt64Empty())
+	require.NoError(t, err)
+	require.NotEqual(t, sourceID, copyID)
+
+	source, err := queries.GetNoteByID(ctx, sourceID)
+	require.NoError(t, err)
+	duplicate, err := queries.GetNoteByID(ctx, copyID)
+	require.NoError(t, err)
+
+	require.Equal(t, "Plan (copy)", duplicate.Title)
+	require.Equal(t, collectionID, duplicate.CollectionID)
+	require.Equal(t, source.Body, duplicate.Body)
+	require.NotEqual(t, source.Uuid, duplicate.Uuid)
+	require.Equal(t, noteMeta(t, queries, sourceID), noteMeta(t, queries, copyID))
+	require.Equal(t, "Jane", noteMeta(t, queries, copyID)["author"])
+
+	// A second duplicate gets a numbered suffix
+	secondID, err := service.DuplicateNote(ctx, sourceID, utils.NullInt64Empty())
+	require.NoError(t, err)
+	second, err := queries.GetNoteByID(ctx, secondID)
+	require.NoError(t, err)
+	require.Equal(t, "Plan (copy 2)", second.Title)
+}
+
+func TestDuplicateNote_DifferentCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	workID := createTestCollection(t, queries, "Work")
+	archiveID := createTestCollection(t, queries, "Archive")
+	sourceID := createTestNote(t, service, "Plan", "# Plan", workID)
+
+	copyID, err := service.DuplicateNote(ctx, sourceID, utils.NullInt64(archiveID))
+	require.NoError(t, err)
+
+	duplicate, err := queries.GetNoteByID(ctx, copyID)
+	require.NoError(t, err)
+	require.Equal(t, "Plan (copy)", duplicate.Title)
+	require.Equal(t, archiveID, duplicate.CollectionID)
+}
+
+func TestDuplicateNote_TitleAlreadyEndsInCopy(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	createTestNote(t, service, "Plan", "# Plan", collectionID)
+	copySourceID := createTestNote(t, service, "Plan (copy)", "# Plan", collectionID)
+
+	copyID, err := service.DuplicateNote(ctx, copySourceID, utils.NullInt64Empty())
+	require.NoError(t, err)
+
+	duplicate, err := queries.GetNoteByID(ctx, copyID)
+	require.NoError(t, err)
+	require.Equal(t, "Plan (copy 2)", duplicate.Title)
+}
+
+func TestDuplicateNote_MissingTargetCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	sourceID := createTestNote(t, service, "Plan", "# Plan", collectionID)
+
+	_, err := service.DuplicateNote(ctx, sourceID, utils.NullInt64(9999))
+	require.True(t, sharederrors.IsForeignKeyConstraintError(err), "got %v", err)
+
+	count, err := queries.CountNotes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestDuplicateNote_SourceNotFound(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.DuplicateNote(context.Background(), 9999, utils.NullInt64Empty())
+	require.ErrorIs(t, err, ErrNoteNotFound)
+}
+
+// ============================================================================
+// NewNoteFromTemplate Tests
+// ============================================================================
+
+func TestNewNoteFromTemplate_SubstitutesVariables(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	templateID := createTestNote(t, service, "Meeting Template",
+		"# {{title}}\n\nWith {{attendee}} on {{date}}.\nID: {{uuid}}\nAgenda: {{agenda}}", collectionID)
+
+	noteID, err := service.NewNoteFromTemplate(ctx, collectionID, templateID, map[string]string{
+		"title":    "Standup",
+		"attendee": "<b>Jane & Bob</b>",
+	})
+	require.NoError(t, err)
+
+	note, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+
+	expected := "# Standup\n\nWith <b>Jane & Bob</b> on " + time.Now().Format(time.DateOnly) +
+		".\nID: " + note.Uuid.String() + "\nAgenda: {{agenda}}"
+	require.Equal(t, "Standup", note.Title)
+	require.Equal(t, expected, note.Body.String)
+}
+
+func TestNewNoteFromTemplate_GeneratesTitleWhenNotSupplied(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	templateID := createTestNote(t, service, "Template", "# {{title}}", collectionID)
+
+	noteID, err := service.NewNoteFromTemplate(ctx, collectionID, templateID, nil)
+	require.NoError(t, err)
+
+	note, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(note.Title, "Untitled "))
+	require.Equal(t, "# "+note.Title, note.Body.String)
+}
+
+func TestNewNoteFromTemplate_RollsBackWhenCreateFails(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	templateID := createTestNote(t, service, "Template", "# {{title}}", collectionID)
+	createTestNote(t, service, "Taken", "", collectionID)
+
+	before, err := queries.CountNotes(ctx)
+	require.NoError(t, err)
+
+	_, err = service.NewNoteFromTemplate(ctx, collectionID, templateID, map[string]string{"title": "Taken"})
+	require.ErrorIs(t, err, ErrNoteAlreadyExists)
+
+	after, err := queries.CountNotes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+}
+
+func TestSubstituteTemplateVars_LeavesUnknownKeys(t *testing.T) {
+	got := substituteTemplateVars("{{known}} {{unknown}} {{ known }}", map[string]string{"known": "value"})
+	require.Equal(t, "value {{unknown}} {{ known }}", got)
+}
+
+// ============================================================================
+// Metrics Tests
+// ============================================================================
+
+func TestCreateNote_IncrementsMetrics(t *testing.T) {
+	service, queries := setupTestService(t)
+	m := metrics.New()
+	service.SetMetrics(m)
+
+	collectionID := createTestCollection(t, queries, "Work")
+	createTestNote(t, service, "Plan", "# Plan", collectionID)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	require.Contains(t, body, "\nmindweaver_notes_created_total 1\n")
+	require.Contains(t, body, "\nmindweaver_notes_count 1\n")
+	require.Contains(t, body, "\nmindweaver_notes_updated_total 0\n")
+}
+
+// recordingCollectionCache records the collections invalidated by NotesService.
+type recordingCollectionCache struct {
+	invalidated []int64
+}
+
+func (c *recordingCollectionCache) InvalidateCacheForCollection(collectionID int64) {
+	c.invalidated = append(c.invalidated, collectionID)
+}
+
+func TestNoteWrites_InvalidateCollectionCache(t *testing.T) {
+	service, queries := setupTestService(t)
+	cache := &recordingCollectionCache{}
+	service.SetCollectionCache(cache)
+	ctx := context.Background()
+
+	work := createTestCollection(t, queries, "Work")
+	home := createTestCollection(t, queries, "Home")
+
+	noteID := createTestNote(t, service, "Plan", "# Plan", work)
+	require.Equal(t, []int64{work}, cache.invalidated)
+
+	// Moving a note invalidates both the old and the new collection
+	note, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	cache.invalidated = nil
+	require.NoError(t, service.UpdateNote(ctx, store.UpdateNoteByIDParams{
+		ID:           noteID,
+		Uuid:         note.Uuid,
+		Title:        note.Title,
+		Body:         note.Body,
+		CollectionID: home,
+		Version:      note.Version,
+	}))
+	require.ElementsMatch(t, []int64{work, home}, cache.invalidated)
+
+	cache.invalidated = nil
+	require.NoError(t, service.DeleteNote(ctx, noteID))
+	require.NoError(t, service.RestoreNote(ctx, noteID))
+	require.Equal(t, []int64{home, home}, cache.invalidated)
+
+	// Failed writes leave the cache alone
+	cache.invalidated = nil
+	require.ErrorIs(t, service.RestoreNote(ctx, noteID), ErrNoteNotFound)
+	require.Empty(t, cache.invalidated)
+}
+
+// ============================================================================
+// Soft Delete Tests
+// ============================================================================
+
+func TestDeleteNote_SoftDeletesAndRestores(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Plan", "# Plan #project", collectionID)
+
+	require.NoError(t, service.DeleteNote(ctx, noteID))
+
+	_, err := service.GetNoteByID(ctx, noteID)
+	require.ErrorIs(t, err, ErrNoteNotFound)
+	count, err := service.CountNotes(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	deleted, err := service.ListDeletedNotesPaginated(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	require.Equal(t, noteID, deleted[0].ID)
+	require.True(t, deleted[0].DeletedAt.Valid)
+
+	// Deleting twice reports not found
+	require.ErrorIs(t, service.DeleteNote(ctx, noteID), ErrNoteNotFound)
+
+	require.NoError(t, service.RestoreNote(ctx, noteID))
+
+	note, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.False(t, note.DeletedAt.Valid)
+	deletedCount, err := service.CountDeletedNotes(ctx)
+	require.NoError(t, err)
+	require.Zero(t, deletedCount)
+
+	// Derived data survives the round trip
+	tags, err := queries.ListTagsForNote(ctx, noteID)
+	require.NoError(t, err)
+	require.Len(t, tags, 1)
+
+	// Restoring a live note reports not found
+	require.ErrorIs(t, service.RestoreNote(ctx, noteID), ErrNoteNotFound)
+}
+
+func TestDeleteNote_DropsTrashedNoteFromRelationshipsAndTagCounts(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+	logger := testdb.NewTestLogger(t)
+	linksService := links.NewLinksService(queries, logger, "links-test")
+	tagsService := tags.NewTagsService(service.db, queries, logger, "tags-test")
+
+	collectionID := createTestCollection(t, queries, "Work")
+	targetID := createTestNote(t, service, "Target", "# Target #project", collectionID)
+	linkerID := createTestNote(t, service, "Linker", "See [[Target]] #project", collectionID)
+	readerID := createTestNote(t, service, "Reader", "See [[Linker]]", collectionID)
+
+	projectUsage := func() int64 {
+		tag, err := queries.GetTagByName(ctx, "project")
+		require.NoError(t, err)
+		rows, err := queries.TagUsageCount(ctx)
+		require.NoError(t, err)
+		for _, row := range rows {
+			if row.TagID == tag.ID {
+				return row.UsageCount
+			}
+		}
+		return 0
+	}
+
+	_, incoming, _, err := service.GetNoteRelationships(ctx, targetID, linksService, tagsService)
+	require.NoError(t, err)
+	require.Equal(t, []int64{linkerID}, incoming)
+	outgoing, _, _, err := service.GetNoteRelationships(ctx, readerID, linksService, tagsService)
+	require.NoError(t, err)
+	require.Equal(t, []int64{linkerID}, outgoing)
+	require.Equal(t, int64(2), projectUsage())
+
+	require.NoError(t, service.DeleteNote(ctx, linkerID))
+
+	_, incoming, _, err = service.GetNoteRelationships(ctx, targetID, linksService, tagsService)
+	require.NoError(t, err)
+	require.Empty(t, incoming, "trashed notes are not backlinks")
+	outgoing, _, _, err = service.GetNoteRelationships(ctx, readerID, linksService, tagsService)
+	require.NoError(t, err)
+	require.Empty(t, outgoing, "links to trashed notes are hidden")
+	require.Equal(t, int64(1), projectUsage())
+
+	// Restoring brings the links and the tag usage back
+	require.NoError(t, service.RestoreNote(ctx, linkerID))
+	_, incoming, _, err = service.GetNoteRelationships(ctx, targetID, linksService, tagsService)
+	require.NoError(t, err)
+	require.Equal(t, []int64{linkerID}, incoming)
+	require.Equal(t, int64(2), projectUsage())
+}
+
+func TestPermanentlyDeleteNote(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	createTestActor(t, queries, "alice")
+	collectionID := createTestCollection(t, queries, "Work")
+	liveID := createTestNote(t, service, "Live", "---\nauthor: Jane\n---\n# Live #plan with @alice, see [[Trashed]]", collectionID)
+	trashedID := createTestNote(t, service, "Trashed", "# Trashed", collectionID)
+	require.NoError(t, service.DeleteNote(ctx, trashedID))
+
+	derivedQueries := []string{
+		"SELECT COUNT(*) FROM links WHERE src_id = ?",
+		"SELECT COUNT(*) FROM note_tags WHERE note_id = ?",
+		"SELECT COUNT(*) FROM note_meta WHERE note_id = ?",
+		"SELECT COUNT(*) FROM note_mentions WHERE note_id = ?",
+	}
+	countDerived := func(query string) int {
+		var count int
+		require.NoError(t, service.db.QueryRowContext(ctx, query, liveID).Scan(&count))
+		return count
+	}
+	for _, query := range derivedQueries {
+		require.NotZero(t, countDerived(query), query)
+	}
+
+	require.NoError(t, service.PermanentlyDeleteNote(ctx, liveID))
+	require.NoError(t, service.PermanentlyDeleteNote(ctx, trashedID))
+
+	// Derived data is removed by the foreign key cascades
+	for _, query := range derivedQueries {
+		require.Zero(t, countDerived(query), query)
+	}
+
+	deletedCount, err := service.CountDeletedNotes(ctx)
+	require.NoError(t, err)
+	require.Zero(t, deletedCount)
+	require.ErrorIs(t, service.RestoreNote(ctx, trashedID), ErrNoteNotFound)
+	require.ErrorIs(t, service.PermanentlyDeleteNote(ctx, liveID), ErrNoteNotFound)
+}
+
+func TestPermanentlyDeleteNote_KeepsTemplateStarterNotes(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	starterID := createTestNote(t, service, "Meeting Template", "# {{title}}", collectionID)
+	_, err := queries.CreateTemplate(ctx, store.CreateTemplateParams{Name: "Meeting", StarterNoteID: starterID})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, service.PermanentlyDeleteNote(ctx, starterID), ErrTemplateStarterNote)
+
+	// Purging skips it rather than failing for the other trashed notes
+	otherID := createTestNote(t, service, "Other", "", collectionID)
+	require.NoError(t, service.DeleteNote(ctx, starterID))
+	require.NoError(t, service.DeleteNote(ctx, otherID))
+	_, err = service.db.ExecContext(ctx, "UPDATE notes SET deleted_at = datetime('now', '-31 days') WHERE deleted_at IS NOT NULL")
+	require.NoError(t, err)
+	purged, err := service.PurgeDeletedNotes(ctx, 30*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	deleted, err := service.ListDeletedNotesPaginated(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	require.Equal(t, starterID, deleted[0].ID)
+}
+
+func TestPurgeDeletedNotes_RespectsRetention(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	oldID := createTestNote(t, service, "Old", "# Old", collectionID)
+	recentID := createTestNote(t, service, "Recent", "# Recent", collectionID)
+	require.NoError(t, service.DeleteNote(ctx, oldID))
+	require.NoError(t, service.DeleteNote(ctx, recentID))
+
+	_, err := service.db.ExecContext(ctx, "UPDATE notes SET deleted_at = datetime('now', '-31 days') WHERE id = ?", oldID)
+	require.NoError(t, err)
+
+	purged, err := service.PurgeDeletedNotes(ctx, 30*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	deleted, err := service.ListDeletedNotesPaginated(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	require.Equal(t, recentID, deleted[0].ID)
+}
+
+// ============================================================================
+// Task List Metadata Tests
+// ============================================================================
+
+func TestNoteTasks_OpenTaskCountInMetadata(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Sprint", "- [x] Plan\n- [ ] Build\n- [ ] Ship\n", collectionID)
+	require.Equal(t, "2", noteMeta(t, queries, noteID)["open_tasks"])
+
+	// Re-extracted on update
+	note, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateNote(ctx, store.UpdateNoteByIDParams{
+		ID:           noteID,
+		Uuid:         note.Uuid,
+		Title:        note.Title,
+		Body:         utils.NullString("- [x] Plan\n- [x] Build\n- [ ] Ship\n"),
+		CollectionID: note.CollectionID,
+		Version:      note.Version,
+	}))
+	require.Equal(t, "1", noteMeta(t, queries, noteID)["open_tasks"])
+
+	// Notes without task lists get no key
+	plainID := createTestNote(t, service, "Plain", "No tasks here", collectionID)
+	require.NotContains(t, noteMeta(t, queries, plainID), "open_tasks")
+}
+
+func TestNoteCallouts_CountInMetadata(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Runbook", "> [!WARNING] Careful\n> > [!TIP]\n> > Nested\n\n> [!NOTE]\n> Done\n", collectionID)
+	require.Equal(t, "3", noteMeta(t, queries, noteID)["callout_count"])
+
+	plainID := createTestNote(t, service, "Plain", "> Just a quote", collectionID)
+	require.NotContains(t, noteMeta(t, queries, plainID), "callout_count")
+}
+
+func TestNoteHeadings_TitleFromHeading(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Spec", "Preamble\n\n## Sub\n\n# Real Title\n\n# Second\n", collectionID)
+	require.Equal(t, "Real Title", noteMeta(t, queries, noteID)["title_from_heading"])
+
+	// An explicit frontmatter title wins
+	titledID := createTestNote(t, service, "Titled", "---\ntitle: Explicit\n---\n# Heading\n", collectionID)
+	require.NotContains(t, noteMeta(t, queries, titledID), "title_from_heading")
+}
+
+func TestComputeNoteStats(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            string
+		expectedWords   int
+		expectedMinutes int
+	}{
+		{"empty", "", 0, 0},
+		{"single word rounds up", "hello", 1, 1},
+		{"markdown syntax not counted", "# Heading\n\n- item one\n\n```\ncode\n```", 4, 1},
+		{"exactly 200 words", strings.Repeat("word ", 200), 200, 1},
+		{"201 words", strings.Repeat("word ", 201), 201, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := ComputeNoteStats(tt.body)
+			require.Equal(t, tt.expectedWords, stats.WordCount)
+			require.Equal(t, tt.expectedMinutes, stats.ReadingTimeMinutes)
+		})
+	}
+}
+
+func TestNoteStats_StoredInMetadata(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	body := "---\nauthor: Jane Doe\n---\n" + strings.Repeat("lorem ", 400)
+	noteID := createTestNote(t, service, "Essay", body, collectionID)
+
+	// Frontmatter words are not counted
+	metadata := noteMeta(t, queries, noteID)
+	require.Equal(t, "400", metadata["word_count"])
+	require.Equal(t, "2", metadata["reading_time_minutes"])
+
+	note, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateNote(ctx, store.UpdateNoteByIDParams{
+		ID:           noteID,
+		Uuid:         note.Uuid,
+		Title:        note.Title,
+		Body:         utils.NullString(strings.Repeat("lorem ", 650)),
+		CollectionID: note.CollectionID,
+		Version:      note.Version,
+	}))
+
+	metadata = noteMeta(t, queries, noteID)
+	require.Equal(t, "650", metadata["word_count"])
+	require.Equal(t, "4", metadata["reading_time_minutes"])
+}
+
+// ============================================================================
+// GetReachableNotes Tests
+// ============================================================================
+
+// createLinkChain creates notes A->B->C->D linked by wiki-links and returns their IDs.
+// Notes are created from the end of the chain so each link resolves on creation.
+func createLinkChain(t *testing.T, service *NotesService, collectionID int64) map[string]int64 {
+	t.Helper()
+
+	ids := make(map[string]int64)
+	ids["D"] = createTestNote(t, service, "D", "# D", collectionID)
+	ids["C"] = createTestNote(t, service, "C", "See [[D]]", collectionID)
+	ids["B"] = createTestNote(t, service, "B", "See [[C]]", collectionID)
+	ids["A"] = createTestNote(t, service, "A", "See [[B]]", collectionID)
+	return ids
+}
+
+func TestGetReachableNotes_Chain(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	ids := createLinkChain(t, service, createTestCollection(t, queries, "Work"))
+
+	reachable, err := service.GetReachableNotes(ctx, ids["A"], 5, 10)
+	require.NoError(t, err)
+	require.Len(t, reachable, 3)
+
+	for i, expected := range []struct {
+		title    string
+		distance int
+	}{{"B", 1}, {"C", 2}, {"D", 3}} {
+		require.Equal(t, ids[expected.title], reachable[i].ID)
+		require.Equal(t, expected.title, reachable[i].Title)
+		require.Equal(t, expected.distance, reachable[i].Distance)
+	}
+}
+
+func TestGetReachableNotes_RespectsDepthAndTrash(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	ids := createLinkChain(t, service, createTestCollection(t, queries, "Work"))
+
+	reachable, err := service.GetReachableNotes(ctx, ids["A"], 2, 10)
+	require.NoError(t, err)
+	require.Len(t, reachable, 2)
+
+	// A trashed note is neither returned nor traversed
+	require.NoError(t, service.DeleteNote(ctx, ids["C"]))
+	reachable, err = service.GetReachableNotes(ctx, ids["A"], 5, 10)
+	require.NoError(t, err)
+	require.Len(t, reachable, 1)
+	require.Equal(t, ids["B"], reachable[0].ID)
+
+	// Links are followed forward only
+	reachable, err = service.GetReachableNotes(ctx, ids["D"], 5, 10)
+	require.NoError(t, err)
+	require.Empty(t, reachable)
+
+	_, err = service.GetReachableNotes(ctx, 9999, 5, 10)
+	require.ErrorIs(t, err, ErrNoteNotFound)
+}
+
+// ============================================================================
+// CreateNotesBatch Tests
+// ============================================================================
+
+// batchParams builds n notes titled "<prefix> 0..n-1"; each links to the previous note and shares a tag.
+func batchParams(prefix string, n int, collectionID int64) []store.CreateNoteParams {
+	params := make([]store.CreateNoteParams, n)
+	for i := range params {
+		body := fmt.Sprintf("---\nauthor: Jane\n---\n# Note %d #imported", i)
+		if i > 0 {
+			body += fmt.Sprintf("\n\nSee [[%s %d]]", prefix, i-1)
+		}
+		params[i] = store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        fmt.Sprintf("%s %d", prefix, i),
+			Body:         utils.NullString(body),
+			CollectionID: collectionID,
+		}
+	}
+	return params
+}
+
+// noteTitles returns the titles of notes in order.
+func noteTitles(notes []store.Note) []string {
+	titles := make([]string, len(notes))
+	for i, note := range notes {
+		titles[i] = note.Title
+	}
+	return titles
+}
+
+func TestPinNote_PinnedFirstOrdering(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	oldPinned := createTestNote(t, service, "Old Pinned", "", collectionID)
+	recent := createTestNote(t, service, "Recent", "", collectionID)
+	older := createTestNote(t, service, "Older", "", collectionID)
+	topPinned := createTestNote(t, service, "Top Pinned", "", collectionID)
+
+	// Pinned notes are the least recently updated; pinning must still put them first
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for id, updatedAt := range map[int64]time.Time{
+		oldPinned: base,
+		topPinned: base.Add(time.Hour),
+		older:     base.Add(2 * time.Hour),
+		recent:    base.Add(3 * time.Hour),
+	} {
+		_, err := service.db.ExecContext(ctx, "UPDATE notes SET updated_at = ? WHERE id = ?", updatedAt, id)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, service.PinNote(ctx, oldPinned, 2))
+	require.NoError(t, service.PinNote(ctx, topPinned, 1))
+
+	notes, err := service.ListNotesByCollectionIDPaginated(ctx, collectionID, NoteSortPinnedFirst, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Top Pinned", "Old Pinned", "Recent", "Older"}, noteTitles(notes))
+
+	notes, err = service.ListNotesByCollectionIDPaginated(ctx, collectionID, NoteSortUpdatedAt, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Recent", "Older", "Top Pinned", "Old Pinned"}, noteTitles(notes))
+
+	// Unpinned notes fall back into updated_at order
+	require.NoError(t, service.UnpinNote(ctx, topPinned))
+	notes, err = service.ListNotesByCollectionIDPaginated(ctx, collectionID, NoteSortPinnedFirst, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Old Pinned", "Recent", "Older", "Top Pinned"}, noteTitles(notes))
+
+	// Pinning does not count as an update
+	note, err := queries.GetNoteByID(ctx, oldPinned)
+	require.NoError(t, err)
+	require.True(t, note.UpdatedAt.Time.Equal(base))
+}
+
+func TestPinNote_Errors(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	id := createTestNote(t, service, "Note", "", collectionID)
+
+	require.ErrorIs(t, service.PinNote(ctx, id, -1), ErrInvalidPinPosition)
+	require.ErrorIs(t, service.PinNote(ctx, 9999, 0), ErrNoteNotFound)
+	require.ErrorIs(t, service.UnpinNote(ctx, 9999), ErrNoteNotFound)
+
+	require.NoError(t, service.DeleteNote(ctx, id))
+	require.ErrorIs(t, service.PinNote(ctx, id, 0), ErrNoteNotFound)
+
+	_, err := service.ListNotesByCollectionIDPaginated(ctx, collectionID, "title", 10, 0)
+	require.ErrorIs(t, err, ErrInvalidSortBy)
+}
+
+func TestCreateNotesBatch_CreatesNotesAndDerivedData(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	params := batchParams("Imported", 250, collectionID)
+
+	ids, err := service.CreateNotesBatch(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, ids, len(params))
+
+	// IDs are returned in input order
+	for i, id := range ids {
+		note, err := queries.GetNoteByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, params[i].Title, note.Title)
+	}
+
+	// Links between notes of the same batch resolve
+	outgoing, err := queries.ListLinksBySrcID(ctx, ids[10])
+	require.NoError(t, err)
+	require.Len(t, outgoing, 1)
+	require.Equal(t, ids[9], outgoing[0].DestID.Int64)
+
+	// One shared tag for all notes
+	tag, err := queries.GetTagByName(ctx, "imported")
+	require.NoError(t, err)
+	tagged, err := queries.CountNotesForTag(ctx, tag.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(params)), tagged)
+
+	require.Equal(t, "Jane", noteMeta(t, queries, ids[42])["author"])
+}
+
+func TestCreateNotesBatch_RollsBackOnDuplicate(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	createTestNote(t, service, "Imported 3", "", collectionID)
+
+	_, err := service.CreateNotesBatch(ctx, batchParams("Imported", 5, collectionID))
+	require.ErrorIs(t, err, ErrNoteAlreadyExists)
+
+	count, err := queries.CountNotes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestUpdateNote_ConcurrentEditsConflict(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	noteID := createTestNote(t, service, "Shared", "Original", collectionID)
+
+	// Two actors load the same version of the note
+	first, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	second, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+
+	edit := func(loaded store.Note, body string) error {
+		return service.UpdateNote(ctx, store.UpdateNoteByIDParams{
+			ID:           noteID,
+			Uuid:         loaded.Uuid,
+			Title:        loaded.Title,
+			Body:         utils.NullString(body),
+			CollectionID: loaded.CollectionID,
+			Version:      loaded.Version,
+		})
+	}
+
+	require.NoError(t, edit(first, "Edit from actor one"))
+	require.ErrorIs(t, edit(second, "Edit from actor two"), ErrStaleNote)
+
+	// The first edit survives
+	note, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.Equal(t, "Edit from actor one", note.Body.String)
+	require.Equal(t, first.Version+1, note.Version)
+
+	// Reloading picks up the new version and the retry succeeds
+	require.NoError(t, edit(note, "Edit from actor two"))
+}
+
+func TestUpdateNote_TrashedNoteNotFound(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	noteID := createTestNote(t, service, "Trashed", "Original", collectionID)
+	loaded, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteNote(ctx, noteID))
+
+	params := store.UpdateNoteByIDParams{
+		ID:           noteID,
+		Uuid:         loaded.Uuid,
+		Title:        loaded.Title,
+		Body:         utils.NullString("Edited in the trash"),
+		CollectionID: loaded.CollectionID,
+		Version:      loaded.Version,
+	}
+	require.ErrorIs(t, service.UpdateNote(ctx, params), ErrNoteNotFound)
+
+	// The queries skip trashed notes on their own too
+	result, err := queries.UpdateNoteByID(ctx, params)
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Zero(t, affected)
+	require.NoError(t, queries.UpdateNoteMetadataByID(ctx, store.UpdateNoteMetadataByIDParams{
+		ID:           noteID,
+		Title:        "Renamed in the trash",
+		CollectionID: loaded.CollectionID,
+	}))
+
+	trashed, err := queries.GetNoteByTitle(ctx, store.GetNoteByTitleParams{Title: "Trashed", CollectionID: collectionID})
+	require.NoError(t, err)
+	require.Equal(t, "Original", trashed.Body.String)
+	require.Equal(t, loaded.Version, trashed.Version)
+}
+
+func TestReplaceNote_RequiresConcreteETag(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	noteID := createTestNote(t, service, "Shared", "Original", collectionID)
+	handler := NewNotesHandler(service, nil, nil, nil)
+
+	body := "Blind overwrite"
+	replace := func(ifMatch string) error {
+		req := connect.NewRequest(&mindv3.ReplaceNoteRequest{Id: noteID, Title: "Shared", Body: &body})
+		if ifMatch != "" {
+			req.Header().Set("If-Match", ifMatch)
+		}
+		_, err := handler.ReplaceNote(ctx, req)
+		return err
+	}
+	update := func(ifMatch string) error {
+		req := connect.NewRequest(&mindv3.UpdateNoteRequest{Id: noteID, Body: &body})
+		req.Header().Set("If-Match", ifMatch)
+		_, err := handler.UpdateNote(ctx, req)
+		return err
+	}
+
+	// The "*" wildcard matches any version, so it can't guard against lost updates
+	for _, err := range []error{replace(""), replace("*"), update("*")} {
+		require.Equal(t, connect.CodeFailedPrecondition, connect.CodeOf(err))
+		require.Contains(t, err.Error(), "ETAG_REQUIRED")
+	}
+
+	note, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.Equal(t, "Original", note.Body.String)
+
+	require.NoError(t, replace(utils.ComputeHashedETag(note.Version)))
+}
+
+func TestUpdateNote_FrontmatterSync(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	noteID := createTestNote(t, service, "Draft", "---\ntitle: Draft\nauthor: Jane\n---\n# Body\n", collectionID)
+
+	update := func(title string) store.Note {
+		t.Helper()
+		note, err := service.GetNoteByID(ctx, noteID)
+		require.NoError(t, err)
+		require.NoError(t, service.UpdateNote(ctx, store.UpdateNoteByIDParams{
+			ID:           noteID,
+			Uuid:         note.Uuid,
+			Title:        title,
+			Body:         note.Body,
+			CollectionID: note.CollectionID,
+			Version:      note.Version,
+		}))
+		note, err = service.GetNoteByID(ctx, noteID)
+		require.NoError(t, err)
+		return note
+	}
+
+	// Disabled by default: the body is stored as sent
+	note := update("Renamed")
+	require.Contains(t, note.Body.String, "title: Draft")
+
+	service.SetFrontmatterSync(true)
+	note = update("Final")
+	require.Contains(t, note.Body.String, "title: Final")
+	require.Contains(t, note.Body.String, "author: Jane")
+	require.Contains(t, note.Body.String, "updated: ")
+	require.Equal(t, "Final", noteMeta(t, queries, noteID)["title"])
+}
+
+func TestCreateNote_MaxBodySize(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	service.SetMaxBodySize(16)
+
+	create := func(title, body string) (int64, error) {
+		return service.CreateNote(ctx, store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        title,
+			Body:         utils.NullString(body),
+			CollectionID: collectionID,
+		})
+	}
+
+	// Exactly at the limit is accepted
+	noteID, err := create("At Limit", strings.Repeat("a", 16))
+	require.NoError(t, err)
+
+	// One byte over is rejected on create and update
+	_, err = create("Over Limit", strings.Repeat("a", 17))
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+
+	note, err := service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	err = service.UpdateNote(ctx, store.UpdateNoteByIDParams{
+		ID:           noteID,
+		Uuid:         note.Uuid,
+		Title:        note.Title,
+		Body:         utils.NullString(strings.Repeat("b", 17)),
+		CollectionID: note.CollectionID,
+		Version:      note.Version,
+	})
+	require.ErrorIs(t, err, ErrBodyTooLarge)
+
+	note, err = service.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("a", 16), note.Body.String)
+
+	// Zero disables the limit
+	service.SetMaxBodySize(0)
+	_, err = create("Unlimited", strings.Repeat("a", 1<<20))
+	require.NoError(t, err)
+}
+
+func TestUpsertNote_CreatesUpdatesAndSkipsUnchanged(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	params := store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        "Imported",
+		Body:         utils.NullString("# First #draft"),
+		CollectionID: collectionID,
+	}
+
+	// Unknown UUID: created
+	id, created, err := service.UpsertNote(ctx, params)
+	require.NoError(t, err)
+	require.True(t, created)
+
+	note, err := service.GetNoteByUUID(ctx, params.Uuid)
+	require.NoError(t, err)
+	require.Equal(t, id, note.ID)
+	version := note.Version
+
+	// Same content: nothing is written
+	sameID, created, err := service.UpsertNote(ctx, params)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, id, sameID)
+	note, err = queries.GetNoteByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, version, note.Version)
+
+	// Changed content: updated in place, derived data re-extracted
+	params.Body = utils.NullString("# Second #final")
+	updatedID, created, err := service.UpsertNote(ctx, params)
+	require.NoError(t, err)
+	require.False(t, created)
+	require.Equal(t, id, updatedID)
+	note, err = queries.GetNoteByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, "# Second #final", note.Body.String)
+	require.Equal(t, version+1, note.Version)
+
+	count, err := queries.CountNotes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	_, err = queries.GetTagByName(ctx, "final")
+	require.NoError(t, err)
+
+	_, err = service.GetNoteByUUID(ctx, uuid.New())
+	require.ErrorIs(t, err, ErrNoteNotFound)
+}
+
+func TestCreateNotesBatch_ReimportIsIdempotent(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	params := batchParams("Imported", 10, collectionID)
+
+	ids, err := service.CreateNotesBatch(ctx, params)
+	require.NoError(t, err)
+
+	// Re-import with one edited note linking to one new note
+	params[4].Body = utils.NullString("# Edited\n\nSee [[Extra 0]]")
+	params = append(params, batchParams("Extra", 1, collectionID)...)
+
+	again, err := service.CreateNotesBatch(ctx, params)
+	require.NoError(t, err)
+	require.Len(t, again, len(params))
+	require.Equal(t, ids, again[:len(ids)])
+
+	count, err := queries.CountNotes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(params)), count)
+
+	note, err := queries.GetNoteByID(ctx, ids[4])
+	require.NoError(t, err)
+	require.Equal(t, "# Edited\n\nSee [[Extra 0]]", note.Body.String)
+
+	// The update runs after the insert, so its link to the new note resolves
+	outgoing, err := queries.ListLinksBySrcID(ctx, ids[4])
+	require.NoError(t, err)
+	require.Len(t, outgoing, 1)
+	require.Equal(t, again[len(again)-1], outgoing[0].DestID.Int64)
+
+	unchanged, err := queries.GetNoteByID(ctx, ids[5])
+	require.NoError(t, err)
+	require.Equal(t, int64(1), unchanged.Version)
+}
+
+func TestCreateNotesBatch_RollsBackUpdatesWithInserts(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	params := batchParams("Imported", 5, collectionID)
+	ids, err := service.CreateNotesBatch(ctx, params)
+	require.NoError(t, err)
+
+	// One edit succeeds, the next collides with another note's title
+	params[1].Body = utils.NullString("# Edited")
+	params[2].Title = "Imported 3"
+	params = append(params, batchParams("Extra", 1, collectionID)...)
+
+	_, err = service.CreateNotesBatch(ctx, params)
+	require.ErrorIs(t, err, ErrNoteAlreadyExists)
+
+	count, err := queries.CountNotes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), count)
+
+	note, err := queries.GetNoteByID(ctx, ids[1])
+	require.NoError(t, err)
+	require.Equal(t, int64(1), note.Version)
+	require.NotEqual(t, "# Edited", note.Body.String)
+}
+
+func TestCreateNotesBatch_RejectsTrashedNote(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	params := batchParams("Imported", 3, collectionID)
+	ids, err := service.CreateNotesBatch(ctx, params)
+	require.NoError(t, err)
+	require.NoError(t, service.DeleteNote(ctx, ids[1]))
+
+	// Re-importing the trashed note is rejected instead of colliding with its UUID
+	params = append(params, batchParams("Extra", 1, collectionID)...)
+	_, err = service.CreateNotesBatch(ctx, params)
+	require.ErrorIs(t, err, ErrNoteTrashed)
+
+	_, _, err = service.UpsertNote(ctx, params[1])
+	require.ErrorIs(t, err, ErrNoteTrashed)
+
+	_, err = queries.GetNoteByTitle(ctx, store.GetNoteByTitleParams{Title: "Extra 0", CollectionID: collectionID})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Once restored, the note is updated like any other
+	require.NoError(t, service.RestoreNote(ctx, ids[1]))
+	params[1].Body = utils.NullString("# Restored")
+	again, err := service.CreateNotesBatch(ctx, params)
+	require.NoError(t, err)
+	require.Equal(t, ids[1], again[1])
+
+	note, err := queries.GetNoteByID(ctx, ids[1])
+	require.NoError(t, err)
+	require.Equal(t, "# Restored", note.Body.String)
+}
+
+func TestBulkAssignTags_AssignsEveryCombination(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	noteIDs, err := service.CreateNotesBatch(ctx, batchParams("Bulk", 100, collectionID))
+	require.NoError(t, err)
+
+	tagNames := []string{"alpha", "beta", "gamma", "delta", "imported"}
+	require.NoError(t, service.BulkAssignTags(ctx, noteIDs, tagNames))
+
+	// Repeating the assignment must not fail or duplicate rows
+	require.NoError(t, service.BulkAssignTags(ctx, noteIDs, tagNames))
+
+	var total int64
+	for _, name := range tagNames {
+		tag, err := queries.GetTagByName(ctx, name)
+		require.NoError(t, err)
+		count, err := queries.CountNotesForTag(ctx, tag.ID)
+		require.NoError(t, err)
+		total += count
+	}
+	require.Equal(t, int64(500), total)
+}
+
+func TestBulkAssignTags_MissingNoteRollsBack(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Vault")
+	noteID := createTestNote(t, service, "Tagged", "", collectionID)
+
+	err := service.BulkAssignTags(ctx, []int64{noteID, 9999}, []string{"orphan"})
+	require.ErrorIs(t, err, ErrNoteNotFound)
+
+	_, err = queries.GetTagByName(ctx, "orphan")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+// setupBenchService creates a NotesService on a temporary database for benchmarks.
+func setupBenchService(b *testing.B) (*NotesService, int64) {
+	b.Helper()
+
+	db, err := sql.Open("sqlite", database.DSN(filepath.Join(b.TempDir(), "bench.db")))
+	if err != nil {
+		b.Fatalf("failed to open database: %v", err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if err := mindmigrations.RunMigrations(db, logger); err != nil {
+		b.Fatalf("failed to run migrations: %v", err)
+	}
+
+	queries := store.New(db)
+	collectionID, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{Name: "Bench", Path: "bench"})
+	if err != nil {
+		b.Fatalf("failed to create collection: %v", err)
+	}
+
+	return NewNotesService(db, queries, logger, "notes-bench"), collectionID
+}
+
+func BenchmarkCreateNotes_Batch500(b *testing.B) {
+	service, collectionID := setupBenchService(b)
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		params := batchParams(fmt.Sprintf("Batch %d", i), 500, collectionID)
+		if _, err := service.CreateNotesBatch(ctx, params); err != nil {
+			b.Fatalf("CreateNotesBatch failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkCreateNotes_Single500(b *testing.B) {
+	service, collectionID := setupBenchService(b)
+	ctx := context.Background()
+
+	for i := 0; i < b.N; i++ {
+		for _, params := range batchParams(fmt.Sprintf("Single %d", i), 500, collectionID) {
+			if _, err := service.CreateNote(ctx, params); err != nil {
+				b.Fatalf("CreateNote failed: %v", err)
+			}
+		}
+	}
+}
+
+func TestGetNoteByTitle_ScopedToCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+	docs := createTestCollection(t, queries, "docs")
+	guides := createTestCollection(t, queries, "guides")
+
+	inDocs := createTestNote(t, service, "Setup", "", docs)
+	inGuides := createTestNote(t, service, "Setup", "", guides)
+
+	note, err := service.GetNoteByTitle(ctx, guides, "Setup")
+	require.NoError(t, err)
+	require.Equal(t, inGuides, note.ID)
+
+	require.NoError(t, service.DeleteNote(ctx, inDocs))
+	_, err = service.GetNoteByTitle(ctx, docs, "Setup")
+	require.ErrorIs(t, err, ErrNoteNotFound, "trashed notes are not returned")
+}
+
+func TestWikiLinks_CollectionScopedResolutionWins(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+	docs := createTestCollection(t, queries, "docs")
+	misc := createTestCollection(t, queries, "misc")
+
+	// A note whose title is literally "docs/Setup" would match a global lookup
+	globalMatch := createTestNote(t, service, "docs/Setup", "", misc)
+	scoped := createTestNote(t, service, "Setup", "", docs)
+	plain := createTestNote(t, service, "Readme", "", misc)
+
+	src := createTestNote(t, service, "Index", "[[docs/Setup]] [[misc/Missing]] [[Readme]] [[nowhere/Readme]]", misc)
+
+	links, err := queries.ListLinksBySrcID(ctx, src)
+	require.NoError(t, err)
+	var dests []int64
+	for _, link := range links {
+		dests = append(dests, link.DestID.Int64)
+	}
+	// misc/Missing and nowhere/Readme match nothing: no collection-scoped note and no such global title
+	require.ElementsMatch(t, []int64{scoped, plain}, dests)
+	require.NotContains(t, dests, globalMatch)
+}
+
+func TestGetNoteCollectionIDs(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	workID := createTestCollection(t, queries, "Work")
+	homeID := createTestCollection(t, queries, "Home")
+	plan := createTestNote(t, service, "Plan", "# Plan", workID)
+	chores := createTestNote(t, service, "Chores", "# Chores", homeID)
+	trashed := createTestNote(t, service, "Trashed", "# Trashed", homeID)
+	require.NoError(t, service.DeleteNote(ctx, trashed))
+
+	collectionIDs, err := service.GetNoteCollectionIDs(ctx, []int64{plan, chores, trashed, 9999})
+	require.NoError(t, err)
+	require.Equal(t, map[int64]int64{plan: workID, chores: homeID, trashed: homeID}, collectionIDs)
+
+	collectionIDs, err = service.GetNoteCollectionIDs(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, collectionIDs)
+}
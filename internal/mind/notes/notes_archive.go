@@ -0,0 +1,232 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// archiveRootPath is the system collection holding one archive/YYYY collection per year.
+const archiveRootPath = "archive"
+
+// archivedAtMetaKey is the note_meta key holding when a note was archived (RFC 3339, UTC).
+const archivedAtMetaKey = "archived_at"
+
+// isArchivePath reports whether a collection path is the archive root or below it.
+func isArchivePath(path string) bool {
+	return path == archiveRootPath || strings.HasPrefix(path, archiveRootPath+"/")
+}
+
+// ArchiveNote moves a note to the archive/YYYY system collection for the current year,
+// creating it if needed, and records archived_at in its metadata.
+// The note's updated_at and version are left unchanged. Archiving an archived note is a no-op.
+func (s *NotesService) ArchiveNote(ctx context.Context, noteID int64) error {
+	note, err := s.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.store.GetCollectionByID(ctx, note.CollectionID)
+	if err != nil {
+		s.logger.Error("failed to get note collection", "note_id", noteID, "collection_id", note.CollectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if isArchivePath(current.Path) {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+	now := time.Now().UTC()
+
+	archiveID, err := ensureArchiveCollection(ctx, txStore, now.Year())
+	if err != nil {
+		s.logger.Error("failed to ensure archive collection", "year", now.Year(), "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	result, err := txStore.ArchiveNoteByID(ctx, store.ArchiveNoteByIDParams{
+		ID:           noteID,
+		CollectionID: archiveID,
+	})
+	if err != nil {
+		s.logger.Error("failed to archive note", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrNoteNotFound
+	}
+
+	if err := txStore.UpsertNoteMeta(ctx, store.UpsertNoteMetaParams{
+		NoteID: noteID,
+		Key:    archivedAtMetaKey,
+		Value:  utils.NullString(now.Format(time.RFC3339)),
+	}); err != nil {
+		s.logger.Error("failed to set archived_at", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("note archived", "note_id", noteID, "from_collection_id", note.CollectionID, "archive_collection_id", archiveID)
+	s.afterCollectionMove(ctx, noteID, note.CollectionID, archiveID)
+	return nil
+}
+
+// UnarchiveNote moves an archived note to collectionID and clears archived_at.
+// Returns ErrNoteNotArchived if the note is not in an archive collection,
+// ErrInvalidCollectionID if the target doesn't exist, and ErrArchiveCollection if it is an archive collection.
+func (s *NotesService) UnarchiveNote(ctx context.Context, noteID, collectionID int64) error {
+	note, err := s.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.store.GetCollectionByID(ctx, note.CollectionID)
+	if err != nil {
+		s.logger.Error("failed to get note collection", "note_id", noteID, "collection_id", note.CollectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if !isArchivePath(current.Path) {
+		return ErrNoteNotArchived
+	}
+
+	target, err := s.store.GetCollectionByID(ctx, collectionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidCollectionID
+		}
+		s.logger.Error("failed to get target collection", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if isArchivePath(target.Path) {
+		return ErrArchiveCollection
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	result, err := txStore.UnarchiveNoteByID(ctx, store.UnarchiveNoteByIDParams{
+		ID:           noteID,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		s.logger.Error("failed to unarchive note", "note_id", noteID, "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrNoteNotFound
+	}
+
+	if err := txStore.DeleteNoteMetaByKey(ctx, store.DeleteNoteMetaByKeyParams{
+		NoteID: noteID,
+		Key:    archivedAtMetaKey,
+	}); err != nil {
+		s.logger.Error("failed to clear archived_at", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("note unarchived", "note_id", noteID, "collection_id", collectionID)
+	s.afterCollectionMove(ctx, noteID, note.CollectionID, collectionID)
+	return nil
+}
+
+// ArchiveInactiveNotes archives every live, non-template note not updated within inactiveFor.
+// Failures on single notes are logged and skipped. Returns the number of notes archived.
+func (s *NotesService) ArchiveInactiveNotes(ctx context.Context, inactiveFor time.Duration) (int, error) {
+	cutoff := fmt.Sprintf("-%d seconds", int64(inactiveFor.Seconds()))
+	ids, err := s.store.ListInactiveNoteIDs(ctx, cutoff)
+	if err != nil {
+		s.logger.Error("failed to list inactive notes", "inactive_for", inactiveFor, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	archived := 0
+	for _, id := range ids {
+		if err := s.ArchiveNote(ctx, id); err != nil {
+			s.logger.Warn("failed to archive inactive note", "note_id", id, "err", err)
+			continue
+		}
+		archived++
+	}
+
+	if archived > 0 {
+		middleware.ContextLogger(ctx, s.logger).Info("archived inactive notes", "count", archived, "inactive_for", inactiveFor)
+	}
+	return archived, nil
+}
+
+// afterCollectionMove invalidates cached counts and notifies subscribers after a note changed collection.
+func (s *NotesService) afterCollectionMove(ctx context.Context, noteID, fromID, toID int64) {
+	s.invalidateCollections(fromID, toID)
+
+	if s.scheduler != nil {
+		s.scheduler.TrackChange("note_updated", noteID)
+	}
+
+	if s.eventHub != nil {
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, noteID)
+	}
+}
+
+// ensureArchiveCollection returns the ID of the archive/YYYY system collection, creating it
+// (and the archive root) if needed.
+func ensureArchiveCollection(ctx context.Context, q *store.Queries, year int) (int64, error) {
+	rootID, err := ensureSystemCollection(ctx, q, archiveRootPath, archiveRootPath, nil)
+	if err != nil {
+		return 0, err
+	}
+	name := strconv.Itoa(year)
+	return ensureSystemCollection(ctx, q, name, archiveRootPath+"/"+name, rootID)
+}
+
+// ensureSystemCollection looks a collection up by path and creates it as a system collection if missing.
+func ensureSystemCollection(ctx context.Context, q *store.Queries, name, path string, parentID interface{}) (int64, error) {
+	collection, err := q.GetCollectionByPath(ctx, path)
+	if err == nil {
+		return collection.ID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	return q.CreateCollection(ctx, store.CreateCollectionParams{
+		Name:     name,
+		ParentID: parentID,
+		Path:     path,
+		IsSystem: true,
+	})
+}
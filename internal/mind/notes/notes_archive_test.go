@@ -0,0 +1,80 @@
+package notes
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// ageNote sets a note's updated_at to days ago.
+func ageNote(t *testing.T, service *NotesService, noteID int64, days int) {
+	t.Helper()
+
+	_, err := service.db.Exec("UPDATE notes SET updated_at = datetime('now', ?) WHERE id = ?", "-"+strconv.Itoa(days)+" days", noteID)
+	require.NoError(t, err)
+}
+
+func TestArchiveInactiveNotes_MovesOnlyNotesPastThreshold(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	staleID := createTestNote(t, service, "Stale", "old", collectionID)
+	freshID := createTestNote(t, service, "Fresh", "new", collectionID)
+	ageNote(t, service, staleID, 400)
+	ageNote(t, service, freshID, 100)
+
+	archived, err := service.ArchiveInactiveNotes(ctx, 365*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, 1, archived)
+
+	archive, err := queries.GetCollectionByPath(ctx, "archive/"+strconv.Itoa(time.Now().UTC().Year()))
+	require.NoError(t, err)
+	require.True(t, archive.IsSystem)
+
+	stale, err := queries.GetNoteByID(ctx, staleID)
+	require.NoError(t, err)
+	require.Equal(t, archive.ID, stale.CollectionID)
+	archivedAt, err := time.Parse(time.RFC3339, noteMeta(t, queries, staleID)["archived_at"])
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), archivedAt, time.Minute)
+
+	fresh, err := queries.GetNoteByID(ctx, freshID)
+	require.NoError(t, err)
+	require.Equal(t, collectionID, fresh.CollectionID)
+
+	// Archived notes keep their age but are not archived again
+	archived, err = service.ArchiveInactiveNotes(ctx, 365*24*time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, archived)
+}
+
+func TestUnarchiveNote(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Old", "old", collectionID)
+
+	require.ErrorIs(t, service.UnarchiveNote(ctx, noteID, collectionID), ErrNoteNotArchived)
+
+	require.NoError(t, service.ArchiveNote(ctx, noteID))
+	require.NoError(t, service.ArchiveNote(ctx, noteID), "archiving twice is a no-op")
+
+	archive, err := queries.GetCollectionByPath(ctx, "archive")
+	require.NoError(t, err)
+	require.ErrorIs(t, service.UnarchiveNote(ctx, noteID, archive.ID), ErrArchiveCollection)
+	require.ErrorIs(t, service.UnarchiveNote(ctx, noteID, 9999), ErrInvalidCollectionID)
+
+	require.NoError(t, service.UnarchiveNote(ctx, noteID, collectionID))
+
+	note, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.Equal(t, collectionID, note.CollectionID)
+	require.NotContains(t, noteMeta(t, queries, noteID), "archived_at")
+
+	require.ErrorIs(t, service.ArchiveNote(ctx, 9999), ErrNoteNotFound)
+}
@@ -8,6 +8,8 @@ import (
 	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
 	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
 	"github.com/nkapatos/mindweaver/shared/utils"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -50,6 +52,18 @@ func StoreNotesToProto(notes []store.Note) []*mindv3.Note {
 	return result
 }
 
+// NotesWithDistanceToProto converts reachable notes to proto proximity results.
+func NotesWithDistanceToProto(notes []NoteWithDistance) []*mindv3.ProximityResult {
+	results := make([]*mindv3.ProximityResult, len(notes))
+	for i, n := range notes {
+		results[i] = &mindv3.ProximityResult{
+			Note:     StoreNoteToProto(n.Note),
+			Distance: int32(n.Distance),
+		}
+	}
+	return results
+}
+
 // ProtoCreateNoteToStore converts a CreateNoteRequest to store params.
 // Generates a new UUID for the note. Defaults collectionID to DefaultCollectionID if not specified.
 func ProtoCreateNoteToStore(req *mindv3.CreateNoteRequest) store.CreateNoteParams {
@@ -192,64 +206,46 @@ func ProtoNewNoteToParams(req *mindv3.NewNoteRequest) (collectionID, templateID
 // ApplyFieldMask applies field masking to a Note proto message.
 // If fieldMask is empty, returns the note unchanged (all fields).
 // Otherwise, returns a new Note with only the requested fields populated.
-// Field names are comma-separated (e.g., "id,title,collectionId").
+// Field names are comma-separated (e.g., "id,title,collectionId"); see ApplyFieldMaskToNote.
 func ApplyFieldMask(note *mindv3.Note, fieldMask string) *mindv3.Note {
 	if fieldMask == "" {
 		return note
 	}
+	return ApplyFieldMaskToNote(note, &fieldmaskpb.FieldMask{Paths: strings.Split(fieldMask, ",")})
+}
 
-	// Parse field mask into set
-	fields := make(map[string]bool)
-	for _, field := range strings.Split(fieldMask, ",") {
-		fields[strings.TrimSpace(field)] = true
-	}
-
-	// Create new note with only requested fields
-	masked := &mindv3.Note{}
+// fieldMaskAliases maps column-style names clients commonly send to Note field names.
+var fieldMaskAliases = map[string]string{
+	"created_at": "create_time",
+	"updated_at": "update_time",
+}
 
-	if fields["id"] {
-		masked.Id = note.Id
-	}
-	if fields["uuid"] {
-		masked.Uuid = note.Uuid
-	}
-	if fields["name"] {
-		masked.Name = note.Name
-	}
-	if fields["title"] {
-		masked.Title = note.Title
-	}
-	if fields["body"] {
-		masked.Body = note.Body
-	}
-	if fields["description"] {
-		masked.Description = note.Description
-	}
-	if fields["noteTypeId"] || fields["note_type_id"] {
-		masked.NoteTypeId = note.NoteTypeId
-	}
-	if fields["collectionId"] || fields["collection_id"] {
-		masked.CollectionId = note.CollectionId
-	}
-	if fields["isTemplate"] || fields["is_template"] {
-		masked.IsTemplate = note.IsTemplate
-	}
-	if fields["etag"] {
-		masked.Etag = note.Etag
-	}
-	if fields["createTime"] || fields["create_time"] {
-		masked.CreateTime = note.CreateTime
-	}
-	if fields["updateTime"] || fields["update_time"] {
-		masked.UpdateTime = note.UpdateTime
-	}
-	if fields["metadata"] {
-		masked.Metadata = note.Metadata
+// ApplyFieldMaskToNote returns a new Note with only the fields named in mask populated.
+// Paths may use proto names (update_time), JSON names (updateTime) or the created_at and
+// updated_at aliases. Nested paths (e.g., "meta.author") and unknown fields are ignored.
+// A nil or empty mask returns the note unchanged.
+func ApplyFieldMaskToNote(note *mindv3.Note, mask *fieldmaskpb.FieldMask) *mindv3.Note {
+	if note == nil || len(mask.GetPaths()) == 0 {
+		return note
 	}
-	if fields["collectionPath"] || fields["collection_path"] {
-		masked.CollectionPath = note.CollectionPath
+
+	fields := make(map[string]bool, len(mask.GetPaths()))
+	for _, path := range mask.GetPaths() {
+		path = strings.TrimSpace(path)
+		if alias, ok := fieldMaskAliases[path]; ok {
+			path = alias
+		}
+		fields[path] = true
 	}
 
+	masked := &mindv3.Note{}
+	dst := masked.ProtoReflect()
+	note.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fields[string(fd.Name())] || fields[fd.JSONName()] {
+			dst.Set(fd, v)
+		}
+		return true
+	})
 	return masked
 }
 
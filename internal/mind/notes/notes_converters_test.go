@@ -0,0 +1,88 @@
+package notes
+
+import (
+	"context"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+)
+
+func testProtoNote() *mindv3.Note {
+	body := "# Body"
+	return &mindv3.Note{
+		Name:         "notes/7",
+		Id:           7,
+		Title:        "Title",
+		Body:         &body,
+		CollectionId: 1,
+		Etag:         `W/"abc"`,
+		UpdateTime:   timestamppb.Now(),
+		Metadata:     map[string]string{"author": "Jane"},
+	}
+}
+
+func TestApplyFieldMaskToNote(t *testing.T) {
+	note := testProtoNote()
+
+	masked := ApplyFieldMaskToNote(note, &fieldmaskpb.FieldMask{Paths: []string{"id", "title", "body", "updated_at"}})
+	require.Equal(t, int64(7), masked.Id)
+	require.Equal(t, "Title", masked.Title)
+	require.Equal(t, "# Body", masked.GetBody())
+	require.Equal(t, note.UpdateTime.AsTime(), masked.UpdateTime.AsTime())
+	require.Empty(t, masked.Name)
+	require.Empty(t, masked.Etag)
+	require.Zero(t, masked.CollectionId)
+	require.Empty(t, masked.Metadata)
+
+	// JSON names work too; nested and unknown paths are ignored
+	masked = ApplyFieldMaskToNote(note, &fieldmaskpb.FieldMask{Paths: []string{"collectionId", "meta.author", "bogus"}})
+	require.Equal(t, int64(1), masked.CollectionId)
+	require.Zero(t, masked.Id)
+	require.Empty(t, masked.Metadata)
+
+	// No mask returns everything
+	require.Same(t, note, ApplyFieldMaskToNote(note, nil))
+	require.Same(t, note, ApplyFieldMaskToNote(note, &fieldmaskpb.FieldMask{}))
+
+	// The input is left untouched
+	require.Equal(t, "notes/7", note.Name)
+	require.Equal(t, "Jane", note.Metadata["author"])
+}
+
+func TestNotesHandler_FieldMask(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Masked", "---\nauthor: Jane\n---\n# Body", collectionID)
+
+	handler := NewNotesHandler(service, nil, nil, nil)
+	mask := "id,title"
+
+	resp, err := handler.GetNote(ctx, connect.NewRequest(&mindv3.GetNoteRequest{Id: noteID, FieldMask: &mask}))
+	require.NoError(t, err)
+	require.Equal(t, noteID, resp.Msg.Id)
+	require.Equal(t, "Masked", resp.Msg.Title)
+	require.Equal(t, "", resp.Msg.GetBody())
+	require.Empty(t, resp.Msg.Metadata)
+
+	resp, err = handler.GetNote(ctx, connect.NewRequest(&mindv3.GetNoteRequest{Id: noteID}))
+	require.NoError(t, err)
+	require.Contains(t, resp.Msg.GetBody(), "# Body")
+
+	list, err := handler.ListNotes(ctx, connect.NewRequest(&mindv3.ListNotesRequest{FieldMask: &mask}))
+	require.NoError(t, err)
+	require.NotEmpty(t, list.Msg.Notes)
+	for _, note := range list.Msg.Notes {
+		require.NotZero(t, note.Id)
+		require.NotEmpty(t, note.Title)
+		require.Equal(t, "", note.GetBody())
+		require.Empty(t, note.Metadata)
+		require.Empty(t, note.Etag)
+	}
+}
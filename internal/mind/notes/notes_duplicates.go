@@ -0,0 +1,175 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// DefaultDuplicateThreshold is the title similarity used when none is given.
+const DefaultDuplicateThreshold = 0.9
+
+// duplicateLengthTolerance bounds the title length ratio of compared notes (±20%).
+// Titles further apart in length cannot reach a useful similarity anyway.
+const duplicateLengthTolerance = 0.2
+
+// DuplicateGroup is a set of notes with near-identical titles.
+type DuplicateGroup struct {
+	Notes      []store.Note
+	Similarity float64 // Lowest similarity among the matched title pairs in the group
+}
+
+// FindDuplicates groups live notes of a collection whose titles are at least threshold
+// similar (1 - Levenshtein distance / longer title length, case-insensitive).
+// Groups are transitive: if A~B and B~C, all three are returned together.
+// Groups are ordered by descending similarity.
+func (s *NotesService) FindDuplicates(ctx context.Context, collectionID int64, threshold float64) ([]DuplicateGroup, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+
+	notes, err := s.store.ListNotesByCollectionID(ctx, collectionID)
+	if err != nil {
+		s.logger.Error("failed to list notes for duplicate detection", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	type candidate struct {
+		title  string
+		length int
+	}
+	candidates := make([]candidate, len(notes))
+	order := make([]int, len(notes))
+	for i, n := range notes {
+		title := strings.ToLower(strings.TrimSpace(n.Title))
+		candidates[i] = candidate{title: title, length: utf8.RuneCountInString(title)}
+		order[i] = i
+	}
+	// Sorting by length lets each note stop comparing once titles get too long
+	slices.SortStableFunc(order, func(a, b int) int {
+		return candidates[a].length - candidates[b].length
+	})
+
+	// Union-find over note indexes; weakest tracks the lowest joining similarity per root
+	parent := make([]int, len(notes))
+	weakest := make([]float64, len(notes))
+	for i := range parent {
+		parent[i] = i
+		weakest[i] = 1
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for x, i := range order {
+		maxLength := float64(candidates[i].length) * (1 + duplicateLengthTolerance)
+		for _, j := range order[x+1:] {
+			if float64(candidates[j].length) > maxLength {
+				break
+			}
+			similarity := utils.Similarity(candidates[i].title, candidates[j].title)
+			if similarity < threshold {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			if ri != rj {
+				parent[rj] = ri
+				weakest[ri] = min(weakest[ri], weakest[rj])
+			}
+			weakest[ri] = min(weakest[ri], similarity)
+		}
+	}
+
+	members := make(map[int][]store.Note)
+	for i, n := range notes {
+		root := find(i)
+		members[root] = append(members[root], n)
+	}
+
+	var groups []DuplicateGroup
+	for root, group := range members {
+		if len(group) > 1 {
+			groups = append(groups, DuplicateGroup{Notes: group, Similarity: weakest[root]})
+		}
+	}
+	slices.SortFunc(groups, func(a, b DuplicateGroup) int {
+		if a.Similarity != b.Similarity {
+			if a.Similarity > b.Similarity {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Notes[0].Title, b.Notes[0].Title)
+	})
+
+	s.logger.Debug("duplicate notes found", "collection_id", collectionID, "threshold", threshold, "groups", len(groups), "request_id", middleware.GetRequestID(ctx))
+	return groups, nil
+}
+
+// DuplicateNote is a note reported by GET /notes:findDuplicates.
+type DuplicateNote struct {
+	ID    int64  `json:"id"`
+	UUID  string `json:"uuid"`
+	Title string `json:"title"`
+}
+
+// DuplicateGroupResponse is a group reported by GET /notes:findDuplicates.
+type DuplicateGroupResponse struct {
+	Similarity float64         `json:"similarity"`
+	Notes      []DuplicateNote `json:"notes"`
+}
+
+// FindDuplicatesResponse is the body of GET /notes:findDuplicates.
+type FindDuplicatesResponse struct {
+	Groups []DuplicateGroupResponse `json:"groups"`
+}
+
+// FindDuplicatesHandler serves GET /notes:findDuplicates?collection_id=&threshold=.
+// threshold is optional and defaults to DefaultDuplicateThreshold.
+func FindDuplicatesHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		collectionID, err := strconv.ParseInt(c.QueryParam("collection_id"), 10, 64)
+		if err != nil || collectionID <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "collection_id is required")
+		}
+
+		threshold := DefaultDuplicateThreshold
+		if raw := c.QueryParam("threshold"); raw != "" {
+			threshold, err = strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "threshold must be a number")
+			}
+		}
+
+		groups, err := service.FindDuplicates(c.Request().Context(), collectionID, threshold)
+		if err != nil {
+			if errors.Is(err, ErrInvalidThreshold) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to find duplicates")
+		}
+
+		resp := FindDuplicatesResponse{Groups: make([]DuplicateGroupResponse, len(groups))}
+		for i, g := range groups {
+			resp.Groups[i] = DuplicateGroupResponse{Similarity: g.Similarity, Notes: make([]DuplicateNote, len(g.Notes))}
+			for j, n := range g.Notes {
+				resp.Groups[i].Notes[j] = DuplicateNote{ID: n.ID, UUID: n.Uuid.String(), Title: n.Title}
+			}
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,75 @@
+package notes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// groupTitles returns the note titles of each duplicate group.
+func groupTitles(groups []DuplicateGroup) [][]string {
+	titles := make([][]string, len(groups))
+	for i, g := range groups {
+		for _, n := range g.Notes {
+			titles[i] = append(titles[i], n.Title)
+		}
+	}
+	return titles
+}
+
+func TestFindDuplicates_GroupsSimilarTitles(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	createTestNote(t, service, "Golang Guide", "", collectionID)
+	createTestNote(t, service, "Golang Guides", "", collectionID)
+	createTestNote(t, service, "Rust Book", "", collectionID)
+
+	groups, err := service.FindDuplicates(ctx, collectionID, 0.9)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.ElementsMatch(t, []string{"Golang Guide", "Golang Guides"}, groupTitles(groups)[0])
+	require.InDelta(t, 1-1.0/13, groups[0].Similarity, 1e-9)
+}
+
+func TestFindDuplicates_TransitiveAndCaseInsensitive(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	createTestNote(t, service, "Meeting Notes 1", "", collectionID)
+	createTestNote(t, service, "meeting notes 2", "", collectionID)
+	createTestNote(t, service, "Meeting Notes 12", "", collectionID)
+	createTestNote(t, service, "Groceries", "", collectionID)
+
+	groups, err := service.FindDuplicates(ctx, collectionID, 0.9)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0].Notes, 3)
+}
+
+func TestFindDuplicates_OnlyWithinCollection(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, queries, "Work")
+	home := createTestCollection(t, queries, "Home")
+	createTestNote(t, service, "Golang Guide", "", work)
+	createTestNote(t, service, "Golang Guides", "", home)
+
+	groups, err := service.FindDuplicates(ctx, work, 0.9)
+	require.NoError(t, err)
+	require.Empty(t, groups)
+}
+
+func TestFindDuplicates_InvalidThreshold(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	for _, threshold := range []float64{0, -0.5, 1.5} {
+		_, err := service.FindDuplicates(ctx, 1, threshold)
+		require.ErrorIs(t, err, ErrInvalidThreshold)
+	}
+}
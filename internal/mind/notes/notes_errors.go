@@ -10,18 +10,59 @@ var (
 	// ErrNoteAlreadyExists is returned when a note with the same UUID already exists.
 	ErrNoteAlreadyExists = errors.New("note already exists")
 
+	// ErrNoteTrashed is returned when upserting a note whose UUID belongs to a trashed note.
+	// The note has to be restored or permanently deleted first.
+	ErrNoteTrashed = errors.New("note with this uuid is in the trash")
+
 	// ErrInvalidCollectionID is returned when collection_id references a non-existent collection.
 	ErrInvalidCollectionID = errors.New("invalid collection id")
 
 	// ErrInvalidNoteTypeID is returned when note_type_id references a non-existent note type.
 	ErrInvalidNoteTypeID = errors.New("invalid note type id")
 
+	// ErrTemplateStarterNote is returned when permanently deleting a note that a template starts from.
+	ErrTemplateStarterNote = errors.New("note is the starter note of a template")
+
 	// ErrStaleNote is returned when the note version doesn't match (optimistic locking failure).
 	ErrStaleNote = errors.New("note has been modified by another request")
 
 	// ErrInvalidTitle is returned when the title is empty or exceeds max length.
 	ErrInvalidTitle = errors.New("invalid title")
 
+	// ErrBodyTooLarge is returned when the body exceeds the configured maximum size.
+	ErrBodyTooLarge = errors.New("note body too large")
+
 	// ErrInvalidDescription is returned when the description exceeds max length.
 	ErrInvalidDescription = errors.New("invalid description")
+
+	// ErrInvalidSortBy is returned when a list sort order is not recognized.
+	ErrInvalidSortBy = errors.New("invalid sort order")
+
+	// ErrInvalidPinPosition is returned when a pin position is negative.
+	ErrInvalidPinPosition = errors.New("invalid pin position")
+
+	// ErrNoteNotArchived is returned when unarchiving a note that is not in an archive collection.
+	ErrNoteNotArchived = errors.New("note is not archived")
+
+	// ErrArchiveCollection is returned when unarchiving into an archive collection.
+	ErrArchiveCollection = errors.New("cannot unarchive into an archive collection")
+
+	// ErrActorRequired is returned when an actor-scoped operation gets no actor ID.
+	ErrActorRequired = errors.New("actor id is required")
+
+	// ErrNoteNotFavorited is returned when a note is not one of the actor's favorites.
+	ErrNoteNotFavorited = errors.New("note is not a favorite")
+
+	// ErrInvalidReorder is returned when a reorder list is empty or contains duplicate IDs.
+	ErrInvalidReorder = errors.New("reorder requires distinct note ids")
+
+	// ErrInvalidDays is returned when a recent notes window is not a positive number of days.
+	ErrInvalidDays = errors.New("days must be positive")
+
+	// ErrInvalidThreshold is returned when a duplicate similarity threshold is not in (0, 1].
+	ErrInvalidThreshold = errors.New("threshold must be greater than 0 and at most 1")
+
+	// ErrSchemaValidationFailed is returned when a note's frontmatter doesn't match its note type's schema.
+	// The wrapped notetypes.ValidationErrors lists the offending keys.
+	ErrSchemaValidationFailed = errors.New("frontmatter does not match the note type schema")
 )
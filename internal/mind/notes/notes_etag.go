@@ -0,0 +1,59 @@
+package notes
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/gen/proto/mind/v3/mindv3connect"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/interceptors"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// noteVersions resolves note versions for interceptors.NewETagInterceptor.
+type noteVersions struct {
+	service *NotesService
+}
+
+// ResourceVersion returns the version of the note targeted by a Replace or Delete request.
+func (v noteVersions) ResourceVersion(ctx context.Context, req connect.AnyRequest) (int64, bool, error) {
+	var id int64
+	switch msg := req.Any().(type) {
+	case *mindv3.ReplaceNoteRequest:
+		id = msg.Id
+	case *mindv3.DeleteNoteRequest:
+		id = msg.Id
+	default:
+		return 0, false, nil
+	}
+
+	note, err := v.service.GetNoteByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrNoteNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return note.Version, true, nil
+}
+
+// ETagResources returns the note procedures guarded by If-Match, keyed by procedure.
+// UpdateNote is not listed: it checks If-Match itself because it is only required for body changes.
+func ETagResources(service *NotesService) map[string]interceptors.VersionedResource {
+	versions := noteVersions{service: service}
+	return map[string]interceptors.VersionedResource{
+		mindv3connect.NotesServiceReplaceNoteProcedure: versions,
+		mindv3connect.NotesServiceDeleteNoteProcedure:  versions,
+	}
+}
+
+// noteResponse wraps a note in a response whose ETag header carries the note version,
+// so clients can send it back as If-Match without reading the message body.
+func noteResponse(note store.Note) *connect.Response[mindv3.Note] {
+	resp := connect.NewResponse(StoreNoteToProto(note))
+	resp.Header().Set("ETag", utils.ComputeHashedETag(note.Version))
+	return resp
+}
@@ -0,0 +1,160 @@
+package notes
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+//go:embed templates/export.html templates/export.css
+var exportFS embed.FS
+
+var (
+	exportTemplate = template.Must(template.ParseFS(exportFS, "templates/export.html"))
+	exportCSS      = template.CSS(mustReadExportAsset("templates/export.css"))
+)
+
+// exportHTMLAction is the AIP-136 style suffix of the export route (/notes/{id}:exportHTML).
+const exportHTMLAction = ":exportHTML"
+
+// transclusionCycleHTML replaces an embed of a note that is already being rendered higher up.
+const transclusionCycleHTML = `<p class="transclusion-cycle">[cycle detected]</p>`
+
+// exportMeta is a metadata entry rendered as a <meta> tag.
+type exportMeta struct {
+	Key   string
+	Value string
+}
+
+// exportPage is the data for templates/export.html.
+type exportPage struct {
+	Title     string
+	UUID      string
+	CreatedAt string
+	UpdatedAt string
+	Meta      []exportMeta
+	CSS       template.CSS
+	Body      template.HTML
+}
+
+func mustReadExportAsset(name string) string {
+	data, err := exportFS.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// ExportNoteHTML renders a note as a self-contained HTML page for sharing.
+// The stylesheet is inlined and note metadata is emitted as <meta> tags.
+// Raw HTML in the markdown body is omitted, so the output is safe to serve as is.
+// ![[title]] embeds of other notes are inlined (see renderTransclusions).
+func (s *NotesService) ExportNoteHTML(ctx context.Context, id int64) (string, error) {
+	note, err := s.GetNoteByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	if err := s.renderTransclusions(ctx, note, map[int64]bool{}, &body); err != nil {
+		s.logger.Error("failed to render note html", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", err
+	}
+
+	metaRows, err := s.store.GetNoteMetaByNoteID(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to get note meta", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", err
+	}
+
+	page := exportPage{
+		Title: note.Title,
+		UUID:  note.Uuid.String(),
+		Meta:  make([]exportMeta, 0, len(metaRows)),
+		CSS:   exportCSS,
+		Body:  template.HTML(body.String()),
+	}
+	if note.CreatedAt.Valid {
+		page.CreatedAt = note.CreatedAt.Time.UTC().Format(time.RFC3339)
+	}
+	if note.UpdatedAt.Valid {
+		page.UpdatedAt = note.UpdatedAt.Time.UTC().Format(time.RFC3339)
+	}
+	for _, m := range metaRows {
+		page.Meta = append(page.Meta, exportMeta{Key: m.Key, Value: m.Value.String})
+	}
+
+	var out bytes.Buffer
+	if err := exportTemplate.Execute(&out, page); err != nil {
+		s.logger.Error("failed to execute export template", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return "", err
+	}
+
+	return out.String(), nil
+}
+
+// renderTransclusions renders a note body to w, recursively inlining ![[title]] embeds of
+// other notes. rendering holds the notes on the current embed chain: embedding one of them
+// again would recurse forever, so it renders a [cycle detected] placeholder instead.
+// Embeds of unknown titles keep the default wikilink rendering.
+func (s *NotesService) renderTransclusions(ctx context.Context, note store.Note, rendering map[int64]bool, w io.Writer) error {
+	rendering[note.ID] = true
+	defer delete(rendering, note.ID)
+
+	return s.parser.RenderHTMLWithEmbeds([]byte(note.Body.String), w, func(target string) ([]byte, bool, error) {
+		embedded, err := s.store.GetNoteByTitleGlobal(ctx, target)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		if rendering[embedded.ID] {
+			return []byte(transclusionCycleHTML), true, nil
+		}
+
+		var buf bytes.Buffer
+		if err := s.renderTransclusions(ctx, embedded, rendering, &buf); err != nil {
+			return nil, false, err
+		}
+		return buf.Bytes(), true, nil
+	})
+}
+
+// ExportHTMLHandler serves GET /notes/{id}:exportHTML as text/html.
+// It is a plain Echo route because Connect-RPC only speaks protobuf and JSON.
+func ExportHTMLHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rawID, ok := strings.CutSuffix(c.Param("id"), exportHTMLAction)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "unknown note action")
+		}
+		id, err := strconv.ParseInt(rawID, 10, 64)
+		if err != nil || id <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid note id")
+		}
+
+		page, err := service.ExportNoteHTML(c.Request().Context(), id)
+		if err != nil {
+			if errors.Is(err, ErrNoteNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "note not found")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to export note")
+		}
+
+		return c.HTML(http.StatusOK, page)
+	}
+}
@@ -0,0 +1,149 @@
+package notes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+// findElements returns all elements with the given tag name in document order.
+func findElements(n *html.Node, tag string) []*html.Node {
+	var found []*html.Node
+	if n.Type == html.ElementNode && n.Data == tag {
+		found = append(found, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		found = append(found, findElements(c, tag)...)
+	}
+	return found
+}
+
+// attr returns the value of an attribute, or "" if it is not set.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func TestExportNoteHTML_RendersHeadingCodeAndMeta(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	body := "---\nauthor: Jane <jane@example.com>\n---\n## Setup\n\n```go\nfmt.Println(\"<hi>\")\n```\n"
+	id := createTestNote(t, service, "Export Me", body, DefaultCollectionID)
+
+	page, err := service.ExportNoteHTML(ctx, id)
+	require.NoError(t, err)
+
+	doc, err := html.Parse(strings.NewReader(page))
+	require.NoError(t, err)
+
+	require.Len(t, findElements(doc, "style"), 1)
+	require.Equal(t, "Export Me", findElements(doc, "title")[0].FirstChild.Data)
+
+	headings := findElements(doc, "h2")
+	require.Len(t, headings, 1)
+	require.Equal(t, "Setup", headings[0].FirstChild.Data)
+
+	codes := findElements(doc, "code")
+	require.Len(t, codes, 1)
+	require.Equal(t, "language-go", attr(codes[0], "class"))
+	require.Equal(t, "fmt.Println(\"<hi>\")\n", codes[0].FirstChild.Data)
+
+	meta := make(map[string]string)
+	for _, m := range findElements(doc, "meta") {
+		meta[attr(m, "name")] = attr(m, "content")
+	}
+	require.Equal(t, "Jane <jane@example.com>", meta["mindweaver:meta:author"])
+
+	note, err := queries.GetNoteByID(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, note.Uuid.String(), meta["mindweaver:uuid"])
+}
+
+func TestExportNoteHTML_NotFound(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	_, err := service.ExportNoteHTML(context.Background(), 9999)
+	require.ErrorIs(t, err, ErrNoteNotFound)
+}
+
+func TestExportNoteHTML_TransclusionChain(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	createTestNote(t, service, "Leaf", "Leaf **body**", DefaultCollectionID)
+	createTestNote(t, service, "Middle", "Middle body\n\n![[Leaf]]", DefaultCollectionID)
+	id := createTestNote(t, service, "Top", "Top body\n\n![[Middle]]", DefaultCollectionID)
+
+	page, err := service.ExportNoteHTML(context.Background(), id)
+	require.NoError(t, err)
+
+	doc, err := html.Parse(strings.NewReader(page))
+	require.NoError(t, err)
+
+	quotes := findElements(doc, "blockquote")
+	require.Len(t, quotes, 2)
+	for _, q := range quotes {
+		require.Equal(t, "transclusion", attr(q, "class"))
+	}
+	// The leaf is nested inside the middle note's transclusion
+	require.Len(t, findElements(quotes[0], "blockquote"), 2)
+	require.Equal(t, "body", findElements(quotes[1], "strong")[0].FirstChild.Data)
+	require.NotContains(t, page, "[cycle detected]")
+}
+
+func TestExportNoteHTML_TransclusionCycle(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	id := createTestNote(t, service, "A", "A body\n\n![[B]]", DefaultCollectionID)
+	createTestNote(t, service, "B", "B body\n\n![[A]]", DefaultCollectionID)
+
+	page, err := service.ExportNoteHTML(context.Background(), id)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, strings.Count(page, "A body"))
+	require.Equal(t, 1, strings.Count(page, "B body"))
+	require.Contains(t, page, `<blockquote class="transclusion"><p>B body</p>`)
+	require.Contains(t, page, `<blockquote class="transclusion"><p class="transclusion-cycle">[cycle detected]</p></blockquote>`)
+}
+
+func TestExportHTMLHandler(t *testing.T) {
+	service, _ := setupTestService(t)
+	id := createTestNote(t, service, "Shared", "# Hello", DefaultCollectionID)
+
+	e := echo.New()
+	e.GET("/notes/:id", ExportHTMLHandler(service))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+	}{
+		{"exports note", "/notes/" + strconv.FormatInt(id, 10) + ":exportHTML", http.StatusOK},
+		{"unknown action", "/notes/" + strconv.FormatInt(id, 10) + ":exportPDF", http.StatusNotFound},
+		{"invalid id", "/notes/abc:exportHTML", http.StatusBadRequest},
+		{"missing note", "/notes/9999:exportHTML", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				require.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/html"))
+				require.Contains(t, rec.Body.String(), `<h1 id="hello">Hello</h1>`)
+			}
+		})
+	}
+}
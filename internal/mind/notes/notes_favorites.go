@@ -0,0 +1,311 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+const (
+	defaultFavoritesLimit = 50
+	maxFavoritesLimit     = 200
+)
+
+// FavoriteNote adds noteID to the end of actorID's favorites.
+// Favoriting a note twice is a no-op that keeps its position.
+// Returns ErrActorRequired if actorID is empty and ErrNoteNotFound if the note doesn't exist.
+func (s *NotesService) FavoriteNote(ctx context.Context, actorID string, noteID int64) error {
+	if actorID == "" {
+		return ErrActorRequired
+	}
+	if _, err := s.GetNoteByID(ctx, noteID); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	position, err := txStore.GetNextFavoritePosition(ctx, actorID)
+	if err != nil {
+		s.logger.Error("failed to get next favorite position", "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if err := txStore.CreateNoteFavorite(ctx, store.CreateNoteFavoriteParams{
+		ActorID:  actorID,
+		NoteID:   noteID,
+		Position: position,
+	}); err != nil {
+		s.logger.Error("failed to favorite note", "actor_id", actorID, "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return nil
+}
+
+// UnfavoriteNote removes noteID from actorID's favorites.
+// Returns ErrNoteNotFavorited if the note isn't one of the actor's favorites.
+func (s *NotesService) UnfavoriteNote(ctx context.Context, actorID string, noteID int64) error {
+	result, err := s.store.DeleteNoteFavorite(ctx, store.DeleteNoteFavoriteParams{
+		ActorID: actorID,
+		NoteID:  noteID,
+	})
+	if err != nil {
+		s.logger.Error("failed to unfavorite note", "actor_id", actorID, "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return checkFavoriteRowsAffected(result)
+}
+
+// IsFavorited reports whether noteID is one of actorID's favorites.
+func (s *NotesService) IsFavorited(ctx context.Context, actorID string, noteID int64) (bool, error) {
+	favorited, err := s.store.IsNoteFavorited(ctx, store.IsNoteFavoritedParams{
+		ActorID: actorID,
+		NoteID:  noteID,
+	})
+	if err != nil {
+		s.logger.Error("failed to check favorite", "actor_id", actorID, "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return false, err
+	}
+	return favorited != 0, nil
+}
+
+// ListFavorites returns a page of actorID's live favorite notes in their order,
+// and the total number of live favorites.
+func (s *NotesService) ListFavorites(ctx context.Context, actorID string, limit, offset int32) ([]store.Note, int64, error) {
+	notes, err := s.store.ListFavoriteNotes(ctx, store.ListFavoriteNotesParams{
+		ActorID: actorID,
+		Limit:   int64(limit),
+		Offset:  int64(offset),
+	})
+	if err != nil {
+		s.logger.Error("failed to list favorites", "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, 0, err
+	}
+
+	total, err := s.store.CountFavoriteNotes(ctx, actorID)
+	if err != nil {
+		s.logger.Error("failed to count favorites", "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, 0, err
+	}
+	return notes, total, nil
+}
+
+// ReorderFavorites sets the position of actorID's favorites to their index in
+// orderedNoteIDs, in one transaction. Every ID must already be a favorite;
+// favorites left out keep their position.
+// Returns ErrInvalidReorder for an empty list or duplicate IDs and ErrNoteNotFavorited
+// if an ID isn't a favorite of the actor.
+func (s *NotesService) ReorderFavorites(ctx context.Context, actorID string, orderedNoteIDs []int64) error {
+	if len(orderedNoteIDs) == 0 {
+		return ErrInvalidReorder
+	}
+	seen := make(map[int64]bool, len(orderedNoteIDs))
+	for _, id := range orderedNoteIDs {
+		if seen[id] {
+			return ErrInvalidReorder
+		}
+		seen[id] = true
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	for position, noteID := range orderedNoteIDs {
+		result, err := txStore.UpdateNoteFavoritePosition(ctx, store.UpdateNoteFavoritePositionParams{
+			Position: int64(position),
+			ActorID:  actorID,
+			NoteID:   noteID,
+		})
+		if err != nil {
+			s.logger.Error("failed to update favorite position", "actor_id", actorID, "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+		if err := checkFavoriteRowsAffected(result); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("favorites reordered", "actor_id", actorID, "count", len(orderedNoteIDs))
+	return nil
+}
+
+// checkFavoriteRowsAffected returns ErrNoteNotFavorited when a favorite write matched no rows.
+func checkFavoriteRowsAffected(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNoteNotFavorited
+	}
+	return nil
+}
+
+// FavoritedNote identifies a note in a FavoritesResponse.
+type FavoritedNote struct {
+	ID    int64  `json:"id"`
+	UUID  string `json:"uuid"`
+	Title string `json:"title"`
+}
+
+// FavoritesResponse is the JSON body of GET /actors/{id}/favorites.
+type FavoritesResponse struct {
+	Notes []FavoritedNote `json:"notes"`
+	Total int64           `json:"total"`
+}
+
+// ReorderFavoritesRequest is the JSON body of PUT /actors/{id}/favorites:reorder.
+type ReorderFavoritesRequest struct {
+	NoteIDs []int64 `json:"note_ids"`
+}
+
+// ListFavoritesHandler serves GET /actors/{id}/favorites?limit=&offset=.
+func ListFavoritesHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		actorID, err := favoritesActor(c)
+		if err != nil {
+			return err
+		}
+
+		limit, offset := int32(defaultFavoritesLimit), int32(0)
+		if raw := c.QueryParam("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+			}
+			limit = int32(min(parsed, maxFavoritesLimit))
+		}
+		if raw := c.QueryParam("offset"); raw != "" {
+			parsed, err := strconv.ParseInt(raw, 10, 32)
+			if err != nil || parsed < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid offset")
+			}
+			offset = int32(parsed)
+		}
+
+		notes, total, err := service.ListFavorites(c.Request().Context(), actorID, limit, offset)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list favorites")
+		}
+
+		resp := FavoritesResponse{Notes: make([]FavoritedNote, len(notes)), Total: total}
+		for i, n := range notes {
+			resp.Notes[i] = FavoritedNote{ID: n.ID, UUID: n.Uuid.String(), Title: n.Title}
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
+
+// FavoriteNoteHandler serves PUT /actors/{id}/favorites/{note_id}.
+func FavoriteNoteHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		actorID, err := favoritesActor(c)
+		if err != nil {
+			return err
+		}
+		noteID, err := strconv.ParseInt(c.Param("note_id"), 10, 64)
+		if err != nil || noteID <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid note id")
+		}
+
+		if err := service.FavoriteNote(c.Request().Context(), actorID, noteID); err != nil {
+			if errors.Is(err, ErrNoteNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "note not found")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to favorite note")
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// UnfavoriteNoteHandler serves DELETE /actors/{id}/favorites/{note_id}.
+func UnfavoriteNoteHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		actorID, err := favoritesActor(c)
+		if err != nil {
+			return err
+		}
+		noteID, err := strconv.ParseInt(c.Param("note_id"), 10, 64)
+		if err != nil || noteID <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid note id")
+		}
+
+		if err := service.UnfavoriteNote(c.Request().Context(), actorID, noteID); err != nil {
+			if errors.Is(err, ErrNoteNotFavorited) {
+				return echo.NewHTTPError(http.StatusNotFound, "note is not a favorite")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to unfavorite note")
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ReorderFavoritesHandler serves PUT /actors/{id}/favorites:reorder.
+func ReorderFavoritesHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		actorID, err := favoritesActor(c)
+		if err != nil {
+			return err
+		}
+
+		var req ReorderFavoritesRequest
+		if err := c.Bind(&req); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+
+		if err := service.ReorderFavorites(c.Request().Context(), actorID, req.NoteIDs); err != nil {
+			switch {
+			case errors.Is(err, ErrInvalidReorder), errors.Is(err, ErrNoteNotFavorited):
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			default:
+				return echo.NewHTTPError(http.StatusInternalServerError, "failed to reorder favorites")
+			}
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// favoritesActor returns the actor ID path parameter. Favorites are private: the caller
+// must be authenticated and may only access their own.
+func favoritesActor(c echo.Context) (string, error) {
+	actorID := c.Param("id")
+	if actorID == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "actor id is required")
+	}
+	caller := middleware.GetActorID(c.Request().Context())
+	if caller == "" {
+		return "", echo.NewHTTPError(http.StatusUnauthorized, "authentication required")
+	}
+	if caller != actorID {
+		return "", echo.NewHTTPError(http.StatusForbidden, "cannot access another actor's favorites")
+	}
+	return actorID, nil
+}
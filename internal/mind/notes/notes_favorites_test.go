@@ -0,0 +1,176 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+func TestFavorites_AddRemoveAndList(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	first := createTestNote(t, service, "First", "# First", collectionID)
+	second := createTestNote(t, service, "Second", "# Second", collectionID)
+	third := createTestNote(t, service, "Third", "# Third", collectionID)
+
+	require.NoError(t, service.FavoriteNote(ctx, "alice", second))
+	require.NoError(t, service.FavoriteNote(ctx, "alice", first))
+	require.NoError(t, service.FavoriteNote(ctx, "alice", third))
+	require.NoError(t, service.FavoriteNote(ctx, "alice", second), "favoriting twice is a no-op")
+	require.NoError(t, service.FavoriteNote(ctx, "bob", first))
+
+	// Favorites keep the order they were added in
+	notes, total, err := service.ListFavorites(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+	require.Equal(t, []int64{second, first, third}, noteIDs(notes))
+
+	notes, total, err = service.ListFavorites(ctx, "alice", 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+	require.Equal(t, []int64{first}, noteIDs(notes))
+
+	favorited, err := service.IsFavorited(ctx, "bob", first)
+	require.NoError(t, err)
+	require.True(t, favorited)
+	favorited, err = service.IsFavorited(ctx, "bob", second)
+	require.NoError(t, err)
+	require.False(t, favorited)
+
+	require.NoError(t, service.UnfavoriteNote(ctx, "alice", first))
+	require.ErrorIs(t, service.UnfavoriteNote(ctx, "alice", first), ErrNoteNotFavorited)
+
+	notes, total, err = service.ListFavorites(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total)
+	require.Equal(t, []int64{second, third}, noteIDs(notes))
+
+	// Trashed notes drop out of the list
+	require.NoError(t, service.DeleteNote(ctx, third))
+	notes, total, err = service.ListFavorites(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, []int64{second}, noteIDs(notes))
+}
+
+func TestFavorites_Errors(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Note", "# Note", collectionID)
+
+	require.ErrorIs(t, service.FavoriteNote(ctx, "alice", 9999), ErrNoteNotFound)
+	require.ErrorIs(t, service.FavoriteNote(ctx, "", noteID), ErrActorRequired)
+
+	notes, total, err := service.ListFavorites(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	require.Zero(t, total)
+	require.Empty(t, notes)
+}
+
+func TestReorderFavorites(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	a := createTestNote(t, service, "A", "# A", collectionID)
+	b := createTestNote(t, service, "B", "# B", collectionID)
+	c := createTestNote(t, service, "C", "# C", collectionID)
+	other := createTestNote(t, service, "Other", "# Other", collectionID)
+	for _, id := range []int64{a, b, c} {
+		require.NoError(t, service.FavoriteNote(ctx, "alice", id))
+	}
+
+	require.NoError(t, service.ReorderFavorites(ctx, "alice", []int64{c, a, b}))
+	notes, _, err := service.ListFavorites(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int64{c, a, b}, noteIDs(notes))
+
+	// New favorites go to the end of the reordered list
+	require.NoError(t, service.FavoriteNote(ctx, "alice", other))
+	notes, _, err = service.ListFavorites(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int64{c, a, b, other}, noteIDs(notes))
+
+	require.ErrorIs(t, service.ReorderFavorites(ctx, "alice", nil), ErrInvalidReorder)
+	require.ErrorIs(t, service.ReorderFavorites(ctx, "alice", []int64{a, a}), ErrInvalidReorder)
+
+	// A non-favorite rolls back the whole reorder
+	require.ErrorIs(t, service.ReorderFavorites(ctx, "bob", []int64{b}), ErrNoteNotFavorited)
+	require.ErrorIs(t, service.ReorderFavorites(ctx, "alice", []int64{b, a, 9999}), ErrNoteNotFavorited)
+	notes, _, err = service.ListFavorites(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []int64{c, a, b, other}, noteIDs(notes))
+}
+
+func TestFavoritesHandlers(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	collectionID := createTestCollection(t, queries, "Work")
+	a := createTestNote(t, service, "A", "# A", collectionID)
+	b := createTestNote(t, service, "B", "# B", collectionID)
+
+	e := echo.New()
+	asActor := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if actorID := c.Request().Header.Get("X-Actor"); actorID != "" {
+				req := c.Request()
+				c.SetRequest(req.WithContext(middleware.WithActorID(req.Context(), actorID)))
+			}
+			return next(c)
+		}
+	}
+	e.GET("/actors/:id/favorites", ListFavoritesHandler(service), asActor)
+	e.PUT("/actors/:id/favorites\\:reorder", ReorderFavoritesHandler(service), asActor)
+	e.PUT("/actors/:id/favorites/:note_id", FavoriteNoteHandler(service), asActor)
+	e.DELETE("/actors/:id/favorites/:note_id", UnfavoriteNoteHandler(service), asActor)
+
+	send := func(method, target, actorID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if actorID != "" {
+			req.Header.Set("X-Actor", actorID)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	itoa := func(id int64) string { return strconv.FormatInt(id, 10) }
+
+	require.Equal(t, http.StatusNoContent, send(http.MethodPut, "/actors/alice/favorites/"+itoa(a), "alice", "").Code)
+	require.Equal(t, http.StatusNoContent, send(http.MethodPut, "/actors/alice/favorites/"+itoa(b), "alice", "").Code)
+	require.Equal(t, http.StatusNotFound, send(http.MethodPut, "/actors/alice/favorites/9999", "alice", "").Code)
+	require.Equal(t, http.StatusForbidden, send(http.MethodPut, "/actors/alice/favorites/"+itoa(a), "bob", "").Code)
+
+	// Anonymous callers can't read or change anyone's favorites
+	require.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/actors/alice/favorites", "", "").Code)
+	require.Equal(t, http.StatusUnauthorized, send(http.MethodPut, "/actors/alice/favorites/"+itoa(a), "", "").Code)
+	require.Equal(t, http.StatusUnauthorized, send(http.MethodDelete, "/actors/alice/favorites/"+itoa(a), "", "").Code)
+	require.Equal(t, http.StatusUnauthorized, send(http.MethodPut, "/actors/alice/favorites:reorder", "", `{"note_ids": [`+itoa(a)+`]}`).Code)
+
+	rec := send(http.MethodPut, "/actors/alice/favorites:reorder", "alice", `{"note_ids": [`+itoa(b)+`, `+itoa(a)+`]}`)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPut, "/actors/alice/favorites:reorder", "alice", `{"note_ids": []}`).Code)
+
+	rec = send(http.MethodGet, "/actors/alice/favorites", "alice", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp FavoritesResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, int64(2), resp.Total)
+	require.Equal(t, []int64{b, a}, []int64{resp.Notes[0].ID, resp.Notes[1].ID})
+
+	require.Equal(t, http.StatusNoContent, send(http.MethodDelete, "/actors/alice/favorites/"+itoa(a), "alice", "").Code)
+	require.Equal(t, http.StatusNotFound, send(http.MethodDelete, "/actors/alice/favorites/"+itoa(a), "alice", "").Code)
+}
@@ -47,20 +47,26 @@ func (h *NotesHandler) CreateNote(
 	noteID, err := h.service.CreateNote(ctx, params)
 	if err != nil {
 		if errors.Is(err, ErrNoteAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "notes", "title", req.Msg.Title)
+			return nil, apierrors.Mind.AlreadyExists("notes", "title", req.Msg.Title)
+		}
+		if errors.Is(err, ErrBodyTooLarge) {
+			return nil, apierrors.NewInvalidArgumentError("body", err.Error())
+		}
+		if errors.Is(err, ErrSchemaValidationFailed) {
+			return nil, apierrors.NewInvalidArgumentError("body", err.Error())
 		}
 		if apierrors.IsForeignKeyConstraintError(err) {
 			return nil, apierrors.NewInvalidArgumentError("collection_id or note_type_id", "referenced resource does not exist")
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to create note", err)
+		return nil, apierrors.Mind.Internal("failed to create note", err)
 	}
 
 	note, err := h.service.GetNoteByID(ctx, noteID)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve created note", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve created note", err)
 	}
 
-	return connect.NewResponse(StoreNoteToProto(note)), nil
+	return noteResponse(note), nil
 }
 
 func (h *NotesHandler) GetNote(
@@ -70,12 +76,19 @@ func (h *NotesHandler) GetNote(
 	note, err := h.service.GetNoteByID(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, ErrNoteNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note", strconv.FormatInt(req.Msg.Id, 10))
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.Id, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get note", err)
+		return nil, apierrors.Mind.Internal("failed to get note", err)
 	}
 
-	return connect.NewResponse(StoreNoteToProto(note)), nil
+	// Best effort: a failed view record (logged by the service) doesn't fail the read
+	_ = h.service.RecordNoteView(ctx, note.ID)
+
+	resp := noteResponse(note)
+	if req.Msg.FieldMask != nil && *req.Msg.FieldMask != "" {
+		resp.Msg = ApplyFieldMask(resp.Msg, *req.Msg.FieldMask)
+	}
+	return resp, nil
 }
 
 func (h *NotesHandler) ReplaceNote(
@@ -85,43 +98,49 @@ func (h *NotesHandler) ReplaceNote(
 	current, err := h.service.GetNoteByID(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, ErrNoteNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note", strconv.FormatInt(req.Msg.Id, 10))
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.Id, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get note", err)
+		return nil, apierrors.Mind.Internal("failed to get note", err)
 	}
 
-	// Optimistic locking via ETag
-	if req.Header().Get("If-Match") != "" {
-		currentETag := utils.ComputeHashedETag(current.Version)
-		if req.Header().Get("If-Match") != currentETag {
-			metadata := map[string]string{
-				"provided_etag": req.Header().Get("If-Match"),
-				"current_etag":  currentETag,
-				"header":        "If-Match",
-			}
-			return nil, apierrors.NewFailedPreconditionError(apierrors.MindDomain, "ETAG_MISMATCH", metadata)
-		}
+	// A matching If-Match is enforced by the ETag interceptor (see ETagResources);
+	// a replace without one, or with the "*" wildcard, could silently overwrite another actor's edit
+	if ifMatch := req.Header().Get("If-Match"); ifMatch == "" || ifMatch == "*" {
+		return nil, apierrors.Mind.FailedPrecondition("ETAG_REQUIRED", map[string]string{
+			"header": "If-Match",
+			"reason": "If-Match header with ETag is required when replacing a note",
+		})
 	}
-
 	params := ProtoReplaceNoteToStore(req.Msg, current)
 
 	err = h.service.UpdateNote(ctx, params)
 	if err != nil {
+		if errors.Is(err, ErrNoteNotFound) {
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.Id, 10))
+		}
 		if errors.Is(err, ErrNoteAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "notes", "title", req.Msg.Title)
+			return nil, apierrors.Mind.AlreadyExists("notes", "title", req.Msg.Title)
+		}
+		if errors.Is(err, ErrStaleNote) {
+			return nil, apierrors.Mind.FailedPrecondition("STALE_NOTE", map[string]string{
+				"reason": "note was modified by another request",
+			})
+		}
+		if errors.Is(err, ErrBodyTooLarge) {
+			return nil, apierrors.NewInvalidArgumentError("body", err.Error())
 		}
 		if apierrors.IsForeignKeyConstraintError(err) {
 			return nil, apierrors.NewInvalidArgumentError("collection_id or note_type_id", "referenced resource does not exist")
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to replace note", err)
+		return nil, apierrors.Mind.Internal("failed to replace note", err)
 	}
 
 	updated, err := h.service.GetNoteByID(ctx, req.Msg.Id)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve replaced note", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve replaced note", err)
 	}
 
-	return connect.NewResponse(StoreNoteToProto(updated)), nil
+	return noteResponse(updated), nil
 }
 
 func (h *NotesHandler) UpdateNote(
@@ -132,17 +151,17 @@ func (h *NotesHandler) UpdateNote(
 	current, err := h.service.GetNoteByID(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, ErrNoteNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note", strconv.FormatInt(req.Msg.Id, 10))
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.Id, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get note", err)
+		return nil, apierrors.Mind.Internal("failed to get note", err)
 	}
 
 	// Route based on whether body is being updated
 	if req.Msg.Body != nil {
-		// Body update path: require ETag, use UpdateNote (increments version)
+		// Body update path: require a concrete ETag ("*" matches any version), use UpdateNote (increments version)
 		ifMatch := req.Header().Get("If-Match")
-		if ifMatch == "" {
-			return nil, apierrors.NewFailedPreconditionError(apierrors.MindDomain, "ETAG_REQUIRED", map[string]string{
+		if ifMatch == "" || ifMatch == "*" {
+			return nil, apierrors.Mind.FailedPrecondition("ETAG_REQUIRED", map[string]string{
 				"header": "If-Match",
 				"reason": "If-Match header with ETag is required when updating note body",
 			})
@@ -155,7 +174,7 @@ func (h *NotesHandler) UpdateNote(
 				"current_etag":  currentETag,
 				"header":        "If-Match",
 			}
-			return nil, apierrors.NewFailedPreconditionError(apierrors.MindDomain, "ETAG_MISMATCH", metadata)
+			return nil, apierrors.Mind.FailedPrecondition("ETAG_MISMATCH", metadata)
 		}
 
 		// Merge request fields with current note (PATCH semantics)
@@ -163,18 +182,24 @@ func (h *NotesHandler) UpdateNote(
 
 		err = h.service.UpdateNote(ctx, params)
 		if err != nil {
+			if errors.Is(err, ErrNoteNotFound) {
+				return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.Id, 10))
+			}
 			if errors.Is(err, ErrNoteAlreadyExists) {
-				return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "notes", "title", *req.Msg.Title)
+				return nil, apierrors.Mind.AlreadyExists("notes", "title", *req.Msg.Title)
 			}
 			if errors.Is(err, ErrStaleNote) {
-				return nil, apierrors.NewFailedPreconditionError(apierrors.MindDomain, "STALE_NOTE", map[string]string{
+				return nil, apierrors.Mind.FailedPrecondition("STALE_NOTE", map[string]string{
 					"reason": "note was modified by another request",
 				})
 			}
+			if errors.Is(err, ErrBodyTooLarge) {
+				return nil, apierrors.NewInvalidArgumentError("body", err.Error())
+			}
 			if apierrors.IsForeignKeyConstraintError(err) {
 				return nil, apierrors.NewInvalidArgumentError("collection_id or note_type_id", "referenced resource does not exist")
 			}
-			return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to update note", err)
+			return nil, apierrors.Mind.Internal("failed to update note", err)
 		}
 	} else {
 		// Metadata-only update path: no ETag required, use UpdateNoteMetadata (no version change)
@@ -183,21 +208,21 @@ func (h *NotesHandler) UpdateNote(
 		err = h.service.UpdateNoteMetadata(ctx, params, current)
 		if err != nil {
 			if errors.Is(err, ErrNoteAlreadyExists) {
-				return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "notes", "title", *req.Msg.Title)
+				return nil, apierrors.Mind.AlreadyExists("notes", "title", *req.Msg.Title)
 			}
 			if apierrors.IsForeignKeyConstraintError(err) {
 				return nil, apierrors.NewInvalidArgumentError("collection_id or note_type_id", "referenced resource does not exist")
 			}
-			return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to update note metadata", err)
+			return nil, apierrors.Mind.Internal("failed to update note metadata", err)
 		}
 	}
 
 	updated, err := h.service.GetNoteByID(ctx, req.Msg.Id)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve updated note", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve updated note", err)
 	}
 
-	return connect.NewResponse(StoreNoteToProto(updated)), nil
+	return noteResponse(updated), nil
 }
 
 func (h *NotesHandler) DeleteNote(
@@ -207,14 +232,14 @@ func (h *NotesHandler) DeleteNote(
 	_, err := h.service.GetNoteByID(ctx, req.Msg.Id)
 	if err != nil {
 		if errors.Is(err, ErrNoteNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note", strconv.FormatInt(req.Msg.Id, 10))
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.Id, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get note", err)
+		return nil, apierrors.Mind.Internal("failed to get note", err)
 	}
 
 	err = h.service.DeleteNote(ctx, req.Msg.Id)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to delete note", err)
+		return nil, apierrors.Mind.Internal("failed to delete note", err)
 	}
 
 	return connect.NewResponse(&emptypb.Empty{}), nil
@@ -237,7 +262,10 @@ func (h *NotesHandler) ListNotes(
 	// Priority: collection_id > note_type_id > is_template > all
 	switch {
 	case req.Msg.CollectionId != nil:
-		notes, err = h.service.ListNotesByCollectionIDPaginated(ctx, *req.Msg.CollectionId, params.Limit, params.Offset)
+		notes, err = h.service.ListNotesByCollectionIDPaginated(ctx, *req.Msg.CollectionId, req.Msg.GetSortBy(), params.Limit, params.Offset)
+		if errors.Is(err, ErrInvalidSortBy) {
+			return nil, apierrors.NewInvalidArgumentError("sort_by", "must be one of: updated_at, pinned_first")
+		}
 		if err == nil && pageReq.IsFirstPage() {
 			totalCount, countErr = h.service.CountNotesByCollectionID(ctx, *req.Msg.CollectionId)
 		}
@@ -261,7 +289,7 @@ func (h *NotesHandler) ListNotes(
 	}
 
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list notes", err)
+		return nil, apierrors.Mind.Internal("failed to list notes", err)
 	}
 
 	// Count errors are logged in service but don't fail the request
@@ -301,9 +329,9 @@ func (h *NotesHandler) GetNoteMeta(
 	metadata, err := h.service.GetNoteMeta(ctx, req.Msg.NoteId, h.metaService)
 	if err != nil {
 		if errors.Is(err, ErrNoteNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note", strconv.FormatInt(req.Msg.NoteId, 10))
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.NoteId, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get note metadata", err)
+		return nil, apierrors.Mind.Internal("failed to get note metadata", err)
 	}
 
 	resp := &mindv3.GetNoteMetaResponse{
@@ -320,9 +348,9 @@ func (h *NotesHandler) GetNoteRelationships(
 	outgoingLinks, incomingLinks, tagIDs, err := h.service.GetNoteRelationships(ctx, req.Msg.NoteId, h.linksService, h.tagsSvc)
 	if err != nil {
 		if errors.Is(err, ErrNoteNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note", strconv.FormatInt(req.Msg.NoteId, 10))
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.NoteId, 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get note relationships", err)
+		return nil, apierrors.Mind.Internal("failed to get note relationships", err)
 	}
 
 	resp := &mindv3.GetNoteRelationshipsResponse{
@@ -342,21 +370,49 @@ func (h *NotesHandler) NewNote(
 	noteID, err := h.service.NewNoteCreation(ctx, collectionID, templateID)
 	if err != nil {
 		if errors.Is(err, ErrNoteAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "notes", "title", "auto-generated title")
+			return nil, apierrors.Mind.AlreadyExists("notes", "title", "auto-generated title")
 		}
 		if errors.Is(err, ErrNoteNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "template", strconv.FormatInt(templateID, 10))
+			return nil, apierrors.Mind.NotFound("template", strconv.FormatInt(templateID, 10))
 		}
 		if apierrors.IsForeignKeyConstraintError(err) {
 			return nil, apierrors.NewInvalidArgumentError("collection_id or template_id", "referenced resource does not exist")
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to create new note", err)
+		return nil, apierrors.Mind.Internal("failed to create new note", err)
 	}
 	note, err := h.service.GetNoteByID(ctx, noteID)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve created note", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve created note", err)
 	}
-	return connect.NewResponse(StoreNoteToProto(note)), nil
+	return noteResponse(note), nil
+}
+
+// DuplicateNote implements the AIP-136 :duplicate custom method for notes.
+// Copies the source note's body and metadata into a new note with a " (copy)" title.
+func (h *NotesHandler) DuplicateNote(
+	ctx context.Context,
+	req *connect.Request[mindv3.DuplicateNoteRequest],
+) (*connect.Response[mindv3.Note], error) {
+	noteID, err := h.service.DuplicateNote(ctx, req.Msg.SourceId, utils.ToNullInt64(req.Msg.TargetCollectionId))
+	if err != nil {
+		if errors.Is(err, ErrNoteNotFound) {
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.SourceId, 10))
+		}
+		if errors.Is(err, ErrNoteAlreadyExists) {
+			return nil, apierrors.Mind.AlreadyExists("notes", "title", "generated copy title")
+		}
+		if apierrors.IsForeignKeyConstraintError(err) {
+			return nil, apierrors.NewInvalidArgumentError("target_collection_id", "referenced resource does not exist")
+		}
+		return nil, apierrors.Mind.Internal("failed to duplicate note", err)
+	}
+
+	note, err := h.service.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return nil, apierrors.Mind.Internal("failed to retrieve duplicated note", err)
+	}
+
+	return noteResponse(note), nil
 }
 
 // FindNotes implements the AIP-136 :find custom method for notes.
@@ -392,7 +448,7 @@ func (h *NotesHandler) FindNotes(
 	// Execute find query
 	rows, err := h.service.FindNotesPaginated(ctx, findParams)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to find notes", err)
+		return nil, apierrors.Mind.Internal("failed to find notes", err)
 	}
 
 	// Get total count (only on first page)
@@ -438,3 +494,84 @@ func (h *NotesHandler) FindNotes(
 
 	return connect.NewResponse(resp), nil
 }
+
+// SearchByProximity implements the AIP-136 :searchByProximity custom method for notes.
+// Returns notes reachable over links from the given note with their hop distance.
+func (h *NotesHandler) SearchByProximity(
+	ctx context.Context,
+	req *connect.Request[mindv3.SearchByProximityRequest],
+) (*connect.Response[mindv3.SearchByProximityResponse], error) {
+	notes, err := h.service.GetReachableNotes(ctx, req.Msg.NoteId, int(req.Msg.GetMaxDepth()), int(req.Msg.GetLimit()))
+	if err != nil {
+		if errors.Is(err, ErrNoteNotFound) {
+			return nil, apierrors.Mind.NotFound("note", strconv.FormatInt(req.Msg.NoteId, 10))
+		}
+		return nil, apierrors.Mind.Internal("failed to search notes by proximity", err)
+	}
+
+	resp := &mindv3.SearchByProximityResponse{
+		Results: NotesWithDistanceToProto(notes),
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// BulkTagNotes implements the AIP-136 :bulkTag custom method for notes.
+// Assigns every requested tag to every requested note in one transaction.
+func (h *NotesHandler) BulkTagNotes(
+	ctx context.Context,
+	req *connect.Request[mindv3.BulkTagNotesRequest],
+) (*connect.Response[emptypb.Empty], error) {
+	if err := h.service.BulkAssignTags(ctx, req.Msg.NoteIds, req.Msg.TagNames); err != nil {
+		if errors.Is(err, ErrNoteNotFound) {
+			return nil, apierrors.Mind.NotFound("note", "one or more note_ids")
+		}
+		return nil, apierrors.Mind.Internal("failed to bulk tag notes", err)
+	}
+
+	return connect.NewResponse(&emptypb.Empty{}), nil
+}
+
+// SearchNotesByMeta implements the AIP-136 :searchByMeta custom method for notes.
+// Returns live notes whose metadata key matches exactly and whose value matches a LIKE pattern.
+func (h *NotesHandler) SearchNotesByMeta(
+	ctx context.Context,
+	req *connect.Request[mindv3.SearchNotesByMetaRequest],
+) (*connect.Response[mindv3.SearchNotesByMetaResponse], error) {
+	pageReq := pagination.ParseRequest(req.Msg.GetPageSize(), req.Msg.GetPageToken())
+	params := pageReq.ToParams()
+
+	notes, total, err := h.service.SearchNotesByMeta(ctx, req.Msg.Key, req.Msg.Value, params.Limit, params.Offset)
+	if err != nil {
+		return nil, apierrors.Mind.Internal("failed to search notes by meta", err)
+	}
+
+	pagResp := pageReq.BuildResponse(len(notes), total)
+	notes = pagination.TrimResults(notes, pageReq.PageSize)
+
+	resp := &mindv3.SearchNotesByMetaResponse{
+		Notes:         StoreNotesToProto(notes),
+		NextPageToken: pagResp.NextPageToken,
+	}
+
+	// Include total size only on first page
+	if pageReq.IsFirstPage() {
+		totalSize := int32(total)
+		resp.TotalSize = &totalSize
+	}
+
+	return connect.NewResponse(resp), nil
+}
+
+// ListMetaKeys implements the AIP-136 :listMetaKeys custom method for notes.
+func (h *NotesHandler) ListMetaKeys(
+	ctx context.Context,
+	req *connect.Request[mindv3.ListMetaKeysRequest],
+) (*connect.Response[mindv3.ListMetaKeysResponse], error) {
+	keys, err := h.service.ListDistinctMetaKeys(ctx)
+	if err != nil {
+		return nil, apierrors.Mind.Internal("failed to list meta keys", err)
+	}
+
+	return connect.NewResponse(&mindv3.ListMetaKeysResponse{Keys: keys}), nil
+}
@@ -0,0 +1,43 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/markdown"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// ListExternalImages returns the distinct external image URLs used by live notes in a
+// collection, in note order, for link checking. They are read from the 'image_urls'
+// metadata, which only keeps URLs, so Alt is empty and IsLocal is always false.
+func (s *NotesService) ListExternalImages(ctx context.Context, collectionID int64) ([]markdown.ImageRef, error) {
+	metas, err := s.store.ListNoteMetaByKeyInCollection(ctx, store.ListNoteMetaByKeyInCollectionParams{
+		Key:          imageURLsMetaKey,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		s.logger.Error("failed to list image metadata", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	var images []markdown.ImageRef
+	seen := make(map[string]bool)
+	for _, meta := range metas {
+		var urls []string
+		if err := json.Unmarshal([]byte(meta.Value.String), &urls); err != nil {
+			// A frontmatter key named image_urls is not ours to interpret
+			s.logger.Warn("skipping malformed image_urls metadata", "note_id", meta.NoteID, "err", err)
+			continue
+		}
+		for _, url := range urls {
+			if seen[url] {
+				continue
+			}
+			seen[url] = true
+			images = append(images, markdown.ImageRef{URL: url})
+		}
+	}
+	return images, nil
+}
@@ -0,0 +1,34 @@
+package notes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/shared/markdown"
+)
+
+func TestCreateNote_StoresOnlyExternalImageURLs(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+	collection := createTestCollection(t, queries, "Gallery")
+
+	body := "# Trip\n\n![Beach](https://example.com/beach.jpg)\n\n" +
+		"![Map](./maps/route.png)\n\n![Sunset](https://cdn.example.org/sunset.png)\n"
+	noteID := createTestNote(t, service, "Trip", body, collection)
+
+	meta := noteMeta(t, queries, noteID)
+	require.JSONEq(t, `["https://example.com/beach.jpg","https://cdn.example.org/sunset.png"]`, meta["image_urls"])
+
+	// A second note reusing an image is reported once
+	createTestNote(t, service, "Again", "![Beach again](https://example.com/beach.jpg)", collection)
+	createTestNote(t, service, "Local only", "![Diagram](../diagram.svg)", collection)
+
+	images, err := service.ListExternalImages(ctx, collection)
+	require.NoError(t, err)
+	require.Equal(t, []markdown.ImageRef{
+		{URL: "https://example.com/beach.jpg"},
+		{URL: "https://cdn.example.org/sunset.png"},
+	}, images)
+}
@@ -0,0 +1,56 @@
+package notes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// GetMentionedNotes returns the live notes whose body mentions actorID (@actorID),
+// most recently updated first.
+func (s *NotesService) GetMentionedNotes(ctx context.Context, actorID string) ([]store.Note, error) {
+	notes, err := s.store.ListNotesMentioningActor(ctx, actorID)
+	if err != nil {
+		s.logger.Error("failed to list notes mentioning actor", "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	return notes, nil
+}
+
+// MentionedNote identifies a note in a MentionsResponse.
+type MentionedNote struct {
+	ID    int64  `json:"id"`
+	UUID  string `json:"uuid"`
+	Title string `json:"title"`
+}
+
+// MentionsResponse is the JSON body of GET /actors/{id}/mentions.
+type MentionsResponse struct {
+	Notes []MentionedNote `json:"notes"`
+}
+
+// MentionsHandler serves GET /actors/{id}/mentions.
+// Actor IDs are opaque strings, so an unknown actor simply has no mentions.
+func MentionsHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		actorID := c.Param("id")
+		if actorID == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "actor id is required")
+		}
+
+		notes, err := service.GetMentionedNotes(c.Request().Context(), actorID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list mentions")
+		}
+
+		resp := MentionsResponse{Notes: make([]MentionedNote, len(notes))}
+		for i, n := range notes {
+			resp.Notes[i] = MentionedNote{ID: n.ID, UUID: n.Uuid.String(), Title: n.Title}
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,118 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// createTestActor registers actorID as a known actor by giving it an API key.
+func createTestActor(t *testing.T, queries *store.Queries, actorID string) {
+	t.Helper()
+
+	_, err := queries.CreateApiKey(context.Background(), store.CreateApiKeyParams{
+		ActorID: actorID,
+		KeyHash: "hash-" + actorID,
+		Name:    "test key",
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateNote_RecordsMentionsOfKnownActors(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	createTestActor(t, queries, "alice")
+	createTestActor(t, queries, "bob")
+	collectionID := createTestCollection(t, queries, "Work")
+
+	id := createTestNote(t, service, "Standup", "Ask @alice and @bob, not @nobody.", collectionID)
+
+	for _, actorID := range []string{"alice", "bob"} {
+		notes, err := service.GetMentionedNotes(ctx, actorID)
+		require.NoError(t, err)
+		require.Len(t, notes, 1)
+		require.Equal(t, id, notes[0].ID)
+	}
+
+	notes, err := service.GetMentionedNotes(ctx, "nobody")
+	require.NoError(t, err)
+	require.Empty(t, notes, "unknown handles are not recorded")
+}
+
+func TestUpdateNote_ReplacesMentions(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	createTestActor(t, queries, "alice")
+	createTestActor(t, queries, "bob")
+	collectionID := createTestCollection(t, queries, "Work")
+	id := createTestNote(t, service, "Standup", "Ask @alice.", collectionID)
+
+	note, err := service.GetNoteByID(ctx, id)
+	require.NoError(t, err)
+	require.NoError(t, service.UpdateNote(ctx, store.UpdateNoteByIDParams{
+		ID:           id,
+		Uuid:         note.Uuid,
+		Title:        note.Title,
+		Body:         utils.NullString("Now ask @bob."),
+		CollectionID: note.CollectionID,
+		Version:      note.Version,
+	}))
+
+	notes, err := service.GetMentionedNotes(ctx, "alice")
+	require.NoError(t, err)
+	require.Empty(t, notes)
+
+	notes, err = service.GetMentionedNotes(ctx, "bob")
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+}
+
+func TestMentionsHandler(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	createTestActor(t, queries, "alice")
+	collectionID := createTestCollection(t, queries, "Work")
+	createTestNote(t, service, "Standup", "Ask @alice.", collectionID)
+
+	e := echo.New()
+	e.GET("/actors/:id/mentions", MentionsHandler(service))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/actors/alice/mentions", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp MentionsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Notes, 1)
+	require.Equal(t, "Standup", resp.Notes[0].Title)
+}
+
+func TestCreateNotesBatch_RecordsMentions(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	createTestActor(t, queries, "alice")
+	collectionID := createTestCollection(t, queries, "Import")
+
+	ids, err := service.CreateNotesBatch(ctx, []store.CreateNoteParams{
+		{Uuid: uuid.New(), Title: "Standup", Body: utils.NullString("Ask @alice, not @nobody."), CollectionID: collectionID},
+		{Uuid: uuid.New(), Title: "Quiet", Body: utils.NullString("No mentions here."), CollectionID: collectionID},
+	})
+	require.NoError(t, err)
+
+	notes, err := service.GetMentionedNotes(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, ids[0], notes[0].ID)
+}
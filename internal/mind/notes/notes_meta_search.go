@@ -0,0 +1,50 @@
+package notes
+
+import (
+	"context"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// SearchNotesByMeta returns a page of live notes with a metadata key whose value matches
+// a SQL LIKE pattern (e.g., key "author", value "John%"), and the total number of matches.
+// An empty value matches any value of the key.
+func (s *NotesService) SearchNotesByMeta(ctx context.Context, key, value string, limit, offset int32) ([]store.Note, int64, error) {
+	if value == "" {
+		value = "%"
+	}
+
+	notes, err := s.store.SearchNotesByMeta(ctx, store.SearchNotesByMetaParams{
+		Key:          key,
+		ValuePattern: utils.NullString(value),
+		Limit:        int64(limit),
+		Offset:       int64(offset),
+	})
+	if err != nil {
+		s.logger.Error("failed to search notes by meta", "key", key, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, 0, err
+	}
+
+	total, err := s.store.CountNotesByMeta(ctx, store.CountNotesByMetaParams{
+		Key:          key,
+		ValuePattern: utils.NullString(value),
+	})
+	if err != nil {
+		s.logger.Error("failed to count notes by meta", "key", key, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, 0, err
+	}
+
+	return notes, total, nil
+}
+
+// ListDistinctMetaKeys returns every metadata key in use, sorted, for building meta filters.
+func (s *NotesService) ListDistinctMetaKeys(ctx context.Context) ([]string, error) {
+	keys, err := s.store.ListDistinctNoteMetaKeys(ctx)
+	if err != nil {
+		s.logger.Error("failed to list meta keys", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	return keys, nil
+}
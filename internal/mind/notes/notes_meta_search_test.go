@@ -0,0 +1,58 @@
+package notes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchNotesByMeta_MatchesValuePattern(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Library")
+	createTestNote(t, service, "Essays", "---\nauthor: John Smith\n---\nBody", collectionID)
+	createTestNote(t, service, "Letters", "---\nauthor: Johnny Cash\n---\nBody", collectionID)
+	createTestNote(t, service, "Poems", "---\nauthor: Mary John\n---\nBody", collectionID)
+	createTestNote(t, service, "Untitled", "---\neditor: John Doe\n---\nBody", collectionID)
+	trashed := createTestNote(t, service, "Drafts", "---\nauthor: John Roe\n---\nBody", collectionID)
+	require.NoError(t, service.DeleteNote(ctx, trashed))
+
+	notes, total, err := service.SearchNotesByMeta(ctx, "author", "John%", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total)
+	require.Equal(t, []string{"Essays", "Letters"}, noteTitles(notes))
+
+	// Paging keeps the total
+	notes, total, err = service.SearchNotesByMeta(ctx, "author", "John%", 1, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), total)
+	require.Equal(t, []string{"Letters"}, noteTitles(notes))
+
+	// An empty value matches any value of the key
+	_, total, err = service.SearchNotesByMeta(ctx, "author", "", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), total)
+}
+
+func TestListDistinctMetaKeys(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Library")
+	createTestNote(t, service, "Essays", "---\nauthor: John\nstatus: draft\n---\nBody", collectionID)
+	createTestNote(t, service, "Letters", "---\nauthor: Jane\n---\nBody", collectionID)
+
+	keys, err := service.ListDistinctMetaKeys(ctx)
+	require.NoError(t, err)
+	require.Contains(t, keys, "author")
+	require.Contains(t, keys, "status")
+	require.IsNonDecreasing(t, keys)
+
+	seen := make(map[string]bool)
+	for _, k := range keys {
+		require.False(t, seen[k], "duplicate key %q", k)
+		seen[k] = true
+	}
+}
@@ -0,0 +1,149 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	sharederrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// MoveNote moves a live note to targetCollectionID and rewrites the path-qualified wiki-links
+// pointing at it, in a single transaction: [[docs/A]] and [[docs/A|A]] in linking notes become
+// [[guides/A]] and [[guides/A|A]], and link display texts naming the old path are updated.
+// Plain [[A]] links resolve by title and are left as-is. Moving a note to its own collection is a no-op.
+// Returns ErrInvalidCollectionID if the target doesn't exist and ErrNoteAlreadyExists if it
+// already holds a note with the same title.
+func (s *NotesService) MoveNote(ctx context.Context, noteID, targetCollectionID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	note, err := txStore.GetNoteByID(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoteNotFound
+		}
+		s.logger.Error("failed to get note for move", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if note.CollectionID == targetCollectionID {
+		return nil
+	}
+
+	from, err := txStore.GetCollectionByID(ctx, note.CollectionID)
+	if err != nil {
+		s.logger.Error("failed to get note collection", "note_id", noteID, "collection_id", note.CollectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	to, err := txStore.GetCollectionByID(ctx, targetCollectionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidCollectionID
+		}
+		s.logger.Error("failed to get target collection", "collection_id", targetCollectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	result, err := txStore.MoveNoteToCollection(ctx, store.MoveNoteToCollectionParams{
+		ID:           noteID,
+		CollectionID: targetCollectionID,
+	})
+	if err != nil {
+		if sharederrors.IsUniqueConstraintError(err) {
+			return ErrNoteAlreadyExists
+		}
+		s.logger.Error("failed to move note", "note_id", noteID, "collection_id", targetCollectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if rows, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rows == 0 {
+		return ErrNoteNotFound
+	}
+
+	oldTarget := from.Path + "/" + note.Title
+	newTarget := to.Path + "/" + note.Title
+
+	links, err := txStore.ListLinksByDestID(ctx, utils.NullInt64(noteID))
+	if err != nil {
+		s.logger.Error("failed to list links to moved note", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	// Match the target right after [[ (or ![[) and before an alias, heading or the closing brackets
+	pattern := regexp.MustCompile(`(\[\[)` + regexp.QuoteMeta(oldTarget) + `([|#\]])`)
+	replacement := "${1}" + strings.ReplaceAll(newTarget, "$", "$$") + "${2}"
+
+	var updatedNoteIDs []int64
+	seen := make(map[int64]bool)
+	for _, link := range links {
+		if link.DisplayText.Valid && link.DisplayText.String == oldTarget {
+			if err := txStore.UpdateLinkDisplayText(ctx, store.UpdateLinkDisplayTextParams{
+				ID:          link.ID,
+				DisplayText: utils.NullString(newTarget),
+			}); err != nil {
+				s.logger.Error("failed to update link display text", "link_id", link.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+				return err
+			}
+		}
+
+		if seen[link.SrcID] {
+			continue
+		}
+		seen[link.SrcID] = true
+
+		src, err := txStore.GetNoteByID(ctx, link.SrcID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue // Trashed notes keep their body until restored
+		}
+		if err != nil {
+			s.logger.Error("failed to get linking note", "note_id", link.SrcID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+		if !src.Body.Valid {
+			continue
+		}
+		newBody := pattern.ReplaceAllString(src.Body.String, replacement)
+		if newBody == src.Body.String {
+			continue
+		}
+		if err := txStore.UpdateNoteBodyByID(ctx, store.UpdateNoteBodyByIDParams{
+			ID:   src.ID,
+			Body: utils.NullString(newBody),
+		}); err != nil {
+			s.logger.Error("failed to rewrite linking note body", "note_id", src.ID, "moved_note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return err
+		}
+		updatedNoteIDs = append(updatedNoteIDs, src.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("note moved", "note_id", noteID, "from_collection_id", from.ID, "to_collection_id", to.ID, "updated_links_in", len(updatedNoteIDs))
+	s.afterCollectionMove(ctx, noteID, from.ID, to.ID)
+
+	for _, id := range updatedNoteIDs {
+		if s.scheduler != nil {
+			s.scheduler.TrackChange("note_updated", id)
+		}
+		if s.eventHub != nil {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, id)
+		}
+	}
+	return nil
+}
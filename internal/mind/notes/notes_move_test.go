@@ -0,0 +1,59 @@
+package notes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+func TestMoveNote_RewritesPathQualifiedLinks(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+	docs := createTestCollection(t, queries, "docs")
+	guides := createTestCollection(t, queries, "guides")
+
+	noteA := createTestNote(t, service, "A", "Setup steps", docs)
+	noteB := createTestNote(t, service, "B", "See [[docs/A|A]], ![[docs/A]] and [[A]]", docs)
+
+	links, err := queries.ListLinksByDestID(ctx, utils.NullInt64(noteA))
+	require.NoError(t, err)
+	require.Len(t, links, 3, "path-qualified targets resolve to the note")
+
+	require.NoError(t, service.MoveNote(ctx, noteA, guides))
+
+	moved, err := service.GetNoteByID(ctx, noteA)
+	require.NoError(t, err)
+	require.Equal(t, guides, moved.CollectionID)
+
+	linking, err := service.GetNoteByID(ctx, noteB)
+	require.NoError(t, err)
+	require.Equal(t, "See [[guides/A|A]], ![[guides/A]] and [[A]]", linking.Body.String)
+
+	links, err = queries.ListLinksByDestID(ctx, utils.NullInt64(noteA))
+	require.NoError(t, err)
+	require.Len(t, links, 3, "links keep pointing at the moved note")
+
+	// Moving back to the same collection twice is a no-op the second time
+	require.NoError(t, service.MoveNote(ctx, noteA, docs))
+	require.NoError(t, service.MoveNote(ctx, noteA, docs))
+	linking, err = service.GetNoteByID(ctx, noteB)
+	require.NoError(t, err)
+	require.Equal(t, "See [[docs/A|A]], ![[docs/A]] and [[A]]", linking.Body.String)
+}
+
+func TestMoveNote_Errors(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+	docs := createTestCollection(t, queries, "docs")
+	guides := createTestCollection(t, queries, "guides")
+
+	note := createTestNote(t, service, "A", "", docs)
+	createTestNote(t, service, "A", "", guides)
+
+	require.ErrorIs(t, service.MoveNote(ctx, note, guides), ErrNoteAlreadyExists)
+	require.ErrorIs(t, service.MoveNote(ctx, note, 999999), ErrInvalidCollectionID)
+	require.ErrorIs(t, service.MoveNote(ctx, 999999, guides), ErrNoteNotFound)
+}
@@ -0,0 +1,131 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+const (
+	defaultRecentDays  = 7
+	defaultRecentLimit = 50
+	maxRecentLimit     = 500
+)
+
+// GetRecentNotes returns live notes updated within the last days days, most recent first.
+// With includeViewed, notes viewed (RecordNoteView) within the window are included too,
+// ordered by their latest update or view. limit defaults to 50 (max 500).
+// Returns ErrInvalidDays if days is not positive.
+func (s *NotesService) GetRecentNotes(ctx context.Context, days int, limit int, includeViewed bool) ([]store.Note, error) {
+	if days <= 0 {
+		return nil, ErrInvalidDays
+	}
+	if limit <= 0 {
+		limit = defaultRecentLimit
+	}
+	limit = min(limit, maxRecentLimit)
+
+	cutoff := fmt.Sprintf("-%d days", days)
+	var notes []store.Note
+	var err error
+	if includeViewed {
+		notes, err = s.store.ListRecentlyUpdatedOrViewedNotes(ctx, store.ListRecentlyUpdatedOrViewedNotesParams{
+			Cutoff: cutoff,
+			Limit:  int64(limit),
+		})
+	} else {
+		notes, err = s.store.ListRecentlyUpdatedNotes(ctx, store.ListRecentlyUpdatedNotesParams{
+			Cutoff: cutoff,
+			Limit:  int64(limit),
+		})
+	}
+	if err != nil {
+		s.logger.Error("failed to list recent notes", "days", days, "include_viewed", includeViewed, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	return notes, nil
+}
+
+// RecordNoteView marks noteID as viewed now and increments its view count.
+// Returns ErrNoteNotFound if the note doesn't exist or is trashed.
+func (s *NotesService) RecordNoteView(ctx context.Context, noteID int64) error {
+	if _, err := s.GetNoteByID(ctx, noteID); err != nil {
+		return err
+	}
+	if err := s.store.UpsertNoteView(ctx, noteID); err != nil {
+		s.logger.Error("failed to record note view", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return nil
+}
+
+// RecentNote identifies a note in a RecentNotesResponse.
+type RecentNote struct {
+	ID        int64  `json:"id"`
+	UUID      string `json:"uuid"`
+	Title     string `json:"title"`
+	UpdatedAt string `json:"updated_at,omitempty"` // RFC 3339
+}
+
+// RecentNotesResponse is the JSON body of GET /notes:recent.
+type RecentNotesResponse struct {
+	Notes []RecentNote `json:"notes"`
+}
+
+// RecentNotesHandler serves GET /notes:recent?days=7&limit=&include_viewed=.
+// days defaults to 7; include_viewed also returns notes only viewed within the window.
+func RecentNotesHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		days := defaultRecentDays
+		if raw := c.QueryParam("days"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "days must be an integer")
+			}
+			days = parsed
+		}
+
+		limit := 0
+		if raw := c.QueryParam("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+			}
+			limit = parsed
+		}
+
+		includeViewed := false
+		if raw := c.QueryParam("include_viewed"); raw != "" {
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, "include_viewed must be a boolean")
+			}
+			includeViewed = parsed
+		}
+
+		notes, err := service.GetRecentNotes(c.Request().Context(), days, limit, includeViewed)
+		if err != nil {
+			if errors.Is(err, ErrInvalidDays) {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to list recent notes")
+		}
+
+		resp := RecentNotesResponse{Notes: make([]RecentNote, len(notes))}
+		for i, n := range notes {
+			resp.Notes[i] = RecentNote{ID: n.ID, UUID: n.Uuid.String(), Title: n.Title}
+			if n.UpdatedAt.Valid {
+				resp.Notes[i].UpdatedAt = n.UpdatedAt.Time.UTC().Format(time.RFC3339)
+			}
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+}
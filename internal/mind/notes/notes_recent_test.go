@@ -0,0 +1,125 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+)
+
+// ageNote moves the note's updated_at daysAgo days into the past.
+func ageNote(t *testing.T, service *NotesService, noteID int64, daysAgo int) {
+	t.Helper()
+
+	_, err := service.db.ExecContext(context.Background(),
+		"UPDATE notes SET updated_at = datetime('now', '-' || ? || ' days') WHERE id = ?", daysAgo, noteID)
+	require.NoError(t, err)
+}
+
+// noteIDs returns the IDs of notes in order.
+func noteIDs(notes []store.Note) []int64 {
+	ids := make([]int64, len(notes))
+	for i, n := range notes {
+		ids[i] = n.ID
+	}
+	return ids
+}
+
+func TestGetRecentNotes_FiltersByAge(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Journal")
+	todayID := createTestNote(t, service, "Today", "# Today", collectionID)
+	threeDaysID := createTestNote(t, service, "Three Days", "# Three", collectionID)
+	monthID := createTestNote(t, service, "Last Month", "# Month", collectionID)
+	trashedID := createTestNote(t, service, "Trashed", "# Trashed", collectionID)
+	ageNote(t, service, threeDaysID, 3)
+	ageNote(t, service, monthID, 30)
+	require.NoError(t, service.DeleteNote(ctx, trashedID))
+
+	notes, err := service.GetRecentNotes(ctx, 7, 0, false)
+	require.NoError(t, err)
+	require.Equal(t, []int64{todayID, threeDaysID}, noteIDs(notes))
+
+	notes, err = service.GetRecentNotes(ctx, 1, 0, false)
+	require.NoError(t, err)
+	require.Equal(t, []int64{todayID}, noteIDs(notes))
+
+	notes, err = service.GetRecentNotes(ctx, 7, 1, false)
+	require.NoError(t, err)
+	require.Equal(t, []int64{todayID}, noteIDs(notes))
+
+	_, err = service.GetRecentNotes(ctx, 0, 0, false)
+	require.ErrorIs(t, err, ErrInvalidDays)
+}
+
+func TestGetRecentNotes_IncludesViewedNotes(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Journal")
+	editedID := createTestNote(t, service, "Edited", "# Edited", collectionID)
+	viewedID := createTestNote(t, service, "Viewed", "# Viewed", collectionID)
+	staleID := createTestNote(t, service, "Stale", "# Stale", collectionID)
+	ageNote(t, service, editedID, 2)
+	ageNote(t, service, viewedID, 30)
+	ageNote(t, service, staleID, 30)
+
+	require.NoError(t, service.RecordNoteView(ctx, viewedID))
+	require.NoError(t, service.RecordNoteView(ctx, viewedID))
+	require.ErrorIs(t, service.RecordNoteView(ctx, 9999), ErrNoteNotFound)
+
+	var viewCount int64
+	require.NoError(t, service.db.QueryRowContext(ctx, "SELECT view_count FROM note_views WHERE note_id = ?", viewedID).Scan(&viewCount))
+	require.Equal(t, int64(2), viewCount)
+
+	notes, err := service.GetRecentNotes(ctx, 7, 0, false)
+	require.NoError(t, err)
+	require.Equal(t, []int64{editedID}, noteIDs(notes))
+
+	// The view today is more recent than the edit two days ago
+	notes, err = service.GetRecentNotes(ctx, 7, 0, true)
+	require.NoError(t, err)
+	require.Equal(t, []int64{viewedID, editedID}, noteIDs(notes))
+}
+
+func TestRecentNotesHandler(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	collectionID := createTestCollection(t, queries, "Journal")
+	recentID := createTestNote(t, service, "Recent", "# Recent", collectionID)
+	oldID := createTestNote(t, service, "Old", "# Old", collectionID)
+	ageNote(t, service, oldID, 10)
+
+	e := echo.New()
+	e.GET("/notes\\:recent", RecentNotesHandler(service))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := get("/notes:recent?days=7")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var resp RecentNotesResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Notes, 1)
+	require.Equal(t, recentID, resp.Notes[0].ID)
+
+	rec = get("/notes:recent?days=30")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Notes, 2)
+
+	require.Equal(t, http.StatusBadRequest, get("/notes:recent?days=0").Code)
+	require.Equal(t, http.StatusBadRequest, get("/notes:recent?days=abc").Code)
+	require.Equal(t, http.StatusBadRequest, get("/notes:recent?include_viewed=maybe").Code)
+}
@@ -0,0 +1,76 @@
+package notes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// RestoreFromMarkdown rebuilds a note's links, tags, metadata and mentions from its
+// stored body, in one transaction. It runs the same extraction as UpdateNote but
+// leaves the note row (body, version, updated_at) untouched, so it can backfill
+// notes imported before a piece of derived data existed. Running it on a note whose
+// derived data is already current is a no-op.
+// Returns ErrNoteNotFound if the note doesn't exist or is trashed.
+func (s *NotesService) RestoreFromMarkdown(ctx context.Context, noteID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	note, err := txStore.GetNoteByID(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoteNotFound
+		}
+		s.logger.Error("failed to get note", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if err := s.deleteDerivedDataWithStore(ctx, txStore, noteID); err != nil {
+		return err
+	}
+	if note.Body.Valid && note.Body.String != "" {
+		if err := s.insertDerivedDataWithStore(ctx, txStore, noteID, note.Body.String); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	s.logger.Debug("note derived data restored from markdown", "note_id", noteID, "request_id", middleware.GetRequestID(ctx))
+	return nil
+}
+
+// BulkRestoreFromMarkdown runs RestoreFromMarkdown on every live note directly in
+// collectionID. A note that fails is logged and counted in failed without stopping
+// the run; err is only returned when the notes can't be listed.
+func (s *NotesService) BulkRestoreFromMarkdown(ctx context.Context, collectionID int64) (processed, failed int, err error) {
+	notes, err := s.store.ListNotesByCollectionID(ctx, collectionID)
+	if err != nil {
+		s.logger.Error("failed to list notes for markdown restore", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, 0, err
+	}
+
+	for _, note := range notes {
+		if restoreErr := s.RestoreFromMarkdown(ctx, note.ID); restoreErr != nil {
+			s.logger.Warn("failed to restore note from markdown", "note_id", note.ID, "err", restoreErr, "request_id", middleware.GetRequestID(ctx))
+			failed++
+			continue
+		}
+		processed++
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("notes restored from markdown", "collection_id", collectionID, "processed", processed, "failed", failed)
+	return processed, failed, nil
+}
@@ -0,0 +1,104 @@
+package notes
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+)
+
+// noteTagNames returns the sorted names of the tags attached to a note.
+func noteTagNames(t *testing.T, queries *store.Queries, noteID int64) []string {
+	t.Helper()
+
+	tags, err := queries.ListTagsForNote(context.Background(), noteID)
+	require.NoError(t, err)
+
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRestoreFromMarkdown_IsIdempotent(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	noteID := createTestNote(t, service, "Plan", "---\nauthor: Jane\n---\n# Plan\n\nSee #roadmap and #q3.", collectionID)
+
+	before, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	metaBefore := noteMeta(t, queries, noteID)
+	tagsBefore := noteTagNames(t, queries, noteID)
+	require.Equal(t, "Jane", metaBefore["author"])
+	require.NotEmpty(t, tagsBefore)
+
+	require.NoError(t, service.RestoreFromMarkdown(ctx, noteID))
+	require.NoError(t, service.RestoreFromMarkdown(ctx, noteID))
+
+	require.Equal(t, metaBefore, noteMeta(t, queries, noteID))
+	require.Equal(t, tagsBefore, noteTagNames(t, queries, noteID))
+
+	// The note row itself is left alone
+	after, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.Equal(t, before.Body, after.Body)
+	require.Equal(t, before.Version, after.Version)
+	require.Equal(t, before.UpdatedAt, after.UpdatedAt)
+}
+
+func TestRestoreFromMarkdown_RebuildsStaleData(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	body := "---\nauthor: Jane\n---\n# Plan\n\nSee #roadmap."
+	noteID := createTestNote(t, service, "Plan", body, collectionID)
+	expectedMeta := noteMeta(t, queries, noteID)
+	expectedTags := noteTagNames(t, queries, noteID)
+
+	// Simulate a note imported before tags and metadata were extracted
+	_, err := service.db.ExecContext(ctx, "DELETE FROM note_meta WHERE note_id = ?", noteID)
+	require.NoError(t, err)
+	_, err = service.db.ExecContext(ctx, "DELETE FROM note_tags WHERE note_id = ?", noteID)
+	require.NoError(t, err)
+	_, err = service.db.ExecContext(ctx, "INSERT INTO note_meta (note_id, key, value) VALUES (?, 'author', 'Stale')", noteID)
+	require.NoError(t, err)
+
+	require.NoError(t, service.RestoreFromMarkdown(ctx, noteID))
+	require.Equal(t, expectedMeta, noteMeta(t, queries, noteID))
+	require.Equal(t, expectedTags, noteTagNames(t, queries, noteID))
+
+	require.ErrorIs(t, service.RestoreFromMarkdown(ctx, 9999), ErrNoteNotFound)
+}
+
+func TestBulkRestoreFromMarkdown(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Work")
+	otherID := createTestCollection(t, queries, "Other")
+	first := createTestNote(t, service, "First", "---\nstatus: draft\n---\n# First", collectionID)
+	second := createTestNote(t, service, "Second", "# Second #idea", collectionID)
+	untouched := createTestNote(t, service, "Untouched", "---\nstatus: done\n---\n# Untouched", otherID)
+
+	_, err := service.db.ExecContext(ctx, "DELETE FROM note_meta")
+	require.NoError(t, err)
+	_, err = service.db.ExecContext(ctx, "DELETE FROM note_tags")
+	require.NoError(t, err)
+
+	processed, failed, err := service.BulkRestoreFromMarkdown(ctx, collectionID)
+	require.NoError(t, err)
+	require.Equal(t, 2, processed)
+	require.Zero(t, failed)
+
+	require.Equal(t, "draft", noteMeta(t, queries, first)["status"])
+	require.Contains(t, noteTagNames(t, queries, second), "idea")
+	require.Empty(t, noteMeta(t, queries, untouched))
+}
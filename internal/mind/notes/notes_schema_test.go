@@ -0,0 +1,162 @@
+package notes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/internal/mind/notetypes"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// setupSchemaTest wires a SchemaService into the notes service and creates a "review"
+// note type whose notes must have author and tags frontmatter.
+func setupSchemaTest(t *testing.T) (*NotesService, *store.Queries, *notetypes.SchemaService, int64) {
+	t.Helper()
+
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	schemaService := notetypes.NewSchemaService(queries, testdb.NewTestLogger(t), "schema-test")
+	service.SetSchemaService(schemaService)
+
+	noteTypeID, err := queries.CreateNoteType(ctx, store.CreateNoteTypeParams{
+		Type: "review",
+		Name: "Book Review",
+	})
+	require.NoError(t, err)
+	require.NoError(t, schemaService.CreateNoteTypeSchema(ctx, notetypes.NoteTypeSchema{
+		NoteTypeID:   noteTypeID,
+		RequiredKeys: []string{"author", "tags"},
+		FieldTypes:   map[string]string{"rating": notetypes.FieldTypeNumber, "tags": notetypes.FieldTypeList},
+	}))
+
+	return service, queries, schemaService, noteTypeID
+}
+
+func TestCreateNote_EnforcesFrontmatterSchema(t *testing.T) {
+	service, queries, _, noteTypeID := setupSchemaTest(t)
+	ctx := context.Background()
+	collectionID := createTestCollection(t, queries, "Reviews")
+
+	create := func(title, body string, noteTypeID int64) (int64, error) {
+		return service.CreateNote(ctx, store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        title,
+			Body:         utils.NullString(body),
+			NoteTypeID:   utils.NullInt64(noteTypeID),
+			CollectionID: collectionID,
+		})
+	}
+
+	id, err := create("Dune", "---\nauthor: Frank Herbert\ntags: [scifi]\nrating: 5\n---\n# Dune", noteTypeID)
+	require.NoError(t, err)
+	require.NotZero(t, id)
+
+	tests := []struct {
+		name string
+		body string
+		keys []string
+	}{
+		{name: "no frontmatter", body: "# Missing", keys: []string{"author", "tags"}},
+		{name: "missing tags", body: "---\nauthor: Jane\n---\n# Missing tags", keys: []string{"tags"}},
+		{name: "blank author and empty tags", body: "---\nauthor: \"\"\ntags: []\n---\n# Blank", keys: []string{"author", "tags"}},
+		{name: "wrong types", body: "---\nauthor: Jane\ntags: scifi\nrating: great\n---\n# Types", keys: []string{"rating", "tags"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := create("Rejected "+tt.name, tt.body, noteTypeID)
+			require.ErrorIs(t, err, ErrSchemaValidationFailed)
+
+			var validationErrs notetypes.ValidationErrors
+			require.True(t, errors.As(err, &validationErrs))
+			keys := make([]string, len(validationErrs))
+			for i, v := range validationErrs {
+				keys[i] = v.Key
+			}
+			require.Equal(t, tt.keys, keys)
+		})
+	}
+
+	// Nothing was written for the rejected notes
+	count, err := service.CountNotesByCollectionID(ctx, collectionID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// Notes of other types, or without a type, are not checked
+	otherTypeID, err := queries.CreateNoteType(ctx, store.CreateNoteTypeParams{Type: "free", Name: "Free"})
+	require.NoError(t, err)
+	_, err = create("Free", "# No frontmatter", otherTypeID)
+	require.NoError(t, err)
+	_, err = service.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        "Untyped",
+		Body:         utils.NullString("# Untyped"),
+		CollectionID: collectionID,
+	})
+	require.NoError(t, err)
+}
+
+func TestSchemaService_CreateAndGet(t *testing.T) {
+	_, queries, schemaService, noteTypeID := setupSchemaTest(t)
+	ctx := context.Background()
+
+	schema, err := schemaService.GetNoteTypeSchema(ctx, noteTypeID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"author", "tags"}, schema.RequiredKeys)
+	require.Equal(t, notetypes.FieldTypeNumber, schema.FieldTypes["rating"])
+
+	err = schemaService.CreateNoteTypeSchema(ctx, notetypes.NoteTypeSchema{NoteTypeID: noteTypeID, RequiredKeys: []string{"isbn"}})
+	require.ErrorIs(t, err, notetypes.ErrNoteTypeSchemaAlreadyExists)
+
+	err = schemaService.CreateNoteTypeSchema(ctx, notetypes.NoteTypeSchema{NoteTypeID: 9999, RequiredKeys: []string{"isbn"}})
+	require.ErrorIs(t, err, notetypes.ErrNoteTypeNotFound)
+
+	otherTypeID, err := queries.CreateNoteType(ctx, store.CreateNoteTypeParams{Type: "person", Name: "Person"})
+	require.NoError(t, err)
+	err = schemaService.CreateNoteTypeSchema(ctx, notetypes.NoteTypeSchema{NoteTypeID: otherTypeID, RequiredKeys: []string{" "}})
+	require.ErrorIs(t, err, notetypes.ErrInvalidSchema)
+	err = schemaService.CreateNoteTypeSchema(ctx, notetypes.NoteTypeSchema{
+		NoteTypeID: otherTypeID,
+		FieldTypes: map[string]string{"born": "date"},
+	})
+	require.ErrorIs(t, err, notetypes.ErrInvalidSchema)
+
+	_, err = schemaService.GetNoteTypeSchema(ctx, otherTypeID)
+	require.ErrorIs(t, err, notetypes.ErrNoteTypeSchemaNotFound)
+	require.Empty(t, schemaService.ValidateFrontmatter(ctx, otherTypeID, nil))
+}
+
+func TestCreateNotesBatch_EnforcesFrontmatterSchema(t *testing.T) {
+	service, queries, _, noteTypeID := setupSchemaTest(t)
+	ctx := context.Background()
+	collectionID := createTestCollection(t, queries, "Imported Reviews")
+
+	_, err := service.CreateNotesBatch(ctx, []store.CreateNoteParams{
+		{
+			Uuid:         uuid.New(),
+			Title:        "Dune",
+			Body:         utils.NullString("---\nauthor: Frank Herbert\ntags: [scifi]\n---\n# Dune"),
+			NoteTypeID:   utils.NullInt64(noteTypeID),
+			CollectionID: collectionID,
+		},
+		{
+			Uuid:         uuid.New(),
+			Title:        "Untagged",
+			Body:         utils.NullString("---\nauthor: Jane\n---\n# Untagged"),
+			NoteTypeID:   utils.NullInt64(noteTypeID),
+			CollectionID: collectionID,
+		},
+	})
+	require.ErrorIs(t, err, ErrSchemaValidationFailed)
+
+	// The valid note is not written either
+	count, err := service.CountNotesByCollectionID(ctx, collectionID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
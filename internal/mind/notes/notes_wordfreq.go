@@ -0,0 +1,148 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/markdown"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// topWordsLimit is the number of words stored under top_words.
+const topWordsLimit = 20
+
+// topWordsJSON returns the JSON array of the most frequent meaningful words of a body,
+// or "" if it has none.
+func topWordsJSON(body string) (string, error) {
+	top := markdown.TopWords(markdown.ComputeWordFrequency(body), topWordsLimit)
+	if len(top) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(top)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetWordFrequency returns the most frequent meaningful words of a note body, most frequent first.
+// The top_words metadata is used when present; otherwise the body is analyzed on the fly.
+func (s *NotesService) GetWordFrequency(ctx context.Context, noteID int64) ([]markdown.WordCount, error) {
+	note, err := s.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	metaItems, err := s.store.GetNoteMetaByNoteID(ctx, noteID)
+	if err != nil {
+		s.logger.Error("failed to get note metadata for word frequency", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	for _, item := range metaItems {
+		if item.Key != topWordsMetaKey || !item.Value.Valid {
+			continue
+		}
+		var top []markdown.WordCount
+		if err := json.Unmarshal([]byte(item.Value.String), &top); err == nil {
+			return top, nil
+		}
+		s.logger.Warn("ignoring malformed top_words metadata", "note_id", noteID, "request_id", middleware.GetRequestID(ctx))
+	}
+
+	body := markdown.ExtractBodyWithoutFrontmatter([]byte(note.Body.String))
+	return markdown.TopWords(markdown.ComputeWordFrequency(body), topWordsLimit), nil
+}
+
+// ComputeCorpusWordFrequency recomputes the top_words metadata of every live note directly
+// in a collection (e.g., for notes created before it existed) in one transaction, and
+// returns the most frequent words across all of them.
+func (s *NotesService) ComputeCorpusWordFrequency(ctx context.Context, collectionID int64) ([]markdown.WordCount, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	notes, err := txStore.ListNotesByCollectionID(ctx, collectionID)
+	if err != nil {
+		s.logger.Error("failed to list notes for word frequency", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	corpus := make(map[string]int)
+	for _, n := range notes {
+		freq := markdown.ComputeWordFrequency(markdown.ExtractBodyWithoutFrontmatter([]byte(n.Body.String)))
+		for w, c := range freq {
+			corpus[w] += c
+		}
+
+		top := markdown.TopWords(freq, topWordsLimit)
+		if len(top) == 0 {
+			if err := txStore.DeleteNoteMetaByKey(ctx, store.DeleteNoteMetaByKeyParams{NoteID: n.ID, Key: topWordsMetaKey}); err != nil {
+				s.logger.Error("failed to delete top words", "note_id", n.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+				return nil, err
+			}
+			continue
+		}
+
+		data, err := json.Marshal(top)
+		if err != nil {
+			return nil, err
+		}
+		if err := txStore.UpsertNoteMeta(ctx, store.UpsertNoteMetaParams{
+			NoteID: n.ID,
+			Key:    topWordsMetaKey,
+			Value:  utils.NullString(string(data)),
+		}); err != nil {
+			s.logger.Error("failed to store top words", "note_id", n.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("word frequency recomputed", "collection_id", collectionID, "notes", len(notes))
+
+	return markdown.TopWords(corpus, topWordsLimit), nil
+}
+
+// WordFrequencyResponse is the JSON body of GET /notes/{id}/word-frequency.
+type WordFrequencyResponse struct {
+	Words []markdown.WordCount `json:"words"`
+}
+
+// WordFrequencyHandler serves GET /notes/{id}/word-frequency.
+func WordFrequencyHandler(service *NotesService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid note id")
+		}
+
+		words, err := service.GetWordFrequency(c.Request().Context(), id)
+		if err != nil {
+			if errors.Is(err, ErrNoteNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, "note not found")
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to get word frequency")
+		}
+
+		if words == nil {
+			words = []markdown.WordCount{}
+		}
+		return c.JSON(http.StatusOK, WordFrequencyResponse{Words: words})
+	}
+}
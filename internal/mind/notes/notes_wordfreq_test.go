@@ -0,0 +1,100 @@
+package notes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/markdown"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// topWordsMeta returns the stored top_words metadata of a note ("" if absent).
+func topWordsMeta(t *testing.T, queries *store.Queries, noteID int64) string {
+	t.Helper()
+
+	items, err := queries.GetNoteMetaByNoteID(context.Background(), noteID)
+	require.NoError(t, err)
+	for _, item := range items {
+		if item.Key == topWordsMetaKey {
+			return item.Value.String
+		}
+	}
+	return ""
+}
+
+func TestCreateNote_StoresTopWords(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Garden")
+	id := createTestNote(t, service, "Garden", "---\ntitle: Garden\n---\nThe garden is green and the garden is quiet. Basil is the best.", collectionID)
+
+	var top []markdown.WordCount
+	require.NoError(t, json.Unmarshal([]byte(topWordsMeta(t, queries, id)), &top))
+	require.Equal(t, markdown.WordCount{Word: "garden", Count: 2}, top[0])
+	for _, w := range top {
+		require.NotContains(t, []string{"the", "and", "is", "title"}, w.Word)
+	}
+
+	words, err := service.GetWordFrequency(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, top, words)
+
+	_, err = service.GetWordFrequency(ctx, 9999)
+	require.ErrorIs(t, err, ErrNoteNotFound)
+}
+
+func TestComputeCorpusWordFrequency_ReindexesNotes(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Garden")
+	createTestNote(t, service, "Basil", "Basil and tomatoes love the sun.", collectionID)
+
+	// A note inserted without derived data, as before top_words existed
+	legacyID, err := queries.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        "Tomatoes",
+		Body:         utils.NullString("Tomatoes need sun and water. Tomatoes are red."),
+		CollectionID: collectionID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, topWordsMeta(t, queries, legacyID))
+
+	corpus, err := service.ComputeCorpusWordFrequency(ctx, collectionID)
+	require.NoError(t, err)
+	require.Equal(t, markdown.WordCount{Word: "tomatoes", Count: 3}, corpus[0])
+	require.Equal(t, markdown.WordCount{Word: "sun", Count: 2}, corpus[1])
+	require.NotEmpty(t, topWordsMeta(t, queries, legacyID))
+}
+
+func TestWordFrequencyHandler(t *testing.T) {
+	service, queries := setupTestService(t)
+
+	collectionID := createTestCollection(t, queries, "Garden")
+	id := createTestNote(t, service, "Basil", "Basil basil pesto.", collectionID)
+
+	e := echo.New()
+	e.GET("/notes/:id/word-frequency", WordFrequencyHandler(service))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notes/"+strconv.FormatInt(id, 10)+"/word-frequency", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp WordFrequencyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, []markdown.WordCount{{Word: "basil", Count: 2}, {Word: "pesto", Count: 1}}, resp.Words)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notes/9999/word-frequency", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
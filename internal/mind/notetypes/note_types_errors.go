@@ -12,4 +12,13 @@ var (
 
 	// ErrNoteTypeIsSystem is returned when attempting to delete a system note type
 	ErrNoteTypeIsSystem = errors.New("cannot delete system note type")
+
+	// ErrNoteTypeSchemaNotFound is returned when a note type has no frontmatter schema
+	ErrNoteTypeSchemaNotFound = errors.New("note type schema not found")
+
+	// ErrNoteTypeSchemaAlreadyExists is returned when creating a schema for a note type that already has one
+	ErrNoteTypeSchemaAlreadyExists = errors.New("note type schema already exists")
+
+	// ErrInvalidSchema is returned when a schema has blank keys or an unknown field type
+	ErrInvalidSchema = errors.New("invalid note type schema")
 )
@@ -31,14 +31,14 @@ func (h *NoteTypesHandler) CreateNoteType(
 	noteTypeID, err := h.service.CreateNoteType(ctx, params)
 	if err != nil {
 		if errors.Is(err, ErrNoteTypeAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "note_types", "type", req.Msg.Type)
+			return nil, apierrors.Mind.AlreadyExists("note_types", "type", req.Msg.Type)
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to create note type", err)
+		return nil, apierrors.Mind.Internal("failed to create note type", err)
 	}
 
 	noteType, err := h.service.GetNoteTypeByID(ctx, noteTypeID)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve created note type", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve created note type", err)
 	}
 
 	return connect.NewResponse(StoreNoteTypeToProto(noteType)), nil
@@ -54,7 +54,7 @@ func (h *NoteTypesHandler) ListNoteTypes(
 
 	noteTypes, err := h.service.ListNoteTypesPaginated(ctx, params.Limit, params.Offset)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list note types", err)
+		return nil, apierrors.Mind.Internal("failed to list note types", err)
 	}
 
 	var totalCount int64
@@ -90,9 +90,9 @@ func (h *NoteTypesHandler) GetNoteType(
 	noteType, err := h.service.GetNoteTypeByID(ctx, req.Msg.GetId())
 	if err != nil {
 		if errors.Is(err, ErrNoteTypeNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note_types", strconv.FormatInt(req.Msg.GetId(), 10))
+			return nil, apierrors.Mind.NotFound("note_types", strconv.FormatInt(req.Msg.GetId(), 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get note type", err)
+		return nil, apierrors.Mind.Internal("failed to get note type", err)
 	}
 
 	return connect.NewResponse(StoreNoteTypeToProto(noteType)), nil
@@ -107,20 +107,20 @@ func (h *NoteTypesHandler) UpdateNoteType(
 	err := h.service.UpdateNoteType(ctx, params)
 	if err != nil {
 		if errors.Is(err, ErrNoteTypeNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note_types", strconv.FormatInt(req.Msg.GetId(), 10))
+			return nil, apierrors.Mind.NotFound("note_types", strconv.FormatInt(req.Msg.GetId(), 10))
 		}
 		if errors.Is(err, ErrNoteTypeAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "note_types", "type", req.Msg.Type)
+			return nil, apierrors.Mind.AlreadyExists("note_types", "type", req.Msg.Type)
 		}
 		if errors.Is(err, ErrNoteTypeIsSystem) {
-			return nil, apierrors.NewPermissionDeniedError(apierrors.MindDomain, "cannot update system note type")
+			return nil, apierrors.Mind.PermissionDenied("cannot update system note type")
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to update note type", err)
+		return nil, apierrors.Mind.Internal("failed to update note type", err)
 	}
 
 	noteType, err := h.service.GetNoteTypeByID(ctx, req.Msg.GetId())
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve updated note type", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve updated note type", err)
 	}
 
 	return connect.NewResponse(StoreNoteTypeToProto(noteType)), nil
@@ -133,12 +133,12 @@ func (h *NoteTypesHandler) DeleteNoteType(
 	err := h.service.DeleteNoteType(ctx, req.Msg.GetId())
 	if err != nil {
 		if errors.Is(err, ErrNoteTypeNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "note_types", strconv.FormatInt(req.Msg.GetId(), 10))
+			return nil, apierrors.Mind.NotFound("note_types", strconv.FormatInt(req.Msg.GetId(), 10))
 		}
 		if errors.Is(err, ErrNoteTypeIsSystem) {
-			return nil, apierrors.NewPermissionDeniedError(apierrors.MindDomain, "cannot delete system note type")
+			return nil, apierrors.Mind.PermissionDenied("cannot delete system note type")
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to delete note type", err)
+		return nil, apierrors.Mind.Internal("failed to delete note type", err)
 	}
 
 	return connect.NewResponse(&emptypb.Empty{}), nil
@@ -0,0 +1,241 @@
+package notetypes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	sharedErrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// Field types a NoteTypeSchema can require of a frontmatter value.
+const (
+	FieldTypeString  = "string"
+	FieldTypeNumber  = "number"
+	FieldTypeBoolean = "boolean"
+	FieldTypeList    = "list"
+)
+
+// NoteTypeSchema describes the frontmatter notes of a note type must have.
+type NoteTypeSchema struct {
+	NoteTypeID int64
+
+	// RequiredKeys are frontmatter keys that must be present with a non-empty value.
+	RequiredKeys []string
+
+	// FieldTypes maps frontmatter keys to one of the FieldType constants.
+	// Keys listed here are only type checked when present.
+	FieldTypes map[string]string
+}
+
+// ValidationError is a frontmatter key that doesn't satisfy a note type's schema.
+type ValidationError struct {
+	Key     string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Key + ": " + e.Message
+}
+
+// ValidationErrors is the list of problems found by ValidateFrontmatter.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, v := range e {
+		msgs[i] = v.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// SchemaService manages per note type frontmatter schemas and validates frontmatter against them.
+type SchemaService struct {
+	store  store.Querier
+	logger *slog.Logger
+}
+
+// NewSchemaService creates a new SchemaService.
+func NewSchemaService(store store.Querier, logger *slog.Logger, serviceName string) *SchemaService {
+	return &SchemaService{
+		store:  store,
+		logger: logger.With("service", serviceName),
+	}
+}
+
+// CreateNoteTypeSchema stores the frontmatter schema of a note type.
+// Returns ErrInvalidSchema for blank keys or unknown field types, ErrNoteTypeNotFound if the
+// note type doesn't exist and ErrNoteTypeSchemaAlreadyExists if it already has a schema.
+func (s *SchemaService) CreateNoteTypeSchema(ctx context.Context, schema NoteTypeSchema) error {
+	if err := checkSchema(schema); err != nil {
+		return err
+	}
+
+	if _, err := s.store.GetNoteTypeByID(ctx, schema.NoteTypeID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoteTypeNotFound
+		}
+		s.logger.Error("failed to get note_type", "id", schema.NoteTypeID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	requiredKeys := schema.RequiredKeys
+	if requiredKeys == nil {
+		requiredKeys = []string{}
+	}
+	requiredJSON, err := json.Marshal(requiredKeys)
+	if err != nil {
+		return err
+	}
+	schemaJSON := utils.NullStringEmpty()
+	if len(schema.FieldTypes) > 0 {
+		fieldsJSON, err := json.Marshal(schema.FieldTypes)
+		if err != nil {
+			return err
+		}
+		schemaJSON = utils.NullString(string(fieldsJSON))
+	}
+
+	err = s.store.CreateNoteTypeSchema(ctx, store.CreateNoteTypeSchemaParams{
+		NoteTypeID:   schema.NoteTypeID,
+		RequiredKeys: string(requiredJSON),
+		SchemaJson:   schemaJSON,
+	})
+	if err != nil {
+		if sharedErrors.IsUniqueConstraintError(err) {
+			return ErrNoteTypeSchemaAlreadyExists
+		}
+		s.logger.Error("failed to create note_type schema", "note_type_id", schema.NoteTypeID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	s.logger.Info("note_type schema created", "note_type_id", schema.NoteTypeID, "request_id", middleware.GetRequestID(ctx))
+	return nil
+}
+
+// GetNoteTypeSchema returns the frontmatter schema of a note type.
+// Returns ErrNoteTypeSchemaNotFound if the note type has none.
+func (s *SchemaService) GetNoteTypeSchema(ctx context.Context, noteTypeID int64) (NoteTypeSchema, error) {
+	row, err := s.store.GetNoteTypeSchema(ctx, noteTypeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return NoteTypeSchema{}, ErrNoteTypeSchemaNotFound
+		}
+		s.logger.Error("failed to get note_type schema", "note_type_id", noteTypeID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return NoteTypeSchema{}, err
+	}
+
+	schema := NoteTypeSchema{NoteTypeID: row.NoteTypeID}
+	if err := json.Unmarshal([]byte(row.RequiredKeys), &schema.RequiredKeys); err != nil {
+		return NoteTypeSchema{}, fmt.Errorf("decode required keys of note type %d: %w", noteTypeID, err)
+	}
+	if row.SchemaJson.Valid && row.SchemaJson.String != "" {
+		if err := json.Unmarshal([]byte(row.SchemaJson.String), &schema.FieldTypes); err != nil {
+			return NoteTypeSchema{}, fmt.Errorf("decode schema of note type %d: %w", noteTypeID, err)
+		}
+	}
+	return schema, nil
+}
+
+// ValidateFrontmatter checks parsed frontmatter against the schema of noteTypeID and returns
+// every problem found; nil means the frontmatter is valid or the note type has no schema.
+// A schema that can't be loaded is reported as a validation error so it isn't silently skipped.
+func (s *SchemaService) ValidateFrontmatter(ctx context.Context, noteTypeID int64, metadata map[string]any) []ValidationError {
+	schema, err := s.GetNoteTypeSchema(ctx, noteTypeID)
+	if errors.Is(err, ErrNoteTypeSchemaNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("failed to load schema for validation", "note_type_id", noteTypeID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return []ValidationError{{Message: "note type schema could not be loaded"}}
+	}
+
+	var errs []ValidationError
+	for _, key := range schema.RequiredKeys {
+		if isEmptyValue(metadata[key]) {
+			errs = append(errs, ValidationError{Key: key, Message: "is required"})
+		}
+	}
+
+	keys := make([]string, 0, len(schema.FieldTypes))
+	for key := range schema.FieldTypes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := metadata[key]
+		if !ok || value == nil {
+			continue
+		}
+		if fieldType := schema.FieldTypes[key]; !hasFieldType(value, fieldType) {
+			errs = append(errs, ValidationError{Key: key, Message: "must be a " + fieldType})
+		}
+	}
+	return errs
+}
+
+// checkSchema rejects blank keys and unknown field types.
+func checkSchema(schema NoteTypeSchema) error {
+	for _, key := range schema.RequiredKeys {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: required keys must not be blank", ErrInvalidSchema)
+		}
+	}
+	for key, fieldType := range schema.FieldTypes {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: field keys must not be blank", ErrInvalidSchema)
+		}
+		switch fieldType {
+		case FieldTypeString, FieldTypeNumber, FieldTypeBoolean, FieldTypeList:
+		default:
+			return fmt.Errorf("%w: unknown type %q for %q", ErrInvalidSchema, fieldType, key)
+		}
+	}
+	return nil
+}
+
+// isEmptyValue reports whether a frontmatter value counts as missing: absent, null,
+// a blank string or an empty list.
+func isEmptyValue(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+// hasFieldType reports whether a decoded YAML value matches fieldType.
+func hasFieldType(value any, fieldType string) bool {
+	switch fieldType {
+	case FieldTypeString:
+		_, ok := value.(string)
+		return ok
+	case FieldTypeNumber:
+		switch value.(type) {
+		case int, int64, uint64, float64:
+			return true
+		}
+	case FieldTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case FieldTypeList:
+		switch value.(type) {
+		case []any, []string:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package permissions
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminHandler serves collection grant management under /admin.
+// Requests are authenticated by middleware.AdminAuthMiddleware, which guards all /admin/* routes.
+type AdminHandler struct {
+	service *PermissionsService
+}
+
+// Grant is one actor's role on a collection.
+type Grant struct {
+	ActorID string `json:"actor_id"`
+	Role    string `json:"role"`
+}
+
+// ListGrantsResponse is the body of GET /admin/mind/collections/{id}/permissions.
+type ListGrantsResponse struct {
+	Grants []Grant `json:"grants"`
+}
+
+// GrantRequest is the JSON body of PUT /admin/mind/collections/{id}/permissions/{actor_id}.
+type GrantRequest struct {
+	Role string `json:"role"`
+}
+
+// NewAdminHandler creates a new permissions admin handler.
+func NewAdminHandler(service *PermissionsService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// RegisterRoutes registers permission admin routes on the Echo instance.
+func (h *AdminHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/admin/mind/collections/:id/permissions")
+	admin.GET("", h.HandleList)
+	admin.PUT("/:actor_id", h.HandleGrant)
+	admin.DELETE("/:actor_id", h.HandleRevoke)
+}
+
+// HandleList lists the grants on a collection.
+func (h *AdminHandler) HandleList(c echo.Context) error {
+	collectionID, err := parseCollectionID(c)
+	if err != nil {
+		return err
+	}
+
+	grants, err := h.service.ListPermissions(c.Request().Context(), collectionID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list permissions")
+	}
+
+	resp := ListGrantsResponse{Grants: make([]Grant, len(grants))}
+	for i, g := range grants {
+		resp.Grants[i] = Grant{ActorID: g.ActorID, Role: g.Role}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleGrant gives an actor a role on a collection, replacing any role it already had.
+func (h *AdminHandler) HandleGrant(c echo.Context) error {
+	collectionID, err := parseCollectionID(c)
+	if err != nil {
+		return err
+	}
+
+	var req GrantRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	actorID := c.Param("actor_id")
+	err = h.service.GrantPermission(c.Request().Context(), collectionID, actorID, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidRole), errors.Is(err, ErrInvalidActorID):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrCollectionNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, "collection not found")
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to grant permission")
+		}
+	}
+	return c.JSON(http.StatusOK, Grant{ActorID: actorID, Role: req.Role})
+}
+
+// HandleRevoke removes an actor's grant on a collection.
+func (h *AdminHandler) HandleRevoke(c echo.Context) error {
+	collectionID, err := parseCollectionID(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.service.RevokePermission(c.Request().Context(), collectionID, c.Param("actor_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidActorID):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, ErrPermissionNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, "permission not found")
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to revoke permission")
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// parseCollectionID reads the collection ID path parameter.
+func parseCollectionID(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid collection id")
+	}
+	return id, nil
+}
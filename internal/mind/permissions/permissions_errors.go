@@ -0,0 +1,26 @@
+package permissions
+
+// Permissions Domain Errors
+// Domain-specific errors for the collection permissions service layer
+
+import (
+	"errors"
+)
+
+// Domain errors for permissions service
+var (
+	// ErrPermissionDenied indicates the actor lacks the required role on a collection
+	ErrPermissionDenied = errors.New("permission denied")
+
+	// ErrPermissionNotFound indicates the actor has no grant on the collection
+	ErrPermissionNotFound = errors.New("permission not found")
+
+	// ErrInvalidRole indicates a role other than viewer, editor, or owner
+	ErrInvalidRole = errors.New("invalid role")
+
+	// ErrInvalidActorID indicates an empty actor ID on grant or revoke
+	ErrInvalidActorID = errors.New("invalid actor id")
+
+	// ErrCollectionNotFound indicates a grant on a collection that doesn't exist
+	ErrCollectionNotFound = errors.New("collection not found")
+)
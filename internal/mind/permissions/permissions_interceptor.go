@@ -0,0 +1,184 @@
+package permissions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"connectrpc.com/connect"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/gen/proto/mind/v3/mindv3connect"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/internal/mind/notes"
+	apierrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// CollectionResolver returns the collections a request writes to.
+// An empty result means the request targets no specific collection and is not checked.
+type CollectionResolver func(ctx context.Context, req connect.AnyRequest) ([]int64, error)
+
+// RequireCollectionPermission returns a Connect interceptor that requires the caller
+// (middleware.GetActorID) to hold role on every collection a request targets.
+// Only procedures with an entry in WriteResolvers are checked; all others pass through.
+func (s *PermissionsService) RequireCollectionPermission(role string) connect.UnaryInterceptorFunc {
+	resolvers := s.WriteResolvers()
+
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			resolve, ok := resolvers[req.Spec().Procedure]
+			if req.Spec().IsClient || !ok {
+				return next(ctx, req)
+			}
+
+			collectionIDs, err := resolve(ctx, req)
+			if err != nil {
+				return nil, apierrors.Mind.Internal("failed to resolve collection", err)
+			}
+
+			actorID := middleware.GetActorID(ctx)
+			for _, collectionID := range collectionIDs {
+				err := s.CheckPermission(ctx, actorID, collectionID, role)
+				if errors.Is(err, ErrPermissionDenied) {
+					return nil, apierrors.Mind.PermissionDenied("requires " + role + " role on collection")
+				}
+				if err != nil {
+					return nil, apierrors.Mind.Internal("failed to check permission", err)
+				}
+			}
+
+			return next(ctx, req)
+		}
+	})
+}
+
+// WriteResolvers maps note, collection, and tag write procedures to the collections they modify.
+// Moving a note or collection requires the role on both the current and the target collection.
+// Renaming a tag rewrites the body of every note carrying it, so it requires the role on all
+// of their collections.
+func (s *PermissionsService) WriteResolvers() map[string]CollectionResolver {
+	return map[string]CollectionResolver{
+		mindv3connect.NotesServiceCreateNoteProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.CreateNoteRequest)
+			return []int64{collectionOrDefault(msg.CollectionId)}, nil
+		},
+		mindv3connect.NotesServiceNewNoteProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.NewNoteRequest)
+			return []int64{collectionOrDefault(msg.CollectionId)}, nil
+		},
+		mindv3connect.NotesServiceReplaceNoteProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.ReplaceNoteRequest)
+			return s.noteCollections(ctx, msg.Id, collectionOrDefault(msg.CollectionId))
+		},
+		mindv3connect.NotesServiceUpdateNoteProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.UpdateNoteRequest)
+			return s.noteCollections(ctx, msg.Id, msg.GetCollectionId())
+		},
+		mindv3connect.NotesServiceDeleteNoteProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.DeleteNoteRequest)
+			return s.noteCollections(ctx, msg.Id, 0)
+		},
+		mindv3connect.NotesServiceDuplicateNoteProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.DuplicateNoteRequest)
+			if msg.TargetCollectionId != nil {
+				return []int64{*msg.TargetCollectionId}, nil
+			}
+			return s.noteCollections(ctx, msg.SourceId, 0)
+		},
+		mindv3connect.NotesServiceBulkTagNotesProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.BulkTagNotesRequest)
+			return s.notesCollections(ctx, msg.NoteIds)
+		},
+		mindv3connect.TagsServiceRenameTagProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.RenameTagRequest)
+			return s.tagCollections(ctx, msg.OldName)
+		},
+		mindv3connect.CollectionsServiceCreateCollectionProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.CreateCollectionRequest)
+			if msg.ParentId == nil {
+				return nil, nil // New root collections have nothing to inherit from
+			}
+			return []int64{*msg.ParentId}, nil
+		},
+		mindv3connect.CollectionsServiceUpdateCollectionProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.UpdateCollectionRequest)
+			ids := []int64{msg.Id}
+			if msg.ParentId != nil && *msg.ParentId != msg.Id {
+				ids = append(ids, *msg.ParentId)
+			}
+			return ids, nil
+		},
+		mindv3connect.CollectionsServiceDeleteCollectionProcedure: func(ctx context.Context, req connect.AnyRequest) ([]int64, error) {
+			msg := req.Any().(*mindv3.DeleteCollectionRequest)
+			return []int64{msg.Id}, nil
+		},
+	}
+}
+
+// noteCollections returns the collection of a note, plus target if it is set and differs.
+// A missing note resolves to no collections so the handler can report NotFound.
+func (s *PermissionsService) noteCollections(ctx context.Context, noteID, target int64) ([]int64, error) {
+	note, err := s.store.GetNoteByID(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	ids := []int64{note.CollectionID}
+	if target != 0 && target != note.CollectionID {
+		ids = append(ids, target)
+	}
+	return ids, nil
+}
+
+// notesCollections returns the distinct collections of the given notes.
+// Missing notes are skipped so the handler can report NotFound.
+func (s *PermissionsService) notesCollections(ctx context.Context, noteIDs []int64) ([]int64, error) {
+	notes, err := s.store.ListNotesByIDs(ctx, noteIDs)
+	if err != nil {
+		return nil, err
+	}
+	return distinctCollections(notes), nil
+}
+
+// distinctCollections returns the collections of rows in first-seen order.
+func distinctCollections(rows []store.Note) []int64 {
+	seen := make(map[int64]bool, len(rows))
+	var ids []int64
+	for _, note := range rows {
+		if !seen[note.CollectionID] {
+			seen[note.CollectionID] = true
+			ids = append(ids, note.CollectionID)
+		}
+	}
+	return ids
+}
+
+// tagCollections returns the distinct collections of the notes carrying the named tag.
+// A missing tag resolves to no collections so the handler can report NotFound.
+func (s *PermissionsService) tagCollections(ctx context.Context, name string) ([]int64, error) {
+	tag, err := s.store.GetTagByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	tagged, err := s.store.ListNotesForTag(ctx, tag.ID)
+	if err != nil {
+		return nil, err
+	}
+	return distinctCollections(tagged), nil
+}
+
+// collectionOrDefault mirrors the handlers' fallback to the root collection.
+func collectionOrDefault(collectionID *int64) int64 {
+	if collectionID == nil {
+		return notes.DefaultCollectionID
+	}
+	return *collectionID
+}
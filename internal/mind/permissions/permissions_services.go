@@ -0,0 +1,150 @@
+// Package permissions implements per-actor roles on collections.
+//
+// Roles are ordered viewer < editor < owner; holding a role implies the ones below it.
+// A collection without any grants is open to every actor, so existing single-user setups
+// keep working until the first grant is made. Grants apply to the collection itself only,
+// not to its children.
+package permissions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	sharedErrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// Collection roles, from least to most privileged.
+const (
+	RoleViewer = "viewer" // Read notes and the collection
+	RoleEditor = "editor" // Also create, update, and delete notes and the collection
+	RoleOwner  = "owner"  // Also manage grants
+)
+
+// roleRanks orders roles so a higher role satisfies a lower requirement.
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// PermissionsService provides business logic for collection permissions.
+type PermissionsService struct {
+	store  store.Querier
+	logger *slog.Logger
+}
+
+// NewPermissionsService creates a new PermissionsService.
+func NewPermissionsService(store store.Querier, logger *slog.Logger, serviceName string) *PermissionsService {
+	return &PermissionsService{
+		store:  store,
+		logger: logger.With("service", serviceName),
+	}
+}
+
+// GrantPermission gives actorID role on a collection, replacing any role it already had.
+// Returns ErrCollectionNotFound if the collection doesn't exist.
+func (s *PermissionsService) GrantPermission(ctx context.Context, collectionID int64, actorID, role string) error {
+	if actorID == "" {
+		return ErrInvalidActorID
+	}
+	if _, ok := roleRanks[role]; !ok {
+		return ErrInvalidRole
+	}
+
+	err := s.store.UpsertCollectionPermission(ctx, store.UpsertCollectionPermissionParams{
+		CollectionID: collectionID,
+		ActorID:      actorID,
+		Role:         role,
+	})
+	if err != nil {
+		if sharedErrors.IsForeignKeyConstraintError(err) {
+			return ErrCollectionNotFound
+		}
+		s.logger.Error("failed to grant permission", "collection_id", collectionID, "grantee", actorID, "role", role, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("permission granted", "collection_id", collectionID, "grantee", actorID, "role", role)
+	return nil
+}
+
+// RevokePermission removes actorID's grant on a collection.
+// Returns ErrPermissionNotFound if the actor has no grant.
+func (s *PermissionsService) RevokePermission(ctx context.Context, collectionID int64, actorID string) error {
+	if actorID == "" {
+		return ErrInvalidActorID
+	}
+
+	result, err := s.store.DeleteCollectionPermission(ctx, store.DeleteCollectionPermissionParams{
+		CollectionID: collectionID,
+		ActorID:      actorID,
+	})
+	if err != nil {
+		s.logger.Error("failed to revoke permission", "collection_id", collectionID, "grantee", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrPermissionNotFound
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("permission revoked", "collection_id", collectionID, "grantee", actorID)
+	return nil
+}
+
+// ListPermissions returns all grants on a collection, ordered by actor ID.
+func (s *PermissionsService) ListPermissions(ctx context.Context, collectionID int64) ([]store.CollectionPermission, error) {
+	grants, err := s.store.ListCollectionPermissions(ctx, collectionID)
+	if err != nil {
+		s.logger.Error("failed to list permissions", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+	}
+	return grants, err
+}
+
+// CheckPermission returns nil if actorID holds role (or a higher one) on a collection.
+// Collections without grants allow every actor, including anonymous ones (empty actorID).
+// Returns ErrPermissionDenied otherwise.
+func (s *PermissionsService) CheckPermission(ctx context.Context, actorID string, collectionID int64, role string) error {
+	required, ok := roleRanks[role]
+	if !ok {
+		return ErrInvalidRole
+	}
+
+	count, err := s.store.CountCollectionPermissions(ctx, collectionID)
+	if err != nil {
+		s.logger.Error("failed to count permissions", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	if actorID == "" {
+		return ErrPermissionDenied
+	}
+
+	grant, err := s.store.GetCollectionPermission(ctx, store.GetCollectionPermissionParams{
+		CollectionID: collectionID,
+		ActorID:      actorID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrPermissionDenied
+		}
+		s.logger.Error("failed to get permission", "collection_id", collectionID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	if roleRanks[grant.Role] < required {
+		return ErrPermissionDenied
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/gen/proto/mind/v3/mindv3connect"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// testActorHeader carries the actor ID into test requests in place of real authentication.
+const testActorHeader = "X-Test-Actor"
+
+// setupTestService creates a PermissionsService with a test database for testing.
+func setupTestService(t *testing.T) (*PermissionsService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	logger := testdb.NewTestLogger(t)
+	service := NewPermissionsService(queries, logger, "permissions-test")
+
+	return service, queries
+}
+
+// createTestCollection creates a root collection for testing.
+func createTestCollection(t *testing.T, queries *store.Queries, name string) int64 {
+	t.Helper()
+
+	id, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{
+		Name: name,
+		Path: utils.GenerateSlug(name),
+	})
+	require.NoError(t, err)
+	return id
+}
+
+// newDeleteNoteClient serves NotesService/DeleteNote wrapped with the permission interceptor.
+// The handler itself always succeeds, so any error comes from the interceptor.
+func newDeleteNoteClient(t *testing.T, service *PermissionsService) *connect.Client[mindv3.DeleteNoteRequest, emptypb.Empty] {
+	t.Helper()
+
+	procedure := mindv3connect.NotesServiceDeleteNoteProcedure
+	handler := connect.NewUnaryHandler(procedure,
+		func(ctx context.Context, req *connect.Request[mindv3.DeleteNoteRequest]) (*connect.Response[emptypb.Empty], error) {
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(service.RequireCollectionPermission(RoleEditor)),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(procedure, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := middleware.WithActorID(r.Context(), r.Header.Get(testActorHeader))
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return connect.NewClient[mindv3.DeleteNoteRequest, emptypb.Empty](server.Client(), server.URL+procedure)
+}
+
+func TestPermissions_GrantCheckRevokeLifecycle(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Shared")
+
+	// No grants yet: the collection is open to everyone
+	require.NoError(t, service.CheckPermission(ctx, "bob", collectionID, RoleOwner))
+
+	require.NoError(t, service.GrantPermission(ctx, collectionID, "alice", RoleOwner))
+	require.NoError(t, service.GrantPermission(ctx, collectionID, "bob", RoleViewer))
+
+	require.NoError(t, service.CheckPermission(ctx, "alice", collectionID, RoleEditor))
+	require.NoError(t, service.CheckPermission(ctx, "bob", collectionID, RoleViewer))
+	require.ErrorIs(t, service.CheckPermission(ctx, "bob", collectionID, RoleEditor), ErrPermissionDenied)
+	require.ErrorIs(t, service.CheckPermission(ctx, "carol", collectionID, RoleViewer), ErrPermissionDenied)
+	require.ErrorIs(t, service.CheckPermission(ctx, "", collectionID, RoleViewer), ErrPermissionDenied)
+
+	// Granting again replaces the role
+	require.NoError(t, service.GrantPermission(ctx, collectionID, "bob", RoleEditor))
+	require.NoError(t, service.CheckPermission(ctx, "bob", collectionID, RoleEditor))
+
+	grants, err := service.ListPermissions(ctx, collectionID)
+	require.NoError(t, err)
+	require.Len(t, grants, 2)
+
+	require.NoError(t, service.RevokePermission(ctx, collectionID, "bob"))
+	require.ErrorIs(t, service.RevokePermission(ctx, collectionID, "bob"), ErrPermissionNotFound)
+	require.ErrorIs(t, service.CheckPermission(ctx, "bob", collectionID, RoleViewer), ErrPermissionDenied)
+
+	require.ErrorIs(t, service.GrantPermission(ctx, collectionID, "bob", "admin"), ErrInvalidRole)
+	require.ErrorIs(t, service.GrantPermission(ctx, collectionID, "", RoleViewer), ErrInvalidActorID)
+}
+
+func TestRequireCollectionPermission_RejectsViewerOnWrite(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID := createTestCollection(t, queries, "Shared")
+	noteID, err := queries.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        "Plan",
+		CollectionID: collectionID,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, service.GrantPermission(ctx, collectionID, "alice", RoleEditor))
+	require.NoError(t, service.GrantPermission(ctx, collectionID, "bob", RoleViewer))
+
+	client := newDeleteNoteClient(t, service)
+	deleteAs := func(actorID string, id int64) error {
+		req := connect.NewRequest(&mindv3.DeleteNoteRequest{Id: id})
+		req.Header().Set(testActorHeader, actorID)
+		_, err := client.CallUnary(ctx, req)
+		return err
+	}
+
+	require.Equal(t, connect.CodePermissionDenied, connect.CodeOf(deleteAs("bob", noteID)))
+	require.Equal(t, connect.CodePermissionDenied, connect.CodeOf(deleteAs("", noteID)))
+	require.NoError(t, deleteAs("alice", noteID))
+
+	// Unknown notes are left to the handler to report as not found
+	require.NoError(t, deleteAs("bob", noteID+1000))
+}
+
+func TestWriteResolvers_RenameTagCoversTaggedNoteCollections(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	work := createTestCollection(t, queries, "Work")
+	home := createTestCollection(t, queries, "Home")
+	tagID, err := queries.CreateTag(ctx, "project")
+	require.NoError(t, err)
+
+	for _, collectionID := range []int64{work, home, work} {
+		noteID, err := queries.CreateNote(ctx, store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        uuid.NewString(),
+			CollectionID: collectionID,
+		})
+		require.NoError(t, err)
+		require.NoError(t, queries.CreateNoteTag(ctx, store.CreateNoteTagParams{NoteID: noteID, TagID: tagID}))
+	}
+
+	ids, err := service.tagCollections(ctx, "project")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{work, home}, ids)
+
+	// Unknown tags are left to the handler to report as not found
+	ids, err = service.tagCollections(ctx, "missing")
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func TestAdminHandler_GrantListRevoke(t *testing.T) {
+	service, queries := setupTestService(t)
+	collectionID := createTestCollection(t, queries, "Shared")
+
+	e := echo.New()
+	NewAdminHandler(service).RegisterRoutes(e)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	base := fmt.Sprintf("/admin/mind/collections/%d/permissions", collectionID)
+
+	rec := send(http.MethodPut, base+"/alice", `{"role": "editor"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPut, base+"/bob", `{"role": "admin"}`).Code)
+
+	rec = send(http.MethodGet, base, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"grants": [{"actor_id": "alice", "role": "editor"}]}`, rec.Body.String())
+
+	require.Equal(t, http.StatusNoContent, send(http.MethodDelete, base+"/alice", "").Code)
+	require.Equal(t, http.StatusNotFound, send(http.MethodDelete, base+"/alice", "").Code)
+	require.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/admin/mind/collections/abc/permissions", "").Code)
+}
@@ -0,0 +1,47 @@
+package presence
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// Handler upgrades presence requests to WebSockets and attaches them to a PresenceHub.
+type Handler struct {
+	hub *PresenceHub
+}
+
+// NewHandler creates a new presence handler.
+func NewHandler(hub *PresenceHub) *Handler {
+	return &Handler{hub: hub}
+}
+
+// HandleConnect handles GET /ws/mind/notes/:id/presence.
+// The caller must be authenticated (middleware.GetActorID); the connection stays subscribed
+// until the client closes it. Messages sent by the client are ignored.
+func (h *Handler) HandleConnect(c echo.Context) error {
+	noteID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || noteID <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid note id")
+	}
+
+	actorID := middleware.GetActorID(c.Request().Context())
+	if actorID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "presence requires an authenticated actor")
+	}
+
+	websocket.Handler(func(conn *websocket.Conn) {
+		h.hub.Subscribe(noteID, actorID, conn)
+		defer h.hub.Unsubscribe(noteID, conn)
+
+		// Reading is how a disconnect is noticed
+		var discard []byte
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}).ServeHTTP(c.Response(), c.Request())
+	return nil
+}
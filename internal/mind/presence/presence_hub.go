@@ -0,0 +1,157 @@
+// Package presence tracks which actors have a note open for editing and
+// broadcasts joins and departures to the other clients on the same note.
+package presence
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// Presence event types.
+const (
+	EventJoined = "joined"
+	EventLeft   = "left"
+)
+
+// writeTimeout bounds how long a slow client can hold up a broadcast.
+const writeTimeout = 5 * time.Second
+
+// PresenceEvent is both the hub's command and the JSON message sent to clients.
+type PresenceEvent struct {
+	Type    string `json:"type"` // EventJoined or EventLeft
+	NoteID  int64  `json:"note_id"`
+	ActorID string `json:"actor_id"`
+
+	conn *websocket.Conn // Connection the event originates from; never sent
+}
+
+// PresenceHub fans out presence events per note.
+// All membership changes go through a single goroutine reading from events, so broadcasts
+// for a note are delivered in the order the changes happened.
+type PresenceHub struct {
+	// notes maps note ID to map[*websocket.Conn]string (connection -> actor ID).
+	// The inner maps are replaced, never mutated, so Editors can read them without locking.
+	notes  sync.Map
+	events chan PresenceEvent
+	done   chan struct{}
+	once   sync.Once
+	logger *slog.Logger
+}
+
+// NewPresenceHub creates a hub and starts its event loop. Call Close to stop it.
+func NewPresenceHub(logger *slog.Logger) *PresenceHub {
+	h := &PresenceHub{
+		events: make(chan PresenceEvent, 64),
+		done:   make(chan struct{}),
+		logger: logger.With("component", "presence-hub"),
+	}
+	go h.run()
+	return h
+}
+
+// Subscribe registers conn as actorID editing noteID and tells the other clients on the note.
+// The new client is sent a joined event for every actor already present.
+func (h *PresenceHub) Subscribe(noteID int64, actorID string, conn *websocket.Conn) {
+	h.send(PresenceEvent{Type: EventJoined, NoteID: noteID, ActorID: actorID, conn: conn})
+}
+
+// Unsubscribe removes conn from noteID and tells the remaining clients the actor left.
+func (h *PresenceHub) Unsubscribe(noteID int64, conn *websocket.Conn) {
+	h.send(PresenceEvent{Type: EventLeft, NoteID: noteID, conn: conn})
+}
+
+// Editors returns the sorted actor IDs connected to noteID (an actor with two tabs appears twice).
+func (h *PresenceHub) Editors(noteID int64) []string {
+	clients := h.clients(noteID)
+	actors := make([]string, 0, len(clients))
+	for _, actorID := range clients {
+		actors = append(actors, actorID)
+	}
+	sort.Strings(actors)
+	return actors
+}
+
+// Close stops the event loop. Subscriptions made afterwards are ignored.
+func (h *PresenceHub) Close() {
+	h.once.Do(func() { close(h.done) })
+}
+
+func (h *PresenceHub) send(event PresenceEvent) {
+	select {
+	case h.events <- event:
+	case <-h.done:
+	}
+}
+
+func (h *PresenceHub) run() {
+	for {
+		select {
+		case event := <-h.events:
+			h.apply(event)
+		case <-h.done:
+			h.logger.Info("presence hub closed")
+			return
+		}
+	}
+}
+
+// apply updates membership for one event and broadcasts it to the note's other clients.
+func (h *PresenceHub) apply(event PresenceEvent) {
+	current := h.clients(event.NoteID)
+
+	switch event.Type {
+	case EventJoined:
+		for _, actorID := range current {
+			h.write(event.conn, PresenceEvent{Type: EventJoined, NoteID: event.NoteID, ActorID: actorID})
+		}
+	case EventLeft:
+		actorID, ok := current[event.conn]
+		if !ok {
+			return
+		}
+		event.ActorID = actorID
+	}
+
+	next := make(map[*websocket.Conn]string, len(current)+1)
+	for conn, actorID := range current {
+		if conn != event.conn {
+			next[conn] = actorID
+		}
+	}
+	if event.Type == EventJoined {
+		next[event.conn] = event.ActorID
+	}
+	if len(next) == 0 {
+		h.notes.Delete(event.NoteID)
+	} else {
+		h.notes.Store(event.NoteID, next)
+	}
+
+	h.logger.Debug("presence changed", "type", event.Type, "note_id", event.NoteID, "actor_id", event.ActorID, "clients", len(next))
+
+	for conn := range next {
+		if conn != event.conn {
+			h.write(conn, event)
+		}
+	}
+}
+
+func (h *PresenceHub) clients(noteID int64) map[*websocket.Conn]string {
+	if v, ok := h.notes.Load(noteID); ok {
+		return v.(map[*websocket.Conn]string)
+	}
+	return nil
+}
+
+// write sends event to conn. Failures are only logged: the client's own handler
+// sees the broken connection and unsubscribes it.
+func (h *PresenceHub) write(conn *websocket.Conn, event PresenceEvent) {
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := websocket.JSON.Send(conn, event); err != nil {
+		h.logger.Debug("failed to send presence event", "note_id", event.NoteID, "err", err)
+	}
+}
@@ -0,0 +1,132 @@
+package presence
+
+import (
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// actorHeader stands in for API key auth in tests: its value becomes the actor ID.
+const actorHeader = "X-Test-Actor"
+
+// setupTestServer serves the presence endpoint and returns the hub and the server's ws:// URL.
+func setupTestServer(t *testing.T) (*PresenceHub, string) {
+	t.Helper()
+
+	hub := NewPresenceHub(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(hub.Close)
+
+	e := echo.New()
+	fakeAuth := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if actorID := c.Request().Header.Get(actorHeader); actorID != "" {
+				req := c.Request()
+				c.SetRequest(req.WithContext(middleware.WithActorID(req.Context(), actorID)))
+			}
+			return next(c)
+		}
+	}
+	e.GET("/ws/mind/notes/:id/presence", NewHandler(hub).HandleConnect, fakeAuth)
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return hub, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+// connect opens a presence WebSocket for noteID as actorID.
+func connect(t *testing.T, baseURL, path, actorID string) *websocket.Conn {
+	t.Helper()
+
+	config, err := websocket.NewConfig(baseURL+path, "http://localhost/")
+	require.NoError(t, err)
+	config.Header.Set(actorHeader, actorID)
+
+	conn, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// receive reads the next presence event, failing the test after a second.
+func receive(t *testing.T, conn *websocket.Conn) PresenceEvent {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	var event PresenceEvent
+	require.NoError(t, websocket.JSON.Receive(conn, &event))
+	return event
+}
+
+// waitForEditors polls until the hub reports the expected editors of noteID.
+func waitForEditors(t *testing.T, hub *PresenceHub, noteID int64, expected ...string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		return strings.Join(hub.Editors(noteID), ",") == strings.Join(expected, ",")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestPresence_JoinAndLeaveBroadcast(t *testing.T) {
+	hub, baseURL := setupTestServer(t)
+
+	alice := connect(t, baseURL, "/ws/mind/notes/7/presence", "alice")
+	waitForEditors(t, hub, 7, "alice")
+
+	bob := connect(t, baseURL, "/ws/mind/notes/7/presence", "bob")
+	require.Equal(t, PresenceEvent{Type: EventJoined, NoteID: 7, ActorID: "bob"}, receive(t, alice))
+	// The newcomer learns who is already editing
+	require.Equal(t, PresenceEvent{Type: EventJoined, NoteID: 7, ActorID: "alice"}, receive(t, bob))
+
+	require.NoError(t, bob.Close())
+	require.Equal(t, PresenceEvent{Type: EventLeft, NoteID: 7, ActorID: "bob"}, receive(t, alice))
+	waitForEditors(t, hub, 7, "alice")
+}
+
+func TestPresence_NotesAreIsolated(t *testing.T) {
+	hub, baseURL := setupTestServer(t)
+
+	alice := connect(t, baseURL, "/ws/mind/notes/1/presence", "alice")
+	waitForEditors(t, hub, 1, "alice")
+
+	connect(t, baseURL, "/ws/mind/notes/2/presence", "bob")
+	waitForEditors(t, hub, 2, "bob")
+
+	require.NoError(t, alice.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	var event PresenceEvent
+	require.Error(t, websocket.JSON.Receive(alice, &event), "expected no event from another note, got %+v", event)
+}
+
+func TestPresence_RejectsBadRequests(t *testing.T) {
+	_, baseURL := setupTestServer(t)
+
+	tests := []struct {
+		name    string
+		path    string
+		actorID string
+	}{
+		{"unauthenticated", "/ws/mind/notes/1/presence", ""},
+		{"invalid note id", "/ws/mind/notes/abc/presence", "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := websocket.NewConfig(baseURL+tt.path, "http://localhost/")
+			require.NoError(t, err)
+			if tt.actorID != "" {
+				config.Header.Set(actorHeader, tt.actorID)
+			}
+			_, err = websocket.DialConfig(config)
+			require.Error(t, err)
+		})
+	}
+}
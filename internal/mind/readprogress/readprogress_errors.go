@@ -0,0 +1,15 @@
+package readprogress
+
+import "errors"
+
+// Domain errors for ReadProgress
+var (
+	// ErrNoteNotFound is returned when the note does not exist or is trashed.
+	ErrNoteNotFound = errors.New("note not found")
+
+	// ErrActorRequired is returned when no actor ID is given; positions are stored per actor.
+	ErrActorRequired = errors.New("actor id is required")
+
+	// ErrInvalidPosition is returned when a scroll position is outside [0.0, 1.0].
+	ErrInvalidPosition = errors.New("position must be between 0.0 and 1.0")
+)
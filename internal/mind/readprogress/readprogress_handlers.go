@@ -0,0 +1,84 @@
+package readprogress
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// ReadPosition is the JSON body of the read-position endpoints.
+type ReadPosition struct {
+	Position float64 `json:"position"`
+}
+
+// GetReadPositionHandler serves GET /notes/{id}/read-position for the authenticated actor.
+// Notes the actor hasn't read yet report position 0.0.
+func GetReadPositionHandler(service *ReadProgressService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		noteID, err := parseNoteID(c)
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		position, err := service.GetReadPosition(ctx, noteID, middleware.GetActorID(ctx))
+		if err != nil {
+			return httpError(err, "failed to get read position")
+		}
+		return c.JSON(http.StatusOK, ReadPosition{Position: position})
+	}
+}
+
+// UpdateReadPositionHandler serves PUT /notes/{id}/read-position with a ReadPosition body
+// for the authenticated actor, and echoes the stored position.
+func UpdateReadPositionHandler(service *ReadProgressService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		noteID, err := parseNoteID(c)
+		if err != nil {
+			return err
+		}
+
+		var body struct {
+			Position *float64 `json:"position"`
+		}
+		if err := c.Bind(&body); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+		if body.Position == nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "position is required")
+		}
+
+		ctx := c.Request().Context()
+		if err := service.UpdateReadPosition(ctx, noteID, middleware.GetActorID(ctx), *body.Position); err != nil {
+			return httpError(err, "failed to update read position")
+		}
+		return c.JSON(http.StatusOK, ReadPosition{Position: *body.Position})
+	}
+}
+
+// parseNoteID reads the positive note ID path parameter.
+func parseNoteID(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "invalid note id")
+	}
+	return id, nil
+}
+
+// httpError maps service errors to HTTP errors; anything unexpected becomes a 500 with msg.
+func httpError(err error, msg string) error {
+	switch {
+	case errors.Is(err, ErrNoteNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "note not found")
+	case errors.Is(err, ErrActorRequired):
+		return echo.NewHTTPError(http.StatusUnauthorized, "read positions require an authenticated actor")
+	case errors.Is(err, ErrInvalidPosition):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, msg)
+	}
+}
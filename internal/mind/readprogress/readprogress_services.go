@@ -0,0 +1,90 @@
+// Package readprogress tracks how far each actor has read into each note, so clients
+// can resume reading where the actor left off.
+package readprogress
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// ReadProgressService stores the last read position per actor and note.
+// A position is the fraction of the note scrolled through, from 0.0 (top) to 1.0 (end).
+type ReadProgressService struct {
+	store  store.Querier
+	logger *slog.Logger
+}
+
+// NewReadProgressService creates a new ReadProgressService.
+func NewReadProgressService(store store.Querier, logger *slog.Logger, serviceName string) *ReadProgressService {
+	return &ReadProgressService{
+		store:  store,
+		logger: logger.With("service", serviceName),
+	}
+}
+
+// UpdateReadPosition records position as the last read position of actorID in noteID,
+// replacing any earlier one.
+// Returns ErrInvalidPosition if position is outside [0.0, 1.0], ErrActorRequired if actorID
+// is empty, and ErrNoteNotFound if the note doesn't exist.
+func (s *ReadProgressService) UpdateReadPosition(ctx context.Context, noteID int64, actorID string, position float64) error {
+	if math.IsNaN(position) || position < 0 || position > 1 {
+		return ErrInvalidPosition
+	}
+	if err := s.checkNote(ctx, noteID, actorID); err != nil {
+		return err
+	}
+
+	err := s.store.UpsertNoteReadPosition(ctx, store.UpsertNoteReadPositionParams{
+		NoteID:         noteID,
+		ActorID:        actorID,
+		ScrollPosition: position,
+	})
+	if err != nil {
+		s.logger.Error("failed to update read position", "note_id", noteID, "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return nil
+}
+
+// GetReadPosition returns the last read position of actorID in noteID,
+// or 0.0 if the actor hasn't read the note yet.
+// Returns ErrActorRequired if actorID is empty and ErrNoteNotFound if the note doesn't exist.
+func (s *ReadProgressService) GetReadPosition(ctx context.Context, noteID int64, actorID string) (float64, error) {
+	if err := s.checkNote(ctx, noteID, actorID); err != nil {
+		return 0, err
+	}
+
+	position, err := s.store.GetNoteReadPosition(ctx, store.GetNoteReadPositionParams{
+		NoteID:  noteID,
+		ActorID: actorID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		s.logger.Error("failed to get read position", "note_id", noteID, "actor_id", actorID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	return position, nil
+}
+
+// checkNote validates actorID and that noteID is a live note.
+func (s *ReadProgressService) checkNote(ctx context.Context, noteID int64, actorID string) error {
+	if actorID == "" {
+		return ErrActorRequired
+	}
+	if _, err := s.store.GetNoteByID(ctx, noteID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoteNotFound
+		}
+		s.logger.Error("failed to get note", "note_id", noteID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package readprogress
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+)
+
+// setupTestService creates a ReadProgressService with a test database for testing.
+func setupTestService(t *testing.T) (*ReadProgressService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	logger := testdb.NewTestLogger(t)
+	service := NewReadProgressService(queries, logger, "readprogress-test")
+
+	return service, queries
+}
+
+// createTestNote creates a note in a new collection and returns its ID.
+func createTestNote(t *testing.T, queries *store.Queries, title string) int64 {
+	t.Helper()
+	ctx := context.Background()
+
+	collectionID, err := queries.CreateCollection(ctx, store.CreateCollectionParams{
+		Name: title + " Collection",
+		Path: strings.ToLower(strings.ReplaceAll(title, " ", "-")),
+	})
+	require.NoError(t, err)
+
+	id, err := queries.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        title,
+		CollectionID: collectionID,
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestReadPosition_CreateUpdateAndGet(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	noteID := createTestNote(t, queries, "Long Read")
+
+	// Unread notes start at the top
+	position, err := service.GetReadPosition(ctx, noteID, "alice")
+	require.NoError(t, err)
+	require.Equal(t, 0.0, position)
+
+	require.NoError(t, service.UpdateReadPosition(ctx, noteID, "alice", 0.25))
+	position, err = service.GetReadPosition(ctx, noteID, "alice")
+	require.NoError(t, err)
+	require.Equal(t, 0.25, position)
+
+	require.NoError(t, service.UpdateReadPosition(ctx, noteID, "alice", 0.75))
+	position, err = service.GetReadPosition(ctx, noteID, "alice")
+	require.NoError(t, err)
+	require.Equal(t, 0.75, position)
+
+	// Positions are per actor
+	position, err = service.GetReadPosition(ctx, noteID, "bob")
+	require.NoError(t, err)
+	require.Equal(t, 0.0, position)
+}
+
+func TestReadPosition_Errors(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	noteID := createTestNote(t, queries, "Long Read")
+
+	for _, position := range []float64{-0.1, 1.1} {
+		require.ErrorIs(t, service.UpdateReadPosition(ctx, noteID, "alice", position), ErrInvalidPosition)
+	}
+	require.NoError(t, service.UpdateReadPosition(ctx, noteID, "alice", 1.0))
+
+	require.ErrorIs(t, service.UpdateReadPosition(ctx, noteID, "", 0.5), ErrActorRequired)
+	require.ErrorIs(t, service.UpdateReadPosition(ctx, 9999, "alice", 0.5), ErrNoteNotFound)
+
+	_, err := service.GetReadPosition(ctx, 9999, "alice")
+	require.ErrorIs(t, err, ErrNoteNotFound)
+}
+
+func TestReadPositionHandlers(t *testing.T) {
+	service, queries := setupTestService(t)
+	noteID := createTestNote(t, queries, "Long Read")
+
+	e := echo.New()
+	asActor := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if actorID := c.Request().Header.Get("X-Actor"); actorID != "" {
+				req := c.Request()
+				c.SetRequest(req.WithContext(middleware.WithActorID(req.Context(), actorID)))
+			}
+			return next(c)
+		}
+	}
+	e.GET("/notes/:id/read-position", GetReadPositionHandler(service), asActor)
+	e.PUT("/notes/:id/read-position", UpdateReadPositionHandler(service), asActor)
+
+	send := func(method, target, actorID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		if actorID != "" {
+			req.Header.Set("X-Actor", actorID)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	target := "/notes/" + strconv.FormatInt(noteID, 10) + "/read-position"
+
+	rec := send(http.MethodPut, target, "alice", `{"position": 0.75}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = send(http.MethodGet, target, "alice", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got ReadPosition
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Equal(t, 0.75, got.Position)
+
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPut, target, "alice", `{"position": 2}`).Code)
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPut, target, "alice", `{}`).Code)
+	require.Equal(t, http.StatusUnauthorized, send(http.MethodGet, target, "", "").Code)
+	require.Equal(t, http.StatusNotFound, send(http.MethodGet, "/notes/9999/read-position", "alice", "").Code)
+	require.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/notes/abc/read-position", "alice", "").Code)
+}
@@ -7,19 +7,42 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"sync"
 	"time"
 )
 
+// Priority controls how soon a tracked change is sent to Brain.
+type Priority int
+
+const (
+	PriorityNormal   Priority = iota // Sent with the next periodic or batch-size flush
+	PriorityCritical                 // Sent immediately (e.g., deletions Brain must not keep serving)
+)
+
 // ChangeEvent represents a single note modification that Brain should process.
 type ChangeEvent struct {
 	EventType  string    `json:"event_type"`  // "note_created", "note_updated", "note_deleted"
 	NoteID     int64     `json:"note_id"`     // ID of the affected note
 	Timestamp  time.Time `json:"timestamp"`   // When the change occurred
 	UserAction bool      `json:"user_action"` // true if user-initiated (vs. system)
+	Priority   Priority  `json:"priority"`    // PriorityCritical changes bypass the flush interval
+}
+
+// NoteReader looks up which collection notes belong to, so flushes can be grouped by collection.
+// Implemented by notes.NotesService; declared here because notes imports this package.
+type NoteReader interface {
+	GetNoteCollectionIDs(ctx context.Context, noteIDs []int64) (map[int64]int64, error)
+}
+
+// brainBatch is the JSON body posted to Brain's ingestion API.
+type brainBatch struct {
+	CollectionID int64         `json:"collection_id,omitempty"` // Set when changes are grouped by collection
+	Changes      []ChangeEvent `json:"changes"`
 }
 
 // ChangeAccumulator collects note changes and periodically flushes them to Brain.
@@ -28,15 +51,27 @@ type ChangeEvent struct {
 type ChangeAccumulator struct {
 	mu       sync.Mutex
 	changes  []ChangeEvent
+	critical chan ChangeEvent // Critical changes, flushed by the ticker goroutine as they arrive
 	ticker   *time.Ticker
 	stopChan chan struct{}
+	done     chan struct{}  // Closed when the ticker goroutine exits
+	flushes  sync.WaitGroup // Batch-size flushes started by TrackChange
+	stopOnce sync.Once
+	stopErr  error
 
-	brainURL string // Brain ingestion API endpoint
+	// Guarded by mu: last flush that succeeded or had nothing to send, reported by CheckHealth
+	lastFlushAt time.Time
+
+	brainURL string       // Brain ingestion API endpoint
+	client   *http.Client // Shared by all flushes so connections to Brain are reused
 	logger   *slog.Logger
+	breaker  *circuitBreaker // Skips flushes while Brain keeps failing
+	notes    NoteReader      // Optional: collection lookup for grouped flushes
 
 	// Config
-	flushInterval time.Duration
-	batchSize     int // Max changes per batch
+	flushInterval     time.Duration
+	batchSize         int  // Max changes per batch
+	groupByCollection bool // Send one request per collection instead of one flat batch
 }
 
 // Config holds scheduler configuration.
@@ -44,6 +79,14 @@ type Config struct {
 	BrainURL      string        // e.g., "http://localhost:8080"
 	FlushInterval time.Duration // e.g., 5 * time.Minute
 	BatchSize     int           // e.g., 100
+
+	CircuitBreaker CircuitBreakerConfig // Zero value uses defaults (5 failures, 1 minute)
+	Transport      TransportConfig      // Zero value uses defaults (10 idle connections per host, 90 seconds)
+
+	// GroupByCollection sends each flush as one request per collection, with the
+	// collection ID in the payload. Requires NoteReader; ignored without it.
+	GroupByCollection bool
+	NoteReader        NoteReader
 }
 
 // NewChangeAccumulator creates a new change accumulator.
@@ -56,12 +99,19 @@ func NewChangeAccumulator(cfg Config, logger *slog.Logger) *ChangeAccumulator {
 	}
 
 	return &ChangeAccumulator{
-		changes:       make([]ChangeEvent, 0),
-		stopChan:      make(chan struct{}),
-		brainURL:      cfg.BrainURL,
-		logger:        logger.With("component", "scheduler"),
-		flushInterval: cfg.FlushInterval,
-		batchSize:     cfg.BatchSize,
+		changes:           make([]ChangeEvent, 0),
+		critical:          make(chan ChangeEvent, cfg.BatchSize),
+		stopChan:          make(chan struct{}),
+		done:              make(chan struct{}),
+		brainURL:          cfg.BrainURL,
+		client:            newHTTPClient(cfg.Transport),
+		logger:            logger.With("component", "scheduler"),
+		breaker:           newCircuitBreaker(cfg.CircuitBreaker),
+		notes:             cfg.NoteReader,
+		flushInterval:     cfg.FlushInterval,
+		batchSize:         cfg.BatchSize,
+		groupByCollection: cfg.GroupByCollection && cfg.NoteReader != nil,
+		lastFlushAt:       time.Now(),
 	}
 }
 
@@ -70,15 +120,27 @@ func (c *ChangeAccumulator) Start() {
 	c.logger.Info("starting change accumulator",
 		"flush_interval", c.flushInterval,
 		"batch_size", c.batchSize,
+		"group_by_collection", c.groupByCollection,
 		"brain_url", c.brainURL)
 
 	c.ticker = time.NewTicker(c.flushInterval)
 
 	go func() {
+		defer close(c.done)
 		for {
 			select {
+			case change := <-c.critical:
+				c.enqueue(change)
+				c.drainCritical()
+				c.logger.Info("critical change tracked, flushing immediately",
+					"event_type", change.EventType,
+					"note_id", change.NoteID)
+				if err := c.flush(context.Background(), false); err != nil {
+					c.logger.Error("failed to flush changes", "error", err)
+				}
 			case <-c.ticker.C:
-				if err := c.flush(context.Background()); err != nil {
+				c.drainCritical()
+				if err := c.flush(context.Background(), false); err != nil {
 					c.logger.Error("failed to flush changes", "error", err)
 				}
 			case <-c.stopChan:
@@ -90,17 +152,30 @@ func (c *ChangeAccumulator) Start() {
 }
 
 // Stop stops the accumulator and flushes any pending changes.
+// Prefer DrainAndStop on shutdown so the final flush honors the shutdown deadline.
 func (c *ChangeAccumulator) Stop() error {
-	c.logger.Info("stopping change accumulator")
-
-	if c.ticker != nil {
-		c.ticker.Stop()
-	}
+	return c.DrainAndStop(context.Background())
+}
 
-	close(c.stopChan)
+// DrainAndStop stops the ticker goroutine, waits for in-flight flushes, then flushes
+// the pending queue exactly once with ctx. The final flush is attempted even while the
+// circuit breaker is open, since changes left pending now would be lost.
+// Only the first call does any work; later calls return its result.
+func (c *ChangeAccumulator) DrainAndStop(ctx context.Context) error {
+	c.stopOnce.Do(func() {
+		c.logger.Info("draining change accumulator", "pending_changes", c.GetPendingCount())
+
+		if c.ticker != nil {
+			c.ticker.Stop()
+			close(c.stopChan)
+			<-c.done
+		}
+		c.flushes.Wait()
+		c.drainCritical()
 
-	// Final flush
-	return c.flush(context.Background())
+		c.stopErr = c.flush(ctx, true)
+	})
+	return c.stopErr
 }
 
 // TrackChange records a note modification event.
@@ -125,24 +200,76 @@ func (c *ChangeAccumulator) TrackChange(eventType string, noteID int64) {
 	if len(c.changes) >= c.batchSize {
 		c.logger.Info("batch size limit reached, flushing immediately",
 			"pending_changes", len(c.changes))
+		c.flushes.Add(1)
 		go func() {
-			if err := c.flush(context.Background()); err != nil {
+			defer c.flushes.Done()
+			if err := c.flush(context.Background(), false); err != nil {
 				c.logger.Error("failed to flush changes", "error", err)
 			}
 		}()
 	}
 }
 
+// TrackCriticalChange records a note modification that Brain must see right away.
+// The change is flushed as soon as the accumulator's goroutine picks it up, together with
+// any pending normal changes, instead of waiting for the flush interval.
+func (c *ChangeAccumulator) TrackCriticalChange(eventType string, noteID int64) {
+	change := ChangeEvent{
+		EventType:  eventType,
+		NoteID:     noteID,
+		Timestamp:  time.Now(),
+		UserAction: true,
+		Priority:   PriorityCritical,
+	}
+
+	select {
+	case c.critical <- change:
+		c.logger.Debug("tracked critical change", "event_type", eventType, "note_id", noteID)
+	default:
+		// A burst filled the channel; the goroutine is already flushing, so queue normally
+		c.enqueue(change)
+	}
+}
+
+// enqueue adds a change to the pending queue.
+func (c *ChangeAccumulator) enqueue(change ChangeEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.changes = append(c.changes, change)
+}
+
+// drainCritical moves buffered critical changes into the pending queue.
+func (c *ChangeAccumulator) drainCritical() {
+	for {
+		select {
+		case change := <-c.critical:
+			c.enqueue(change)
+		default:
+			return
+		}
+	}
+}
+
 // flush sends accumulated changes to Brain's ingestion API.
-func (c *ChangeAccumulator) flush(ctx context.Context) error {
+// With force the circuit breaker is not consulted (used for the final flush).
+func (c *ChangeAccumulator) flush(ctx context.Context, force bool) error {
 	c.mu.Lock()
 
 	if len(c.changes) == 0 {
+		c.lastFlushAt = time.Now()
 		c.mu.Unlock()
 		c.logger.Debug("no changes to flush")
 		return nil
 	}
 
+	// Keep changes pending while Brain is known to be failing
+	if !force && !c.breaker.allow() {
+		pending := len(c.changes)
+		c.mu.Unlock()
+		c.logger.Warn("circuit breaker open, skipping flush", "pending_changes", pending)
+		return nil
+	}
+
 	// Take a snapshot and clear the accumulator
 	changesToFlush := make([]ChangeEvent, len(c.changes))
 	copy(changesToFlush, c.changes)
@@ -155,29 +282,80 @@ func (c *ChangeAccumulator) flush(ctx context.Context) error {
 		"brain_url", c.brainURL)
 
 	// Send to Brain
-	if err := c.sendToBrain(ctx, changesToFlush); err != nil {
+	if err := c.sendBatches(ctx, c.batches(ctx, changesToFlush)); err != nil {
+		c.breaker.recordFailure()
 		c.logger.Error("failed to send changes to Brain",
 			"error", err,
-			"count", len(changesToFlush))
+			"count", len(changesToFlush),
+			"circuit_state", c.breaker.currentState().String())
 
 		// Note: Retry queue not implemented - See issue #40
 		// For now, we log and drop (Brain can re-ingest via manual API if needed)
 		return err
 	}
 
+	c.breaker.recordSuccess()
+	c.mu.Lock()
+	c.lastFlushAt = time.Now()
+	c.mu.Unlock()
 	c.logger.Info("successfully flushed changes to Brain", "count", len(changesToFlush))
 	return nil
 }
 
-// sendToBrain sends a batch of changes to Brain's ingestion API.
-func (c *ChangeAccumulator) sendToBrain(ctx context.Context, changes []ChangeEvent) error {
-	endpoint := fmt.Sprintf("%s/api/brain/ingest/batch", c.brainURL)
+// batches splits changes into the requests of one flush: a single flat batch, or with
+// GroupByCollection one batch per collection in order of first appearance. Changes whose
+// note no longer exists go in a batch without a collection ID. If the lookup fails the
+// changes are sent as one flat batch rather than held back.
+func (c *ChangeAccumulator) batches(ctx context.Context, changes []ChangeEvent) []brainBatch {
+	if !c.groupByCollection {
+		return []brainBatch{{Changes: changes}}
+	}
 
-	payload := map[string]any{
-		"changes": changes,
+	noteIDs := make([]int64, len(changes))
+	for i, change := range changes {
+		noteIDs[i] = change.NoteID
 	}
+	collectionIDs, err := c.notes.GetNoteCollectionIDs(ctx, noteIDs)
+	if err != nil {
+		c.logger.Warn("failed to look up note collections, sending one batch", "error", err, "count", len(changes))
+		return []brainBatch{{Changes: changes}}
+	}
+
+	var batches []brainBatch
+	index := make(map[int64]int) // Collection ID -> position in batches
+	for _, change := range changes {
+		collectionID := collectionIDs[change.NoteID]
+		i, ok := index[collectionID]
+		if !ok {
+			i = len(batches)
+			index[collectionID] = i
+			batches = append(batches, brainBatch{CollectionID: collectionID})
+		}
+		batches[i].Changes = append(batches[i].Changes, change)
+	}
+	return batches
+}
 
-	jsonData, err := json.Marshal(payload)
+// sendBatches sends every batch to Brain, continuing past failures so one bad
+// collection doesn't hold back the others. Returns the joined errors.
+func (c *ChangeAccumulator) sendBatches(ctx context.Context, batches []brainBatch) error {
+	var errs []error
+	for _, batch := range batches {
+		if err := c.sendToBrain(ctx, batch); err != nil {
+			if batch.CollectionID != 0 {
+				err = fmt.Errorf("collection %d: %w", batch.CollectionID, err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendToBrain sends a batch of changes to Brain's ingestion API.
+func (c *ChangeAccumulator) sendToBrain(ctx context.Context, batch brainBatch) error {
+	endpoint := fmt.Sprintf("%s/api/brain/ingest/batch", c.brainURL)
+
+	jsonData, err := json.Marshal(batch)
 	if err != nil {
 		return fmt.Errorf("failed to marshal changes: %w", err)
 	}
@@ -189,15 +367,13 @@ func (c *ChangeAccumulator) sendToBrain(ctx context.Context, changes []ChangeEve
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	resp, err := client.Do(req)
+	resp, err := c.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
+	// Read the body to the end so the connection goes back to the pool
+	_, _ = io.Copy(io.Discard, resp.Body)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		return fmt.Errorf("brain returned non-OK status: %d", resp.StatusCode)
@@ -212,5 +388,25 @@ func (c *ChangeAccumulator) sendToBrain(ctx context.Context, changes []ChangeEve
 func (c *ChangeAccumulator) GetPendingCount() int {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	return len(c.changes)
+	return len(c.changes) + len(c.critical)
+}
+
+// CheckHealth reports an error if no flush has succeeded (or found nothing to send) within
+// twice the flush interval, i.e., the ticker goroutine is stuck or Brain keeps failing.
+// Used as the /health scheduler probe.
+func (c *ChangeAccumulator) CheckHealth(ctx context.Context) error {
+	c.mu.Lock()
+	since := time.Since(c.lastFlushAt)
+	c.mu.Unlock()
+
+	if since > 2*c.flushInterval {
+		return fmt.Errorf("no successful flush for %s (flush interval %s, circuit %s)", since.Round(time.Second), c.flushInterval, c.CircuitBreakerState())
+	}
+	return nil
+}
+
+// CircuitBreakerState returns the state of the Brain API circuit breaker
+// ("closed", "open" or "half-open"). Exposed for the /health endpoint.
+func (c *ChangeAccumulator) CircuitBreakerState() string {
+	return c.breaker.currentState().String()
 }
@@ -0,0 +1,313 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBrain records the batches posted to the ingestion API.
+type fakeBrain struct {
+	mu            sync.Mutex
+	batches       [][]ChangeEvent
+	collectionIDs []int64 // collection_id of each batch (0 when ungrouped)
+	status        int
+}
+
+func newFakeBrain(t testing.TB, status int) (*fakeBrain, *httptest.Server) {
+	t.Helper()
+
+	brain := &fakeBrain{status: status}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body brainBatch
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode batch: %v", err)
+		}
+		brain.mu.Lock()
+		brain.batches = append(brain.batches, body.Changes)
+		brain.collectionIDs = append(brain.collectionIDs, body.CollectionID)
+		brain.mu.Unlock()
+		w.WriteHeader(brain.status)
+	}))
+	t.Cleanup(server.Close)
+	return brain, server
+}
+
+func (b *fakeBrain) flushes() [][]ChangeEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.batches
+}
+
+func TestChangeAccumulator_DrainAndStopFlushesOnce(t *testing.T) {
+	brain, server := newFakeBrain(t, http.StatusAccepted)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	acc := NewChangeAccumulator(Config{
+		BrainURL:      server.URL,
+		FlushInterval: time.Hour, // No tick during the test
+		BatchSize:     100,
+	}, logger)
+	acc.Start()
+
+	for i := int64(1); i <= 5; i++ {
+		acc.TrackChange("note_updated", i)
+	}
+
+	if err := acc.DrainAndStop(context.Background()); err != nil {
+		t.Fatalf("DrainAndStop failed: %v", err)
+	}
+
+	flushes := brain.flushes()
+	if len(flushes) != 1 {
+		t.Fatalf("expected exactly one flush, got %d", len(flushes))
+	}
+	if len(flushes[0]) != 5 {
+		t.Fatalf("expected 5 changes in the flush, got %d", len(flushes[0]))
+	}
+	if got := acc.GetPendingCount(); got != 0 {
+		t.Fatalf("expected no pending changes after drain, got %d", got)
+	}
+
+	// Later calls (e.g., Stop from a deferred cleanup) do not flush again
+	if err := acc.Stop(); err != nil {
+		t.Fatalf("Stop after drain failed: %v", err)
+	}
+	if got := len(brain.flushes()); got != 1 {
+		t.Fatalf("expected no further flushes, got %d", got)
+	}
+}
+
+func TestChangeAccumulator_DrainAndStopIgnoresOpenCircuit(t *testing.T) {
+	brain, server := newFakeBrain(t, http.StatusInternalServerError)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	acc := NewChangeAccumulator(Config{
+		BrainURL:       server.URL,
+		FlushInterval:  time.Hour,
+		CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 1, ResetTimeout: time.Hour},
+	}, logger)
+
+	acc.TrackChange("note_created", 1)
+	if err := acc.flush(context.Background(), false); err == nil {
+		t.Fatal("expected flush to fail against failing Brain")
+	}
+
+	brain.mu.Lock()
+	brain.status = http.StatusOK
+	brain.mu.Unlock()
+
+	acc.TrackChange("note_updated", 2)
+	if err := acc.DrainAndStop(context.Background()); err != nil {
+		t.Fatalf("DrainAndStop failed: %v", err)
+	}
+	if got := len(brain.flushes()); got != 2 {
+		t.Fatalf("expected the final flush despite the open circuit, got %d flushes", got)
+	}
+}
+
+func TestChangeAccumulator_CriticalChangeBypassesInterval(t *testing.T) {
+	brain, server := newFakeBrain(t, http.StatusAccepted)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	acc := NewChangeAccumulator(Config{
+		BrainURL:      server.URL,
+		FlushInterval: 5 * time.Minute,
+		BatchSize:     100,
+	}, logger)
+	acc.Start()
+	defer acc.Stop()
+
+	acc.TrackChange("note_updated", 1)
+	acc.TrackCriticalChange("note_deleted", 2)
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for len(brain.flushes()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("critical change was not flushed within 500ms")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Pending normal changes go out with the critical one
+	flushes := brain.flushes()
+	if len(flushes) != 1 || len(flushes[0]) != 2 {
+		t.Fatalf("expected one flush with 2 changes, got %v", flushes)
+	}
+	last := flushes[0][1]
+	if last.NoteID != 2 || last.Priority != PriorityCritical {
+		t.Fatalf("expected critical note_deleted for note 2, got %+v", last)
+	}
+	if got := acc.GetPendingCount(); got != 0 {
+		t.Fatalf("expected no pending changes, got %d", got)
+	}
+}
+
+func TestChangeAccumulator_DrainAndStopFlushesCriticalBeforeStart(t *testing.T) {
+	brain, server := newFakeBrain(t, http.StatusAccepted)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	acc := NewChangeAccumulator(Config{BrainURL: server.URL, FlushInterval: time.Hour}, logger)
+
+	// Without a running goroutine, critical changes wait in the channel
+	acc.TrackCriticalChange("note_deleted", 1)
+	if got := acc.GetPendingCount(); got != 1 {
+		t.Fatalf("expected 1 pending change, got %d", got)
+	}
+
+	if err := acc.DrainAndStop(context.Background()); err != nil {
+		t.Fatalf("DrainAndStop failed: %v", err)
+	}
+	if flushes := brain.flushes(); len(flushes) != 1 || len(flushes[0]) != 1 {
+		t.Fatalf("expected the critical change in the final flush, got %v", flushes)
+	}
+}
+
+func TestChangeAccumulator_CheckHealth(t *testing.T) {
+	brain, server := newFakeBrain(t, http.StatusInternalServerError)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	acc := NewChangeAccumulator(Config{BrainURL: server.URL, FlushInterval: time.Minute}, logger)
+	ctx := context.Background()
+
+	if err := acc.CheckHealth(ctx); err != nil {
+		t.Fatalf("expected a new accumulator to be healthy, got %v", err)
+	}
+
+	// Stuck: the last good flush is older than twice the interval and Brain keeps failing
+	acc.mu.Lock()
+	acc.lastFlushAt = time.Now().Add(-3 * time.Minute)
+	acc.mu.Unlock()
+	acc.TrackChange("note_updated", 1)
+	if err := acc.flush(ctx, false); err == nil {
+		t.Fatal("expected the flush to fail")
+	}
+	if err := acc.CheckHealth(ctx); err == nil {
+		t.Fatal("expected a stuck scheduler to be reported")
+	}
+
+	// A successful flush clears it
+	brain.mu.Lock()
+	brain.status = http.StatusAccepted
+	brain.mu.Unlock()
+	acc.TrackChange("note_updated", 2)
+	if err := acc.flush(ctx, false); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if err := acc.CheckHealth(ctx); err != nil {
+		t.Fatalf("expected healthy after a successful flush, got %v", err)
+	}
+}
+
+// fakeNoteReader maps note IDs to collection IDs.
+type fakeNoteReader struct {
+	collections map[int64]int64
+	err         error
+}
+
+func (r *fakeNoteReader) GetNoteCollectionIDs(ctx context.Context, noteIDs []int64) (map[int64]int64, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	result := make(map[int64]int64, len(noteIDs))
+	for _, id := range noteIDs {
+		if collectionID, ok := r.collections[id]; ok {
+			result[id] = collectionID
+		}
+	}
+	return result, nil
+}
+
+func TestChangeAccumulator_GroupByCollection(t *testing.T) {
+	// Notes 1-3 are in collection 10, notes 4-6 in collection 20
+	reader := &fakeNoteReader{collections: map[int64]int64{1: 10, 2: 20, 3: 10, 4: 20, 5: 10, 6: 20}}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name          string
+		group         bool
+		reader        *fakeNoteReader
+		batchSizes    []int
+		collectionIDs []int64
+	}{
+		{name: "grouped", group: true, reader: reader, batchSizes: []int{3, 3}, collectionIDs: []int64{10, 20}},
+		{name: "disabled", group: false, reader: reader, batchSizes: []int{6}, collectionIDs: []int64{0}},
+		{name: "no reader", group: true, batchSizes: []int{6}, collectionIDs: []int64{0}},
+		{name: "lookup fails", group: true, reader: &fakeNoteReader{err: errors.New("db closed")}, batchSizes: []int{6}, collectionIDs: []int64{0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			brain, server := newFakeBrain(t, http.StatusAccepted)
+			cfg := Config{BrainURL: server.URL, FlushInterval: time.Hour, GroupByCollection: tt.group}
+			if tt.reader != nil {
+				cfg.NoteReader = tt.reader
+			}
+			acc := NewChangeAccumulator(cfg, logger)
+
+			for i := int64(1); i <= 6; i++ {
+				acc.TrackChange("note_updated", i)
+			}
+			if err := acc.flush(context.Background(), false); err != nil {
+				t.Fatalf("flush failed: %v", err)
+			}
+
+			flushes := brain.flushes()
+			if len(flushes) != len(tt.batchSizes) {
+				t.Fatalf("expected %d API calls, got %d", len(tt.batchSizes), len(flushes))
+			}
+			for i, batch := range flushes {
+				if len(batch) != tt.batchSizes[i] {
+					t.Errorf("batch %d: expected %d changes, got %d", i, tt.batchSizes[i], len(batch))
+				}
+				for _, change := range batch {
+					if tt.group && tt.reader == reader && reader.collections[change.NoteID] != tt.collectionIDs[i] {
+						t.Errorf("batch %d for collection %d contains note %d", i, tt.collectionIDs[i], change.NoteID)
+					}
+				}
+			}
+			brain.mu.Lock()
+			collectionIDs := brain.collectionIDs
+			brain.mu.Unlock()
+			for i, id := range tt.collectionIDs {
+				if collectionIDs[i] != id {
+					t.Errorf("batch %d: expected collection_id %d, got %d", i, id, collectionIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestChangeAccumulator_GroupByCollectionUnknownNotes(t *testing.T) {
+	brain, server := newFakeBrain(t, http.StatusAccepted)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	acc := NewChangeAccumulator(Config{
+		BrainURL:          server.URL,
+		FlushInterval:     time.Hour,
+		GroupByCollection: true,
+		NoteReader:        &fakeNoteReader{collections: map[int64]int64{1: 10}},
+	}, logger)
+
+	// Note 2 was permanently deleted, so it has no collection any more
+	acc.TrackChange("note_updated", 1)
+	acc.TrackChange("note_deleted", 2)
+	if err := acc.flush(context.Background(), false); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+
+	if flushes := brain.flushes(); len(flushes) != 2 || flushes[1][0].NoteID != 2 {
+		t.Fatalf("expected the unknown note in its own batch, got %v", flushes)
+	}
+	brain.mu.Lock()
+	defer brain.mu.Unlock()
+	if brain.collectionIDs[0] != 10 || brain.collectionIDs[1] != 0 {
+		t.Fatalf("expected collection IDs [10 0], got %v", brain.collectionIDs)
+	}
+}
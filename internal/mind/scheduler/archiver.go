@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// archiveInterval is how often the archive job runs.
+const archiveInterval = 24 * time.Hour
+
+// NoteArchiver archives notes that have not been updated for a while.
+// Implemented by notes.NotesService; declared here because notes imports this package.
+type NoteArchiver interface {
+	ArchiveInactiveNotes(ctx context.Context, inactiveFor time.Duration) (int, error)
+}
+
+// ArchiveJob archives inactive notes once at start and then daily.
+type ArchiveJob struct {
+	archiver    NoteArchiver
+	inactiveFor time.Duration
+	interval    time.Duration
+	stopChan    chan struct{}
+	logger      *slog.Logger
+}
+
+// NewArchiveJob creates a job archiving notes not updated for archiveDays days.
+func NewArchiveJob(archiver NoteArchiver, archiveDays int, logger *slog.Logger) *ArchiveJob {
+	if archiveDays <= 0 {
+		archiveDays = 365 // Default: one year
+	}
+
+	return &ArchiveJob{
+		archiver:    archiver,
+		inactiveFor: time.Duration(archiveDays) * 24 * time.Hour,
+		interval:    archiveInterval,
+		stopChan:    make(chan struct{}),
+		logger:      logger.With("component", "archiver"),
+	}
+}
+
+// Start runs the job in the background until Stop is called.
+func (j *ArchiveJob) Start() {
+	j.logger.Info("starting archive job", "inactive_for", j.inactiveFor, "interval", j.interval)
+
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(context.Background())
+
+			select {
+			case <-ticker.C:
+			case <-j.stopChan:
+				j.logger.Info("stopping archive job")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the job. A run in progress finishes first.
+func (j *ArchiveJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *ArchiveJob) run(ctx context.Context) {
+	archived, err := j.archiver.ArchiveInactiveNotes(ctx, j.inactiveFor)
+	if err != nil {
+		j.logger.Error("archive run failed", "error", err)
+		return
+	}
+	j.logger.Info("archive run finished", "archived", archived)
+}
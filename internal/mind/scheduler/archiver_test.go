@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeArchiver records the inactivity thresholds it is called with.
+type fakeArchiver struct {
+	calls chan time.Duration
+}
+
+func (a *fakeArchiver) ArchiveInactiveNotes(ctx context.Context, inactiveFor time.Duration) (int, error) {
+	a.calls <- inactiveFor
+	return 0, nil
+}
+
+func TestArchiveJob_RunsOnStartAndOnInterval(t *testing.T) {
+	archiver := &fakeArchiver{calls: make(chan time.Duration, 10)}
+	job := NewArchiveJob(archiver, 30, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	job.interval = 10 * time.Millisecond
+
+	job.Start()
+	defer job.Stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case got := <-archiver.calls:
+			if want := 30 * 24 * time.Hour; got != want {
+				t.Fatalf("expected threshold %s, got %s", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for run %d", i+1)
+		}
+	}
+}
+
+func TestNewArchiveJob_DefaultsToOneYear(t *testing.T) {
+	job := NewArchiveJob(&fakeArchiver{}, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if want := 365 * 24 * time.Hour; job.inactiveFor != want {
+		t.Fatalf("expected default threshold %s, got %s", want, job.inactiveFor)
+	}
+}
@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreakerConfig configures the breaker guarding Brain API calls.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // Consecutive failures before opening, e.g., 5
+	ResetTimeout     time.Duration // How long to stay open before a trial request, e.g., 1 * time.Minute
+}
+
+// circuitState is the state of a circuitBreaker.
+type circuitState int
+
+const (
+	circuitClosed   circuitState = iota // Requests flow normally
+	circuitOpen                         // Requests are skipped until ResetTimeout elapses
+	circuitHalfOpen                     // A single trial request is allowed
+)
+
+// String returns the state name reported by CircuitBreakerState.
+func (s circuitState) String() string {
+	switch s {
+	case circuitClosed:
+		return "closed"
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker is a three-state (Closed/Open/Half-Open) breaker.
+// After FailureThreshold consecutive failures it opens and rejects requests for
+// ResetTimeout, then lets a single trial request through. A successful trial closes
+// the breaker; a failed one reopens it.
+type circuitBreaker struct {
+	mu               sync.Mutex
+	state            circuitState
+	failures         int
+	openedAt         time.Time
+	trialInFlight    bool
+	failureThreshold int
+	resetTimeout     time.Duration
+	now              func() time.Time // Overridable for tests
+}
+
+// newCircuitBreaker creates a closed circuit breaker, applying defaults for zero values.
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5 // Default: open after 5 consecutive failures
+	}
+	if cfg.ResetTimeout <= 0 {
+		cfg.ResetTimeout = time.Minute // Default: retry after 1 minute
+	}
+
+	return &circuitBreaker{
+		state:            circuitClosed,
+		failureThreshold: cfg.FailureThreshold,
+		resetTimeout:     cfg.ResetTimeout,
+		now:              time.Now,
+	}
+}
+
+// allow reports whether a request may be attempted.
+// An open breaker moves to half-open once ResetTimeout has elapsed and admits one trial request.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.resetTimeout {
+			return false
+		}
+		b.state = circuitHalfOpen
+		b.trialInFlight = true
+		return true
+	case circuitHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// recordSuccess closes the breaker and resets the failure count.
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = circuitClosed
+	b.failures = 0
+	b.trialInFlight = false
+}
+
+// recordFailure counts a failure, opening the breaker at the threshold or after a failed trial.
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+
+	if b.state == circuitHalfOpen || b.failures >= b.failureThreshold {
+		b.state = circuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// currentState returns the breaker state without triggering transitions.
+func (b *circuitBreaker) currentState() circuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
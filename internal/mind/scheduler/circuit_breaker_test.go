@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a controllable time source for circuit breaker tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestBreaker(threshold int, reset time.Duration) (*circuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: threshold, ResetTimeout: reset})
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestCircuitBreaker_ClosedToOpen(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if !b.allow() {
+			t.Fatalf("expected closed breaker to allow request %d", i+1)
+		}
+		b.recordFailure()
+	}
+	if got := b.currentState(); got != circuitClosed {
+		t.Fatalf("expected closed below threshold, got %s", got)
+	}
+
+	b.allow()
+	b.recordFailure()
+	if got := b.currentState(); got != circuitOpen {
+		t.Fatalf("expected open after threshold failures, got %s", got)
+	}
+	if b.allow() {
+		t.Fatal("expected open breaker to reject requests")
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailureCount(t *testing.T) {
+	b, _ := newTestBreaker(2, time.Minute)
+
+	b.recordFailure()
+	b.recordSuccess()
+	b.recordFailure()
+
+	if got := b.currentState(); got != circuitClosed {
+		t.Fatalf("expected non-consecutive failures to keep breaker closed, got %s", got)
+	}
+}
+
+func TestCircuitBreaker_OpenToHalfOpenToClosed(t *testing.T) {
+	b, clock := newTestBreaker(1, time.Minute)
+
+	b.recordFailure()
+	if got := b.currentState(); got != circuitOpen {
+		t.Fatalf("expected open, got %s", got)
+	}
+
+	clock.Advance(59 * time.Second)
+	if b.allow() {
+		t.Fatal("expected breaker to stay open before reset timeout")
+	}
+
+	clock.Advance(time.Second)
+	if !b.allow() {
+		t.Fatal("expected trial request after reset timeout")
+	}
+	if got := b.currentState(); got != circuitHalfOpen {
+		t.Fatalf("expected half-open, got %s", got)
+	}
+	if b.allow() {
+		t.Fatal("expected half-open breaker to allow only one trial request")
+	}
+
+	b.recordSuccess()
+	if got := b.currentState(); got != circuitClosed {
+		t.Fatalf("expected closed after successful trial, got %s", got)
+	}
+	if !b.allow() {
+		t.Fatal("expected closed breaker to allow requests")
+	}
+}
+
+func TestCircuitBreaker_HalfOpenToOpen(t *testing.T) {
+	b, clock := newTestBreaker(1, time.Minute)
+
+	b.recordFailure()
+	clock.Advance(time.Minute)
+	if !b.allow() {
+		t.Fatal("expected trial request after reset timeout")
+	}
+
+	b.recordFailure()
+	if got := b.currentState(); got != circuitOpen {
+		t.Fatalf("expected reopened after failed trial, got %s", got)
+	}
+	if b.allow() {
+		t.Fatal("expected reopened breaker to reject requests until the next reset timeout")
+	}
+}
+
+func TestChangeAccumulator_FlushSkippedWhileCircuitOpen(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	acc := NewChangeAccumulator(Config{
+		BrainURL:       server.URL,
+		BatchSize:      1000,
+		CircuitBreaker: CircuitBreakerConfig{FailureThreshold: 1, ResetTimeout: time.Hour},
+	}, logger)
+	ctx := context.Background()
+
+	acc.TrackChange("note_created", 1)
+	if err := acc.flush(ctx, false); err == nil {
+		t.Fatal("expected flush to fail against failing Brain")
+	}
+	if got := acc.CircuitBreakerState(); got != "open" {
+		t.Fatalf("expected open circuit, got %s", got)
+	}
+
+	acc.TrackChange("note_updated", 1)
+	if err := acc.flush(ctx, false); err != nil {
+		t.Fatalf("expected skipped flush to return nil, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected Brain to be called once, got %d", got)
+	}
+	if got := acc.GetPendingCount(); got != 1 {
+		t.Fatalf("expected change to stay pending while circuit is open, got %d", got)
+	}
+}
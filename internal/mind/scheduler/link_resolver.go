@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// defaultResolveInterval is how often the link resolve job runs when no interval is given.
+const defaultResolveInterval = time.Hour
+
+// LinkResolver resolves pending wiki-links in bulk.
+// Implemented by links.LinksService.
+type LinkResolver interface {
+	BulkResolveLinks(ctx context.Context) (resolved, broken int, err error)
+}
+
+// LinkResolveJob re-processes unresolved wiki-links once at start and then on an interval.
+type LinkResolveJob struct {
+	resolver LinkResolver
+	interval time.Duration
+	stopChan chan struct{}
+	logger   *slog.Logger
+}
+
+// NewLinkResolveJob creates a job running resolver every interval (default: hourly).
+func NewLinkResolveJob(resolver LinkResolver, interval time.Duration, logger *slog.Logger) *LinkResolveJob {
+	if interval <= 0 {
+		interval = defaultResolveInterval
+	}
+
+	return &LinkResolveJob{
+		resolver: resolver,
+		interval: interval,
+		stopChan: make(chan struct{}),
+		logger:   logger.With("component", "link_resolver"),
+	}
+}
+
+// Start runs the job in the background until Stop is called.
+func (j *LinkResolveJob) Start() {
+	j.logger.Info("starting link resolve job", "interval", j.interval)
+
+	go func() {
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			j.run(context.Background())
+
+			select {
+			case <-ticker.C:
+			case <-j.stopChan:
+				j.logger.Info("stopping link resolve job")
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the job. A run in progress finishes first.
+func (j *LinkResolveJob) Stop() {
+	close(j.stopChan)
+}
+
+func (j *LinkResolveJob) run(ctx context.Context) {
+	resolved, broken, err := j.resolver.BulkResolveLinks(ctx)
+	if err != nil {
+		j.logger.Error("link resolve run failed", "error", err, "resolved", resolved, "broken", broken)
+		return
+	}
+	j.logger.Info("link resolve run finished", "resolved", resolved, "broken", broken)
+}
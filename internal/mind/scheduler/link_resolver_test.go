@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeLinkResolver counts BulkResolveLinks calls.
+type fakeLinkResolver struct {
+	calls chan struct{}
+}
+
+func (r *fakeLinkResolver) BulkResolveLinks(ctx context.Context) (int, int, error) {
+	r.calls <- struct{}{}
+	return 0, 0, nil
+}
+
+func TestLinkResolveJob_RunsOnStartAndOnInterval(t *testing.T) {
+	resolver := &fakeLinkResolver{calls: make(chan struct{}, 10)}
+	job := NewLinkResolveJob(resolver, 10*time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	job.Start()
+	defer job.Stop()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-resolver.calls:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for run %d", i+1)
+		}
+	}
+}
+
+func TestNewLinkResolveJob_DefaultsToHourly(t *testing.T) {
+	job := NewLinkResolveJob(&fakeLinkResolver{}, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if job.interval != time.Hour {
+		t.Fatalf("expected default interval 1h, got %s", job.interval)
+	}
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig configures connection pooling for requests to Brain.
+type TransportConfig struct {
+	MaxIdleConns        int           // Idle connections kept across all hosts, e.g., 100
+	MaxIdleConnsPerHost int           // Idle connections kept to Brain, e.g., 10
+	IdleConnTimeout     time.Duration // How long an idle connection is kept, e.g., 90 * time.Second
+}
+
+// brainRequestTimeout bounds a single request to Brain, including reading the response.
+const brainRequestTimeout = 30 * time.Second
+
+// newHTTPClient creates the client used for every flush, applying defaults for zero values.
+// Reusing one client keeps connections to Brain open between batches instead of dialing
+// for each one.
+func newHTTPClient(cfg TransportConfig) *http.Client {
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = 100 // Default: same as http.DefaultTransport
+	}
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = 10 // Default: 10 (http.DefaultTransport keeps only 2)
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = 90 * time.Second // Default: 90 seconds
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &http.Client{Transport: transport, Timeout: brainRequestTimeout}
+}
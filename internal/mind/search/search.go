@@ -1,5 +1,6 @@
 // Package search provides full-text search functionality for Mind notes.
-// This service uses FTS5 (SQLite Full-Text Search) for efficient text searching.
+// This service uses FTS5 (SQLite Full-Text Search) for efficient text searching,
+// falling back to LIKE keyword matching when the notes_fts table is unavailable.
 //
 // Note: Future enhancements tracked in issue #41
 package search
@@ -8,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
@@ -20,8 +22,18 @@ type SearchService struct {
 	store      *store.Queries      // Use concrete type to access generated queries
 	ftsQuerier *sqlcext.FTSQuerier // FTS5 querier for full-text search
 	logger     *slog.Logger
+
+	// ftsAvailable is detected once at startup; when false, Search uses SearchNotesFallback
+	ftsAvailable bool
 }
 
+// fallbackSnippetLength is the number of body characters returned as a snippet
+// by keyword fallback search (matches the related-notes snippets).
+const fallbackSnippetLength = 200
+
+// likeEscaper escapes LIKE wildcards so user input matches literally (ESCAPE '\').
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // SearchQuery represents a search request.
 type SearchQuery struct {
 	Query       string  // The search query text
@@ -38,6 +50,9 @@ type SearchResult struct {
 	Snippet   string    `json:"snippet"` // Body snippet or full body
 	Score     float64   `json:"score"`   // Relevance score
 	CreatedAt time.Time `json:"created_at"`
+
+	CollectionID   int64  `json:"collection_id"`
+	CollectionPath string `json:"collection_path"` // Empty when unknown (keyword fallback)
 }
 
 // SearchResponse represents the search results and metadata.
@@ -58,11 +73,27 @@ func NewSearchService(db sqlcext.DB, store *store.Queries, logger *slog.Logger)
 		ContentRowID: "id",
 	}
 
-	return &SearchService{
+	s := &SearchService{
 		store:      store,
 		ftsQuerier: sqlcext.NewFTSQuerier(db, ftsConfig),
 		logger:     logger.With("service", "mind_search"),
 	}
+
+	s.ftsAvailable = detectFTS(context.Background(), db, ftsConfig.FTSTable)
+	if !s.ftsAvailable {
+		s.logger.Warn("FTS5 table not found, using keyword search fallback", "fts_table", ftsConfig.FTSTable)
+	}
+
+	return s
+}
+
+// detectFTS reports whether the FTS5 table exists in the database.
+func detectFTS(ctx context.Context, db sqlcext.DB, table string) bool {
+	var name string
+	err := db.QueryRowContext(ctx,
+		"SELECT name FROM sqlite_master WHERE type='table' AND name=?", table,
+	).Scan(&name)
+	return err == nil
 }
 
 // Search performs full-text search on Mind notes.
@@ -87,30 +118,22 @@ func (s *SearchService) Search(ctx context.Context, query SearchQuery) (SearchRe
 		query.Limit = 100 // Max limit
 	}
 
-	// Perform FTS search using sqlcext
-	var ftsResults []sqlcext.FTSSearchResult
-	var err error
+	if !s.ftsAvailable {
+		return s.searchFallback(ctx, query, startTime)
+	}
 
+	// Perform FTS search using sqlcext; results carry their collection for "where is it?" UX
 	ftsParams := sqlcext.FTSSearchParams{
 		Query:       query.Query, // FTS querier handles sanitization
 		LimitCount:  int64(query.Limit),
 		OffsetCount: int64(query.Offset),
+		WithSnippet: !query.IncludeBody, // Full body or highlighted snippet
 	}
 
-	if query.IncludeBody {
-		// Full body search
-		ftsResults, err = s.ftsQuerier.Search(ctx, ftsParams)
-		if err != nil {
-			s.logger.Error("fts search failed", "err", err, "query", query.Query, "request_id", middleware.GetRequestID(ctx))
-			return SearchResponse{}, fmt.Errorf("search failed: %w", err)
-		}
-	} else {
-		// Snippet-only search
-		ftsResults, err = s.ftsQuerier.SearchWithSnippet(ctx, ftsParams)
-		if err != nil {
-			s.logger.Error("fts search with snippet failed", "err", err, "query", query.Query, "request_id", middleware.GetRequestID(ctx))
-			return SearchResponse{}, fmt.Errorf("search failed: %w", err)
-		}
+	ftsResults, err := s.ftsQuerier.SearchAcrossCollections(ctx, ftsParams)
+	if err != nil {
+		s.logger.Error("fts search failed", "err", err, "query", query.Query, "request_id", middleware.GetRequestID(ctx))
+		return SearchResponse{}, fmt.Errorf("search failed: %w", err)
 	}
 
 	// Convert FTS results to search results
@@ -154,7 +177,7 @@ func (s *SearchService) Search(ctx context.Context, query SearchQuery) (SearchRe
 }
 
 // convertFTSResults converts sqlcext FTS results to SearchResult
-func (s *SearchService) convertFTSResults(ftsResults []sqlcext.FTSSearchResult) []SearchResult {
+func (s *SearchService) convertFTSResults(ftsResults []sqlcext.FTSResultWithCollection) []SearchResult {
 	results := make([]SearchResult, 0, len(ftsResults))
 	for _, fts := range ftsResults {
 		results = append(results, SearchResult{
@@ -163,7 +186,89 @@ func (s *SearchService) convertFTSResults(ftsResults []sqlcext.FTSSearchResult)
 			Snippet:   fts.Body, // Body or snippet depending on search type
 			Score:     fts.Score,
 			CreatedAt: fts.CreatedAt,
+
+			CollectionID:   fts.CollectionID,
+			CollectionPath: fts.CollectionPath,
 		})
 	}
 	return results
 }
+
+// searchFallback serves Search with keyword matching when FTS5 is unavailable.
+// Results are unranked (score 0), so MinScore is not applied.
+func (s *SearchService) searchFallback(ctx context.Context, query SearchQuery, startTime time.Time) (SearchResponse, error) {
+	notes, err := s.SearchNotesFallback(ctx, query.Query, int32(query.Limit), int32(query.Offset))
+	if err != nil {
+		return SearchResponse{}, fmt.Errorf("search failed: %w", err)
+	}
+
+	total, err := s.store.CountNotesByKeyword(ctx, likePattern(query.Query))
+	if err != nil {
+		s.logger.Error("failed to count search results", "err", err, "query", query.Query, "request_id", middleware.GetRequestID(ctx))
+		// Don't fail the request, just log the error
+		total = int64(len(notes))
+	}
+
+	results := make([]SearchResult, 0, len(notes))
+	for _, note := range notes {
+		snippet := note.Body.String
+		if !query.IncludeBody {
+			snippet = truncateRunes(snippet, fallbackSnippetLength)
+		}
+		results = append(results, SearchResult{
+			ID:        note.ID,
+			Title:     note.Title,
+			Snippet:   snippet,
+			CreatedAt: note.CreatedAt.Time,
+
+			CollectionID: note.CollectionID, // Path is not resolved by keyword search
+		})
+	}
+
+	duration := time.Since(startTime).Milliseconds()
+
+	s.logger.Info("search completed",
+		"query", query.Query,
+		"results", len(results),
+		"total", total,
+		"duration_ms", duration,
+		"fallback", true,
+		"request_id", middleware.GetRequestID(ctx),
+	)
+
+	return SearchResponse{
+		Results:  results,
+		Total:    int(total),
+		Query:    query.Query,
+		Duration: duration,
+	}, nil
+}
+
+// SearchNotesFallback finds notes whose title or body contains query (case-insensitive for ASCII),
+// most recently updated first. Used when the SQLite build has no FTS5 support.
+func (s *SearchService) SearchNotesFallback(ctx context.Context, query string, limit, offset int32) ([]store.Note, error) {
+	notes, err := s.store.SearchNotesByKeyword(ctx, store.SearchNotesByKeywordParams{
+		Pattern: likePattern(query),
+		Limit:   int64(limit),
+		Offset:  int64(offset),
+	})
+	if err != nil {
+		s.logger.Error("keyword search failed", "err", err, "query", query, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	return notes, nil
+}
+
+// likePattern wraps query in % wildcards, escaping any wildcards it contains.
+func likePattern(query string) string {
+	return "%" + likeEscaper.Replace(query) + "%"
+}
+
+// truncateRunes shortens s to at most n runes.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -12,6 +12,9 @@ func SearchResultToProto(result SearchResult) *mindv3.SearchResult {
 		Snippet:    result.Snippet,
 		Score:      result.Score,
 		CreateTime: timestamppb.New(result.CreatedAt),
+
+		CollectionId:   result.CollectionID,
+		CollectionPath: result.CollectionPath,
 	}
 }
 
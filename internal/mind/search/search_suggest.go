@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// Suggestion limits for GET /notes:suggest.
+const (
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 20
+)
+
+// Suggest returns up to limit distinct note titles matching prefix, best matches first.
+// Without FTS5 there is no ranked prefix search, so no suggestions are returned.
+func (s *SearchService) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	limit = min(limit, maxSuggestLimit)
+
+	if !s.ftsAvailable {
+		return nil, nil
+	}
+
+	titles, err := s.ftsQuerier.Suggest(ctx, prefix, limit)
+	if err != nil {
+		s.logger.Error("fts suggest failed", "err", err, "prefix", prefix, "request_id", middleware.GetRequestID(ctx))
+		return nil, err
+	}
+	return titles, nil
+}
+
+// SuggestResponse is the JSON body of GET /notes:suggest.
+type SuggestResponse struct {
+	Suggestions []string `json:"suggestions"`
+}
+
+// SuggestHandler serves GET /notes:suggest?q=gol&limit=5 for search-as-you-type.
+func SuggestHandler(service *SearchService) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		limit := 0
+		if raw := c.QueryParam("limit"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed <= 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid limit")
+			}
+			limit = parsed
+		}
+
+		suggestions, err := service.Suggest(c.Request().Context(), c.QueryParam("q"), limit)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to get suggestions")
+		}
+
+		if suggestions == nil {
+			suggestions = []string{}
+		}
+		return c.JSON(http.StatusOK, SuggestResponse{Suggestions: suggestions})
+	}
+}
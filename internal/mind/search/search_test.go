@@ -0,0 +1,227 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// setupTestService creates a SearchService with a test database for testing.
+func setupTestService(t *testing.T) (*SearchService, *store.Queries, *sql.DB) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	createDefaultCollection(t, queries)
+	logger := testdb.NewTestLogger(t)
+	service := NewSearchService(db, queries, logger)
+
+	return service, queries, db
+}
+
+// createDefaultCollection creates collection 1, which notes default to (the server creates it on startup).
+func createDefaultCollection(t *testing.T, queries *store.Queries) {
+	t.Helper()
+
+	_, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{
+		Name: "default",
+		Path: "default",
+	})
+	require.NoError(t, err)
+}
+
+// seedNotes creates the notes shared by the search tests.
+func seedNotes(t *testing.T, queries *store.Queries) {
+	t.Helper()
+
+	notes := []struct{ title, body string }{
+		{"Gardening", "Tomatoes need full sun and regular watering."},
+		{"Recipes", "Slow-roasted tomatoes with garlic."},
+		{"Budget", "Track 100% of expenses in a spreadsheet."},
+		{"Travel", "Pack light for the mountains."},
+	}
+	for _, n := range notes {
+		_, err := queries.CreateNote(context.Background(), store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        n.title,
+			Body:         utils.NullString(n.body),
+			CollectionID: 1, // Default collection
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestNewSearchService_DetectsFTS(t *testing.T) {
+	service, _, db := setupTestService(t)
+	require.True(t, service.ftsAvailable)
+
+	_, err := db.Exec("DROP TABLE notes_fts")
+	require.NoError(t, err)
+
+	withoutFTS := NewSearchService(db, store.New(db), testdb.NewTestLogger(t))
+	require.False(t, withoutFTS.ftsAvailable)
+}
+
+func TestSearch_FTSAndFallback(t *testing.T) {
+	paths := []struct {
+		name         string
+		ftsAvailable bool
+	}{
+		{"fts5", true},
+		{"fallback", false},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedTitles []string
+	}{
+		{"matches body", "tomatoes", []string{"Gardening", "Recipes"}},
+		{"matches title", "travel", []string{"Travel"}},
+		{"no match", "bicycle", nil},
+	}
+
+	for _, path := range paths {
+		t.Run(path.name, func(t *testing.T) {
+			service, queries, _ := setupTestService(t)
+			service.ftsAvailable = path.ftsAvailable
+			seedNotes(t, queries)
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					resp, err := service.Search(context.Background(), SearchQuery{Query: tt.query, Limit: 10, IncludeBody: true})
+					require.NoError(t, err)
+
+					titles := make([]string, 0, len(resp.Results))
+					for _, r := range resp.Results {
+						titles = append(titles, r.Title)
+					}
+					require.ElementsMatch(t, tt.expectedTitles, titles)
+					require.Equal(t, len(tt.expectedTitles), resp.Total)
+				})
+			}
+		})
+	}
+}
+
+func TestSearchNotesFallback(t *testing.T) {
+	service, queries, _ := setupTestService(t)
+	seedNotes(t, queries)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		query         string
+		limit, offset int32
+		expectedCount int
+	}{
+		{"case insensitive", "TOMATOES", 10, 0, 2},
+		{"percent matched literally", "100%", 10, 0, 1},
+		{"underscore matched literally", "full_sun", 10, 0, 0},
+		{"limit", "a", 2, 0, 2},
+		{"offset past results", "tomatoes", 10, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes, err := service.SearchNotesFallback(ctx, tt.query, tt.limit, tt.offset)
+			require.NoError(t, err)
+			require.Len(t, notes, tt.expectedCount)
+		})
+	}
+}
+
+func TestSearchNotesFallback_ExcludesDeletedNotes(t *testing.T) {
+	service, queries, _ := setupTestService(t)
+	seedNotes(t, queries)
+	ctx := context.Background()
+
+	note, err := queries.GetNoteByTitleGlobal(ctx, "Gardening")
+	require.NoError(t, err)
+	_, err = queries.SoftDeleteNoteByID(ctx, note.ID)
+	require.NoError(t, err)
+
+	notes, err := service.SearchNotesFallback(ctx, "tomatoes", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	require.Equal(t, "Recipes", notes[0].Title)
+}
+
+func TestSearch_IncludesCollectionPath(t *testing.T) {
+	service, queries, _ := setupTestService(t)
+	ctx := context.Background()
+
+	collectionID, err := queries.CreateCollection(ctx, store.CreateCollectionParams{
+		Name: "Projects",
+		Path: "work/projects",
+	})
+	require.NoError(t, err)
+	_, err = queries.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        "Roadmap",
+		Body:         utils.NullString("Quarterly milestones"),
+		CollectionID: collectionID,
+	})
+	require.NoError(t, err)
+
+	resp, err := service.Search(ctx, SearchQuery{Query: "milestones", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	require.Equal(t, collectionID, resp.Results[0].CollectionID)
+	require.Equal(t, "work/projects", resp.Results[0].CollectionPath)
+	require.Contains(t, resp.Results[0].Snippet, "<mark>")
+}
+
+func TestSuggest_TitlePrefix(t *testing.T) {
+	service, queries, _ := setupTestService(t)
+	ctx := context.Background()
+
+	for _, title := range []string{"Golang Basics", "Golang Advanced", "Python Guide"} {
+		_, err := queries.CreateNote(ctx, store.CreateNoteParams{
+			Uuid:         uuid.New(),
+			Title:        title,
+			Body:         utils.NullString("Notes about golang and python"),
+			CollectionID: 1,
+		})
+		require.NoError(t, err)
+	}
+
+	suggestions, err := service.Suggest(ctx, "Gol", 5)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"Golang Basics", "Golang Advanced"}, suggestions)
+
+	suggestions, err = service.Suggest(ctx, "Py", 5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Python Guide"}, suggestions)
+
+	// Served over HTTP with the limit applied
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/mind/notes:suggest?q=gol&limit=1", nil)
+	rec := httptest.NewRecorder()
+	require.NoError(t, SuggestHandler(service)(e.NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp SuggestResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Len(t, resp.Suggestions, 1)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/mind/notes:suggest?q=gol&limit=abc", nil)
+	err = SuggestHandler(service)(e.NewContext(req, httptest.NewRecorder()))
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
@@ -14,4 +14,7 @@ var (
 
 	// ErrTagNotFound indicates a tag was not found
 	ErrTagNotFound = errors.New("tag not found")
+
+	// ErrInvalidTagName indicates a tag name that cannot be written as a #hashtag
+	ErrInvalidTagName = errors.New("invalid tag name")
 )
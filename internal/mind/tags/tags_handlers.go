@@ -2,6 +2,7 @@ package tags
 
 import (
 	"context"
+	"errors"
 
 	"connectrpc.com/connect"
 	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
@@ -48,7 +49,7 @@ func (h *TagsHandler) ListTags(
 	}
 
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list tags", err)
+		return nil, apierrors.Mind.Internal("failed to list tags", err)
 	}
 
 	// Count errors are logged in service but don't fail the request
@@ -82,7 +83,7 @@ func (h *TagsHandler) ListNotesForTag(
 
 	notes, err := h.service.ListNotesForTagPaginated(ctx, req.Msg.TagId, params.Limit, params.Offset)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list notes for tag", err)
+		return nil, apierrors.Mind.Internal("failed to list notes for tag", err)
 	}
 
 	// Get total count (only on first page)
@@ -122,7 +123,7 @@ func (h *TagsHandler) FindTags(
 
 	tags, err := h.service.FindTagsPaginated(ctx, req.Msg.Name, params.Limit, params.Offset)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to find tags", err)
+		return nil, apierrors.Mind.Internal("failed to find tags", err)
 	}
 
 	// Get total count (only on first page)
@@ -151,3 +152,34 @@ func (h *TagsHandler) FindTags(
 
 	return connect.NewResponse(resp), nil
 }
+
+// RenameTag implements the AIP-136 :rename custom method for tags.
+// Renames the tag and rewrites matching #hashtags in the bodies of tagged notes.
+func (h *TagsHandler) RenameTag(
+	ctx context.Context,
+	req *connect.Request[mindv3.RenameTagRequest],
+) (*connect.Response[mindv3.RenameTagResponse], error) {
+	updated, err := h.service.RenameHashtag(ctx, req.Msg.OldName, req.Msg.NewName)
+	if err != nil {
+		if errors.Is(err, ErrTagNotFound) {
+			return nil, apierrors.Mind.NotFound("tag", req.Msg.OldName)
+		}
+		if errors.Is(err, ErrTagAlreadyExists) {
+			return nil, apierrors.Mind.AlreadyExists("tags", "name", req.Msg.NewName)
+		}
+		if errors.Is(err, ErrInvalidTagName) {
+			return nil, apierrors.NewInvalidArgumentError("new_name", "must not contain whitespace or '#'")
+		}
+		return nil, apierrors.Mind.Internal("failed to rename tag", err)
+	}
+
+	tag, err := h.service.GetTagByName(ctx, req.Msg.NewName)
+	if err != nil {
+		return nil, apierrors.Mind.Internal("failed to retrieve renamed tag", err)
+	}
+
+	return connect.NewResponse(&mindv3.RenameTagResponse{
+		Tag:              StoreTagToProto(tag),
+		UpdatedNoteCount: int32(updated),
+	}), nil
+}
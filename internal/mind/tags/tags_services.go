@@ -5,26 +5,33 @@ import (
 	"database/sql"
 	"errors"
 	"log/slog"
+	"strings"
+	"unicode"
 
 	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
 	"github.com/nkapatos/mindweaver/internal/mind/events"
 	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
 	sharedErrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/markdown"
 	"github.com/nkapatos/mindweaver/shared/middleware"
 )
 
 // TagsService provides business logic for tags (CRUD + search only).
 type TagsService struct {
 	store    store.Querier
+	db       *sql.DB
 	logger   *slog.Logger
+	parser   *markdown.Parser
 	eventHub events.Hub
 }
 
 // NewTagsService creates a new TagsService.
-func NewTagsService(store store.Querier, logger *slog.Logger, serviceName string) *TagsService {
+func NewTagsService(db *sql.DB, store store.Querier, logger *slog.Logger, serviceName string) *TagsService {
 	return &TagsService{
 		store:  store,
+		db:     db,
 		logger: logger.With("service", serviceName),
+		parser: markdown.NewParser(),
 	}
 }
 
@@ -77,6 +84,19 @@ func (s *TagsService) GetTagByID(ctx context.Context, id int64) (store.Tag, erro
 	return tag, nil
 }
 
+// GetTagByName returns a tag by its name.
+func (s *TagsService) GetTagByName(ctx context.Context, name string) (store.Tag, error) {
+	tag, err := s.store.GetTagByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return store.Tag{}, ErrTagNotFound
+		}
+		s.logger.Error("failed to get tag by name", "name", name, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Tag{}, err
+	}
+	return tag, nil
+}
+
 // CreateTag creates a new tag.
 func (s *TagsService) CreateTag(ctx context.Context, name string) (int64, error) {
 	id, err := s.store.CreateTag(ctx, name)
@@ -87,7 +107,7 @@ func (s *TagsService) CreateTag(ctx context.Context, name string) (int64, error)
 		s.logger.Error("failed to create tag", "name", name, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return 0, err
 	}
-	s.logger.Info("tag created", "id", id, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("tag created", "tag_id", id)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_TAG, mindv3.EventType_EVENT_TYPE_CREATED, id)
@@ -110,7 +130,7 @@ func (s *TagsService) UpdateTag(ctx context.Context, id int64, name string) erro
 		s.logger.Error("failed to update tag", "id", id, "name", name, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("tag updated", "id", id, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("tag updated", "tag_id", id)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_TAG, mindv3.EventType_EVENT_TYPE_UPDATED, id)
@@ -129,7 +149,7 @@ func (s *TagsService) DeleteTag(ctx context.Context, id int64) error {
 		s.logger.Error("failed to delete tag", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
 		return err
 	}
-	s.logger.Info("tag deleted", "id", id, "request_id", middleware.GetRequestID(ctx))
+	middleware.ContextLogger(ctx, s.logger).Info("tag deleted", "tag_id", id)
 
 	if s.eventHub != nil {
 		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_TAG, mindv3.EventType_EVENT_TYPE_DELETED, id)
@@ -138,6 +158,112 @@ func (s *TagsService) DeleteTag(ctx context.Context, id int64) error {
 	return nil
 }
 
+// RenameHashtag renames a tag and rewrites every #oldName hashtag in the bodies of notes
+// tagged with it, so re-extraction on the next edit yields the new name.
+// Runs in a single transaction and returns the number of notes whose body changed.
+// Only #hashtags the markdown parser extracts are rewritten; tags listed in frontmatter,
+// code, URL fragments and #oldName-suffixed tags are left as-is.
+func (s *TagsService) RenameHashtag(ctx context.Context, oldName, newName string) (int, error) {
+	if !validTagName(newName) {
+		return 0, ErrInvalidTagName
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.Error("failed to begin transaction", "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	txStore := store.New(tx)
+
+	tag, err := txStore.GetTagByName(ctx, oldName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrTagNotFound
+		}
+		s.logger.Error("failed to get tag by name", "name", oldName, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	if err := txStore.UpdateTagByID(ctx, store.UpdateTagByIDParams{ID: tag.ID, Name: newName}); err != nil {
+		if sharedErrors.IsUniqueConstraintError(err) {
+			return 0, ErrTagAlreadyExists
+		}
+		s.logger.Error("failed to rename tag", "tag_id", tag.ID, "new_name", newName, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	notes, err := txStore.ListNotesForTag(ctx, tag.ID)
+	if err != nil {
+		s.logger.Error("failed to list notes for tag", "tag_id", tag.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	var updatedNoteIDs []int64
+	for _, note := range notes {
+		if !note.Body.Valid {
+			continue
+		}
+		newBody := s.rewriteHashtag(note.Body.String, oldName, newName)
+		if newBody == note.Body.String {
+			continue
+		}
+		err := txStore.UpdateNoteBodyByID(ctx, store.UpdateNoteBodyByIDParams{
+			ID:   note.ID,
+			Body: sql.NullString{String: newBody, Valid: true},
+		})
+		if err != nil {
+			s.logger.Error("failed to rewrite note body", "note_id", note.ID, "tag_id", tag.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+			return 0, err
+		}
+		updatedNoteIDs = append(updatedNoteIDs, note.ID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transaction", "tag_id", tag.ID, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return 0, err
+	}
+
+	middleware.ContextLogger(ctx, s.logger).Info("tag renamed", "tag_id", tag.ID, "old_name", oldName, "new_name", newName, "updated_notes", len(updatedNoteIDs))
+
+	if s.eventHub != nil {
+		s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_TAG, mindv3.EventType_EVENT_TYPE_UPDATED, tag.ID)
+		for _, noteID := range updatedNoteIDs {
+			s.eventHub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, noteID)
+		}
+	}
+
+	return len(updatedNoteIDs), nil
+}
+
+// rewriteHashtag replaces each #oldName hashtag in body with #newName.
+// Positions come from the parser, so #oldName-alpha and #oldName/sub are different tags.
+func (s *TagsService) rewriteHashtag(body, oldName, newName string) string {
+	var b strings.Builder
+	last := 0
+	for _, span := range s.parser.FindHashtags([]byte(body)) {
+		if span.Tag != oldName {
+			continue
+		}
+		b.WriteString(body[last:span.Start])
+		b.WriteString("#" + newName)
+		last = span.Stop
+	}
+	if last == 0 {
+		return body
+	}
+	b.WriteString(body[last:])
+	return b.String()
+}
+
+// validTagName reports whether name can be written as a #hashtag (non-empty, no whitespace or '#').
+func validTagName(name string) bool {
+	return name != "" && !strings.ContainsFunc(name, func(r rune) bool {
+		return r == '#' || unicode.IsSpace(r)
+	})
+}
+
 // SearchTagsByName returns tags matching a name pattern.
 func (s *TagsService) SearchTagsByName(ctx context.Context, namePattern string) ([]store.Tag, error) {
 	tags, err := s.store.SearchTagsByName(ctx, namePattern)
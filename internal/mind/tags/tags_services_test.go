@@ -0,0 +1,151 @@
+package tags
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// setupTestService creates a TagsService with a test database for testing.
+func setupTestService(t *testing.T) (*TagsService, *store.Queries) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	queries := store.New(db)
+	createDefaultCollection(t, queries)
+	logger := testdb.NewTestLogger(t)
+	service := NewTagsService(db, queries, logger, "tags-test")
+
+	return service, queries
+}
+
+// createDefaultCollection creates collection 1, which notes default to (the server creates it on startup).
+func createDefaultCollection(t *testing.T, queries *store.Queries) {
+	t.Helper()
+
+	_, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{
+		Name: "default",
+		Path: "default",
+	})
+	require.NoError(t, err)
+}
+
+// createTaggedNote creates a note with the given body and links it to tagID.
+func createTaggedNote(t *testing.T, queries *store.Queries, title, body string, tagID int64) int64 {
+	t.Helper()
+	ctx := context.Background()
+
+	noteID, err := queries.CreateNote(ctx, store.CreateNoteParams{
+		Uuid:         uuid.New(),
+		Title:        title,
+		Body:         utils.NullString(body),
+		CollectionID: 1, // Default collection
+	})
+	require.NoError(t, err)
+
+	err = queries.CreateNoteTag(ctx, store.CreateNoteTagParams{NoteID: noteID, TagID: tagID})
+	require.NoError(t, err)
+	return noteID
+}
+
+// ============================================================================
+// RenameHashtag Tests
+// ============================================================================
+
+func TestRenameHashtag_RewritesAllOccurrences(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	tagID, err := queries.CreateTag(ctx, "project")
+	require.NoError(t, err)
+
+	noteID := createTaggedNote(t, queries, "Plan", "Start #project today.\n\nStill #project, not #project-alpha.", tagID)
+	before, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+
+	updated, err := service.RenameHashtag(ctx, "project", "work")
+	require.NoError(t, err)
+	require.Equal(t, 1, updated)
+
+	note, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.Equal(t, "Start #work today.\n\nStill #work, not #project-alpha.", note.Body.String)
+	require.Equal(t, before.Version+1, note.Version)
+
+	tag, err := queries.GetTagByID(ctx, tagID)
+	require.NoError(t, err)
+	require.Equal(t, "work", tag.Name)
+}
+
+func TestRenameHashtag_CountsOnlyChangedNotes(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	tagID, err := queries.CreateTag(ctx, "idea")
+	require.NoError(t, err)
+
+	createTaggedNote(t, queries, "First", "An #idea", tagID)
+	createTaggedNote(t, queries, "Second", "#idea and #idea again", tagID)
+	// Tagged via frontmatter only - body has no hashtag to rewrite
+	createTaggedNote(t, queries, "Third", "---\ntags: [idea]\n---\nNo hashtag here", tagID)
+
+	updated, err := service.RenameHashtag(ctx, "idea", "thought")
+	require.NoError(t, err)
+	require.Equal(t, 2, updated)
+}
+
+func TestRenameHashtag_SkipsNonHashtags(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	tagID, err := queries.CreateTag(ctx, "old")
+	require.NoError(t, err)
+
+	body := "(#old) and #old.\n\n" +
+		"Not https://host/page#old, foo#old, ##old, #old/sub or `#old`.\n\n" +
+		"```\n#old\n```\n"
+	noteID := createTaggedNote(t, queries, "Links", body, tagID)
+
+	updated, err := service.RenameHashtag(ctx, "old", "new")
+	require.NoError(t, err)
+	require.Equal(t, 1, updated)
+
+	note, err := queries.GetNoteByID(ctx, noteID)
+	require.NoError(t, err)
+	require.Equal(t, "(#new) and #new.\n\n"+
+		"Not https://host/page#old, foo#old, ##old, #old/sub or `#old`.\n\n"+
+		"```\n#old\n```\n", note.Body.String)
+}
+
+func TestRenameHashtag_Errors(t *testing.T) {
+	service, queries := setupTestService(t)
+	ctx := context.Background()
+
+	_, err := queries.CreateTag(ctx, "project")
+	require.NoError(t, err)
+	_, err = queries.CreateTag(ctx, "work")
+	require.NoError(t, err)
+
+	_, err = service.RenameHashtag(ctx, "missing", "other")
+	require.ErrorIs(t, err, ErrTagNotFound)
+
+	_, err = service.RenameHashtag(ctx, "project", "work")
+	require.ErrorIs(t, err, ErrTagAlreadyExists)
+
+	_, err = service.RenameHashtag(ctx, "project", "two words")
+	require.ErrorIs(t, err, ErrInvalidTagName)
+
+	// Failed renames leave the tag untouched
+	tag, err := queries.GetTagByName(ctx, "project")
+	require.NoError(t, err)
+	require.Equal(t, "project", tag.Name)
+}
@@ -32,14 +32,14 @@ func (h *TemplatesHandler) CreateTemplate(
 	templateID, err := h.service.CreateTemplate(ctx, params)
 	if err != nil {
 		if errors.Is(err, ErrTemplateAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "templates", "display_name", req.Msg.DisplayName)
+			return nil, apierrors.Mind.AlreadyExists("templates", "display_name", req.Msg.DisplayName)
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to create template", err)
+		return nil, apierrors.Mind.Internal("failed to create template", err)
 	}
 
 	template, err := h.service.GetTemplateByID(ctx, templateID)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve created template", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve created template", err)
 	}
 
 	return connect.NewResponse(StoreTemplateToProto(template)), nil
@@ -55,7 +55,7 @@ func (h *TemplatesHandler) ListTemplates(
 
 	templates, err := h.service.ListTemplatesPaginated(ctx, params.Limit, params.Offset)
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to list templates", err)
+		return nil, apierrors.Mind.Internal("failed to list templates", err)
 	}
 
 	var totalCount int64
@@ -91,9 +91,9 @@ func (h *TemplatesHandler) GetTemplate(
 	template, err := h.service.GetTemplateByID(ctx, req.Msg.GetId())
 	if err != nil {
 		if errors.Is(err, ErrTemplateNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "templates", strconv.FormatInt(req.Msg.GetId(), 10))
+			return nil, apierrors.Mind.NotFound("templates", strconv.FormatInt(req.Msg.GetId(), 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to get template", err)
+		return nil, apierrors.Mind.Internal("failed to get template", err)
 	}
 
 	return connect.NewResponse(StoreTemplateToProto(template)), nil
@@ -108,17 +108,17 @@ func (h *TemplatesHandler) UpdateTemplate(
 	err := h.service.UpdateTemplate(ctx, params)
 	if err != nil {
 		if errors.Is(err, ErrTemplateNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "templates", strconv.FormatInt(req.Msg.GetId(), 10))
+			return nil, apierrors.Mind.NotFound("templates", strconv.FormatInt(req.Msg.GetId(), 10))
 		}
 		if errors.Is(err, ErrTemplateAlreadyExists) {
-			return nil, apierrors.NewAlreadyExistsError(apierrors.MindDomain, "templates", "display_name", req.Msg.DisplayName)
+			return nil, apierrors.Mind.AlreadyExists("templates", "display_name", req.Msg.DisplayName)
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to update template", err)
+		return nil, apierrors.Mind.Internal("failed to update template", err)
 	}
 
 	template, err := h.service.GetTemplateByID(ctx, req.Msg.GetId())
 	if err != nil {
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to retrieve updated template", err)
+		return nil, apierrors.Mind.Internal("failed to retrieve updated template", err)
 	}
 
 	return connect.NewResponse(StoreTemplateToProto(template)), nil
@@ -131,9 +131,9 @@ func (h *TemplatesHandler) DeleteTemplate(
 	err := h.service.DeleteTemplate(ctx, req.Msg.GetId())
 	if err != nil {
 		if errors.Is(err, ErrTemplateNotFound) {
-			return nil, apierrors.NewNotFoundError(apierrors.MindDomain, "templates", strconv.FormatInt(req.Msg.GetId(), 10))
+			return nil, apierrors.Mind.NotFound("templates", strconv.FormatInt(req.Msg.GetId(), 10))
 		}
-		return nil, apierrors.NewInternalError(apierrors.MindDomain, "failed to delete template", err)
+		return nil, apierrors.Mind.Internal("failed to delete template", err)
 	}
 
 	return connect.NewResponse(&emptypb.Empty{}), nil
@@ -14,7 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// setupTestService creates a TemplatesService with in-memory database for testing.
+// setupTestService creates a TemplatesService with a test database for testing.
 func setupTestService(t *testing.T) (*TemplatesService, *store.Queries) {
 	t.Helper()
 
@@ -22,12 +22,24 @@ func setupTestService(t *testing.T) (*TemplatesService, *store.Queries) {
 	t.Cleanup(func() { db.Close() })
 
 	queries := store.New(db)
+	createDefaultCollection(t, queries)
 	logger := testdb.NewTestLogger(t)
 	service := NewTemplatesService(queries, logger, "templates-test")
 
 	return service, queries
 }
 
+// createDefaultCollection creates collection 1, which notes default to (the server creates it on startup).
+func createDefaultCollection(t *testing.T, queries *store.Queries) {
+	t.Helper()
+
+	_, err := queries.CreateCollection(context.Background(), store.CreateCollectionParams{
+		Name: "default",
+		Path: "default",
+	})
+	require.NoError(t, err)
+}
+
 // createStarterNote creates a note for testing templates.
 func createStarterNote(t *testing.T, queries *store.Queries, title string) int64 {
 	t.Helper()
@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// AdminHandler serves webhook management endpoints under /admin.
+// Requests are authenticated by middleware.AdminAuthMiddleware, which guards all /admin/* routes.
+type AdminHandler struct {
+	service *WebhookService
+}
+
+// RegisterWebhookRequest is the JSON body of POST /admin/webhooks.
+type RegisterWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// WebhookResponse describes a registered webhook. The signing secret is never included.
+type WebhookResponse struct {
+	ID        int64    `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	CreatedAt *string  `json:"created_at,omitempty"`
+}
+
+// ListWebhooksResponse is the body of GET /admin/webhooks.
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// NewAdminHandler creates a new webhooks admin handler.
+func NewAdminHandler(service *WebhookService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// RegisterRoutes registers webhook admin routes on the Echo instance.
+func (h *AdminHandler) RegisterRoutes(e *echo.Echo) {
+	admin := e.Group("/admin/webhooks")
+	admin.POST("", h.HandleRegister)
+	admin.GET("", h.HandleList)
+	admin.DELETE("/:id", h.HandleDelete)
+}
+
+// HandleRegister registers a webhook for the requested note events.
+func (h *AdminHandler) HandleRegister(c echo.Context) error {
+	var req RegisterWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	webhook, err := h.service.Register(c.Request().Context(), req.URL, req.Secret, req.Events)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidWebhookURL),
+			errors.Is(err, ErrWebhookSecretRequired),
+			errors.Is(err, ErrInvalidWebhookEvents):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to register webhook")
+		}
+	}
+
+	return c.JSON(http.StatusCreated, webhookResponse(webhook))
+}
+
+// HandleList lists registered webhooks.
+func (h *AdminHandler) HandleList(c echo.Context) error {
+	webhooks, err := h.service.List(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to list webhooks")
+	}
+
+	resp := ListWebhooksResponse{Webhooks: make([]WebhookResponse, len(webhooks))}
+	for i, webhook := range webhooks {
+		resp.Webhooks[i] = webhookResponse(webhook)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// HandleDelete removes a webhook.
+func (h *AdminHandler) HandleDelete(c echo.Context) error {
+	id, err := utils.ParseIDParam(c.Param("id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid webhook id")
+	}
+
+	if err := h.service.Delete(c.Request().Context(), id); err != nil {
+		if errors.Is(err, ErrWebhookNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "webhook not found")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to delete webhook")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// webhookResponse converts a stored webhook to its JSON form.
+// Malformed stored events are reported as an empty list.
+func webhookResponse(webhook store.Webhook) WebhookResponse {
+	resp := WebhookResponse{
+		ID:        webhook.ID,
+		URL:       webhook.Url,
+		Events:    []string{},
+		CreatedAt: utils.FormatNullTime(webhook.CreatedAt),
+	}
+	_ = json.Unmarshal([]byte(webhook.Events), &resp.Events)
+	return resp
+}
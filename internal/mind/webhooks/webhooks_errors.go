@@ -0,0 +1,23 @@
+package webhooks
+
+// Webhooks Domain Errors
+// Domain-specific errors for the webhook service layer
+
+import (
+	"errors"
+)
+
+// Domain errors for webhook service
+var (
+	// ErrWebhookNotFound indicates a webhook was not found
+	ErrWebhookNotFound = errors.New("webhook not found")
+
+	// ErrInvalidWebhookURL indicates the URL is not an absolute http(s) URL
+	ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url")
+
+	// ErrWebhookSecretRequired indicates a missing signing secret
+	ErrWebhookSecretRequired = errors.New("webhook secret is required")
+
+	// ErrInvalidWebhookEvents indicates no events or an unsupported event name
+	ErrInvalidWebhookEvents = errors.New("webhook events must be one or more of note.created, note.updated, note.deleted")
+)
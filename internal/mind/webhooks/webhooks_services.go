@@ -0,0 +1,345 @@
+// Package webhooks delivers note lifecycle events to external URLs.
+//
+// WebhookService subscribes to the event hub, so deliveries are only enqueued for
+// changes that were committed and published. Each delivery is a JSON POST signed
+// with the webhook's secret in the X-Mindweaver-Signature header
+// (sha256=<hex HMAC-SHA256 of the body>). Deliveries are sent by a small pool of
+// workers, so one slow receiver doesn't hold up the others. Failed deliveries are
+// retried with exponential backoff, up to maxAttempts in total; the queue lives in
+// memory and pending retries are dropped on shutdown.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/internal/mind/events"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// Event names a webhook can subscribe to.
+const (
+	EventNoteCreated = "note.created"
+	EventNoteUpdated = "note.updated"
+	EventNoteDeleted = "note.deleted"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the request body.
+const SignatureHeader = "X-Mindweaver-Signature"
+
+const (
+	maxAttempts     = 3                // Delivery attempts per webhook and event, including the first
+	queueSize       = 256              // Deliveries buffered before new ones are dropped
+	deliveryWorkers = 4                // Deliveries sent concurrently
+	deliveryTimeout = 10 * time.Second // Per-attempt HTTP timeout
+)
+
+// Payload is the JSON body POSTed to webhook URLs.
+type Payload struct {
+	Event     string `json:"event"`
+	NoteID    int64  `json:"note_id"`
+	Timestamp string `json:"timestamp"` // RFC 3339, UTC
+}
+
+// delivery is one payload bound for one webhook.
+type delivery struct {
+	webhookID int64
+	url       string
+	secret    string
+	body      []byte
+	attempt   int // Attempts made so far
+}
+
+// WebhookService manages webhook registrations and delivers note events to them.
+type WebhookService struct {
+	db      *sql.DB
+	store   store.Querier
+	logger  *slog.Logger
+	client  *http.Client
+	queue   chan delivery
+	backoff time.Duration // Delay before the first retry; doubled for each further retry
+
+	// cache holds the registered webhooks so enqueue doesn't query the store for every
+	// event. It is dropped by Register and Delete; cacheGen keeps a list loaded
+	// concurrently with a change from being cached.
+	cacheMu  sync.Mutex
+	cache    []store.Webhook
+	cached   bool
+	cacheGen uint64
+}
+
+// NewWebhookService creates a new WebhookService. Call Start to begin delivering events.
+func NewWebhookService(db *sql.DB, store store.Querier, logger *slog.Logger, serviceName string) *WebhookService {
+	return &WebhookService{
+		db:      db,
+		store:   store,
+		logger:  logger.With("service", serviceName),
+		client:  &http.Client{Timeout: deliveryTimeout},
+		queue:   make(chan delivery, queueSize),
+		backoff: time.Second,
+	}
+}
+
+// Register creates a webhook that receives the given events at rawURL, signed with secret.
+func (s *WebhookService) Register(ctx context.Context, rawURL, secret string, eventNames []string) (store.Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return store.Webhook{}, ErrInvalidWebhookURL
+	}
+	if secret == "" {
+		return store.Webhook{}, ErrWebhookSecretRequired
+	}
+	if len(eventNames) == 0 {
+		return store.Webhook{}, ErrInvalidWebhookEvents
+	}
+	for _, name := range eventNames {
+		if name != EventNoteCreated && name != EventNoteUpdated && name != EventNoteDeleted {
+			return store.Webhook{}, ErrInvalidWebhookEvents
+		}
+	}
+
+	eventsJSON, err := json.Marshal(eventNames)
+	if err != nil {
+		return store.Webhook{}, err
+	}
+
+	id, err := s.store.CreateWebhook(ctx, store.CreateWebhookParams{
+		Url:    rawURL,
+		Secret: secret,
+		Events: string(eventsJSON),
+	})
+	if err != nil {
+		s.logger.Error("failed to create webhook", "url", rawURL, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Webhook{}, err
+	}
+
+	webhook, err := s.store.GetWebhookByID(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to get created webhook", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return store.Webhook{}, err
+	}
+
+	s.invalidateCache()
+	middleware.ContextLogger(ctx, s.logger).Info("webhook registered", "webhook_id", id, "url", rawURL, "events", eventNames)
+	return webhook, nil
+}
+
+// List returns all registered webhooks.
+func (s *WebhookService) List(ctx context.Context) ([]store.Webhook, error) {
+	webhooks, err := s.store.ListWebhooks(ctx)
+	if err != nil {
+		s.logger.Error("failed to list webhooks", "err", err, "request_id", middleware.GetRequestID(ctx))
+	}
+	return webhooks, err
+}
+
+// Delete removes a webhook. Deliveries already queued for it are still attempted.
+func (s *WebhookService) Delete(ctx context.Context, id int64) error {
+	result, err := s.store.DeleteWebhook(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to delete webhook", "id", id, "err", err, "request_id", middleware.GetRequestID(ctx))
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+
+	s.invalidateCache()
+	middleware.ContextLogger(ctx, s.logger).Info("webhook deleted", "webhook_id", id)
+	return nil
+}
+
+// Start subscribes to hub and delivers note events in the background on deliveryWorkers
+// goroutines. Delivery stops when the hub is closed.
+func (s *WebhookService) Start(hub events.Hub) {
+	eventCh := hub.Subscribe()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for event := range eventCh {
+			if name := eventName(event); name != "" {
+				s.enqueue(context.Background(), name, event)
+			}
+		}
+	}()
+
+	for range deliveryWorkers {
+		go s.deliverLoop(done)
+	}
+}
+
+// eventName maps hub events to webhook event names ("" = not delivered).
+func eventName(event *mindv3.Event) string {
+	if event.Domain != mindv3.EventDomain_EVENT_DOMAIN_NOTE {
+		return ""
+	}
+	switch event.Type {
+	case mindv3.EventType_EVENT_TYPE_CREATED:
+		return EventNoteCreated
+	case mindv3.EventType_EVENT_TYPE_UPDATED:
+		return EventNoteUpdated
+	case mindv3.EventType_EVENT_TYPE_DELETED:
+		return EventNoteDeleted
+	default:
+		return ""
+	}
+}
+
+// cachedWebhooks returns the registered webhooks, loading them from the store on a cache miss.
+func (s *WebhookService) cachedWebhooks(ctx context.Context) ([]store.Webhook, error) {
+	s.cacheMu.Lock()
+	if s.cached {
+		webhooks := s.cache
+		s.cacheMu.Unlock()
+		return webhooks, nil
+	}
+	gen := s.cacheGen
+	s.cacheMu.Unlock()
+
+	webhooks, err := s.store.ListWebhooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMu.Lock()
+	if s.cacheGen == gen {
+		s.cache = webhooks
+		s.cached = true
+	}
+	s.cacheMu.Unlock()
+	return webhooks, nil
+}
+
+// invalidateCache drops the cached webhook list after a registration change.
+func (s *WebhookService) invalidateCache() {
+	s.cacheMu.Lock()
+	s.cache = nil
+	s.cached = false
+	s.cacheGen++
+	s.cacheMu.Unlock()
+}
+
+// enqueue queues a delivery to every webhook subscribed to name.
+func (s *WebhookService) enqueue(ctx context.Context, name string, event *mindv3.Event) {
+	webhooks, err := s.cachedWebhooks(ctx)
+	if err != nil {
+		s.logger.Error("failed to list webhooks for delivery", "event", name, "note_id", event.EntityId, "err", err)
+		return
+	}
+
+	timestamp := time.Now()
+	if event.Timestamp != nil {
+		timestamp = event.Timestamp.AsTime()
+	}
+	body, err := json.Marshal(Payload{
+		Event:     name,
+		NoteID:    event.EntityId,
+		Timestamp: timestamp.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		s.logger.Error("failed to marshal webhook payload", "event", name, "err", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		var subscribed []string
+		if err := json.Unmarshal([]byte(webhook.Events), &subscribed); err != nil {
+			s.logger.Warn("skipping webhook with malformed events", "webhook_id", webhook.ID, "err", err)
+			continue
+		}
+		if !slices.Contains(subscribed, name) {
+			continue
+		}
+		s.push(delivery{webhookID: webhook.ID, url: webhook.Url, secret: webhook.Secret, body: body})
+	}
+}
+
+// push adds d to the queue without blocking; a full queue drops it.
+func (s *WebhookService) push(d delivery) {
+	select {
+	case s.queue <- d:
+	default:
+		s.logger.Warn("webhook queue full, dropping delivery", "webhook_id", d.webhookID, "attempt", d.attempt+1)
+	}
+}
+
+// deliverLoop sends queued deliveries until done is closed; Start runs deliveryWorkers of them.
+// Failed attempts are requeued after backoff, doubling the delay each time, until
+// maxAttempts is reached.
+func (s *WebhookService) deliverLoop(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case d := <-s.queue:
+			d.attempt++
+			err := s.send(d)
+			if err == nil {
+				s.logger.Debug("webhook delivered", "webhook_id", d.webhookID, "attempt", d.attempt)
+				continue
+			}
+			if d.attempt >= maxAttempts {
+				s.logger.Warn("webhook delivery failed, giving up", "webhook_id", d.webhookID, "attempts", d.attempt, "err", err)
+				continue
+			}
+
+			delay := s.backoff << (d.attempt - 1)
+			s.logger.Debug("webhook delivery failed, retrying", "webhook_id", d.webhookID, "attempt", d.attempt, "retry_in", delay, "err", err)
+			time.AfterFunc(delay, func() {
+				select {
+				case <-done:
+				default:
+					s.push(d)
+				}
+			})
+		}
+	}
+}
+
+// send POSTs one delivery; any non-2xx response is an error.
+func (s *WebhookService) send(d delivery) error {
+	req, err := http.NewRequest(http.MethodPost, d.url, bytes.NewReader(d.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(d.secret, d.body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the X-Mindweaver-Signature value for body: "sha256=" and the hex HMAC-SHA256.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
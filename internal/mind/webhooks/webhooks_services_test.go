@@ -0,0 +1,280 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+
+	mindv3 "github.com/nkapatos/mindweaver/gen/proto/mind/v3"
+	"github.com/nkapatos/mindweaver/internal/mind/events"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	mindmigrations "github.com/nkapatos/mindweaver/migrations/mind"
+	"github.com/nkapatos/mindweaver/shared/testdb"
+)
+
+const testSecret = "whsec-test"
+
+// setupTestService creates a started WebhookService with a test database and a fast retry backoff.
+func setupTestService(t *testing.T) (*WebhookService, events.Hub) {
+	t.Helper()
+
+	db := testdb.SetupTestDB(t, mindmigrations.RunMigrations)
+	t.Cleanup(func() { db.Close() })
+
+	logger := testdb.NewTestLogger(t)
+	service := NewWebhookService(db, store.New(db), logger, "webhooks-test")
+	service.backoff = time.Millisecond
+
+	hub := events.NewHub(logger)
+	t.Cleanup(hub.Close)
+	service.Start(hub)
+
+	return service, hub
+}
+
+// receivedRequest is a delivery captured by the test receiver.
+type receivedRequest struct {
+	payload   Payload
+	signature string
+	body      []byte
+}
+
+// newReceiver starts a webhook receiver that fails the first failures requests with 500.
+// It returns the server and a channel of successfully received deliveries.
+func newReceiver(t *testing.T, failures int32) (*httptest.Server, <-chan receivedRequest, *atomic.Int32) {
+	t.Helper()
+
+	received := make(chan receivedRequest, 16)
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var payload Payload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- receivedRequest{payload: payload, signature: r.Header.Get(SignatureHeader), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, received, &attempts
+}
+
+func waitForDelivery(t *testing.T, received <-chan receivedRequest) receivedRequest {
+	t.Helper()
+
+	select {
+	case r := <-received:
+		return r
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for webhook delivery")
+		return receivedRequest{}
+	}
+}
+
+func TestRegister_Validation(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		url      string
+		secret   string
+		events   []string
+		expected error
+	}{
+		{"relative url", "/hooks", testSecret, []string{EventNoteCreated}, ErrInvalidWebhookURL},
+		{"unsupported scheme", "ftp://example.com/hooks", testSecret, []string{EventNoteCreated}, ErrInvalidWebhookURL},
+		{"missing secret", "https://example.com/hooks", "", []string{EventNoteCreated}, ErrWebhookSecretRequired},
+		{"no events", "https://example.com/hooks", testSecret, nil, ErrInvalidWebhookEvents},
+		{"unknown event", "https://example.com/hooks", testSecret, []string{"note.archived"}, ErrInvalidWebhookEvents},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Register(ctx, tt.url, tt.secret, tt.events)
+			require.ErrorIs(t, err, tt.expected)
+		})
+	}
+}
+
+func TestRegisterListDelete(t *testing.T) {
+	service, _ := setupTestService(t)
+	ctx := context.Background()
+
+	webhook, err := service.Register(ctx, "https://example.com/hooks", testSecret, []string{EventNoteCreated, EventNoteDeleted})
+	require.NoError(t, err)
+	require.Equal(t, `["note.created","note.deleted"]`, webhook.Events)
+
+	webhooks, err := service.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+
+	require.NoError(t, service.Delete(ctx, webhook.ID))
+	require.ErrorIs(t, service.Delete(ctx, webhook.ID), ErrWebhookNotFound)
+
+	webhooks, err = service.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, webhooks)
+}
+
+func TestDelivery_SignedPayloadForSubscribedEvents(t *testing.T) {
+	service, hub := setupTestService(t)
+	ctx := context.Background()
+
+	server, received, _ := newReceiver(t, 0)
+	_, err := service.Register(ctx, server.URL, testSecret, []string{EventNoteCreated, EventNoteDeleted})
+	require.NoError(t, err)
+
+	// Updates and other domains are not subscribed and must not be delivered
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, 123)
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_COLLECTION, mindv3.EventType_EVENT_TYPE_CREATED, 5)
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, 123)
+
+	r := waitForDelivery(t, received)
+	require.Equal(t, EventNoteCreated, r.payload.Event)
+	require.Equal(t, int64(123), r.payload.NoteID)
+	_, err = time.Parse(time.RFC3339Nano, r.payload.Timestamp)
+	require.NoError(t, err)
+	require.Equal(t, Sign(testSecret, r.body), r.signature)
+
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_DELETED, 123)
+	require.Equal(t, EventNoteDeleted, waitForDelivery(t, received).payload.Event)
+}
+
+func TestDelivery_RetriesWithBackoff(t *testing.T) {
+	service, hub := setupTestService(t)
+	ctx := context.Background()
+
+	server, received, attempts := newReceiver(t, 2)
+	_, err := service.Register(ctx, server.URL, testSecret, []string{EventNoteUpdated})
+	require.NoError(t, err)
+
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_UPDATED, 7)
+
+	r := waitForDelivery(t, received)
+	require.Equal(t, EventNoteUpdated, r.payload.Event)
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestDelivery_GivesUpAfterMaxAttempts(t *testing.T) {
+	service, hub := setupTestService(t)
+	ctx := context.Background()
+
+	server, received, attempts := newReceiver(t, 100)
+	_, err := service.Register(ctx, server.URL, testSecret, []string{EventNoteCreated})
+	require.NoError(t, err)
+
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, 1)
+
+	require.Eventually(t, func() bool { return attempts.Load() == maxAttempts }, 2*time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond) // Several backoff periods: no further attempt may follow
+	require.Equal(t, int32(maxAttempts), attempts.Load())
+	require.Empty(t, received)
+}
+
+func TestDelivery_SlowReceiverDoesNotStallOthers(t *testing.T) {
+	service, hub := setupTestService(t)
+	ctx := context.Background()
+
+	// The first receiver never answers until the test ends
+	release := make(chan struct{})
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(stalled.Close)
+	t.Cleanup(func() { close(release) })
+
+	server, received, _ := newReceiver(t, 0)
+	_, err := service.Register(ctx, stalled.URL, testSecret, []string{EventNoteCreated})
+	require.NoError(t, err)
+	_, err = service.Register(ctx, server.URL, testSecret, []string{EventNoteCreated})
+	require.NoError(t, err)
+
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, 1)
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, 2)
+
+	// Both deliveries arrive well within the stalled receiver's 10s timeout, in any order
+	noteIDs := []int64{waitForDelivery(t, received).payload.NoteID, waitForDelivery(t, received).payload.NoteID}
+	require.ElementsMatch(t, []int64{1, 2}, noteIDs)
+}
+
+func TestDelivery_CacheFollowsRegistrationChanges(t *testing.T) {
+	service, hub := setupTestService(t)
+	ctx := context.Background()
+
+	first, firstReceived, _ := newReceiver(t, 0)
+	webhook, err := service.Register(ctx, first.URL, testSecret, []string{EventNoteCreated})
+	require.NoError(t, err)
+
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, 1)
+	waitForDelivery(t, firstReceived)
+
+	// The cached list is reloaded after a registration change
+	second, secondReceived, _ := newReceiver(t, 0)
+	_, err = service.Register(ctx, second.URL, testSecret, []string{EventNoteCreated})
+	require.NoError(t, err)
+	require.NoError(t, service.Delete(ctx, webhook.ID))
+
+	hub.Publish(ctx, mindv3.EventDomain_EVENT_DOMAIN_NOTE, mindv3.EventType_EVENT_TYPE_CREATED, 2)
+	require.Equal(t, int64(2), waitForDelivery(t, secondReceived).payload.NoteID)
+	require.Empty(t, firstReceived)
+}
+
+func TestAdminHandler_RegisterListDelete(t *testing.T) {
+	service, _ := setupTestService(t)
+
+	e := echo.New()
+	NewAdminHandler(service).RegisterRoutes(e)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := send(http.MethodPost, "/admin/webhooks", `{"url": "https://example.com/hooks", "secret": "`+testSecret+`", "events": ["note.created"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.NotContains(t, rec.Body.String(), testSecret)
+	var created WebhookResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	require.Equal(t, []string{EventNoteCreated}, created.Events)
+
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/admin/webhooks", `{"url": "https://example.com/hooks", "events": ["note.created"]}`).Code)
+
+	rec = send(http.MethodGet, "/admin/webhooks", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var listed ListWebhooksResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Webhooks, 1)
+	require.Equal(t, "https://example.com/hooks", listed.Webhooks[0].URL)
+
+	path := "/admin/webhooks/" + strconv.FormatInt(created.ID, 10)
+	require.Equal(t, http.StatusNoContent, send(http.MethodDelete, path, "").Code)
+	require.Equal(t, http.StatusNotFound, send(http.MethodDelete, path, "").Code)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"a":1}' | openssl dgst -sha256 -hmac secret
+	require.Equal(t, "sha256=aa9e2e3575f5d7098b6caccd790888c36d5fdb63342a73bada2d6a51747a8494", Sign("secret", []byte(`{"a":1}`)))
+}
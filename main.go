@@ -1,28 +1,35 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	// brainadapters "github.com/nkapatos/mindweaver/internal/brain/adapters"
-	// brainbootstrap "github.com/nkapatos/mindweaver/internal/brain/bootstrap"
+	"github.com/nkapatos/mindweaver/internal/admin/loglevel"
 	"github.com/nkapatos/mindweaver/internal/admin/setup"
+	brainbootstrap "github.com/nkapatos/mindweaver/internal/brain/bootstrap"
 	"github.com/nkapatos/mindweaver/internal/mind/bootstrap"
 	"github.com/nkapatos/mindweaver/internal/mind/events"
+	"github.com/nkapatos/mindweaver/internal/mind/gen/store"
+	"github.com/nkapatos/mindweaver/internal/mind/links"
 	"github.com/nkapatos/mindweaver/internal/mind/notes"
 	"github.com/nkapatos/mindweaver/internal/mind/scheduler"
 	"github.com/nkapatos/mindweaver/shared/config"
+	"github.com/nkapatos/mindweaver/shared/database"
+	"github.com/nkapatos/mindweaver/shared/health"
 	"github.com/nkapatos/mindweaver/shared/logging"
+	"github.com/nkapatos/mindweaver/shared/metrics"
 	mwmiddleware "github.com/nkapatos/mindweaver/shared/middleware"
+	"github.com/nkapatos/mindweaver/shared/server"
+	"github.com/nkapatos/mindweaver/shared/sqlcext"
 	"github.com/nkapatos/mindweaver/shared/utils"
 
 	"github.com/labstack/echo/v4"
@@ -33,6 +40,7 @@ import (
 func main() {
 	// Parse runtime mode flag
 	mode := flag.String("mode", "combined", "Runtime mode: combined, mind, or brain")
+	brainStartupTimeout := flag.Duration("brain-startup-timeout", 30*time.Second, "How long Brain waits for Mind's /health before giving up (brain mode)")
 	flag.Parse()
 
 	// Validate mode and load config
@@ -87,6 +95,9 @@ func main() {
 	var notesDB *sql.DB
 	var assistantDB *sql.DB
 
+	// Mind → Brain scheduler (only set in combined mode, reported by /health)
+	var changeScheduler *scheduler.ChangeAccumulator
+
 	// Create Echo instance (needed for bootstrap)
 	e := echo.New()
 	e.HidePort = true
@@ -98,15 +109,25 @@ func main() {
 	}))
 	e.Use(echomiddleware.Recover())
 
+	// Prometheus metrics (request latency per route, note and LLM counters)
+	appMetrics := metrics.New()
+	e.Use(appMetrics.MetricsMiddleware)
+
 	// Setup wizard middleware - redirects to /admin/setup if no config.yaml exists
 	// Must be registered before other routes but after recovery
 	e.Use(setup.SetupRequiredMiddleware(cfg.DataDir))
 
 	e.Use(mwmiddleware.ErrorHandlerMiddleware)
 	e.Use(mwmiddleware.RequestIDMiddleware)
+	e.Use(mwmiddleware.TraceIDMiddleware)
 	e.Use(mwmiddleware.SessionIDMiddleware)
 
-	// Health check endpoint (always accessible, even without config)
+	// Admin API (/admin/*, except the setup wizard) requires a signed admin JWT
+	e.Use(mwmiddleware.AdminAuthMiddleware(cfg.Security.AdminJWTSecret))
+
+	// Health check endpoint (always accessible, even without config).
+	// Components register probes below as they are initialized.
+	healthChecker := health.NewChecker(2 * time.Second)
 	e.GET("/health", func(c echo.Context) error {
 		var services string
 		switch {
@@ -117,13 +138,17 @@ func main() {
 		case enableBrain:
 			services = "brain"
 		}
-		return c.JSON(200, map[string]string{
-			"status":   "healthy",
-			"mode":     *mode,
-			"services": services,
-		})
+		report := healthChecker.Check(c.Request().Context())
+		return c.JSON(report.HTTPStatus(), struct {
+			health.Report
+			Mode     string `json:"mode"`
+			Services string `json:"services"`
+		}{report, *mode, services})
 	})
 
+	// Metrics endpoint (always accessible, like /health)
+	e.GET("/metrics", echo.WrapHandler(appMetrics.Handler()))
+
 	// Setup wizard routes (accessible without config)
 	setupHandler, err := setup.NewHandler(cfg.DataDir, logger)
 	if err != nil {
@@ -132,6 +157,9 @@ func main() {
 	}
 	setupHandler.RegisterRoutes(e)
 
+	// Runtime log level changes (PUT /admin/log-level)
+	loglevel.NewHandler(logger).RegisterRoutes(e)
+
 	// Create /api group for all services
 	api := e.Group("/api")
 
@@ -139,14 +167,27 @@ func main() {
 	var mindNotesService *notes.NotesService
 	var eventHub events.Hub
 	if enableMind {
-		db, notesSvc, hub, err := bootstrap.Initialize(e, api, cfg.Mind.DBPath, logger)
+		db, notesSvc, hub, err := bootstrap.Initialize(e, api, cfg.Mind.DBPath, cfg.Security.JWTSecret, logger)
 		if err != nil {
 			logger.Error("Failed to initialize mind service", "error", err)
 			os.Exit(1)
 		}
 		notesDB = db
 		mindNotesService = notesSvc
+		mindNotesService.SetMetrics(appMetrics)
+		mindNotesService.SetFrontmatterSync(cfg.Mind.EnableFrontmatterSync)
+		mindNotesService.SetMaxBodySize(cfg.Mind.MaxBodySizeBytes)
+		if count, err := mindNotesService.CountNotes(context.Background()); err != nil {
+			logger.Warn("Failed to initialize notes count metric", "error", err)
+		} else {
+			appMetrics.NotesCount.Set(float64(count))
+		}
 		eventHub = hub
+		healthChecker.Register("db", true, health.DBProbe(notesDB))
+		healthChecker.Register("fts", false, sqlcext.NewFTSQuerier(notesDB, sqlcext.FTSConfig{
+			ContentTable: "notes",
+			FTSTable:     "notes_fts",
+		}).Ping)
 		defer func() {
 			if err := notesDB.Close(); err != nil {
 				logger.Error("Failed to close notes database", "error", err)
@@ -154,7 +195,28 @@ func main() {
 		}()
 	}
 
+	// Standalone Brain only starts once the remote Mind is healthy.
+	// In combined mode Mind runs in-process and is ready once initialized above.
+	if enableBrain && !enableMind {
+		logger.Info("Waiting for Mind service", "url", cfg.Brain.MindServiceURL, "timeout", *brainStartupTimeout)
+		if err := brainbootstrap.BrainReadinessProbe(context.Background(), cfg.Brain.MindServiceURL, *brainStartupTimeout); err != nil {
+			logger.Error("Mind service not ready, not starting brain", "error", err)
+			os.Exit(1)
+		}
+
+		if cfg.Brain.UpstreamMindURL != "" {
+			mindProxy, err := brainbootstrap.MindProxyMiddleware(cfg.Brain.UpstreamMindURL, logger)
+			if err != nil {
+				logger.Error("Failed to configure mind proxy", "error", err)
+				os.Exit(1)
+			}
+			e.Use(mindProxy)
+			logger.Info("Proxying unknown Mind API requests", "upstream", cfg.Brain.UpstreamMindURL)
+		}
+	}
+
 	// Initialize Brain service if needed
+	// TODO: re-enable once the brain adapters and Initialize are back in the tree
 	// if enableBrain {
 	// 	// Create Mind adapter using factory (automatically selects Local or Remote based on mode)
 	// 	mindOps, err := brainadapters.NewMindOperations(cfg, mindNotesService, logger)
@@ -177,6 +239,20 @@ func main() {
 	// 	}()
 	// }
 
+	// Goroutine to periodically purge notes that have been in the trash past retention
+	if mindNotesService != nil && cfg.Mind.TrashRetentionDays > 0 {
+		retention := time.Duration(cfg.Mind.TrashRetentionDays) * 24 * time.Hour
+		go func() {
+			ticker := time.NewTicker(time.Hour)
+			defer ticker.Stop()
+			for ; ; <-ticker.C {
+				if _, err := mindNotesService.PurgeDeletedNotes(context.Background(), retention); err != nil {
+					logger.Error("trash purge failed", "error", err)
+				}
+			}
+		}()
+	}
+
 	// Goroutine to periodically checkpoint WAL files
 	if notesDB != nil || assistantDB != nil {
 		go func() {
@@ -184,33 +260,18 @@ func main() {
 			defer ticker.Stop()
 			for range ticker.C {
 				if notesDB != nil {
-					if _, err := notesDB.Exec("PRAGMA wal_checkpoint(FULL);"); err != nil {
-						logger.Error("notes db wal checkpoint failed", "error", err)
-					}
+					checkpointWAL(notesDB, "notes", appMetrics, logger)
 				}
 				if assistantDB != nil {
-					if _, err := assistantDB.Exec("PRAGMA wal_checkpoint(FULL);"); err != nil {
-						logger.Error("assistant db wal checkpoint failed", "error", err)
-					}
+					checkpointWAL(assistantDB, "assistant", appMetrics, logger)
 				}
 			}
 		}()
 	}
 
-	// Graceful shutdown - checkpoint WAL files before exit
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGTERM, syscall.SIGINT)
-	go func() {
-		<-sigChan
-		logger.Info("Shutdown signal received, stopping services...")
-
-		// Close event hub (sends shutdown event to connected SSE clients)
-		if eventHub != nil {
-			logger.Info("Closing event hub...")
-			eventHub.Close()
-		}
-
-		// Checkpoint databases
+	// Graceful shutdown - once the server has drained, checkpoint WAL files.
+	// Deferred cleanup (scheduler drain, then DB close) runs after this as main returns.
+	defer func() {
 		logger.Info("Checkpointing databases...")
 		if notesDB != nil {
 			if _, err := notesDB.Exec("PRAGMA wal_checkpoint(FULL);"); err != nil {
@@ -222,12 +283,9 @@ func main() {
 				logger.Error("Failed to checkpoint assistant DB on shutdown", "error", err)
 			}
 		}
-		time.Sleep(200 * time.Millisecond) // Give kernel time to flush
-		os.Exit(0)
 	}()
 
 	// Initialize scheduler (Mind → Brain sync) if both services enabled
-	var changeScheduler *scheduler.ChangeAccumulator
 	if enableMind && enableBrain && mindNotesService != nil {
 		logger.Info("🔄 Initializing Mind→Brain scheduler")
 
@@ -242,18 +300,37 @@ func main() {
 		changeScheduler = scheduler.NewChangeAccumulator(schedulerCfg, logger)
 		mindNotesService.SetScheduler(changeScheduler)
 		changeScheduler.Start()
+		healthChecker.Register("scheduler", false, changeScheduler.CheckHealth)
 
 		logger.Info("✅ Scheduler started - Mind will sync changes to Brain")
 
-		// Ensure scheduler stops on shutdown
+		// Flush changes still pending on shutdown, bounded by the shutdown timeout
 		defer func() {
-			logger.Info("Stopping scheduler...")
-			if err := changeScheduler.Stop(); err != nil {
-				logger.Error("Failed to stop scheduler", "error", err)
+			logger.Info("Draining scheduler...", "pending_changes", changeScheduler.GetPendingCount())
+			drainCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+			defer cancel()
+			if err := changeScheduler.DrainAndStop(drainCtx); err != nil {
+				logger.Error("Failed to drain scheduler", "error", err)
 			}
 		}()
 	}
 
+	// Daily job moving notes inactive for ArchiveDays to archive/YYYY collections
+	if mindNotesService != nil && cfg.Mind.Archiver.Enabled {
+		archiveJob := scheduler.NewArchiveJob(mindNotesService, cfg.Mind.Archiver.ArchiveDays, logger)
+		archiveJob.Start()
+		defer archiveJob.Stop()
+	}
+
+	// Hourly job resolving wiki-links whose target notes were created later (e.g., bulk imports)
+	if notesDB != nil && cfg.Mind.Resolver.Enabled {
+		linksService := links.NewLinksService(store.New(notesDB), logger, "Link Resolver")
+		linksService.SetResolveRetryWindow(time.Duration(cfg.Mind.Resolver.RetryWindowHours) * time.Hour)
+		resolveJob := scheduler.NewLinkResolveJob(linksService, cfg.Mind.Resolver.Interval, logger)
+		resolveJob.Start()
+		defer resolveJob.Stop()
+	}
+
 	// Start the server
 	var host string
 	var port int
@@ -274,7 +351,34 @@ func main() {
 		"address", addr,
 		"mode", *mode)
 
-	if err := e.Start(addr); !errors.Is(err, http.ErrServerClosed) {
+	// SIGTERM/SIGINT stop the server gracefully instead of exiting, so deferred cleanup runs
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	// Close event hub first (sends shutdown event to SSE clients so their streams end)
+	closeEventHub := func() {
+		if eventHub != nil {
+			logger.Info("Closing event hub...")
+			eventHub.Close()
+		}
+	}
+
+	if err := server.Run(ctx, e, addr, cfg.ShutdownTimeout, closeEventHub, logger); err != nil {
 		logger.Error("server error", "error", err)
 	}
 }
+
+// checkpointWAL runs a FULL WAL checkpoint on db and records its outcome in the WAL metrics.
+// A busy checkpoint (blocked by readers or writers) is logged as a warning.
+func checkpointWAL(db *sql.DB, name string, m *metrics.Metrics, logger *slog.Logger) {
+	start := time.Now()
+	cp, err := database.CheckpointWAL(context.Background(), db)
+	if err != nil {
+		logger.Error(name+" db wal checkpoint failed", "error", err)
+		return
+	}
+	m.ObserveWALCheckpoint(name, cp.LogFrames, cp.CheckpointedFrames, time.Since(start))
+	if cp.Busy {
+		logger.Warn(name+" db wal checkpoint was blocked", "log_frames", cp.LogFrames, "checkpointed_frames", cp.CheckpointedFrames)
+	}
+}
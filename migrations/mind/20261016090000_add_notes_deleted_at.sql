@@ -0,0 +1,78 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Soft delete: NULL = live note, timestamp = moved to trash
+ALTER TABLE notes ADD COLUMN deleted_at TIMESTAMP NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_notes_deleted_at ON notes (deleted_at);
+-- +goose StatementEnd
+
+-- Keep trashed notes out of full-text search; restoring re-indexes them
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS notes_fts_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_fts_update AFTER UPDATE ON notes
+BEGIN
+INSERT INTO notes_fts (notes_fts, rowid, title, body)
+SELECT 'delete', old.id, old.title, COALESCE (old.body, '')
+WHERE old.deleted_at IS NULL;
+INSERT INTO notes_fts (rowid, title, body)
+SELECT new.id, new.title, COALESCE (new.body, '')
+WHERE new.deleted_at IS NULL;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS notes_fts_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_fts_delete AFTER DELETE ON notes
+WHEN old.deleted_at IS NULL
+BEGIN
+INSERT INTO notes_fts (notes_fts, rowid, title, body)
+VALUES ('delete', old.id, old.title, COALESCE (old.body, ''));
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS notes_fts_delete;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_fts_delete AFTER DELETE ON notes
+BEGIN
+INSERT INTO notes_fts (notes_fts, rowid, title, body)
+VALUES ('delete', old.id, old.title, COALESCE (old.body, ''));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS notes_fts_update;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_fts_update AFTER UPDATE ON notes
+BEGIN
+INSERT INTO notes_fts (notes_fts, rowid, title, body)
+VALUES ('delete', old.id, old.title, COALESCE (old.body, ''));
+INSERT INTO notes_fts (rowid, title, body)
+VALUES (new.id, new.title, COALESCE (new.body, ''));
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE notes DROP COLUMN deleted_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO notes_fts (notes_fts) VALUES ('rebuild');
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- API keys for the Mind HTTP API. Only sha256(key) is stored, never the plaintext.
+CREATE TABLE api_keys (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+actor_id TEXT NOT NULL,             -- Actor the key authenticates as
+key_hash TEXT NOT NULL UNIQUE,      -- Hex-encoded sha256 of the key
+name TEXT NOT NULL,                 -- Human-readable label (e.g., "laptop sync")
+last_used_at TIMESTAMP NULL,
+expires_at TIMESTAMP NULL,          -- NULL = never expires
+revoked_at TIMESTAMP NULL,          -- NULL = active
+created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_api_keys_actor_id ON api_keys (actor_id) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_api_keys_actor_id ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys ;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Pinned notes sort first within a collection, ordered by pin_position (ascending)
+ALTER TABLE notes ADD COLUMN is_pinned BOOLEAN DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE notes ADD COLUMN pin_position INTEGER DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_notes_collection_pinned ON notes (collection_id, is_pinned, pin_position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notes_collection_pinned;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE notes DROP COLUMN pin_position;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE notes DROP COLUMN is_pinned;
+-- +goose StatementEnd
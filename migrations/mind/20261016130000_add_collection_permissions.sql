@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-actor roles on collections. A collection without any rows stays open to every actor.
+CREATE TABLE collection_permissions (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+collection_id INTEGER NOT NULL,
+actor_id TEXT NOT NULL,
+role TEXT NOT NULL CHECK (role IN ('viewer', 'editor', 'owner')),
+created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE,
+UNIQUE (collection_id, actor_id)
+) ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_collection_permissions_actor_id ON collection_permissions (actor_id) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_collection_permissions_actor_id ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS collection_permissions ;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Optimistic locking for collections, mirroring notes.version (ETag source)
+ALTER TABLE collections ADD COLUMN version INTEGER DEFAULT 1 NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collections DROP COLUMN version;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Outgoing webhooks for note lifecycle events. The secret is kept in plaintext
+-- because deliveries are signed with it (HMAC-SHA256).
+CREATE TABLE webhooks (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+url TEXT NOT NULL,
+secret TEXT NOT NULL,
+events TEXT NOT NULL,               -- JSON array of event names (e.g., ["note.created"])
+created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhooks ;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One-time refresh tokens for bearer JWTs. Only sha256(token) is stored, never the plaintext.
+CREATE TABLE refresh_tokens (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+actor_id TEXT NOT NULL,             -- Actor the token issues access tokens for
+token_hash TEXT NOT NULL UNIQUE,    -- Hex-encoded sha256 of the token
+expires_at TIMESTAMP NOT NULL,
+used_at TIMESTAMP NULL,             -- Set when rotated; a used token is never accepted again
+revoked_at TIMESTAMP NULL,          -- Set when reuse of a sibling token is detected
+created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_refresh_tokens_actor_id ON refresh_tokens (actor_id) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_refresh_tokens_actor_id ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refresh_tokens ;
+-- +goose StatementEnd
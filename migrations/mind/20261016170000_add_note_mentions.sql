@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- @handle mentions in note bodies, resolved to known actors (actors with an API key)
+CREATE TABLE note_mentions (
+note_id INTEGER NOT NULL,
+actor_id TEXT NOT NULL,             -- Mentioned actor
+created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+PRIMARY KEY (note_id, actor_id),
+FOREIGN KEY (note_id) REFERENCES notes (id) ON DELETE CASCADE
+) ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_note_mentions_actor_id ON note_mentions (actor_id) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_note_mentions_actor_id ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS note_mentions ;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Last scroll position of each actor in each note, for resuming where they stopped reading
+CREATE TABLE note_read_positions (
+note_id INTEGER NOT NULL,
+actor_id TEXT NOT NULL,
+scroll_position REAL NOT NULL,      -- Fraction of the note scrolled through, 0.0 - 1.0
+read_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+PRIMARY KEY (note_id, actor_id),
+FOREIGN KEY (note_id) REFERENCES notes (id) ON DELETE CASCADE,
+CHECK (scroll_position >= 0.0 AND scroll_position <= 1.0)
+) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS note_read_positions ;
+-- +goose StatementEnd
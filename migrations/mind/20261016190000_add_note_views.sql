@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- When each note was last opened and how often, for "recently viewed" lists
+CREATE TABLE note_views (
+note_id INTEGER PRIMARY KEY,
+last_viewed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+view_count INTEGER NOT NULL DEFAULT 1,
+
+FOREIGN KEY (note_id) REFERENCES notes (id) ON DELETE CASCADE
+) ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_note_views_last_viewed_at ON note_views (last_viewed_at) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_note_views_last_viewed_at ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS note_views ;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notes an actor has favorited, in the actor's own order
+CREATE TABLE note_favorites (
+actor_id TEXT NOT NULL,
+note_id INTEGER NOT NULL,
+position INTEGER NOT NULL DEFAULT 0, -- Lower positions first
+created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+PRIMARY KEY (actor_id, note_id),
+FOREIGN KEY (note_id) REFERENCES notes (id) ON DELETE CASCADE
+) ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_note_favorites_note_id ON note_favorites (note_id) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_note_favorites_note_id ;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS note_favorites ;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Frontmatter a note type requires, checked when notes of that type are created
+CREATE TABLE note_type_schema (
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+note_type_id INTEGER NOT NULL UNIQUE,
+required_keys TEXT NOT NULL DEFAULT '[]', -- JSON array of frontmatter keys that must be present
+schema_json TEXT, -- JSON object of frontmatter key -> expected type ('string', 'number', 'boolean', 'list')
+created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+FOREIGN KEY (note_type_id) REFERENCES note_types (id) ON DELETE CASCADE
+) ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS note_type_schema ;
+-- +goose StatementEnd
@@ -2,17 +2,24 @@
 package mind
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"log/slog"
 
-	"github.com/nkapatos/mindweaver/shared/migrator"
+	"github.com/nkapatos/mindweaver/shared/sqlcext"
 )
 
 //go:embed *.sql
 var migrations embed.FS
 
-// RunMigrations runs all up migrations for the mind service.
+// NewMigrator returns a migrator for the mind service migrations.
+func NewMigrator(db *sql.DB, logger *slog.Logger) *sqlcext.Migrator {
+	return sqlcext.NewMigrator(db, migrations, logger)
+}
+
+// RunMigrations applies all pending mind migrations.
+// Shorthand for NewMigrator(db, logger).Up with a background context (e.g., testdb.SetupTestDB).
 func RunMigrations(db *sql.DB, logger *slog.Logger) error {
-	return migrator.RunMigrations(db, migrations, logger)
+	return NewMigrator(db, logger).Up(context.Background())
 }
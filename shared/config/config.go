@@ -16,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -32,30 +33,51 @@ const (
 
 // Config holds all service configurations
 type Config struct {
-	Mode     DeploymentMode
-	DataDir  string // Root directory for all data (databases, config)
-	Mind     MindConfig
-	Brain    BrainConfig
-	Logging  LoggingConfig
-	Security SecurityConfig
+	Mode            DeploymentMode
+	DataDir         string        // Root directory for all data (databases, config)
+	ShutdownTimeout time.Duration // Max time to drain in-flight requests on SIGTERM/SIGINT
+	Mind            MindConfig
+	Brain           BrainConfig
+	Logging         LoggingConfig
+	Security        SecurityConfig
 }
 
 // MindConfig configures the Mind service (PKM/Notes)
 type MindConfig struct {
-	Host   string // Host to bind to (localhost or 0.0.0.0)
-	Port   int
-	DBPath string
+	Host               string // Host to bind to (localhost or 0.0.0.0)
+	Port               int
+	DBPath             string
+	TrashRetentionDays int // Days before trashed notes are permanently deleted (0 = keep forever)
+
+	EnableFrontmatterSync bool // Rewrite frontmatter title/updated keys when a note is saved
+	MaxBodySizeBytes      int  // Largest accepted note body (0 = unlimited)
+	Archiver              ArchiverConfig
+	Resolver              ResolverConfig
+}
+
+// ArchiverConfig configures the daily job moving inactive notes to archive/YYYY collections
+type ArchiverConfig struct {
+	Enabled     bool
+	ArchiveDays int // Days without updates before a note is archived
+}
+
+// ResolverConfig configures the periodic job re-resolving pending wiki-links
+type ResolverConfig struct {
+	Enabled          bool
+	Interval         time.Duration // How often the job runs
+	RetryWindowHours int           // Hours a pending link is retried before being marked broken
 }
 
 // BrainConfig configures the Brain service (AI Assistant)
 type BrainConfig struct {
-	Port           int
-	DBPath         string
-	BadgerDBPath   string // Path for TitleIndex BadgerDB (future use)
-	MindServiceURL string // URL to Mind service (standalone mode only)
-	LLMEndpoint    string
-	SmallModel     string // Fast model for routing/classification
-	BigModel       string // Powerful model for complex reasoning
+	Port            int
+	DBPath          string
+	BadgerDBPath    string // Path for TitleIndex BadgerDB (future use)
+	MindServiceURL  string // URL to Mind service (standalone mode only)
+	UpstreamMindURL string // Mind instance that unknown /api/mind/* requests are proxied to (standalone mode only, empty = disabled)
+	LLMEndpoint     string
+	SmallModel      string // Fast model for routing/classification
+	BigModel        string // Powerful model for complex reasoning
 }
 
 // LoggingConfig configures structured logging
@@ -66,7 +88,9 @@ type LoggingConfig struct {
 
 // SecurityConfig configures security settings
 type SecurityConfig struct {
-	ETagSalt string // Salt for ETag hashing (set for production to persist across restarts)
+	ETagSalt       string // Salt for ETag hashing (set for production to persist across restarts)
+	AdminJWTSecret string // HS256 secret for admin tokens on /admin/* (empty = admin API disabled)
+	JWTSecret      string // HS256 secret for bearer tokens on Mind RPCs (empty = no token required)
 }
 
 // setDefaults configures all default values in Viper.
@@ -75,16 +99,28 @@ func setDefaults(v *viper.Viper) {
 	// Data directory - root for all persistent data
 	v.SetDefault("data_dir", "./data")
 
+	// Graceful shutdown: in-flight requests get this long to finish
+	v.SetDefault("shutdown_timeout", "30s")
+
 	// Mind service defaults
 	v.SetDefault("mind.host", "0.0.0.0") // Bind to all interfaces (Docker-friendly)
 	v.SetDefault("mind.port", 9421)
 	v.SetDefault("mind.db_path", "") // Derived from data_dir if empty
+	v.SetDefault("mind.trash_retention_days", 30)
+	v.SetDefault("mind.enable_frontmatter_sync", false)
+	v.SetDefault("mind.max_body_size_bytes", 1048576) // 1MB
+	v.SetDefault("mind.archiver.enabled", false)
+	v.SetDefault("mind.archiver.archive_days", 365)
+	v.SetDefault("mind.resolver.enabled", true)
+	v.SetDefault("mind.resolver.interval", "1h")
+	v.SetDefault("mind.resolver.retry_window_hours", 24)
 
 	// Brain service defaults
 	v.SetDefault("brain.port", 9422)
 	v.SetDefault("brain.db_path", "")        // Derived from data_dir if empty
 	v.SetDefault("brain.badger_db_path", "") // Derived from data_dir if empty
 	v.SetDefault("brain.mind_service_url", "http://localhost:9421")
+	v.SetDefault("brain.upstream_mind_url", "") // Empty disables the Mind API proxy
 	v.SetDefault("brain.llm_endpoint", "http://localhost:11434")
 	v.SetDefault("brain.small_model", "phi3-mini")
 	v.SetDefault("brain.big_model", "phi4")
@@ -95,6 +131,8 @@ func setDefaults(v *viper.Viper) {
 
 	// Security defaults - empty means generate random salt
 	v.SetDefault("security.etag_salt", "")
+	v.SetDefault("security.admin_jwt_secret", "") // Empty disables the admin API
+	v.SetDefault("security.jwt_secret", "")       // Empty disables bearer token auth on Mind RPCs
 }
 
 // configureEnvVars sets up environment variable binding with MW_ prefix.
@@ -179,28 +217,44 @@ func buildConfig(v *viper.Viper, mode DeploymentMode) (*Config, error) {
 	}
 
 	cfg := &Config{
-		Mode:    mode,
-		DataDir: dataDir,
+		Mode:            mode,
+		DataDir:         dataDir,
+		ShutdownTimeout: v.GetDuration("shutdown_timeout"),
 		Mind: MindConfig{
-			Host:   v.GetString("mind.host"),
-			Port:   v.GetInt("mind.port"),
-			DBPath: mindDBPath,
+			Host:                  v.GetString("mind.host"),
+			Port:                  v.GetInt("mind.port"),
+			DBPath:                mindDBPath,
+			TrashRetentionDays:    v.GetInt("mind.trash_retention_days"),
+			EnableFrontmatterSync: v.GetBool("mind.enable_frontmatter_sync"),
+			MaxBodySizeBytes:      v.GetInt("mind.max_body_size_bytes"),
+			Archiver: ArchiverConfig{
+				Enabled:     v.GetBool("mind.archiver.enabled"),
+				ArchiveDays: v.GetInt("mind.archiver.archive_days"),
+			},
+			Resolver: ResolverConfig{
+				Enabled:          v.GetBool("mind.resolver.enabled"),
+				Interval:         v.GetDuration("mind.resolver.interval"),
+				RetryWindowHours: v.GetInt("mind.resolver.retry_window_hours"),
+			},
 		},
 		Brain: BrainConfig{
-			Port:           v.GetInt("brain.port"),
-			DBPath:         brainDBPath,
-			BadgerDBPath:   badgerDBPath,
-			MindServiceURL: v.GetString("brain.mind_service_url"),
-			LLMEndpoint:    v.GetString("brain.llm_endpoint"),
-			SmallModel:     v.GetString("brain.small_model"),
-			BigModel:       v.GetString("brain.big_model"),
+			Port:            v.GetInt("brain.port"),
+			DBPath:          brainDBPath,
+			BadgerDBPath:    badgerDBPath,
+			MindServiceURL:  v.GetString("brain.mind_service_url"),
+			UpstreamMindURL: v.GetString("brain.upstream_mind_url"),
+			LLMEndpoint:     v.GetString("brain.llm_endpoint"),
+			SmallModel:      v.GetString("brain.small_model"),
+			BigModel:        v.GetString("brain.big_model"),
 		},
 		Logging: LoggingConfig{
 			Level:  v.GetString("log.level"),
 			Format: v.GetString("log.format"),
 		},
 		Security: SecurityConfig{
-			ETagSalt: etagSalt,
+			ETagSalt:       etagSalt,
+			AdminJWTSecret: v.GetString("security.admin_jwt_secret"),
+			JWTSecret:      v.GetString("security.jwt_secret"),
 		},
 	}
 
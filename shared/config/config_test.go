@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestStandaloneMode verifies configuration in standalone mode
@@ -30,6 +31,9 @@ func TestStandaloneMode(t *testing.T) {
 	if cfg.Mind.DBPath != expectedMindDB {
 		t.Errorf("Expected mind DB path %s, got %s", expectedMindDB, cfg.Mind.DBPath)
 	}
+	if cfg.Mind.TrashRetentionDays != 30 {
+		t.Errorf("Expected trash retention 30 days, got %d", cfg.Mind.TrashRetentionDays)
+	}
 
 	// Verify brain config
 	if cfg.Brain.Port != 9422 {
@@ -55,6 +59,10 @@ func TestStandaloneMode(t *testing.T) {
 	if cfg.DataDir != "./data" {
 		t.Errorf("Expected data dir ./data, got %s", cfg.DataDir)
 	}
+
+	if cfg.ShutdownTimeout != 30*time.Second {
+		t.Errorf("Expected shutdown timeout 30s, got %s", cfg.ShutdownTimeout)
+	}
 }
 
 // TestCombinedMode verifies configuration in combined mode
@@ -87,6 +95,7 @@ func TestEnvironmentVariables(t *testing.T) {
 	os.Setenv("MW_MIND_PORT", "9000")
 	os.Setenv("MW_BRAIN_PORT", "9001")
 	os.Setenv("MW_MIND_DB_PATH", "/custom/mind.db")
+	os.Setenv("MW_MIND_TRASH_RETENTION_DAYS", "7")
 	os.Setenv("MW_BRAIN_DB_PATH", "/custom/brain.db")
 	os.Setenv("MW_BRAIN_MIND_SERVICE_URL", "http://custom:9000")
 	os.Setenv("MW_LOG_LEVEL", "DEBUG")
@@ -108,6 +117,9 @@ func TestEnvironmentVariables(t *testing.T) {
 	if cfg.Mind.DBPath != "/custom/mind.db" {
 		t.Errorf("Expected mind DB path /custom/mind.db, got %s", cfg.Mind.DBPath)
 	}
+	if cfg.Mind.TrashRetentionDays != 7 {
+		t.Errorf("Expected trash retention 7 days, got %d", cfg.Mind.TrashRetentionDays)
+	}
 	if cfg.Brain.DBPath != "/custom/brain.db" {
 		t.Errorf("Expected brain DB path /custom/brain.db, got %s", cfg.Brain.DBPath)
 	}
@@ -254,9 +266,11 @@ func clearEnv() {
 	envVars := []string{
 		// New MW_ prefix vars
 		"MW_DATA_DIR",
+		"MW_SHUTDOWN_TIMEOUT",
 		"MW_MIND_PORT",
 		"MW_BRAIN_PORT",
 		"MW_MIND_DB_PATH",
+		"MW_MIND_TRASH_RETENTION_DAYS",
 		"MW_BRAIN_DB_PATH",
 		"MW_BRAIN_BADGER_DB_PATH",
 		"MW_BRAIN_MIND_SERVICE_URL",
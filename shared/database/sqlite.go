@@ -0,0 +1,68 @@
+// Package database opens SQLite databases with the connection settings Mindweaver relies on.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	_ "modernc.org/sqlite"
+)
+
+// connectionPragmas are applied by the driver to every new connection in the pool.
+// foreign_keys is a per-connection setting in SQLite, so running it once with db.Exec
+// only affects whichever pooled connection happened to execute it.
+var connectionPragmas = []string{
+	"foreign_keys(1)",
+	"journal_mode(WAL)",
+	"synchronous(NORMAL)",
+	"wal_autocheckpoint(100)",
+}
+
+// OpenSQLite opens the SQLite database at path (creating it if needed) with foreign key
+// enforcement and WAL journaling enabled on every connection.
+// The connection is verified with a ping; the caller is responsible for closing the returned DB.
+func OpenSQLite(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", DSN(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to sqlite database: %w", err)
+	}
+
+	return db, nil
+}
+
+// DSN builds the modernc.org/sqlite data source name for path, including the
+// per-connection pragmas.
+func DSN(path string) string {
+	params := url.Values{}
+	params.Set("cache", "shared")
+	params.Set("mode", "rwc")
+	for _, pragma := range connectionPragmas {
+		params.Add("_pragma", pragma)
+	}
+	return fmt.Sprintf("file:%s?%s", path, params.Encode())
+}
+
+// WALCheckpoint is the result of a WAL checkpoint, as returned by PRAGMA wal_checkpoint.
+type WALCheckpoint struct {
+	Busy               bool // The checkpoint could not complete because of readers or writers
+	LogFrames          int  // Frames in the WAL file
+	CheckpointedFrames int  // Frames written back into the database
+}
+
+// CheckpointWAL runs a FULL WAL checkpoint on db and returns its outcome.
+func CheckpointWAL(ctx context.Context, db *sql.DB) (WALCheckpoint, error) {
+	var busy int
+	var cp WALCheckpoint
+	if err := db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(FULL);").Scan(&busy, &cp.LogFrames, &cp.CheckpointedFrames); err != nil {
+		return WALCheckpoint{}, fmt.Errorf("wal checkpoint failed: %w", err)
+	}
+	cp.Busy = busy != 0
+	return cp, nil
+}
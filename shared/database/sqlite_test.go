@@ -0,0 +1,71 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	sharederrors "github.com/nkapatos/mindweaver/shared/errors"
+)
+
+func TestOpenSQLiteEnforcesForeignKeys(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "fk.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLite failed: %v", err)
+	}
+	defer db.Close()
+
+	// Force more than one pooled connection so the pragma must hold on each of them
+	db.SetMaxIdleConns(4)
+
+	schema := `
+CREATE TABLE collections (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL);
+CREATE TABLE notes (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	collection_id INTEGER NOT NULL,
+	FOREIGN KEY (collection_id) REFERENCES collections (id)
+);`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		conn, err := db.Conn(t.Context())
+		if err != nil {
+			t.Fatalf("failed to get connection: %v", err)
+		}
+		defer conn.Close()
+
+		var enabled int
+		if err := conn.QueryRowContext(t.Context(), "PRAGMA foreign_keys").Scan(&enabled); err != nil {
+			t.Fatalf("failed to read foreign_keys pragma: %v", err)
+		}
+		if enabled != 1 {
+			t.Fatalf("expected foreign_keys=1 on connection %d, got %d", i, enabled)
+		}
+	}
+
+	_, err = db.Exec("INSERT INTO notes (title, collection_id) VALUES ('orphan', 999)")
+	if err == nil {
+		t.Fatal("expected foreign key violation for non-existent collection_id")
+	}
+	if !sharederrors.IsForeignKeyConstraintError(err) {
+		t.Fatalf("expected foreign key constraint error, got %v", err)
+	}
+}
+
+func TestOpenSQLiteUsesWAL(t *testing.T) {
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "wal.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLite failed: %v", err)
+	}
+	defer db.Close()
+
+	var mode string
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("failed to read journal_mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Fatalf("expected journal_mode=wal, got %q", mode)
+	}
+}
@@ -13,21 +13,14 @@ import (
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 )
 
-// Domain constants for ErrorInfo per Google AIP-193 standards.
-// These identify which service domain the error originates from.
-const (
-	MindDomain  = "mind.mindweaver.com"
-	BrainDomain = "brain.mindweaver.com"
-)
-
-// NewNotFoundError creates a NOT_FOUND error with ErrorInfo details.
+// NotFound creates a NOT_FOUND error with ErrorInfo details.
 //
 // Usage in handlers:
 //
 //	if errors.Is(err, ErrNoteNotFound) {
-//	    return nil, errors.NewNotFoundError(errors.MindDomain, "note", noteID)
+//	    return nil, errors.Mind.NotFound("note", noteID)
 //	}
-func NewNotFoundError(domain, resource, resourceID string) error {
+func (d ErrorDomain) NotFound(resource, resourceID string) error {
 	err := connect.NewError(
 		connect.CodeNotFound,
 		fmt.Errorf("%s not found: %s", resource, resourceID),
@@ -36,7 +29,7 @@ func NewNotFoundError(domain, resource, resourceID string) error {
 	// Add ErrorInfo details per AIP-193
 	detail, _ := connect.NewErrorDetail(&errdetails.ErrorInfo{
 		Reason: "RESOURCE_NOT_FOUND",
-		Domain: domain,
+		Domain: string(d),
 		Metadata: map[string]string{
 			"resource": resource,
 			"id":       resourceID,
@@ -47,14 +40,14 @@ func NewNotFoundError(domain, resource, resourceID string) error {
 	return err
 }
 
-// NewAlreadyExistsError creates an ALREADY_EXISTS error with ErrorInfo details.
+// AlreadyExists creates an ALREADY_EXISTS error with ErrorInfo details.
 //
 // Usage in handlers:
 //
 //	if errors.Is(err, ErrNoteAlreadyExists) {
-//	    return nil, errors.NewAlreadyExistsError(errors.MindDomain, "note", "title", noteTitle)
+//	    return nil, errors.Mind.AlreadyExists("note", "title", noteTitle)
 //	}
-func NewAlreadyExistsError(domain, resource, field, value string) error {
+func (d ErrorDomain) AlreadyExists(resource, field, value string) error {
 	err := connect.NewError(
 		connect.CodeAlreadyExists,
 		fmt.Errorf("%s already exists: %s=%s", resource, field, value),
@@ -63,7 +56,7 @@ func NewAlreadyExistsError(domain, resource, field, value string) error {
 	// Add ErrorInfo details per AIP-193
 	detail, _ := connect.NewErrorDetail(&errdetails.ErrorInfo{
 		Reason: "RESOURCE_ALREADY_EXISTS",
-		Domain: domain,
+		Domain: string(d),
 		Metadata: map[string]string{
 			"resource": resource,
 			"field":    field,
@@ -75,15 +68,15 @@ func NewAlreadyExistsError(domain, resource, field, value string) error {
 	return err
 }
 
-// NewInternalError creates an INTERNAL error with ErrorInfo details.
+// Internal creates an INTERNAL error with ErrorInfo details.
 //
 // Usage in handlers:
 //
 //	result, err := h.service.CreateNote(ctx, params)
 //	if err != nil {
-//	    return nil, errors.NewInternalError(errors.MindDomain, "failed to create note", err)
+//	    return nil, errors.Mind.Internal("failed to create note", err)
 //	}
-func NewInternalError(domain, operation string, cause error) error {
+func (d ErrorDomain) Internal(operation string, cause error) error {
 	err := connect.NewError(
 		connect.CodeInternal,
 		fmt.Errorf("%s: %w", operation, cause),
@@ -92,7 +85,7 @@ func NewInternalError(domain, operation string, cause error) error {
 	// Add ErrorInfo details per AIP-193
 	detail, _ := connect.NewErrorDetail(&errdetails.ErrorInfo{
 		Reason: "INTERNAL_ERROR",
-		Domain: domain,
+		Domain: string(d),
 		Metadata: map[string]string{
 			"operation": operation,
 			"cause":     cause.Error(),
@@ -103,14 +96,14 @@ func NewInternalError(domain, operation string, cause error) error {
 	return err
 }
 
-// NewPermissionDeniedError creates a PERMISSION_DENIED error with ErrorInfo details.
+// PermissionDenied creates a PERMISSION_DENIED error with ErrorInfo details.
 //
 // Usage in handlers:
 //
 //	if err == ErrCollectionIsSystem {
-//	    return nil, errors.NewPermissionDeniedError(errors.MindDomain, "cannot delete system collection")
+//	    return nil, errors.Mind.PermissionDenied("cannot delete system collection")
 //	}
-func NewPermissionDeniedError(domain, reason string) error {
+func (d ErrorDomain) PermissionDenied(reason string) error {
 	err := connect.NewError(
 		connect.CodePermissionDenied,
 		fmt.Errorf("permission denied: %s", reason),
@@ -119,7 +112,33 @@ func NewPermissionDeniedError(domain, reason string) error {
 	// Add ErrorInfo details per AIP-193
 	detail, _ := connect.NewErrorDetail(&errdetails.ErrorInfo{
 		Reason: "PERMISSION_DENIED",
-		Domain: domain,
+		Domain: string(d),
+		Metadata: map[string]string{
+			"reason": reason,
+		},
+	})
+	err.AddDetail(detail)
+
+	return err
+}
+
+// Unauthenticated creates an UNAUTHENTICATED error with ErrorInfo details.
+//
+// Usage in interceptors:
+//
+//	if token == "" {
+//	    return nil, errors.Mind.Unauthenticated("missing bearer token")
+//	}
+func (d ErrorDomain) Unauthenticated(reason string) error {
+	err := connect.NewError(
+		connect.CodeUnauthenticated,
+		fmt.Errorf("unauthenticated: %s", reason),
+	)
+
+	// Add ErrorInfo details per AIP-193
+	detail, _ := connect.NewErrorDetail(&errdetails.ErrorInfo{
+		Reason: "UNAUTHENTICATED",
+		Domain: string(d),
 		Metadata: map[string]string{
 			"reason": reason,
 		},
@@ -156,7 +175,7 @@ func NewInvalidArgumentError(field, description string) error {
 	return err
 }
 
-// NewFailedPreconditionError creates a FAILED_PRECONDITION error with ErrorInfo details.
+// FailedPrecondition creates a FAILED_PRECONDITION error with ErrorInfo details.
 // This is used for optimistic locking failures (ETag mismatches) and other precondition failures.
 //
 // Usage in handlers:
@@ -165,8 +184,8 @@ func NewInvalidArgumentError(field, description string) error {
 //	    "provided_etag": ifMatchHeader,
 //	    "current_etag":  currentETag,
 //	}
-//	return nil, errors.NewFailedPreconditionError(errors.MindDomain, "ETAG_MISMATCH", metadata)
-func NewFailedPreconditionError(domain, reason string, metadata map[string]string) error {
+//	return nil, errors.Mind.FailedPrecondition("ETAG_MISMATCH", metadata)
+func (d ErrorDomain) FailedPrecondition(reason string, metadata map[string]string) error {
 	err := connect.NewError(
 		connect.CodeFailedPrecondition,
 		fmt.Errorf("precondition failed: %s", reason),
@@ -175,7 +194,7 @@ func NewFailedPreconditionError(domain, reason string, metadata map[string]strin
 	// Add ErrorInfo details per AIP-193
 	detail, _ := connect.NewErrorDetail(&errdetails.ErrorInfo{
 		Reason:   reason,
-		Domain:   domain,
+		Domain:   string(d),
 		Metadata: metadata,
 	})
 	err.AddDetail(detail)
@@ -10,6 +10,8 @@ package errors
 
 import (
 	"errors"
+	"regexp"
+	"strings"
 
 	"modernc.org/sqlite"
 	sqlitelib "modernc.org/sqlite/lib"
@@ -59,3 +61,106 @@ func IsForeignKeyConstraintError(err error) bool {
 
 	return false
 }
+
+// IsNotNullConstraintError checks if an error is a SQLite NOT NULL constraint violation.
+func IsNotNullConstraintError(err error) bool {
+	return hasSQLiteCode(err, sqlitelib.SQLITE_CONSTRAINT_NOTNULL)
+}
+
+// IsCheckConstraintError checks if an error is a SQLite CHECK constraint violation.
+func IsCheckConstraintError(err error) bool {
+	return hasSQLiteCode(err, sqlitelib.SQLITE_CONSTRAINT_CHECK)
+}
+
+// IsRowLockedError checks if an error means the database or table was locked by another
+// connection (SQLITE_BUSY or SQLITE_LOCKED, including their extended codes).
+// Such errors are transient and the operation can be retried.
+func IsRowLockedError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	primary := sqliteErr.Code() & 0xff
+	return primary == sqlitelib.SQLITE_BUSY || primary == sqlitelib.SQLITE_LOCKED
+}
+
+// hasSQLiteCode reports whether err wraps a SQLite error with the given extended code.
+func hasSQLiteCode(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+// ConstraintKind identifies the kind of constraint a ConstraintViolation broke.
+type ConstraintKind string
+
+const (
+	ConstraintUnique     ConstraintKind = "unique"
+	ConstraintForeignKey ConstraintKind = "foreign_key"
+	ConstraintNotNull    ConstraintKind = "not_null"
+	ConstraintCheck      ConstraintKind = "check"
+)
+
+// ConstraintViolation is a constraint error with the table and column SQLite reported.
+// Table and Column are empty when SQLite doesn't name them (FOREIGN KEY and CHECK failures).
+// For multi-column UNIQUE constraints Column lists the columns separated by ", ".
+type ConstraintViolation interface {
+	error
+	Kind() ConstraintKind
+	Table() string
+	Column() string
+}
+
+// constraintFailedPattern matches the column list of UNIQUE and NOT NULL failures,
+// e.g. "UNIQUE constraint failed: notes.collection_id, notes.title".
+var constraintFailedPattern = regexp.MustCompile(`(?:UNIQUE|NOT NULL) constraint failed: (\w+\.\w+(?:, \w+\.\w+)*)`)
+
+// constraintError implements ConstraintViolation and wraps the driver error,
+// so the Is*ConstraintError helpers keep working on it.
+type constraintError struct {
+	kind   ConstraintKind
+	table  string
+	column string
+	err    error
+}
+
+func (e *constraintError) Error() string        { return e.err.Error() }
+func (e *constraintError) Unwrap() error        { return e.err }
+func (e *constraintError) Kind() ConstraintKind { return e.kind }
+func (e *constraintError) Table() string        { return e.table }
+func (e *constraintError) Column() string       { return e.column }
+
+// WrapConstraintError returns err as a ConstraintViolation if it is a UNIQUE, FOREIGN KEY,
+// NOT NULL or CHECK constraint error, and err unchanged otherwise (including nil).
+//
+// Example usage in service layer:
+//
+//	if violation, ok := errors.WrapConstraintError(err).(errors.ConstraintViolation); ok {
+//		logger.Warn("constraint failed", "table", violation.Table(), "column", violation.Column())
+//	}
+func WrapConstraintError(err error) error {
+	var kind ConstraintKind
+	switch {
+	case IsUniqueConstraintError(err):
+		kind = ConstraintUnique
+	case IsForeignKeyConstraintError(err):
+		kind = ConstraintForeignKey
+	case IsNotNullConstraintError(err):
+		kind = ConstraintNotNull
+	case IsCheckConstraintError(err):
+		kind = ConstraintCheck
+	default:
+		return err
+	}
+
+	violation := &constraintError{kind: kind, err: err}
+	if m := constraintFailedPattern.FindStringSubmatch(err.Error()); m != nil {
+		var columns []string
+		for _, qualified := range strings.Split(m[1], ", ") {
+			table, column, _ := strings.Cut(qualified, ".")
+			violation.table = table
+			columns = append(columns, column)
+		}
+		violation.column = strings.Join(columns, ", ")
+	}
+	return violation
+}
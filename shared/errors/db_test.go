@@ -0,0 +1,214 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// setupConstraintDB opens an in-memory database with one table per constraint kind.
+func setupConstraintDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	db.SetMaxOpenConns(1) // One connection so the foreign_keys pragma sticks
+	t.Cleanup(func() { db.Close() })
+
+	for _, stmt := range []string{
+		`PRAGMA foreign_keys = ON`,
+		`CREATE TABLE parents (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE items (
+			id INTEGER PRIMARY KEY,
+			parent_id INTEGER REFERENCES parents (id),
+			owner TEXT NOT NULL,
+			name TEXT NOT NULL,
+			quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+			UNIQUE (owner, name)
+		)`,
+		`CREATE TABLE slugs (slug TEXT NOT NULL UNIQUE)`,
+		`INSERT INTO parents (id) VALUES (1)`,
+		`INSERT INTO items (parent_id, owner, name) VALUES (1, 'alice', 'lamp')`,
+		`INSERT INTO slugs (slug) VALUES ('taken')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to set up test database (%s): %v", stmt, err)
+		}
+	}
+	return db
+}
+
+func TestConstraintErrors(t *testing.T) {
+	db := setupConstraintDB(t)
+
+	tests := []struct {
+		name   string
+		stmt   string
+		is     func(error) bool
+		kind   ConstraintKind
+		table  string
+		column string
+	}{
+		{
+			name:   "unique multi-column",
+			stmt:   `INSERT INTO items (parent_id, owner, name) VALUES (1, 'alice', 'lamp')`,
+			is:     IsUniqueConstraintError,
+			kind:   ConstraintUnique,
+			table:  "items",
+			column: "owner, name",
+		},
+		{
+			name:   "unique single column",
+			stmt:   `INSERT INTO slugs (slug) VALUES ('taken')`,
+			is:     IsUniqueConstraintError,
+			kind:   ConstraintUnique,
+			table:  "slugs",
+			column: "slug",
+		},
+		{
+			name: "foreign key",
+			stmt: `INSERT INTO items (parent_id, owner, name) VALUES (99, 'bob', 'desk')`,
+			is:   IsForeignKeyConstraintError,
+			kind: ConstraintForeignKey,
+		},
+		{
+			name:   "not null",
+			stmt:   `INSERT INTO items (parent_id, owner) VALUES (1, 'bob')`,
+			is:     IsNotNullConstraintError,
+			kind:   ConstraintNotNull,
+			table:  "items",
+			column: "name",
+		},
+		{
+			name: "check",
+			stmt: `INSERT INTO items (parent_id, owner, name, quantity) VALUES (1, 'bob', 'desk', -1)`,
+			is:   IsCheckConstraintError,
+			kind: ConstraintCheck,
+		},
+	}
+
+	checks := map[string]func(error) bool{
+		"unique":      IsUniqueConstraintError,
+		"foreign key": IsForeignKeyConstraintError,
+		"not null":    IsNotNullConstraintError,
+		"check":       IsCheckConstraintError,
+		"row locked":  IsRowLockedError,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Exec(tt.stmt)
+			if err == nil {
+				t.Fatal("expected a constraint error")
+			}
+
+			// Exactly one detector matches, also through wrapping
+			wrapped := fmt.Errorf("create item: %w", err)
+			if !tt.is(wrapped) {
+				t.Errorf("detector did not match %v", err)
+			}
+			matches := 0
+			for _, check := range checks {
+				if check(wrapped) {
+					matches++
+				}
+			}
+			if matches != 1 {
+				t.Errorf("%d detectors matched %v, want 1", matches, err)
+			}
+
+			var violation ConstraintViolation
+			if !stderrors.As(WrapConstraintError(wrapped), &violation) {
+				t.Fatalf("WrapConstraintError(%v) is not a ConstraintViolation", err)
+			}
+			if violation.Kind() != tt.kind {
+				t.Errorf("Kind() = %q, want %q", violation.Kind(), tt.kind)
+			}
+			if violation.Table() != tt.table {
+				t.Errorf("Table() = %q, want %q", violation.Table(), tt.table)
+			}
+			if violation.Column() != tt.column {
+				t.Errorf("Column() = %q, want %q", violation.Column(), tt.column)
+			}
+			if !tt.is(violation) {
+				t.Error("detector does not match the wrapped violation")
+			}
+			if violation.Error() != wrapped.Error() {
+				t.Errorf("Error() = %q, want %q", violation.Error(), wrapped.Error())
+			}
+		})
+	}
+}
+
+func TestWrapConstraintError_PassesOtherErrorsThrough(t *testing.T) {
+	if err := WrapConstraintError(nil); err != nil {
+		t.Errorf("WrapConstraintError(nil) = %v, want nil", err)
+	}
+
+	plain := stderrors.New("boom")
+	if err := WrapConstraintError(plain); err != plain {
+		t.Errorf("WrapConstraintError(plain) = %v, want the same error", err)
+	}
+
+	db := setupConstraintDB(t)
+	_, err := db.Exec(`INSERT INTO missing_table (id) VALUES (1)`)
+	if err == nil {
+		t.Fatal("expected an error for a missing table")
+	}
+	if _, ok := WrapConstraintError(err).(ConstraintViolation); ok {
+		t.Errorf("WrapConstraintError(%v) returned a ConstraintViolation", err)
+	}
+}
+
+func TestIsRowLockedError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "locked.db")
+	ctx := context.Background()
+
+	holder, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer holder.Close()
+	if _, err := holder.Exec(`CREATE TABLE counters (n INTEGER)`); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+
+	// Hold the write lock on a dedicated connection
+	conn, err := holder.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, `BEGIN IMMEDIATE`); err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer conn.ExecContext(ctx, `ROLLBACK`)
+
+	// busy_timeout 0 fails immediately instead of waiting for the lock
+	other, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(0)")
+	if err != nil {
+		t.Fatalf("failed to open second connection: %v", err)
+	}
+	defer other.Close()
+
+	_, err = other.Exec(`INSERT INTO counters (n) VALUES (1)`)
+	if err == nil {
+		t.Fatal("expected the write to fail while the database is locked")
+	}
+	if !IsRowLockedError(fmt.Errorf("increment: %w", err)) {
+		t.Errorf("IsRowLockedError(%v) = false, want true", err)
+	}
+	if IsUniqueConstraintError(err) {
+		t.Errorf("IsUniqueConstraintError(%v) = true for a lock error", err)
+	}
+	if IsRowLockedError(stderrors.New("database is locked")) {
+		t.Error("IsRowLockedError matched a non-SQLite error")
+	}
+}
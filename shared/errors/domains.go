@@ -0,0 +1,29 @@
+package errors
+
+// ErrorDomain identifies the service domain an error originates from, reported as
+// ErrorInfo.domain per Google AIP-193. Error builders are methods on it, so a domain
+// must be named once per call site and typos fail to compile:
+//
+//	return nil, apierrors.Mind.NotFound("note", noteID)
+type ErrorDomain string
+
+// Domain constants for ErrorInfo.
+const (
+	MindDomain      ErrorDomain = "mind.mindweaver.com"
+	BrainDomain     ErrorDomain = "brain.mindweaver.com"
+	AuthDomain      ErrorDomain = "auth.mindweaver.com"
+	SchedulerDomain ErrorDomain = "scheduler.mindweaver.com"
+)
+
+// Short aliases for the fluent API (apierrors.Mind.Internal(...)).
+const (
+	Mind      = MindDomain
+	Brain     = BrainDomain
+	Auth      = AuthDomain
+	Scheduler = SchedulerDomain
+)
+
+// String returns the domain as reported in ErrorInfo.
+func (d ErrorDomain) String() string {
+	return string(d)
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// errorInfo extracts the ErrorInfo detail of a Connect error.
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+
+	var connectErr *connect.Error
+	if !stderrors.As(err, &connectErr) {
+		t.Fatalf("expected *connect.Error, got %T", err)
+	}
+	for _, detail := range connectErr.Details() {
+		value, err := detail.Value()
+		if err != nil {
+			t.Fatalf("failed to decode error detail: %v", err)
+		}
+		if info, ok := value.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("error has no ErrorInfo detail: %v", err)
+	return nil
+}
+
+func TestErrorDomain_Strings(t *testing.T) {
+	tests := []struct {
+		domain   ErrorDomain
+		expected string
+	}{
+		{Mind, "mind.mindweaver.com"},
+		{Brain, "brain.mindweaver.com"},
+		{Auth, "auth.mindweaver.com"},
+		{Scheduler, "scheduler.mindweaver.com"},
+	}
+
+	for _, tt := range tests {
+		if tt.domain.String() != tt.expected {
+			t.Errorf("domain = %q, expected %q", tt.domain, tt.expected)
+		}
+	}
+}
+
+func TestErrorDomain_Builders(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   connect.Code
+		domain ErrorDomain
+		reason string
+	}{
+		{"NotFound", Mind.NotFound("note", "42"), connect.CodeNotFound, Mind, "RESOURCE_NOT_FOUND"},
+		{"AlreadyExists", Mind.AlreadyExists("note", "title", "Plan"), connect.CodeAlreadyExists, Mind, "RESOURCE_ALREADY_EXISTS"},
+		{"Internal", Brain.Internal("failed to index", stderrors.New("boom")), connect.CodeInternal, Brain, "INTERNAL_ERROR"},
+		{"PermissionDenied", Mind.PermissionDenied("read only"), connect.CodePermissionDenied, Mind, "PERMISSION_DENIED"},
+		{"Unauthenticated", Auth.Unauthenticated("missing bearer token"), connect.CodeUnauthenticated, Auth, "UNAUTHENTICATED"},
+		{"FailedPrecondition", Scheduler.FailedPrecondition("JOB_RUNNING", nil), connect.CodeFailedPrecondition, Scheduler, "JOB_RUNNING"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := connect.CodeOf(tt.err); code != tt.code {
+				t.Fatalf("code = %v, expected %v", code, tt.code)
+			}
+			info := errorInfo(t, tt.err)
+			if info.Domain != tt.domain.String() {
+				t.Errorf("domain = %q, expected %q", info.Domain, tt.domain)
+			}
+			if info.Reason != tt.reason {
+				t.Errorf("reason = %q, expected %q", info.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestErrorDomain_NotFoundMetadata(t *testing.T) {
+	info := errorInfo(t, Mind.NotFound("note", "42"))
+	if info.Metadata["resource"] != "note" || info.Metadata["id"] != "42" {
+		t.Errorf("unexpected metadata: %v", info.Metadata)
+	}
+}
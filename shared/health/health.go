@@ -0,0 +1,142 @@
+// Package health runs component probes and aggregates them into the /health response.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Status is the overall health of the server.
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"   // Every probe passed
+	StatusDegraded  Status = "degraded"  // Only non-critical probes failed
+	StatusUnhealthy Status = "unhealthy" // A critical probe failed
+)
+
+// Component statuses reported per probe.
+const (
+	ComponentOK    = "ok"
+	ComponentError = "error"
+)
+
+// defaultTimeout bounds each probe when NewChecker is given no timeout.
+const defaultTimeout = 2 * time.Second
+
+// ProbeFunc checks one component and returns an error if it is not working.
+type ProbeFunc func(ctx context.Context) error
+
+// ComponentResult is the outcome of one probe.
+type ComponentResult struct {
+	Status    string `json:"status"` // ComponentOK or ComponentError
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the aggregated result of all probes.
+type Report struct {
+	Status     Status                     `json:"status"`
+	Components map[string]ComponentResult `json:"components"`
+}
+
+// HTTPStatus returns 503 for an unhealthy report and 200 otherwise.
+func (r Report) HTTPStatus() int {
+	if r.Status == StatusUnhealthy {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+type probe struct {
+	name     string
+	critical bool
+	fn       ProbeFunc
+}
+
+// Checker holds the registered probes. Probes may be registered after the health
+// endpoint is mounted, as services come up.
+type Checker struct {
+	mu      sync.RWMutex
+	probes  []probe
+	timeout time.Duration
+}
+
+// NewChecker creates a Checker that gives each probe up to timeout (default 2s).
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Register adds a probe. A failing critical probe makes the server unhealthy;
+// a failing non-critical probe only degrades it.
+func (c *Checker) Register(name string, critical bool, fn ProbeFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probes = append(c.probes, probe{name: name, critical: critical, fn: fn})
+}
+
+// Check runs all probes concurrently and aggregates their results.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	probes := make([]probe, len(c.probes))
+	copy(probes, c.probes)
+	c.mu.RUnlock()
+
+	results := make([]ComponentResult, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, p.fn)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusHealthy, Components: make(map[string]ComponentResult, len(probes))}
+	for i, p := range probes {
+		report.Components[p.name] = results[i]
+		if results[i].Status == ComponentOK {
+			continue
+		}
+		if p.critical {
+			report.Status = StatusUnhealthy
+		} else if report.Status == StatusHealthy {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// run executes one probe with the checker's timeout and measures its latency.
+func (c *Checker) run(ctx context.Context, fn ProbeFunc) ComponentResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := fn(ctx)
+	result := ComponentResult{Status: ComponentOK, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = ComponentError
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// DBProbe returns a probe that runs SELECT 1 against db.
+func DBProbe(db *sql.DB) ProbeFunc {
+	return func(ctx context.Context) error {
+		var one int
+		if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+			return fmt.Errorf("select 1: %w", err)
+		}
+		return nil
+	}
+}
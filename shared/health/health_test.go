@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nkapatos/mindweaver/shared/database"
+)
+
+func openTestDB(t *testing.T) (dbProbe ProbeFunc, closeDB func()) {
+	t.Helper()
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "health.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return DBProbe(db), func() { db.Close() }
+}
+
+func TestCheck_AllHealthy(t *testing.T) {
+	dbProbe, _ := openTestDB(t)
+	checker := NewChecker(0)
+	checker.Register("db", true, dbProbe)
+	checker.Register("scheduler", false, func(context.Context) error { return nil })
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusHealthy {
+		t.Fatalf("status = %q, want healthy: %+v", report.Status, report.Components)
+	}
+	if report.HTTPStatus() != http.StatusOK {
+		t.Errorf("HTTPStatus = %d, want 200", report.HTTPStatus())
+	}
+	for _, name := range []string{"db", "scheduler"} {
+		if got := report.Components[name].Status; got != ComponentOK {
+			t.Errorf("%s status = %q, want ok", name, got)
+		}
+	}
+}
+
+func TestCheck_DBUnavailableIsUnhealthy(t *testing.T) {
+	dbProbe, closeDB := openTestDB(t)
+	closeDB()
+
+	checker := NewChecker(0)
+	checker.Register("db", true, dbProbe)
+	checker.Register("fts", false, func(context.Context) error { return nil })
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusUnhealthy {
+		t.Fatalf("status = %q, want unhealthy", report.Status)
+	}
+	if report.HTTPStatus() != http.StatusServiceUnavailable {
+		t.Errorf("HTTPStatus = %d, want 503", report.HTTPStatus())
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded struct {
+		Status     string `json:"status"`
+		Components map[string]struct {
+			Status    string `json:"status"`
+			LatencyMs *int64 `json:"latency_ms"`
+			Error     string `json:"error"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Status != "unhealthy" {
+		t.Errorf("json status = %q, want unhealthy", decoded.Status)
+	}
+	db := decoded.Components["db"]
+	if db.Status != "error" || db.Error == "" || db.LatencyMs == nil {
+		t.Errorf("db component = %+v, want error with message and latency_ms", db)
+	}
+	if fts := decoded.Components["fts"]; fts.Status != "ok" || fts.Error != "" {
+		t.Errorf("fts component = %+v, want ok", fts)
+	}
+}
+
+func TestCheck_NonCriticalFailureIsDegraded(t *testing.T) {
+	dbProbe, _ := openTestDB(t)
+	checker := NewChecker(0)
+	checker.Register("db", true, dbProbe)
+	checker.Register("scheduler", false, func(context.Context) error { return errors.New("no flush for 15m0s") })
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusDegraded {
+		t.Fatalf("status = %q, want degraded", report.Status)
+	}
+	if report.HTTPStatus() != http.StatusOK {
+		t.Errorf("HTTPStatus = %d, want 200", report.HTTPStatus())
+	}
+	if got := report.Components["scheduler"].Error; got != "no flush for 15m0s" {
+		t.Errorf("scheduler error = %q", got)
+	}
+}
+
+func TestCheck_ProbeTimeout(t *testing.T) {
+	checker := NewChecker(10 * time.Millisecond)
+	checker.Register("db", true, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := checker.Check(context.Background())
+	if report.Status != StatusUnhealthy {
+		t.Fatalf("status = %q, want unhealthy after timeout", report.Status)
+	}
+}
@@ -0,0 +1,56 @@
+package interceptors
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+
+	apierrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// ifMatchHeader carries the ETag a client expects the resource to have (AIP-154).
+const ifMatchHeader = "If-Match"
+
+// VersionedResource looks up the current version of the resource a request targets.
+// The version is hashed with utils.ComputeHashedETag and compared against If-Match.
+type VersionedResource interface {
+	// ResourceVersion returns the version of the resource targeted by req.
+	// found is false when the resource doesn't exist, so the handler can report NotFound.
+	ResourceVersion(ctx context.Context, req connect.AnyRequest) (version int64, found bool, err error)
+}
+
+// NewETagInterceptor creates an interceptor that enforces If-Match on the given procedures
+// (e.g., mindv3connect.NotesServiceReplaceNoteProcedure).
+// Requests without If-Match, with "If-Match: *", or to unlisted procedures pass through;
+// handlers that must not blindly overwrite (e.g., ReplaceNote) reject those themselves.
+// A mismatched ETag returns FailedPrecondition with reason ETAG_MISMATCH.
+func NewETagInterceptor(domain apierrors.ErrorDomain, resources map[string]VersionedResource) connect.UnaryInterceptorFunc {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			ifMatch := req.Header().Get(ifMatchHeader)
+			resource, ok := resources[req.Spec().Procedure]
+			if req.Spec().IsClient || !ok || ifMatch == "" || ifMatch == "*" {
+				return next(ctx, req)
+			}
+
+			version, found, err := resource.ResourceVersion(ctx, req)
+			if err != nil {
+				return nil, domain.Internal("failed to get resource version", err)
+			}
+			if !found {
+				return next(ctx, req)
+			}
+
+			if currentETag := utils.ComputeHashedETag(version); ifMatch != currentETag {
+				return nil, domain.FailedPrecondition("ETAG_MISMATCH", map[string]string{
+					"provided_etag": ifMatch,
+					"current_etag":  currentETag,
+					"header":        ifMatchHeader,
+				})
+			}
+
+			return next(ctx, req)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package interceptors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/nkapatos/mindweaver/shared/utils"
+)
+
+// fakeResource reports a fixed version for every request.
+type fakeResource struct {
+	version int64
+	found   bool
+}
+
+func (r fakeResource) ResourceVersion(context.Context, connect.AnyRequest) (int64, bool, error) {
+	return r.version, r.found, nil
+}
+
+// newETagServer serves procedure behind the ETag interceptor and reports whether the handler ran.
+func newETagServer(t *testing.T, procedure string, resources map[string]VersionedResource) (*connect.Client[emptypb.Empty, emptypb.Empty], *bool) {
+	t.Helper()
+
+	var called bool
+	handler := connect.NewUnaryHandler(procedure,
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			called = true
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(NewETagInterceptor("test.mindweaver.com", resources)),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(procedure, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return connect.NewClient[emptypb.Empty, emptypb.Empty](server.Client(), server.URL+procedure), &called
+}
+
+func TestETagInterceptor(t *testing.T) {
+	procedure := "/mind.v3.NotesService/DeleteNote"
+	current := utils.ComputeHashedETag(3)
+
+	tests := []struct {
+		name         string
+		procedure    string
+		resource     fakeResource
+		ifMatch      string
+		expectedCode connect.Code // 0 = success
+	}{
+		{"missing If-Match passes through", procedure, fakeResource{3, true}, "", 0},
+		{"matching ETag proceeds", procedure, fakeResource{3, true}, current, 0},
+		{"wildcard proceeds", procedure, fakeResource{3, true}, "*", 0},
+		{"stale ETag rejected", procedure, fakeResource{4, true}, current, connect.CodeFailedPrecondition},
+		{"malformed ETag rejected", procedure, fakeResource{3, true}, "abc", connect.CodeFailedPrecondition},
+		{"missing resource left to handler", procedure, fakeResource{0, false}, current, 0},
+		{"unguarded procedure ignored", "/mind.v3.NotesService/GetNote", fakeResource{4, true}, current, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, called := newETagServer(t, tt.procedure, map[string]VersionedResource{procedure: tt.resource})
+
+			req := connect.NewRequest(&emptypb.Empty{})
+			if tt.ifMatch != "" {
+				req.Header().Set("If-Match", tt.ifMatch)
+			}
+			_, err := client.CallUnary(context.Background(), req)
+
+			if tt.expectedCode == 0 {
+				if err != nil {
+					t.Fatalf("expected success, got %v", err)
+				}
+				if !*called {
+					t.Error("expected handler to be called")
+				}
+				return
+			}
+			if connect.CodeOf(err) != tt.expectedCode {
+				t.Fatalf("expected %s, got %v", tt.expectedCode, err)
+			}
+			if *called {
+				t.Error("expected handler not to be called")
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+
+	apierrors "github.com/nkapatos/mindweaver/shared/errors"
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+// NewJWTInterceptor creates an interceptor that authenticates requests with an HS256 JWT
+// from "Authorization: Bearer <token>" and stores its sub claim as the actor ID
+// (middleware.GetActorID).
+//
+// Requests already carrying an actor ID (e.g., authenticated by API key middleware) pass
+// through. Missing, expired, or tampered tokens return Unauthenticated.
+// An empty secret disables the check, leaving requests unauthenticated as before.
+func NewJWTInterceptor(domain apierrors.ErrorDomain, secret string) connect.UnaryInterceptorFunc {
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if req.Spec().IsClient || secret == "" || middleware.GetActorID(ctx) != "" {
+				return next(ctx, req)
+			}
+
+			token, ok := strings.CutPrefix(req.Header().Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				return nil, domain.Unauthenticated("missing bearer token")
+			}
+
+			actorID, err := middleware.VerifyToken(token, secret)
+			if err != nil {
+				return nil, domain.Unauthenticated("invalid bearer token: " + err.Error())
+			}
+
+			return next(middleware.WithActorID(ctx, actorID), req)
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package interceptors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/nkapatos/mindweaver/shared/middleware"
+)
+
+const (
+	testJWTSecret = "test-jwt-secret"
+	testProcedure = "/mind.v3.NotesService/GetNote"
+)
+
+// newJWTServer serves testProcedure behind the JWT interceptor and records the actor ID seen
+// by the handler. preset, if non-empty, is injected as the actor ID before the interceptor runs.
+func newJWTServer(t *testing.T, secret, preset string) (*connect.Client[emptypb.Empty, emptypb.Empty], *string) {
+	t.Helper()
+
+	var actorID string
+	handler := connect.NewUnaryHandler(testProcedure,
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			actorID = middleware.GetActorID(ctx)
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(NewJWTInterceptor("test.mindweaver.com", secret)),
+	)
+
+	var h http.Handler = handler
+	if preset != "" {
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(w, r.WithContext(middleware.WithActorID(r.Context(), preset)))
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(testProcedure, h)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return connect.NewClient[emptypb.Empty, emptypb.Empty](server.Client(), server.URL+testProcedure), &actorID
+}
+
+func mustSign(t *testing.T, secret, subject string, ttl time.Duration) string {
+	t.Helper()
+	token, err := middleware.SignToken(secret, subject, ttl)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func TestJWTInterceptor(t *testing.T) {
+	valid := mustSign(t, testJWTSecret, "42", time.Hour)
+	parts := strings.Split(valid, ".")
+	tampered := parts[0] + "." + mustSignPayload(t, "1") + "." + parts[2]
+
+	tests := []struct {
+		name          string
+		authorization string
+		expectedCode  connect.Code // 0 = success
+		expectedActor string
+	}{
+		{"valid token sets actor", "Bearer " + valid, 0, "42"},
+		{"missing token", "", connect.CodeUnauthenticated, ""},
+		{"non-bearer scheme", "Basic " + valid, connect.CodeUnauthenticated, ""},
+		{"expired token", "Bearer " + mustSign(t, testJWTSecret, "42", -time.Minute), connect.CodeUnauthenticated, ""},
+		{"wrong secret", "Bearer " + mustSign(t, "other-secret", "42", time.Hour), connect.CodeUnauthenticated, ""},
+		{"tampered payload", "Bearer " + tampered, connect.CodeUnauthenticated, ""},
+		{"missing subject", "Bearer " + mustSign(t, testJWTSecret, "", time.Hour), connect.CodeUnauthenticated, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, actorID := newJWTServer(t, testJWTSecret, "")
+
+			req := connect.NewRequest(&emptypb.Empty{})
+			if tt.authorization != "" {
+				req.Header().Set("Authorization", tt.authorization)
+			}
+			_, err := client.CallUnary(context.Background(), req)
+
+			if tt.expectedCode == 0 {
+				if err != nil {
+					t.Fatalf("expected success, got %v", err)
+				}
+				if *actorID != tt.expectedActor {
+					t.Fatalf("expected actor %q, got %q", tt.expectedActor, *actorID)
+				}
+				return
+			}
+			if connect.CodeOf(err) != tt.expectedCode {
+				t.Fatalf("expected %v, got %v", tt.expectedCode, err)
+			}
+		})
+	}
+}
+
+func TestJWTInterceptor_PassThrough(t *testing.T) {
+	t.Run("disabled without secret", func(t *testing.T) {
+		client, actorID := newJWTServer(t, "", "")
+		if _, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if *actorID != "" {
+			t.Fatalf("expected no actor, got %q", *actorID)
+		}
+	})
+
+	t.Run("already authenticated by api key", func(t *testing.T) {
+		client, actorID := newJWTServer(t, testJWTSecret, "key-owner")
+		if _, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{})); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if *actorID != "key-owner" {
+			t.Fatalf("expected actor key-owner, got %q", *actorID)
+		}
+	})
+}
+
+// mustSignPayload returns the payload segment of a token for subject signed with another key,
+// used to splice a different subject into an otherwise valid token.
+func mustSignPayload(t *testing.T, subject string) string {
+	t.Helper()
+	return strings.Split(mustSign(t, "attacker-secret", subject, time.Hour), ".")[1]
+}
@@ -0,0 +1,69 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the instrumentation scope of RPC spans.
+const tracerName = "github.com/nkapatos/mindweaver/shared/interceptors"
+
+// OTelInterceptor is a singleton Connect-RPC interceptor that creates a server span
+// per RPC call, continuing any W3C trace context sent by the caller.
+// It uses the global TracerProvider, so spans are no-ops until one is installed.
+var OTelInterceptor = NewOTelInterceptor(otel.GetTracerProvider())
+
+// NewOTelInterceptor creates a tracing interceptor backed by the given TracerProvider.
+// Spans are named after the procedure (e.g., "mind.v3.NotesService/CreateNote") and
+// carry rpc.service, rpc.method and, on failure, error.type attributes.
+func NewOTelInterceptor(tp trace.TracerProvider) connect.UnaryInterceptorFunc {
+	tracer := tp.Tracer(tracerName)
+	propagator := propagation.TraceContext{}
+
+	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			// Only trace the server side; outgoing client calls are left untouched
+			if req.Spec().IsClient {
+				return next(ctx, req)
+			}
+
+			ctx = propagator.Extract(ctx, propagation.HeaderCarrier(req.Header()))
+
+			spanName, service, method := splitProcedure(req.Spec().Procedure)
+			ctx, span := tracer.Start(ctx, spanName,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("rpc.system", "connect_rpc"),
+					attribute.String("rpc.service", service),
+					attribute.String("rpc.method", method),
+				),
+			)
+			defer span.End()
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				span.SetAttributes(attribute.String("error.type", connect.CodeOf(err).String()))
+			}
+			return resp, err
+		}
+	})
+}
+
+// splitProcedure turns "/mind.v3.NotesService/CreateNote" into the span name
+// "mind.v3.NotesService/CreateNote", service "mind.v3.NotesService" and method "CreateNote".
+func splitProcedure(procedure string) (name, service, method string) {
+	name = strings.TrimPrefix(procedure, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name, name[:i], name[i+1:]
+	}
+	return name, "", name
+}
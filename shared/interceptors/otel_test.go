@@ -0,0 +1,141 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// newTracedServer serves a single unary procedure wrapped with the OTel interceptor,
+// exporting spans synchronously to an in-memory exporter.
+func newTracedServer(t *testing.T, procedure string, handlerErr error) (*tracetest.InMemoryExporter, *connect.Client[emptypb.Empty, emptypb.Empty]) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	handler := connect.NewUnaryHandler(procedure,
+		func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			if handlerErr != nil {
+				return nil, handlerErr
+			}
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		},
+		connect.WithInterceptors(NewOTelInterceptor(tp)),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(procedure, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return exporter, connect.NewClient[emptypb.Empty, emptypb.Empty](server.Client(), server.URL+procedure)
+}
+
+// spanAttrs flattens span attributes for lookups by key.
+func spanAttrs(span tracetest.SpanStub) map[attribute.Key]string {
+	attrs := make(map[attribute.Key]string, len(span.Attributes))
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	return attrs
+}
+
+func TestOTelInterceptor_CreatesSpanFromTraceParent(t *testing.T) {
+	procedure := "/mind.v3.NotesService/CreateNote"
+	exporter, client := newTracedServer(t, procedure, nil)
+
+	req := connect.NewRequest(&emptypb.Empty{})
+	req.Header().Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if _, err := client.CallUnary(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	span := spans[0]
+	attrs := spanAttrs(span)
+
+	if span.Name != "mind.v3.NotesService/CreateNote" {
+		t.Errorf("expected span name mind.v3.NotesService/CreateNote, got %q", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindServer {
+		t.Errorf("expected server span, got %s", span.SpanKind)
+	}
+	if got := span.SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected trace ID from traceparent, got %s", got)
+	}
+	if got := span.Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("expected parent span ID from traceparent, got %s", got)
+	}
+	if !span.Parent.IsRemote() {
+		t.Error("expected remote parent span context")
+	}
+	if attrs["rpc.system"] != "connect_rpc" {
+		t.Errorf("expected rpc.system=connect_rpc, got %q", attrs["rpc.system"])
+	}
+	if attrs["rpc.service"] != "mind.v3.NotesService" {
+		t.Errorf("expected rpc.service=mind.v3.NotesService, got %q", attrs["rpc.service"])
+	}
+	if attrs["rpc.method"] != "CreateNote" {
+		t.Errorf("expected rpc.method=CreateNote, got %q", attrs["rpc.method"])
+	}
+	if _, ok := attrs["error.type"]; ok {
+		t.Error("expected no error.type on successful call")
+	}
+	if span.Status.Code != codes.Unset {
+		t.Errorf("expected unset status on successful call, got %s", span.Status.Code)
+	}
+}
+
+func TestOTelInterceptor_RecordsErrorSpan(t *testing.T) {
+	procedure := "/mind.v3.NotesService/GetNote"
+	exporter, client := newTracedServer(t, procedure, connect.NewError(connect.CodeNotFound, errors.New("note not found")))
+
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("expected not_found error, got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 exported span, got %d", len(spans))
+	}
+	span := spans[0]
+
+	if span.Status.Code != codes.Error {
+		t.Errorf("expected error status, got %s", span.Status.Code)
+	}
+	if span.Status.Description != "not_found: note not found" {
+		t.Errorf("expected status description from the error, got %q", span.Status.Description)
+	}
+	if len(span.Events) != 1 || span.Events[0].Name != "exception" {
+		t.Errorf("expected 1 exception event, got %+v", span.Events)
+	}
+	if got := spanAttrs(span)["error.type"]; got != "not_found" {
+		t.Errorf("expected error.type=not_found, got %q", got)
+	}
+	if span.Parent.IsValid() {
+		t.Error("expected root span when no traceparent is sent")
+	}
+}
+
+func TestSplitProcedure(t *testing.T) {
+	name, service, method := splitProcedure("/mind.v3.TagsService/RenameTag")
+	if name != "mind.v3.TagsService/RenameTag" || service != "mind.v3.TagsService" || method != "RenameTag" {
+		t.Errorf("unexpected split: %q %q %q", name, service, method)
+	}
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// levels holds one LevelVar per module so log levels can change at runtime.
+// Every logger created for a module shares that module's LevelVar.
+var (
+	levelsMu sync.Mutex
+	levels   = map[string]*slog.LevelVar{}
+)
+
+// ParseLevel converts a level name (DEBUG, INFO, WARN, ERROR; case-insensitive) to a slog.Level.
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return slog.LevelDebug, nil
+	case "INFO":
+		return slog.LevelInfo, nil
+	case "WARN":
+		return slog.LevelWarn, nil
+	case "ERROR":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// moduleLevel returns the LevelVar of a module, creating it if needed.
+func moduleLevel(module string) *slog.LevelVar {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	lv, ok := levels[module]
+	if !ok {
+		lv = &slog.LevelVar{}
+		levels[module] = lv
+	}
+	return lv
+}
+
+// SetModuleLevel changes the level of every logger created for module.
+// Returns an error if the level is unknown or no logger exists for the module.
+func SetModuleLevel(module, level string) error {
+	logLevel, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	levelsMu.Lock()
+	lv, ok := levels[module]
+	levelsMu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown log module %q", module)
+	}
+
+	lv.Set(logLevel)
+	return nil
+}
+
+// ModuleLevel returns the current level of a module and whether a logger exists for it.
+func ModuleLevel(module string) (slog.Level, bool) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	lv, ok := levels[module]
+	if !ok {
+		return slog.LevelInfo, false
+	}
+	return lv.Level(), true
+}
@@ -33,36 +33,34 @@ const (
 
 // NewModuleLogger creates a logger with module context and optional colors.
 // module: one of ModuleMind, ModuleBrain, ModuleLSP, ModuleNVMW
-// level: log level (INFO, DEBUG, WARN, ERROR)
+// level: log level (INFO, DEBUG, WARN, ERROR); unknown values fall back to INFO
 // format: "text" or "json"
+//
+// The level is held in the module's LevelVar and can be changed later with SetModuleLevel.
 func NewModuleLogger(module, level, format string) *slog.Logger {
-	var logLevel slog.Level
-	switch level {
-	case "DEBUG":
-		logLevel = slog.LevelDebug
-	case "WARN":
-		logLevel = slog.LevelWarn
-	case "ERROR":
-		logLevel = slog.LevelError
-	case "INFO", "":
-		logLevel = slog.LevelInfo
-	default:
-		logLevel = slog.LevelInfo
-	}
+	return NewModuleLoggerWithWriter(os.Stdout, module, level, format)
+}
+
+// NewModuleLoggerWithWriter is like NewModuleLogger but writes to w instead of stdout.
+func NewModuleLoggerWithWriter(w io.Writer, module, level, format string) *slog.Logger {
+	logLevel, _ := ParseLevel(level) // Unknown or empty levels default to INFO
+
+	lv := moduleLevel(module)
+	lv.Set(logLevel)
 
-	opts := &slog.HandlerOptions{Level: logLevel}
+	opts := &slog.HandlerOptions{Level: lv}
 
 	if format == "json" {
 		// JSON format: Add module as a field, no colors
 		handler := &ModuleJSONHandler{
 			module:      module,
-			baseHandler: slog.NewJSONHandler(os.Stdout, opts),
+			baseHandler: slog.NewJSONHandler(w, opts),
 		}
 		return slog.New(handler)
 	}
 
 	// Text format: Custom handler with colored module prefix
-	handler := NewModuleTextHandler(os.Stdout, module, opts)
+	handler := NewModuleTextHandler(w, module, opts)
 	return slog.New(handler)
 }
 
@@ -0,0 +1,69 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrFrontmatterNotMapping is returned when frontmatter is valid YAML but not a key/value mapping.
+var ErrFrontmatterNotMapping = errors.New("frontmatter is not a YAML mapping")
+
+// SyncFrontmatter sets the title and updated keys of the body's frontmatter to the given values,
+// adding a frontmatter block if the body has none. Other keys keep their values and order.
+// updated is written as an RFC 3339 UTC timestamp.
+func SyncFrontmatter(body string, title string, updatedAt time.Time) (string, error) {
+	raw := ExtractRawFrontmatter([]byte(body))
+	content := ExtractBodyWithoutFrontmatter([]byte(body))
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if raw != "" {
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(raw), &doc); err != nil {
+			return "", fmt.Errorf("parse frontmatter: %w", err)
+		}
+		if len(doc.Content) == 1 {
+			if doc.Content[0].Kind != yaml.MappingNode {
+				return "", ErrFrontmatterNotMapping
+			}
+			mapping = doc.Content[0]
+		}
+	}
+
+	setMappingValue(mapping, "title", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: title})
+	setMappingValue(mapping, "updated", &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!timestamp",
+		Value: updatedAt.UTC().Format(time.RFC3339),
+	})
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(mapping); err != nil {
+		return "", fmt.Errorf("encode frontmatter: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", fmt.Errorf("encode frontmatter: %w", err)
+	}
+
+	return "---\n" + buf.String() + "---\n" + content, nil
+}
+
+// setMappingValue replaces the value of key in a YAML mapping, or appends the pair if key is missing.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
@@ -0,0 +1,88 @@
+package markdown
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var syncTime = time.Date(2026, 3, 14, 9, 26, 53, 0, time.FixedZone("EET", 2*60*60))
+
+func TestSyncFrontmatterAddsMissingBlock(t *testing.T) {
+	got, err := SyncFrontmatter("# Hello\n\nBody text.\n", "Hello", syncTime)
+	if err != nil {
+		t.Fatalf("SyncFrontmatter failed: %v", err)
+	}
+
+	want := "---\ntitle: Hello\nupdated: 2026-03-14T07:26:53Z\n---\n# Hello\n\nBody text.\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSyncFrontmatterUpdatesKeysAndPreservesOthers(t *testing.T) {
+	body := `---
+author: Jane
+title: Old title # renamed later
+tags:
+  - go
+  - notes
+updated: 2020-01-01T00:00:00Z
+---
+# New title
+`
+	got, err := SyncFrontmatter(body, "New title", syncTime)
+	if err != nil {
+		t.Fatalf("SyncFrontmatter failed: %v", err)
+	}
+
+	want := `---
+author: Jane
+title: New title # renamed later
+tags:
+  - go
+  - notes
+updated: 2026-03-14T07:26:53Z
+---
+# New title
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The result parses back with the synced values
+	result, err := NewParser().Parse([]byte(got))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if result.Metadata["title"] != "New title" || result.Metadata["author"] != "Jane" {
+		t.Errorf("unexpected metadata after sync: %v", result.Metadata)
+	}
+}
+
+func TestSyncFrontmatterQuotesTitles(t *testing.T) {
+	got, err := SyncFrontmatter("---\nstatus: draft\n---\nBody\n", "true: or false", syncTime)
+	if err != nil {
+		t.Fatalf("SyncFrontmatter failed: %v", err)
+	}
+
+	result, err := NewParser().Parse([]byte(got))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if result.Metadata["title"] != "true: or false" {
+		t.Errorf("title did not round-trip, got %#v in:\n%s", result.Metadata["title"], got)
+	}
+	if result.Metadata["status"] != "draft" {
+		t.Errorf("status key lost:\n%s", got)
+	}
+}
+
+func TestSyncFrontmatterRejectsInvalidFrontmatter(t *testing.T) {
+	if _, err := SyncFrontmatter("---\n- a\n- b\n---\nBody\n", "Title", syncTime); !errors.Is(err, ErrFrontmatterNotMapping) {
+		t.Errorf("expected ErrFrontmatterNotMapping for a list, got %v", err)
+	}
+	if _, err := SyncFrontmatter("---\ntitle: [unclosed\n---\nBody\n", "Title", syncTime); err == nil {
+		t.Error("expected an error for malformed YAML")
+	}
+}
@@ -17,8 +17,7 @@
 // Task Lists:
 //   - Syntax: - [x] completed task, - [ ] incomplete task
 //   - AST nodes: TaskCheckBox [GFM]
-//   - Status: PARSED (not extracted to ParseResult yet)
-//   - Future: Consider extracting task lists with completion status
+//   - Status: EXTRACTED to ParseResult.TaskListItems (when EnableTaskLists is true)
 //
 // Tables:
 //   - Syntax: | Header | Header | with alignment using :---|:---:|---:
@@ -39,10 +38,9 @@
 //   - Future: Extract external links for link management/validation
 //
 // Regular Links:
-//   - Syntax: [text](url)
+//   - Syntax: [text](url) and [text](url "title")
 //   - AST nodes: Link
-//   - Status: PARSED (not extracted to ParseResult yet)
-//   - Future: Extract external links separately from WikiLinks
+//   - Status: EXTRACTED to ParseResult.ExternalLinks (when EnableExternalLinks is true)
 //
 // Code Blocks:
 //   - Syntax: ```language with optional language identifier
@@ -53,8 +51,8 @@
 // Blockquotes:
 //   - Syntax: > quoted text
 //   - AST nodes: Blockquote
-//   - Status: PARSED (not extracted to ParseResult yet)
-//   - Future: Extract for callout/admonition support (like Obsidian)
+//   - Status: Obsidian callouts (> [!NOTE] Title) EXTRACTED to ParseResult.Callouts
+//     (when EnableCallouts is true); plain blockquotes are not extracted
 //
 // # Currently Extracted Features
 //
@@ -63,6 +61,11 @@
 //   - Metadata: Frontmatter YAML as map[string]any
 //   - WikiLinks: [[target]] and [[target|display]] with embed support ![[target]]
 //   - Hashtags: #hashtag syntax (deduplicated)
+//   - Mentions: @handle syntax (deduplicated, code spans ignored)
+//   - ExternalLinks: [text](url "title") links, kept separate from WikiLinks
+//   - TaskListItems: - [ ] / - [x] items with text, completion status, and line number
+//   - Callouts: > [!TYPE] Title blockquotes with type, title, and body text
+//   - Headings: # Heading levels, text, and auto-generated anchor IDs
 //   - RawFrontmatter: YAML text without delimiters
 //   - BodyWithoutFrontmatter: Markdown body without frontmatter block
 //
@@ -88,20 +91,31 @@
 package markdown
 
 import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/yuin/goldmark"
 	meta "github.com/yuin/goldmark-meta"
 	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
+	gfmast "github.com/yuin/goldmark/extension/ast"
 	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 	"go.abhg.dev/goldmark/hashtag"
 	"go.abhg.dev/goldmark/wikilink"
 )
 
 // Parser wraps goldmark with configured extensions
 type Parser struct {
-	markdown goldmark.Markdown
-	options  Options
+	markdown   goldmark.Markdown
+	extensions []goldmark.Extender
+	options    Options
 }
 
 // Options configure the markdown parser
@@ -114,10 +128,34 @@ type Options struct {
 	EnableMeta bool
 	// EnableGFM enables GitHub Flavored Markdown (tables, strikethrough, etc)
 	EnableGFM bool
+	// EnableExternalLinks enables extraction of [text](url) links
+	EnableExternalLinks bool
+	// EnableTaskLists enables extraction of - [ ] / - [x] task list items (requires EnableGFM)
+	EnableTaskLists bool
+	// EnableCallouts enables extraction of Obsidian-style > [!NOTE] callouts
+	EnableCallouts bool
+	// EnableHeadings enables extraction of headings for tables of contents
+	EnableHeadings bool
+	// EnableMentions enables extraction of @handle mentions
+	EnableMentions bool
+	// EnableImages enables extraction of ![alt](url) images
+	EnableImages bool
 	// WikiLinkResolver resolves wikilink targets to URLs
 	WikiLinkResolver wikilink.Resolver
 	// HashtagResolver resolves hashtags to URLs
 	HashtagResolver hashtag.Resolver
+	// Extensions are custom goldmark extensions added after the built-in ones
+	Extensions []goldmark.Extender
+}
+
+// Option customizes the Options used by NewParser
+type Option func(*Options)
+
+// WithExtension adds a custom goldmark extension (e.g., math rendering) to the parser pipeline
+func WithExtension(ext goldmark.Extender) Option {
+	return func(o *Options) {
+		o.Extensions = append(o.Extensions, ext)
+	}
 }
 
 // ParseResult contains the results of parsing markdown
@@ -134,6 +172,18 @@ type ParseResult struct {
 	WikiLinks []WikiLink
 	// Hashtags extracted from the document
 	Hashtags []string
+	// Mentions are the @handles in the document (without @), deduplicated in order of appearance
+	Mentions []string
+	// ExternalLinks extracted from the document (if enabled)
+	ExternalLinks []ExternalLink
+	// TaskListItems extracted from the document (if enabled)
+	TaskListItems []TaskListItem
+	// Callouts extracted from the document (if enabled), nested ones after their parent
+	Callouts []Callout
+	// Headings extracted from the document (if enabled), in document order
+	Headings []Heading
+	// Images extracted from the document (if enabled), in document order
+	Images []ImageRef
 }
 
 // WikiLink represents a [[wiki-link]] in the document
@@ -143,19 +193,74 @@ type WikiLink struct {
 	Embed       bool   // Whether this is an embedded link (![[...]])
 }
 
-// DefaultOptions returns sensible defaults for markdown parsing
+// ExternalLink represents a [text](url "title") link in the document
+type ExternalLink struct {
+	URL   string `json:"url"`             // Link destination
+	Text  string `json:"text"`            // Link text
+	Title string `json:"title,omitempty"` // Optional link title
+}
+
+// TaskListItem represents a - [ ] / - [x] task list item in the document
+type TaskListItem struct {
+	Text    string // Item text without the checkbox
+	Checked bool   // Whether the item is completed ([x])
+	Line    int    // 1-based line number in the source (frontmatter included)
+}
+
+// Callout represents an Obsidian-style > [!TYPE] Title callout block
+type Callout struct {
+	Type  string // Upper-cased callout type: NOTE, WARNING, TIP, IMPORTANT, or CAUTION
+	Title string // Text after the [!TYPE] marker on the first line (may be empty)
+	Body  string // Text of the remaining lines, excluding nested callouts
+}
+
+// Heading represents a # Heading in the document
+type Heading struct {
+	Level  int    // 1-6
+	Text   string // Plain heading text
+	Anchor string // ID generated by parser.WithAutoHeadingID, unique within the document
+}
+
+// ImageRef represents a ![alt](url) image in the document
+type ImageRef struct {
+	Alt     string `json:"alt"`      // Alt text
+	URL     string `json:"url"`      // Image source
+	IsLocal bool   `json:"is_local"` // Relative path (./ or ../) rather than an external URL
+}
+
+// OpenTaskCount returns the number of incomplete task list items.
+func (r *ParseResult) OpenTaskCount() int {
+	count := 0
+	for _, item := range r.TaskListItems {
+		if !item.Checked {
+			count++
+		}
+	}
+	return count
+}
+
+// DefaultOptions returns the standard set of built-in extensions and extractors, with no custom extensions
 func DefaultOptions() Options {
 	return Options{
-		EnableWikiLinks: true,
-		EnableHashtags:  true,
-		EnableMeta:      true,
-		EnableGFM:       true,
+		EnableWikiLinks:     true,
+		EnableHashtags:      true,
+		EnableMeta:          true,
+		EnableGFM:           true,
+		EnableExternalLinks: true,
+		EnableTaskLists:     true,
+		EnableCallouts:      true,
+		EnableHeadings:      true,
+		EnableMentions:      true,
+		EnableImages:        true,
 	}
 }
 
-// NewParser creates a new markdown parser with the given options
-func NewParser() *Parser {
+// NewParser creates a new markdown parser with DefaultOptions customized by opts
+func NewParser(opts ...Option) *Parser {
 	options := DefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
 	var extensions []goldmark.Extender
 
 	// Add GFM if enabled
@@ -188,17 +293,112 @@ func NewParser() *Parser {
 		extensions = append(extensions, hashtagExt)
 	}
 
-	md := goldmark.New(
+	extensions = append(extensions, options.Extensions...)
+
+	return &Parser{
+		markdown:   newMarkdown(extensions),
+		extensions: extensions,
+		options:    options,
+	}
+}
+
+// newMarkdown builds a goldmark instance with the parser's extensions and extra renderer options.
+func newMarkdown(extensions []goldmark.Extender, rendererOptions ...renderer.Option) goldmark.Markdown {
+	return goldmark.New(
 		goldmark.WithExtensions(extensions...),
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(),
 		),
+		goldmark.WithRendererOptions(rendererOptions...),
 	)
+}
 
-	return &Parser{
-		markdown: md,
-		options:  options,
+// RenderHTML converts markdown to HTML with the parser's extensions.
+// Frontmatter is consumed and not rendered; raw HTML in the source is omitted.
+func (p *Parser) RenderHTML(source []byte, w io.Writer) error {
+	return p.markdown.Convert(source, w)
+}
+
+// RenderHTMLString is RenderHTML returning the HTML as a string.
+func (p *Parser) RenderHTMLString(source []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := p.RenderHTML(source, &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderHTMLWithMeta parses source and renders it to HTML in one pass: the HTML is rendered
+// from the AST of the returned ParseResult, so the document is only parsed once.
+func (p *Parser) RenderHTMLWithMeta(source []byte) (*ParseResult, string, error) {
+	result, err := p.Parse(source)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	if err := p.markdown.Renderer().Render(&buf, source, result.AST); err != nil {
+		return nil, "", err
+	}
+	return result, buf.String(), nil
+}
+
+// EmbedRenderer returns the HTML to inline for an ![[target]] embed.
+// ok = false falls back to the default wikilink rendering (a link, or an image for image targets).
+type EmbedRenderer func(target string) (html []byte, ok bool, err error)
+
+// RenderHTMLWithEmbeds is RenderHTML with ![[target]] embeds replaced by the HTML from embed,
+// wrapped in <blockquote class="transclusion">. An error from embed aborts rendering.
+func (p *Parser) RenderHTMLWithEmbeds(source []byte, w io.Writer, embed EmbedRenderer) error {
+	embeds := &embedNodeRenderer{
+		fallback: &wikilink.Renderer{Resolver: p.options.WikiLinkResolver},
+		embed:    embed,
+		inlined:  make(map[*wikilink.Node]bool),
+	}
+	// Lower values take precedence; the wikilink extension registers its renderer at 199
+	md := newMarkdown(p.extensions, renderer.WithNodeRenderers(util.Prioritized(embeds, 100)))
+	return md.Convert(source, w)
+}
+
+// embedNodeRenderer renders wikilink embeds through an EmbedRenderer and delegates everything else.
+type embedNodeRenderer struct {
+	fallback *wikilink.Renderer
+	embed    EmbedRenderer
+	inlined  map[*wikilink.Node]bool // Embeds rendered by embed, so exit writes nothing
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *embedNodeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(wikilink.Kind, r.render)
+}
+
+func (r *embedNodeRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n, ok := node.(*wikilink.Node)
+	if !ok || !n.Embed {
+		return r.fallback.Render(w, source, node, entering)
+	}
+
+	if !entering {
+		if r.inlined[n] {
+			delete(r.inlined, n)
+			return ast.WalkContinue, nil
+		}
+		return r.fallback.Render(w, source, node, entering)
+	}
+
+	html, ok, err := r.embed(string(n.Target))
+	if err != nil {
+		return ast.WalkStop, err
 	}
+	if !ok {
+		return r.fallback.Render(w, source, node, entering)
+	}
+
+	r.inlined[n] = true
+	_, _ = w.WriteString(`<blockquote class="transclusion">`)
+	_, _ = w.Write(html)
+	_, _ = w.WriteString("</blockquote>")
+	return ast.WalkSkipChildren, nil
 }
 
 // Parse parses markdown content and returns a ParseResult
@@ -232,6 +432,36 @@ func (p *Parser) Parse(source []byte) (*ParseResult, error) {
 		result.Hashtags = extractHashtags(doc, source)
 	}
 
+	// Extract mentions
+	if p.options.EnableMentions {
+		result.Mentions = extractMentions(doc, source)
+	}
+
+	// Extract external links
+	if p.options.EnableExternalLinks {
+		result.ExternalLinks = extractExternalLinks(doc, source)
+	}
+
+	// Extract task list items (checkboxes only exist when GFM is enabled)
+	if p.options.EnableGFM && p.options.EnableTaskLists {
+		result.TaskListItems = extractTaskListItems(doc, source)
+	}
+
+	// Extract callouts
+	if p.options.EnableCallouts {
+		result.Callouts = extractCallouts(doc, source)
+	}
+
+	// Extract headings
+	if p.options.EnableHeadings {
+		result.Headings = extractHeadings(doc, source)
+	}
+
+	// Extract images
+	if p.options.EnableImages {
+		result.Images = extractImages(doc, source)
+	}
+
 	return result, nil
 }
 
@@ -269,25 +499,342 @@ func extractWikiLinks(node ast.Node, source []byte) []WikiLink {
 	return links
 }
 
+// HashtagSpan locates a #hashtag in the source
+type HashtagSpan struct {
+	Tag   string // Tag name without the '#'
+	Start int    // Byte offset of the '#'
+	Stop  int    // Byte offset just past the tag
+}
+
+// FindHashtags returns the #hashtags in source in document order, with their byte offsets.
+// It sees the same hashtags as ParseResult.Hashtags, so code spans, code blocks and
+// link destinations are skipped.
+func (p *Parser) FindHashtags(source []byte) []HashtagSpan {
+	doc := p.markdown.Parser().Parse(text.NewReader(source))
+	return findHashtags(doc, source)
+}
+
 // extractHashtags walks the AST and collects all hashtags
 func extractHashtags(node ast.Node, source []byte) []string {
 	tagMap := make(map[string]struct{})
+	for _, span := range findHashtags(node, source) {
+		tagMap[span.Tag] = struct{}{}
+	}
+
+	// Convert map to slice
+	var tags []string
+	for tag := range tagMap {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// findHashtags walks the AST and collects hashtag nodes that start at a word boundary.
+// goldmark/hashtag triggers on any '#', so URL fragments, foo#bar and ##bar are dropped here.
+func findHashtags(node ast.Node, source []byte) []HashtagSpan {
+	var spans []HashtagSpan
 	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
 			return ast.WalkContinue, nil
 		}
-		if tag, ok := n.(*hashtag.Node); ok {
-			tagMap[string(tag.Tag)] = struct{}{}
+		tag, ok := n.(*hashtag.Node)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+		seg := tag.FirstChild().(*ast.Text).Segment
+		if hashtagBoundary(source, seg.Start) {
+			spans = append(spans, HashtagSpan{Tag: string(tag.Tag), Start: seg.Start, Stop: seg.Stop})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	return spans
+}
+
+// hashtagBoundary reports whether a hashtag may start at offset i:
+// at the start of the source, or after whitespace or opening punctuation.
+func hashtagBoundary(source []byte, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRune(source[:i])
+	return unicode.IsSpace(r) || strings.ContainsRune("([{*_~\"'", r)
+}
+
+// mentionPattern matches @handle not preceded by a word character (so emails don't match).
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([a-zA-Z0-9_-]+)`)
+
+// extractMentions walks the AST text and collects @handles, skipping code spans
+func extractMentions(node ast.Node, source []byte) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.CodeSpan:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			// Delimiters like _ split text into sibling nodes; match over each run of them
+			if _, ok := n.PreviousSibling().(*ast.Text); ok {
+				return ast.WalkContinue, nil
+			}
+			var text []byte
+			for sib := ast.Node(n); sib != nil; sib = sib.NextSibling() {
+				t, ok := sib.(*ast.Text)
+				if !ok {
+					break
+				}
+				text = append(text, t.Segment.Value(source)...)
+				if t.SoftLineBreak() || t.HardLineBreak() {
+					text = append(text, '\n')
+				}
+			}
+			for _, m := range mentionPattern.FindAllSubmatch(text, -1) {
+				handle := string(m[1])
+				if !seen[handle] {
+					seen[handle] = true
+					mentions = append(mentions, handle)
+				}
+			}
 		}
 		return ast.WalkContinue, nil
 	})
+	return mentions
+}
 
-	// Convert map to slice
-	var tags []string
-	for tag := range tagMap {
-		tags = append(tags, tag)
+// extractExternalLinks walks the AST and collects all [text](url) links
+func extractExternalLinks(node ast.Node, source []byte) []ExternalLink {
+	var links []ExternalLink
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if link, ok := n.(*ast.Link); ok {
+			// Collect text from descendants so emphasis/code inside the link text is kept
+			var textBuf []byte
+			_ = ast.Walk(link, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+				if !entering {
+					return ast.WalkContinue, nil
+				}
+				switch t := c.(type) {
+				case *ast.Text:
+					textBuf = append(textBuf, t.Segment.Value(source)...)
+				case *ast.String:
+					textBuf = append(textBuf, t.Value...)
+				}
+				return ast.WalkContinue, nil
+			})
+
+			links = append(links, ExternalLink{
+				URL:   string(link.Destination),
+				Text:  string(textBuf),
+				Title: string(link.Title),
+			})
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+// extractImages walks the AST and collects all ![alt](url) images
+func extractImages(node ast.Node, source []byte) []ImageRef {
+	var images []ImageRef
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if img, ok := n.(*ast.Image); ok {
+			var altBuf []byte
+			_ = ast.Walk(img, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+				if !entering {
+					return ast.WalkContinue, nil
+				}
+				switch t := c.(type) {
+				case *ast.Text:
+					altBuf = append(altBuf, t.Segment.Value(source)...)
+				case *ast.String:
+					altBuf = append(altBuf, t.Value...)
+				}
+				return ast.WalkContinue, nil
+			})
+
+			url := string(img.Destination)
+			images = append(images, ImageRef{
+				Alt:     string(altBuf),
+				URL:     url,
+				IsLocal: strings.HasPrefix(url, "./") || strings.HasPrefix(url, "../"),
+			})
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return images
+}
+
+// extractTaskListItems walks the AST and collects GFM task list items.
+// The checkbox is the first inline of its list item's text block, so the item text
+// is read from the checkbox's siblings (nested lists are separate items).
+func extractTaskListItems(node ast.Node, source []byte) []TaskListItem {
+	var items []TaskListItem
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		checkBox, ok := n.(*gfmast.TaskCheckBox)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		var textBuf []byte
+		for sibling := checkBox.NextSibling(); sibling != nil; sibling = sibling.NextSibling() {
+			_ = ast.Walk(sibling, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+				if !entering {
+					return ast.WalkContinue, nil
+				}
+				switch t := c.(type) {
+				case *ast.Text:
+					textBuf = append(textBuf, t.Segment.Value(source)...)
+					if t.SoftLineBreak() {
+						textBuf = append(textBuf, ' ')
+					}
+				case *ast.String:
+					textBuf = append(textBuf, t.Value...)
+				}
+				return ast.WalkContinue, nil
+			})
+		}
+
+		line := 0
+		if block := checkBox.Parent(); block != nil && block.Lines().Len() > 0 {
+			line = bytes.Count(source[:block.Lines().At(0).Start], []byte("\n")) + 1
+		}
+
+		items = append(items, TaskListItem{
+			Text:    strings.TrimSpace(string(textBuf)),
+			Checked: checkBox.IsChecked,
+			Line:    line,
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return items
+}
+
+// extractHeadings walks the AST and collects headings with the IDs assigned by WithAutoHeadingID.
+func extractHeadings(node ast.Node, source []byte) []Heading {
+	var headings []Heading
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		heading, ok := n.(*ast.Heading)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		var textBuf []byte
+		_ = ast.Walk(heading, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+			if !entering {
+				return ast.WalkContinue, nil
+			}
+			switch t := c.(type) {
+			case *ast.Text:
+				textBuf = append(textBuf, t.Segment.Value(source)...)
+			case *ast.String:
+				textBuf = append(textBuf, t.Value...)
+			}
+			return ast.WalkContinue, nil
+		})
+
+		var anchor string
+		if id, ok := heading.AttributeString("id"); ok {
+			if b, ok := id.([]byte); ok {
+				anchor = string(b)
+			}
+		}
+
+		headings = append(headings, Heading{
+			Level:  heading.Level,
+			Text:   strings.TrimSpace(string(textBuf)),
+			Anchor: anchor,
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return headings
+}
+
+// calloutPattern matches the first line of a callout: [!TYPE], an optional fold marker, and a title.
+var calloutPattern = regexp.MustCompile(`(?i)^\[!(NOTE|WARNING|TIP|IMPORTANT|CAUTION)\][+-]?(?:\s+(.*))?$`)
+
+// extractCallouts walks ast.Blockquote nodes and collects those starting with a [!TYPE] marker.
+// Nested callouts are reported separately and left out of their parent's body.
+func extractCallouts(node ast.Node, source []byte) []Callout {
+	var callouts []Callout
+	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		quote, ok := n.(*ast.Blockquote)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+		calloutType, title, ok := calloutHeader(quote, source)
+		if !ok {
+			return ast.WalkContinue, nil
+		}
+
+		callouts = append(callouts, Callout{
+			Type:  calloutType,
+			Title: title,
+			Body:  calloutBody(quote, source),
+		})
+		return ast.WalkContinue, nil
+	})
+	return callouts
+}
+
+// calloutHeader reports whether a blockquote is a callout and returns its type and title.
+func calloutHeader(quote *ast.Blockquote, source []byte) (string, string, bool) {
+	para, ok := quote.FirstChild().(*ast.Paragraph)
+	if !ok || para.Lines().Len() == 0 {
+		return "", "", false
 	}
-	return tags
+
+	header := para.Lines().At(0)
+	firstLine := strings.TrimSpace(string(header.Value(source)))
+	match := calloutPattern.FindStringSubmatch(firstLine)
+	if match == nil {
+		return "", "", false
+	}
+	return strings.ToUpper(match[1]), strings.TrimSpace(match[2]), true
+}
+
+// calloutBody joins the source lines of a callout's blocks, skipping the header line
+// and nested callouts. Blockquote markers are already stripped from block lines.
+func calloutBody(quote *ast.Blockquote, source []byte) string {
+	var lines []string
+	_ = ast.Walk(quote, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Type() != ast.TypeBlock {
+			return ast.WalkContinue, nil
+		}
+		if nested, ok := n.(*ast.Blockquote); ok && nested != quote {
+			if _, _, isCallout := calloutHeader(nested, source); isCallout {
+				return ast.WalkSkipChildren, nil
+			}
+		}
+
+		segments := n.Lines()
+		for i := 0; i < segments.Len(); i++ {
+			if n == quote.FirstChild() && i == 0 {
+				continue // [!TYPE] header line
+			}
+			segment := segments.At(i)
+			lines = append(lines, strings.TrimRight(string(segment.Value(source)), "\r\n"))
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(strings.Join(lines, "\n"))
 }
 
 // ExtractRawFrontmatter extracts the YAML frontmatter from markdown source
@@ -0,0 +1,437 @@
+package markdown
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+)
+
+func TestParseSeparatesWikiLinksFromExternalLinks(t *testing.T) {
+	source := []byte(`---
+title: Links
+---
+# Links
+
+See [[Project Alpha]] and [[Roadmap|the roadmap]].
+
+Read the [Go docs](https://go.dev/doc "Go Documentation") and
+the [**goldmark** README](https://github.com/yuin/goldmark).
+`)
+
+	result, err := NewParser().Parse(source)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.WikiLinks) != 2 {
+		t.Fatalf("expected 2 wiki-links, got %d: %+v", len(result.WikiLinks), result.WikiLinks)
+	}
+	if result.WikiLinks[0].Target != "Project Alpha" {
+		t.Errorf("expected first wiki-link target 'Project Alpha', got %q", result.WikiLinks[0].Target)
+	}
+	if result.WikiLinks[1].Target != "Roadmap" {
+		t.Errorf("expected second wiki-link target 'Roadmap', got %q", result.WikiLinks[1].Target)
+	}
+
+	expected := []ExternalLink{
+		{URL: "https://go.dev/doc", Text: "Go docs", Title: "Go Documentation"},
+		{URL: "https://github.com/yuin/goldmark", Text: "goldmark README"},
+	}
+	if len(result.ExternalLinks) != len(expected) {
+		t.Fatalf("expected %d external links, got %d: %+v", len(expected), len(result.ExternalLinks), result.ExternalLinks)
+	}
+	for i, want := range expected {
+		if got := result.ExternalLinks[i]; got != want {
+			t.Errorf("external link %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestParseExternalLinksDisabled(t *testing.T) {
+	p := NewParser()
+	p.options.EnableExternalLinks = false
+
+	result, err := p.Parse([]byte("A [link](https://example.com) and [[Wiki]]"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.ExternalLinks) != 0 {
+		t.Errorf("expected no external links when disabled, got %+v", result.ExternalLinks)
+	}
+	if len(result.WikiLinks) != 1 {
+		t.Errorf("expected wiki-links to still be extracted, got %+v", result.WikiLinks)
+	}
+}
+
+func TestParseNoExternalLinks(t *testing.T) {
+	result, err := NewParser().Parse([]byte("Only [[Wiki]] links and #tags here"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.ExternalLinks) != 0 {
+		t.Errorf("expected no external links, got %+v", result.ExternalLinks)
+	}
+}
+
+func TestParseExtractsImages(t *testing.T) {
+	source := []byte("![Logo](https://example.com/logo.png) and ![*Chart*](./img/chart.png)\n\n" +
+		"![](../assets/photo.jpg) plus a [link](https://example.com) and ![[Embedded Note]]")
+	result, err := NewParser().Parse(source)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []ImageRef{
+		{Alt: "Logo", URL: "https://example.com/logo.png"},
+		{Alt: "Chart", URL: "./img/chart.png", IsLocal: true},
+		{Alt: "", URL: "../assets/photo.jpg", IsLocal: true},
+	}
+	if len(result.Images) != len(expected) {
+		t.Fatalf("expected %d images, got %d: %+v", len(expected), len(result.Images), result.Images)
+	}
+	for i, want := range expected {
+		if got := result.Images[i]; got != want {
+			t.Errorf("image %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+	if len(result.ExternalLinks) != 1 {
+		t.Errorf("expected images not to be counted as links, got %+v", result.ExternalLinks)
+	}
+}
+
+func TestParseExtractsTaskListItems(t *testing.T) {
+	source := []byte(`---
+title: Tasks
+---
+# Sprint
+
+- [x] Ship the **parser** change
+- [ ] Review [[Roadmap]]
+- plain item
+- [ ] Write docs
+  - [X] Outline
+`)
+
+	result, err := NewParser().Parse(source)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []TaskListItem{
+		{Text: "Ship the parser change", Checked: true, Line: 6},
+		{Text: "Review Roadmap", Checked: false, Line: 7},
+		{Text: "Write docs", Checked: false, Line: 9},
+		{Text: "Outline", Checked: true, Line: 10},
+	}
+	if len(result.TaskListItems) != len(expected) {
+		t.Fatalf("expected %d task list items, got %d: %+v", len(expected), len(result.TaskListItems), result.TaskListItems)
+	}
+	for i, want := range expected {
+		if got := result.TaskListItems[i]; got != want {
+			t.Errorf("task list item %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	if got := result.OpenTaskCount(); got != 2 {
+		t.Errorf("expected 2 open tasks, got %d", got)
+	}
+}
+
+func TestParseTaskListsDisabled(t *testing.T) {
+	p := NewParser()
+	p.options.EnableTaskLists = false
+
+	result, err := p.Parse([]byte("- [ ] todo\n- [x] done\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.TaskListItems) != 0 {
+		t.Errorf("expected no task list items when disabled, got %+v", result.TaskListItems)
+	}
+}
+
+func TestRenderHTMLSkipsFrontmatter(t *testing.T) {
+	p := NewParser()
+	source := "---\nauthor: Jane\n---\n# Title\n\n```go\nfmt.Println(\"hi\")\n```\n"
+
+	var buf bytes.Buffer
+	if err := p.RenderHTML([]byte(source), &buf); err != nil {
+		t.Fatalf("RenderHTML failed: %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{`<h1 id="title">Title</h1>`, `<code class="language-go">`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in output:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "author") {
+		t.Errorf("expected frontmatter to be omitted:\n%s", html)
+	}
+}
+
+func TestRenderHTMLString(t *testing.T) {
+	source := "---\nauthor: Jane\n---\nSee [[Project Alpha]] and #golang here.\n"
+
+	html, err := NewParser().RenderHTMLString([]byte(source))
+	if err != nil {
+		t.Fatalf("RenderHTMLString failed: %v", err)
+	}
+
+	for _, want := range []string{
+		`<a href="Project%20Alpha.html">Project Alpha</a>`,
+		`<span class="hashtag">#golang</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in output:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "author") {
+		t.Errorf("expected frontmatter to be omitted:\n%s", html)
+	}
+}
+
+func TestRenderHTMLWithMeta(t *testing.T) {
+	source := "---\nauthor: Jane\n---\n# Title\n\nSee [[Roadmap|the roadmap]] #planning\n"
+	p := NewParser()
+
+	result, html, err := p.RenderHTMLWithMeta([]byte(source))
+	if err != nil {
+		t.Fatalf("RenderHTMLWithMeta failed: %v", err)
+	}
+
+	if result.Metadata["author"] != "Jane" {
+		t.Errorf("expected author metadata 'Jane', got %v", result.Metadata["author"])
+	}
+	if len(result.WikiLinks) != 1 || result.WikiLinks[0].Target != "Roadmap" {
+		t.Errorf("expected one wiki-link to Roadmap, got %+v", result.WikiLinks)
+	}
+	if len(result.Hashtags) != 1 || result.Hashtags[0] != "planning" {
+		t.Errorf("expected hashtag 'planning', got %v", result.Hashtags)
+	}
+
+	expected, err := p.RenderHTMLString([]byte(source))
+	if err != nil {
+		t.Fatalf("RenderHTMLString failed: %v", err)
+	}
+	if html != expected {
+		t.Errorf("expected the same HTML as RenderHTMLString:\ngot:  %s\nwant: %s", html, expected)
+	}
+	if !strings.Contains(html, `<a href="Roadmap.html">the roadmap</a>`) || strings.Contains(html, "author") {
+		t.Errorf("unexpected HTML:\n%s", html)
+	}
+}
+
+func TestParseExtractsCallouts(t *testing.T) {
+	p := NewParser()
+	source := `# Release
+
+> [!WARNING] Breaking changes
+> The v2 API is removed.
+>
+> > [!tip]
+> > Run the migration first.
+>
+> Plan a maintenance window.
+
+> Plain quote, not a callout.
+
+> [!NOTE]- Folded
+> Hidden by default.
+
+> [!DANGER] Unsupported type
+`
+
+	result, err := p.Parse([]byte(source))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []Callout{
+		{Type: "WARNING", Title: "Breaking changes", Body: "The v2 API is removed.\nPlan a maintenance window."},
+		{Type: "TIP", Title: "", Body: "Run the migration first."},
+		{Type: "NOTE", Title: "Folded", Body: "Hidden by default."},
+	}
+	if len(result.Callouts) != len(expected) {
+		t.Fatalf("expected %d callouts, got %d: %+v", len(expected), len(result.Callouts), result.Callouts)
+	}
+	for i, want := range expected {
+		if got := result.Callouts[i]; got != want {
+			t.Errorf("callout %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+}
+
+func TestParseCalloutsDisabled(t *testing.T) {
+	p := NewParser()
+	p.options.EnableCallouts = false
+
+	result, err := p.Parse([]byte("> [!NOTE] Title\n> body\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.Callouts) != 0 {
+		t.Errorf("expected no callouts when disabled, got %+v", result.Callouts)
+	}
+}
+
+func TestParseExtractsHeadings(t *testing.T) {
+	p := NewParser()
+	source := "# Project *Alpha*\n\nIntro.\n\n## Goals & Scope\n\n### Open `questions`\n\n## Goals & Scope\n"
+
+	result, err := p.Parse([]byte(source))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []Heading{
+		{Level: 1, Text: "Project Alpha", Anchor: "project-alpha"},
+		{Level: 2, Text: "Goals & Scope", Anchor: "goals--scope"},
+		{Level: 3, Text: "Open questions", Anchor: "open-questions"},
+		{Level: 2, Text: "Goals & Scope", Anchor: "goals--scope-1"},
+	}
+	if len(result.Headings) != len(expected) {
+		t.Fatalf("expected %d headings, got %d: %+v", len(expected), len(result.Headings), result.Headings)
+	}
+	for i, want := range expected {
+		if got := result.Headings[i]; got != want {
+			t.Errorf("heading %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	// Anchors must match the IDs goldmark renders so TOC links resolve
+	var buf bytes.Buffer
+	if err := p.RenderHTML([]byte(source), &buf); err != nil {
+		t.Fatalf("RenderHTML failed: %v", err)
+	}
+	for _, h := range result.Headings {
+		if want := `id="` + h.Anchor + `"`; !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %s in rendered HTML:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestParseHeadingsDisabled(t *testing.T) {
+	p := NewParser()
+	p.options.EnableHeadings = false
+
+	result, err := p.Parse([]byte("# Title\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if len(result.Headings) != 0 {
+		t.Errorf("expected no headings when disabled, got %+v", result.Headings)
+	}
+}
+
+func TestParseExtractsMentions(t *testing.T) {
+	source := "Ping @alice and @bob-smith about #planning.\n\n" +
+		"Thanks @alice! Mail jane@example.com, not `@code`.\nCc\n@dave\n\n" +
+		"- [ ] @carol_2 follows up\n"
+
+	result, err := NewParser().Parse([]byte(source))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	expected := []string{"alice", "bob-smith", "dave", "carol_2"}
+	if !reflect.DeepEqual(result.Mentions, expected) {
+		t.Errorf("expected mentions %v, got %v", expected, result.Mentions)
+	}
+}
+
+func TestFindHashtags(t *testing.T) {
+	source := "#old at the start, (#old) and **#old**.\n\n" +
+		"Not https://host/page#old, foo#old, ##old or `#old`.\n\n" +
+		"```\n#old\n```\n"
+
+	spans := NewParser().FindHashtags([]byte(source))
+
+	var starts []int
+	for _, span := range spans {
+		if span.Tag != "old" || source[span.Start:span.Stop] != "#old" {
+			t.Errorf("unexpected span %+v", span)
+		}
+		starts = append(starts, span.Start)
+	}
+	expected := []int{0, 20, 32}
+	if !reflect.DeepEqual(starts, expected) {
+		t.Errorf("expected hashtags at %v, got %v", expected, starts)
+	}
+
+	result, err := NewParser().Parse([]byte("See foo#bar and page#baz"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(result.Hashtags) != 0 {
+		t.Errorf("expected no hashtags, got %v", result.Hashtags)
+	}
+}
+
+func TestRenderHTMLWithEmbeds(t *testing.T) {
+	p := NewParser()
+	source := "Intro\n\n![[Inner]]\n\nSee [[Inner]] and ![[photo.png]] and ![[Missing]].\n"
+
+	var targets []string
+	var buf bytes.Buffer
+	err := p.RenderHTMLWithEmbeds([]byte(source), &buf, func(target string) ([]byte, bool, error) {
+		targets = append(targets, target)
+		if target != "Inner" {
+			return nil, false, nil
+		}
+		return []byte("<p>inner body</p>"), true, nil
+	})
+	if err != nil {
+		t.Fatalf("RenderHTMLWithEmbeds failed: %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{
+		`<blockquote class="transclusion"><p>inner body</p></blockquote>`,
+		`<a href="Inner.html">Inner</a>`, // Plain wikilinks are not embedded
+		`<img src="photo.png">`,          // Declined embeds keep default rendering
+		`<a href="Missing.html">Missing</a>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in output:\n%s", want, html)
+		}
+	}
+	if want := []string{"Inner", "photo.png", "Missing"}; strings.Join(targets, ",") != strings.Join(want, ",") {
+		t.Errorf("expected embed callbacks for %v, got %v", want, targets)
+	}
+}
+
+// countingExtender is a no-op goldmark extension that records how often it was applied.
+type countingExtender struct {
+	applied int
+}
+
+func (e *countingExtender) Extend(goldmark.Markdown) { e.applied++ }
+
+func TestNewParserWithExtension(t *testing.T) {
+	ext := &countingExtender{}
+	p := NewParser(WithExtension(ext))
+	if ext.applied == 0 {
+		t.Fatal("expected the custom extension to be applied to the pipeline")
+	}
+
+	result, err := p.Parse([]byte("# Title\n\nSee [[Other]] and #tag"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	// Built-in extensions still run alongside the custom one
+	if len(result.WikiLinks) != 1 || len(result.Hashtags) != 1 {
+		t.Errorf("expected built-in extraction to still work, got %+v and %+v", result.WikiLinks, result.Hashtags)
+	}
+	if _, err := p.RenderHTMLString([]byte("plain")); err != nil {
+		t.Errorf("RenderHTMLString failed: %v", err)
+	}
+}
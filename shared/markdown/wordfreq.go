@@ -0,0 +1,73 @@
+package markdown
+
+import (
+	_ "embed"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+//go:embed stopwords.txt
+var stopWordsFile string
+
+// stopWords holds common English words that carry no meaning on their own.
+var stopWords = func() map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(stopWordsFile) {
+		words[w] = true
+	}
+	return words
+}()
+
+// IsStopWord reports whether word (lowercase) is an English stop word.
+func IsStopWord(word string) bool {
+	return stopWords[word]
+}
+
+// WordCount is a word and its number of occurrences.
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// ComputeWordFrequency counts the meaningful words of a markdown body, lowercased.
+// Words are runs of letters, digits, and inner apostrophes; stop words, single
+// characters, and pure numbers are skipped. Markdown syntax is not stripped, but
+// punctuation-only tokens never form words.
+func ComputeWordFrequency(body string) map[string]int {
+	freq := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(body), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '’'
+	})
+	for _, w := range words {
+		w = strings.Trim(w, "'’")
+		// Possessives and contractions count as their stem ("note's" -> "note")
+		if i := strings.IndexAny(w, "'’"); i >= 0 {
+			w = w[:i]
+		}
+		if len([]rune(w)) < 2 || stopWords[w] || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		freq[w]++
+	}
+	return freq
+}
+
+// TopWords returns the n most frequent words of freq, by descending count then word.
+// A non-positive n returns all words.
+func TopWords(freq map[string]int, n int) []WordCount {
+	words := make([]WordCount, 0, len(freq))
+	for w, c := range freq {
+		words = append(words, WordCount{Word: w, Count: c})
+	}
+	slices.SortFunc(words, func(a, b WordCount) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.Word, b.Word)
+	})
+	if n > 0 && len(words) > n {
+		words = words[:n]
+	}
+	return words
+}
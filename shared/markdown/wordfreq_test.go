@@ -0,0 +1,53 @@
+package markdown
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeWordFrequency(t *testing.T) {
+	body := `# The Garden
+
+The garden is green and the garden is quiet.
+Tomatoes and basil grow in the garden; basil is the best. It's 2024!`
+
+	got := ComputeWordFrequency(body)
+	want := map[string]int{
+		"garden":   4,
+		"green":    1,
+		"quiet":    1,
+		"tomatoes": 1,
+		"basil":    2,
+		"grow":     1,
+		"best":     1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeWordFrequency() = %v, want %v", got, want)
+	}
+
+	for _, stop := range []string{"the", "and", "is", "it", "in"} {
+		if _, ok := got[stop]; ok {
+			t.Errorf("stop word %q was counted", stop)
+		}
+	}
+}
+
+func TestComputeWordFrequency_Empty(t *testing.T) {
+	if got := ComputeWordFrequency("--- ## * 42 a"); len(got) != 0 {
+		t.Errorf("expected no words, got %v", got)
+	}
+}
+
+func TestTopWords(t *testing.T) {
+	freq := map[string]int{"garden": 4, "basil": 2, "green": 1, "best": 1, "quiet": 1}
+
+	got := TopWords(freq, 3)
+	want := []WordCount{{"garden", 4}, {"basil", 2}, {"best", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TopWords(3) = %v, want %v", got, want)
+	}
+
+	if got := TopWords(freq, 0); len(got) != len(freq) {
+		t.Errorf("TopWords(0) returned %d words, want %d", len(got), len(freq))
+	}
+}
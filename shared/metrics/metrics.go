@@ -0,0 +1,154 @@
+// Package metrics exposes Prometheus metrics for Mindweaver.
+//
+// A Metrics value owns its own registry, so tests can create isolated instances.
+// Services receive it through optional setters (e.g., NotesService.SetMetrics)
+// and skip instrumentation when it is nil.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mindweaver"
+
+// unmatchedRoute labels requests that did not match a registered route,
+// keeping raw URLs out of label values.
+const unmatchedRoute = "unmatched"
+
+// Metrics holds the application collectors and the registry they are registered with.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	NotesCreated prometheus.Counter // mindweaver_notes_created_total
+	NotesUpdated prometheus.Counter // mindweaver_notes_updated_total
+	NotesCount   prometheus.Gauge   // mindweaver_notes_count
+
+	LLMRequests *prometheus.CounterVec // mindweaver_llm_requests_total{adapter,status}
+	LLMTokens   *prometheus.CounterVec // mindweaver_llm_tokens_total{adapter,type}
+
+	WALLogFrames          *prometheus.GaugeVec     // mindweaver_wal_log_frames{db}
+	WALCheckpointedFrames *prometheus.GaugeVec     // mindweaver_wal_checkpointed_frames{db}
+	WALCheckpointDuration *prometheus.HistogramVec // mindweaver_wal_checkpoint_duration_seconds{db}
+
+	requestDuration *prometheus.HistogramVec // mindweaver_http_request_duration_seconds{method,route,status}
+}
+
+// New creates a Metrics instance with all collectors registered on a fresh registry.
+// Go runtime and process collectors are included.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		NotesCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notes_created_total",
+			Help:      "Total number of notes created.",
+		}),
+		NotesUpdated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notes_updated_total",
+			Help:      "Total number of notes updated.",
+		}),
+		NotesCount: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "notes_count",
+			Help:      "Current number of notes.",
+		}),
+		LLMRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_requests_total",
+			Help:      "Total number of LLM requests by adapter and status.",
+		}, []string{"adapter", "status"}),
+		LLMTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_tokens_total",
+			Help:      "Total number of LLM tokens by adapter and type (prompt, completion).",
+		}, []string{"adapter", "type"}),
+		WALLogFrames: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "wal_log_frames",
+			Help:      "Frames in the WAL file after the last checkpoint, by database.",
+		}, []string{"db"}),
+		WALCheckpointedFrames: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "wal_checkpointed_frames",
+			Help:      "Frames written back to the database by the last checkpoint, by database.",
+		}, []string{"db"}),
+		WALCheckpointDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "wal_checkpoint_duration_seconds",
+			Help:      "WAL checkpoint latency by database.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"db"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.NotesCreated,
+		m.NotesUpdated,
+		m.NotesCount,
+		m.LLMRequests,
+		m.LLMTokens,
+		m.WALLogFrames,
+		m.WALCheckpointedFrames,
+		m.WALCheckpointDuration,
+		m.requestDuration,
+	)
+
+	return m
+}
+
+// ObserveWALCheckpoint records the outcome and latency of a WAL checkpoint of db.
+func (m *Metrics) ObserveWALCheckpoint(db string, logFrames, checkpointedFrames int, duration time.Duration) {
+	m.WALLogFrames.WithLabelValues(db).Set(float64(logFrames))
+	m.WALCheckpointedFrames.WithLabelValues(db).Set(float64(checkpointedFrames))
+	m.WALCheckpointDuration.WithLabelValues(db).Observe(duration.Seconds())
+}
+
+// Handler returns an HTTP handler serving the registry in Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// MetricsMiddleware records request latency per route in a histogram.
+// Routes are labelled by their registered pattern (e.g., /api/mind/v3/tags/:id), not the raw URL.
+func (m *Metrics) MetricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+
+		// Errors are rendered by the error handler after this returns, so derive the status from them
+		status := c.Response().Status
+		if err != nil {
+			he := &echo.HTTPError{}
+			if errors.As(err, &he) {
+				status = he.Code
+			} else if !c.Response().Committed {
+				status = http.StatusInternalServerError
+			}
+		}
+
+		route := c.Path()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		m.requestDuration.WithLabelValues(c.Request().Method, route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/nkapatos/mindweaver/shared/database"
+)
+
+// scrape returns the text exposition of m.
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestMetricsMiddlewareRecordsRouteLatency(t *testing.T) {
+	m := New()
+
+	e := echo.New()
+	e.Use(m.MetricsMiddleware)
+	e.GET("/api/mind/v3/tags/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/api/mind/v3/notes/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusNotFound, "note not found")
+	})
+
+	for _, path := range []string{"/api/mind/v3/tags/1", "/api/mind/v3/tags/2", "/api/mind/v3/notes/9", "/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, m)
+	expected := []string{
+		`mindweaver_http_request_duration_seconds_count{method="GET",route="/api/mind/v3/tags/:id",status="200"} 2`,
+		`mindweaver_http_request_duration_seconds_count{method="GET",route="/api/mind/v3/notes/:id",status="404"} 1`,
+		`mindweaver_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+	if strings.Contains(body, `route="/missing"`) {
+		t.Error("expected unmatched routes not to leak raw URLs into labels")
+	}
+}
+
+func TestLLMCountersUseLabels(t *testing.T) {
+	m := New()
+	m.LLMRequests.WithLabelValues("openai", "success").Inc()
+	m.LLMTokens.WithLabelValues("openai", "prompt").Add(42)
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		`mindweaver_llm_requests_total{adapter="openai",status="success"} 1`,
+		`mindweaver_llm_tokens_total{adapter="openai",type="prompt"} 42`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}
+
+func TestObserveWALCheckpointAfterWrites(t *testing.T) {
+	m := New()
+
+	db, err := database.OpenSQLite(filepath.Join(t.TempDir(), "wal.db"))
+	if err != nil {
+		t.Fatalf("OpenSQLite failed: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, title TEXT NOT NULL)"); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	for i := 0; i < 20; i++ {
+		if _, err := db.Exec("INSERT INTO notes (title) VALUES (?)", strings.Repeat("x", 512)); err != nil {
+			t.Fatalf("failed to insert: %v", err)
+		}
+	}
+
+	start := time.Now()
+	cp, err := database.CheckpointWAL(t.Context(), db)
+	if err != nil {
+		t.Fatalf("CheckpointWAL failed: %v", err)
+	}
+	m.ObserveWALCheckpoint("notes", cp.LogFrames, cp.CheckpointedFrames, time.Since(start))
+
+	if cp.LogFrames == 0 || cp.CheckpointedFrames == 0 {
+		t.Fatalf("expected frames after writes, got %+v", cp)
+	}
+
+	body := scrape(t, m)
+	expected := []string{
+		fmt.Sprintf(`mindweaver_wal_log_frames{db="notes"} %d`, cp.LogFrames),
+		fmt.Sprintf(`mindweaver_wal_checkpointed_frames{db="notes"} %d`, cp.CheckpointedFrames),
+		`mindweaver_wal_checkpoint_duration_seconds_count{db="notes"} 1`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %q", want)
+		}
+	}
+}
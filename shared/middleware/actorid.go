@@ -0,0 +1,21 @@
+package middleware
+
+import "context"
+
+const actorIDKey contextKey = "actorID"
+
+// WithActorID returns a copy of ctx carrying the ID of the actor performing the request.
+// Set by whatever authenticates the caller; services only read it for logging and auditing.
+func WithActorID(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorIDKey, actorID)
+}
+
+// GetActorID extracts the actor ID from context, or returns empty string if not found.
+func GetActorID(ctx context.Context) string {
+	if v := ctx.Value(actorIDKey); v != nil {
+		if aid, ok := v.(string); ok {
+			return aid
+		}
+	}
+	return ""
+}
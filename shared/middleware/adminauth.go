@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	adminPathPrefix = "/admin/"
+	adminSetupPath  = "/admin/setup" // First-run wizard, must work before any secret exists
+	adminRole       = "admin"
+)
+
+var (
+	errMalformedToken = errors.New("malformed token")
+	errBadSignature   = errors.New("invalid token signature")
+	errTokenExpired   = errors.New("token expired")
+	errNotAdmin       = errors.New("token lacks admin role")
+	errNoSubject      = errors.New("token has no subject")
+)
+
+// tokenClaims are the JWT claims checked by AdminAuthMiddleware and VerifyToken.
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	Role      string `json:"role"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// AdminAuthMiddleware rejects requests to /admin/* unless they carry an HS256 JWT
+// signed with secret, with role "admin" and an exp in the future.
+// The token is read from "Authorization: Bearer <token>"; its subject becomes the actor ID.
+// The setup wizard (/admin/setup) is exempt. An empty secret disables the admin API.
+func AdminAuthMiddleware(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			path := c.Request().URL.Path
+			if !strings.HasPrefix(path, adminPathPrefix) || strings.HasPrefix(path, adminSetupPath) {
+				return next(c)
+			}
+
+			if secret == "" {
+				return echo.NewHTTPError(http.StatusForbidden, "admin API is disabled (security.admin_jwt_secret not set)")
+			}
+
+			token, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || token == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing admin token")
+			}
+
+			claims, err := verifyAdminToken(token, []byte(secret), time.Now())
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid admin token: "+err.Error())
+			}
+
+			if claims.Subject != "" {
+				req := c.Request()
+				c.SetRequest(req.WithContext(WithActorID(req.Context(), claims.Subject)))
+			}
+			return next(c)
+		}
+	}
+}
+
+// SignAdminToken creates an HS256 admin JWT for subject that expires after ttl.
+// Used by tooling and tests to mint tokens accepted by AdminAuthMiddleware.
+func SignAdminToken(secret, subject string, ttl time.Duration) (string, error) {
+	return signToken([]byte(secret), tokenClaims{
+		Subject:   subject,
+		Role:      adminRole,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+}
+
+// SignToken creates an HS256 JWT for subject that expires after ttl.
+// The token authenticates API calls (see VerifyToken) but carries no admin role.
+func SignToken(secret, subject string, ttl time.Duration) (string, error) {
+	return signToken([]byte(secret), tokenClaims{
+		Subject:   subject,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+}
+
+// VerifyToken checks the signature and expiry of an HS256 JWT and returns its subject.
+// Tokens without a subject are rejected since the subject becomes the actor ID.
+func VerifyToken(token, secret string) (string, error) {
+	claims, err := verifyToken(token, []byte(secret), time.Now())
+	if err != nil {
+		return "", err
+	}
+	if claims.Subject == "" {
+		return "", errNoSubject
+	}
+	return claims.Subject, nil
+}
+
+// signToken encodes claims as an HS256 JWT.
+func signToken(secret []byte, claims tokenClaims) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verifyAdminToken checks the signature, expiry, and role of an HS256 JWT.
+func verifyAdminToken(token string, secret []byte, now time.Time) (tokenClaims, error) {
+	claims, err := verifyToken(token, secret, now)
+	if err != nil {
+		return tokenClaims{}, err
+	}
+	if claims.Role != adminRole {
+		return tokenClaims{}, errNotAdmin
+	}
+	return claims, nil
+}
+
+// verifyToken checks the signature and expiry of an HS256 JWT.
+func verifyToken(token string, secret []byte, now time.Time) (tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return tokenClaims{}, errMalformedToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return tokenClaims{}, errMalformedToken
+	}
+	// Only HS256 is accepted; this also rules out "none"
+	if header.Alg != "HS256" {
+		return tokenClaims{}, errBadSignature
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return tokenClaims{}, errMalformedToken
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return tokenClaims{}, errBadSignature
+	}
+
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return tokenClaims{}, errMalformedToken
+	}
+	if claims.ExpiresAt == 0 || !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return tokenClaims{}, errTokenExpired
+	}
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT into v.
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
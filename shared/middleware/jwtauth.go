@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JWTAuthMiddleware is the Echo counterpart of interceptors.NewJWTInterceptor, for plain
+// HTTP routes (exports, SSE, WebSocket) that don't go through Connect.
+// It authenticates requests with an HS256 JWT from "Authorization: Bearer <token>" and
+// stores its sub claim as the actor ID.
+//
+// Requests already carrying an actor ID (e.g., authenticated by API key middleware) pass
+// through. Missing, expired, or tampered tokens are rejected with 401.
+// An empty secret disables the check, leaving requests unauthenticated as before.
+func JWTAuthMiddleware(secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if secret == "" || GetActorID(req.Context()) != "" {
+				return next(c)
+			}
+
+			token, ok := strings.CutPrefix(req.Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || token == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing bearer token")
+			}
+
+			actorID, err := VerifyToken(token, secret)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid bearer token: "+err.Error())
+			}
+
+			c.SetRequest(req.WithContext(WithActorID(req.Context(), actorID)))
+			return next(c)
+		}
+	}
+}
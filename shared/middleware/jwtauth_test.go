@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const testJWTSecret = "test-jwt-secret"
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	valid, err := SignToken(testJWTSecret, "alice", time.Hour)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	expired, _ := SignToken(testJWTSecret, "alice", -time.Minute)
+	wrongSecret, _ := SignToken("other-secret", "alice", time.Hour)
+
+	tests := []struct {
+		name           string
+		secret         string
+		token          string
+		preset         string // Actor ID set by earlier middleware (e.g., API key)
+		expectedStatus int
+		expectedActor  string
+	}{
+		{"valid token", testJWTSecret, valid, "", http.StatusOK, "alice"},
+		{"missing token", testJWTSecret, "", "", http.StatusUnauthorized, ""},
+		{"expired token", testJWTSecret, expired, "", http.StatusUnauthorized, ""},
+		{"wrong secret", testJWTSecret, wrongSecret, "", http.StatusUnauthorized, ""},
+		{"malformed token", testJWTSecret, "not-a-jwt", "", http.StatusUnauthorized, ""},
+		{"already authenticated", testJWTSecret, "", "bob", http.StatusOK, "bob"},
+		{"disabled", "", "", "", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actorID string
+			e := echo.New()
+			preset := func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if tt.preset != "" {
+						req := c.Request()
+						c.SetRequest(req.WithContext(WithActorID(req.Context(), tt.preset)))
+					}
+					return next(c)
+				}
+			}
+			e.GET("/api/mind/notes", func(c echo.Context) error {
+				actorID = GetActorID(c.Request().Context())
+				return c.NoContent(http.StatusOK)
+			}, preset, JWTAuthMiddleware(tt.secret))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/mind/notes", nil)
+			if tt.token != "" {
+				req.Header.Set(echo.HeaderAuthorization, "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if actorID != tt.expectedActor {
+				t.Errorf("expected actor %q, got %q", tt.expectedActor, actorID)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+)
+
+// ContextAttrs returns the request-scoped logging attributes stored in ctx
+// (request_id, actor_id, trace_id) as slog key-value pairs.
+func ContextAttrs(ctx context.Context) []any {
+	return []any{
+		"request_id", GetRequestID(ctx),
+		"actor_id", GetActorID(ctx),
+		"trace_id", GetTraceID(ctx),
+	}
+}
+
+// ContextLogger returns logger enriched with the request-scoped attributes from ctx.
+// Services pass their own logger so its attributes (e.g. service) are kept.
+func ContextLogger(ctx context.Context, logger *slog.Logger) *slog.Logger {
+	return logger.With(ContextAttrs(ctx)...)
+}
+
+// WithContext returns the default logger pre-populated with the request-scoped
+// attributes from ctx.
+func WithContext(ctx context.Context) *slog.Logger {
+	return ContextLogger(ctx, slog.Default())
+}
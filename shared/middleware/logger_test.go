@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestWithContextIncludesRequestAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	e := echo.New()
+	e.Use(RequestIDMiddleware)
+	e.Use(TraceIDMiddleware)
+	e.GET("/notes", func(c echo.Context) error {
+		ctx := WithActorID(c.Request().Context(), "actor-42")
+		WithContext(ctx).Info("note created", "note_id", 7)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/notes", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	req.Header.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode log record %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]string{
+		"request_id": "req-123",
+		"actor_id":   "actor-42",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+	}
+	for key, want := range expected {
+		if got := record[key]; got != want {
+			t.Errorf("expected %s=%q, got %v", key, want, got)
+		}
+	}
+	if record["note_id"] != float64(7) {
+		t.Errorf("expected note_id=7, got %v", record["note_id"])
+	}
+}
+
+func TestContextLoggerThroughMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	serviceLogger := slog.New(slog.NewJSONHandler(&buf, nil)).With("service", "notes")
+
+	token, err := SignToken(testJWTSecret, "alice", time.Hour)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	e := echo.New()
+	e.Use(RequestIDMiddleware)
+	e.Use(TraceIDMiddleware)
+	e.Use(JWTAuthMiddleware(testJWTSecret))
+	e.GET("/notes", func(c echo.Context) error {
+		ContextLogger(c.Request().Context(), serviceLogger).Info("note created", "note_id", 7)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/notes", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	req.Header.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode log record %q: %v", buf.String(), err)
+	}
+
+	expected := map[string]string{
+		"service":    "notes",
+		"request_id": "req-123",
+		"actor_id":   "alice",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+	}
+	for key, want := range expected {
+		if got := record[key]; got != want {
+			t.Errorf("expected %s=%q, got %v", key, want, got)
+		}
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"uppercase normalized", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"empty", "", ""},
+		{"wrong field count", "00-4bf92f3577b34da6a3ce929d0e0e4736-01", ""},
+		{"short trace id", "00-4bf92f35-00f067aa0ba902b7-01", ""},
+		{"non hex", "00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ""},
+		{"all zero", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTraceParent(tt.header); got != tt.expected {
+				t.Errorf("parseTraceParent(%q) = %q, want %q", tt.header, got, tt.expected)
+			}
+		})
+	}
+}
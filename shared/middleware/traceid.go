@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	traceIDKey        contextKey = "traceID"
+	TraceParentHeader string     = "traceparent" // W3C Trace Context header name
+)
+
+// invalidTraceID is the all-zero trace ID, which W3C Trace Context forbids.
+const invalidTraceID = "00000000000000000000000000000000"
+
+// TraceIDMiddleware extracts the trace ID from a W3C traceparent header and stores it in context.
+// Like SessionIDMiddleware, no ID is generated when the header is missing or malformed -
+// request correlation without a tracing client is handled by RequestIDMiddleware.
+func TraceIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if tid := parseTraceParent(req.Header.Get(TraceParentHeader)); tid != "" {
+			ctx := context.WithValue(req.Context(), traceIDKey, tid)
+			c.SetRequest(req.WithContext(ctx))
+		}
+		return next(c)
+	}
+}
+
+// GetTraceID extracts the trace ID from context, or returns empty string if not found.
+func GetTraceID(ctx context.Context) string {
+	if v := ctx.Value(traceIDKey); v != nil {
+		if tid, ok := v.(string); ok {
+			return tid
+		}
+	}
+	return ""
+}
+
+// parseTraceParent returns the trace-id field of a traceparent header
+// (version-traceid-parentid-flags), or empty string if the header is invalid.
+func parseTraceParent(header string) string {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 {
+		return ""
+	}
+	traceID := strings.ToLower(parts[1])
+	if _, err := hex.DecodeString(traceID); err != nil || traceID == invalidTraceID {
+		return ""
+	}
+	return traceID
+}
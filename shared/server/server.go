@@ -0,0 +1,55 @@
+// Package server runs the HTTP server with graceful shutdown.
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Run starts e on addr and blocks until the server fails or ctx is cancelled
+// (typically by signal.NotifyContext on SIGTERM/SIGINT).
+//
+// On cancellation beforeShutdown runs first, so long-lived streams (e.g., SSE) can end,
+// then the server stops accepting connections and waits up to timeout for in-flight
+// requests. Run returns instead of exiting so the caller's deferred cleanup still runs.
+func Run(ctx context.Context, e *echo.Echo, addr string, timeout time.Duration, beforeShutdown func(), logger *slog.Logger) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- e.Start(addr)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("Shutdown signal received, stopping services...", "timeout", timeout)
+	if beforeShutdown != nil {
+		beforeShutdown()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+
+	// Start returns ErrServerClosed as soon as Shutdown begins; anything else is a real failure
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	logger.Info("HTTP server stopped")
+	return nil
+}
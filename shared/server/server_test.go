@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// newTestEcho returns an Echo instance with a /slow route that signals entry on started.
+func newTestEcho(started chan<- struct{}, delay time.Duration) *echo.Echo {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.GET("/slow", func(c echo.Context) error {
+		started <- struct{}{}
+		time.Sleep(delay)
+		return c.String(http.StatusOK, "done")
+	})
+	return e
+}
+
+// waitForListener blocks until e is listening and returns its address.
+func waitForListener(t *testing.T, e *echo.Echo) string {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if addr := e.ListenerAddr(); addr != nil {
+			return addr.String()
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("server did not start listening")
+	return ""
+}
+
+func TestRun_SIGTERMDrainsInFlightRequests(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	started := make(chan struct{}, 1)
+	e := newTestEcho(started, 300*time.Millisecond)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	defer stop()
+
+	var hookCalled, deferredRan bool
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- func() error {
+			defer func() { deferredRan = true }() // Stands in for main's DB close defers
+			return Run(ctx, e, "127.0.0.1:0", 5*time.Second, func() { hookCalled = true }, logger)
+		}()
+	}()
+
+	addr := waitForListener(t, e)
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	requestDone := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		if err != nil {
+			requestDone <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		requestDone <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	<-started
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send SIGTERM: %v", err)
+	}
+
+	res := <-requestDone
+	if res.err != nil {
+		t.Fatalf("in-flight request failed: %v", res.err)
+	}
+	if res.status != http.StatusOK || res.body != "done" {
+		t.Fatalf("expected 200 done, got %d %q", res.status, res.body)
+	}
+
+	select {
+	case err := <-runDone:
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after shutdown")
+	}
+
+	if !hookCalled {
+		t.Error("expected beforeShutdown to be called")
+	}
+	if !deferredRan {
+		t.Error("expected deferred cleanup to run")
+	}
+}
+
+func TestRun_TimeoutExceeded(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	started := make(chan struct{}, 1)
+	e := newTestEcho(started, time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runDone := make(chan error, 1)
+	go func() {
+		runDone <- Run(ctx, e, "127.0.0.1:0", 50*time.Millisecond, nil, logger)
+	}()
+
+	addr := waitForListener(t, e)
+	go func() {
+		if resp, err := http.Get("http://" + addr + "/slow"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-runDone:
+		if err == nil {
+			t.Fatal("expected error when in-flight requests outlive the timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after shutdown timeout")
+	}
+}
+
+func TestRun_StartError(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+
+	if err := Run(context.Background(), e, "invalid-address", time.Second, nil, logger); err == nil {
+		t.Fatal("expected error for invalid listen address")
+	}
+}
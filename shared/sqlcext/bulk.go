@@ -6,7 +6,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -99,7 +101,7 @@ func (b *BulkInserter) Insert(ctx context.Context, db DBTX, rows [][]any) error
 		}
 
 		chunk := rows[i:end]
-		if err := b.insertChunk(ctx, db, chunk); err != nil {
+		if err := b.insertChunk(ctx, db, chunk, ""); err != nil {
 			return fmt.Errorf("bulk insert chunk [%d:%d]: %w", i, end, err)
 		}
 	}
@@ -107,8 +109,37 @@ func (b *BulkInserter) Insert(ctx context.Context, db DBTX, rows [][]any) error
 	return nil
 }
 
+// InsertOrIgnore executes a bulk INSERT that skips rows violating a uniqueness constraint
+// (ON CONFLICT DO NOTHING). Useful for idempotent inserts into join tables and for
+// get-or-create of unique names.
+//
+// Example:
+//
+//	inserter := sqlcext.NewBulkInserter("note_tags", []string{"note_id", "tag_id"}, 100)
+//	err := inserter.InsertOrIgnore(ctx, tx, [][]any{{1, 7}, {1, 7}}) // second row is skipped
+func (b *BulkInserter) InsertOrIgnore(ctx context.Context, db DBTX, rows [][]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	for i := 0; i < len(rows); i += b.batchSize {
+		end := i + b.batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		chunk := rows[i:end]
+		if err := b.insertChunk(ctx, db, chunk, " ON CONFLICT DO NOTHING"); err != nil {
+			return fmt.Errorf("bulk insert-or-ignore chunk [%d:%d]: %w", i, end, err)
+		}
+	}
+
+	return nil
+}
+
 // insertChunk executes a single multi-value INSERT statement for a chunk of rows.
-func (b *BulkInserter) insertChunk(ctx context.Context, db DBTX, rows [][]any) error {
+// conflictClause is appended verbatim (empty for a plain INSERT).
+func (b *BulkInserter) insertChunk(ctx context.Context, db DBTX, rows [][]any, conflictClause string) error {
 	if len(rows) == 0 {
 		return nil
 	}
@@ -135,6 +166,7 @@ func (b *BulkInserter) insertChunk(ctx context.Context, db DBTX, rows [][]any) e
 		valueClauses[i] = rowPlaceholder
 	}
 	sb.WriteString(strings.Join(valueClauses, ", "))
+	sb.WriteString(conflictClause)
 
 	// Flatten rows into args slice
 	args := make([]any, 0, len(rows)*b.valueCount)
@@ -241,3 +273,149 @@ func (b *BulkInserter) upsertChunk(ctx context.Context, db DBTX, rows [][]any, c
 
 	return nil
 }
+
+// UpsertWithExpression executes a bulk INSERT with ON CONFLICT DO UPDATE, setting each
+// column in updates to a SQL expression instead of excluded.<col>. This allows increment
+// semantics that Upsert can't express.
+//
+// Expressions are spliced into the statement, so they are restricted to column names,
+// excluded.<col> references, integer literals, whitespace and the +, - and * operators.
+// Invalid column names or expressions are rejected before anything is executed.
+//
+// Example:
+//
+//	inserter := sqlcext.NewBulkInserter("word_counts", []string{"word", "count"}, 100)
+//	err := inserter.UpsertWithExpression(ctx, tx, rows, []string{"word"},
+//	    map[string]string{"count": "count + excluded.count"})
+func (b *BulkInserter) UpsertWithExpression(ctx context.Context, db DBTX, rows [][]any, conflictColumns []string, updates map[string]string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if len(updates) == 0 {
+		return fmt.Errorf("bulk upsert: no update expressions")
+	}
+
+	// Sorted so the generated statement is stable
+	columns := make([]string, 0, len(updates))
+	for col := range updates {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+
+	assignments := make([]string, len(columns))
+	for i, col := range columns {
+		if !isIdentifier(col) {
+			return fmt.Errorf("bulk upsert: invalid update column %q", col)
+		}
+		expr := updates[col]
+		if !isUpsertExpression(expr) {
+			return fmt.Errorf("bulk upsert: invalid expression for %s: %q", col, expr)
+		}
+		assignments[i] = col + " = " + expr
+	}
+
+	conflictClause := " ON CONFLICT (" + strings.Join(conflictColumns, ", ") + ") DO UPDATE SET " + strings.Join(assignments, ", ")
+
+	for i := 0; i < len(rows); i += b.batchSize {
+		end := i + b.batchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		chunk := rows[i:end]
+		if err := b.insertChunk(ctx, db, chunk, conflictClause); err != nil {
+			return fmt.Errorf("bulk upsert chunk [%d:%d]: %w", i, end, err)
+		}
+	}
+
+	return nil
+}
+
+// isIdentifier reports whether s is a non-empty run of letters, digits and underscores.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// isUpsertExpression reports whether expr only uses operands and operators allowed by
+// UpsertWithExpression. The only table qualifier allowed is excluded.
+func isUpsertExpression(expr string) bool {
+	if strings.Contains(expr, "--") {
+		return false // Line comment
+	}
+	operands := strings.FieldsFunc(expr, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '+' || r == '-' || r == '*'
+	})
+	if len(operands) == 0 {
+		return false
+	}
+	for _, operand := range operands {
+		if col, ok := strings.CutPrefix(operand, "excluded."); ok {
+			operand = col
+		}
+		if !isIdentifier(operand) {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteWhere deletes the rows whose idColumn is in ids, chunked to batchSize
+// (DELETE FROM table WHERE idColumn IN (?, ?, ...)). Missing IDs are ignored.
+//
+// Example:
+//
+//	deleter := sqlcext.NewBulkInserter("note_tags", []string{"note_id", "tag_id"}, 100)
+//	err := deleter.DeleteWhere(ctx, tx, "note_id", noteIDs)
+func (b *BulkInserter) DeleteWhere(ctx context.Context, db DBTX, idColumn string, ids []int64) error {
+	return b.execInChunks(ctx, db, ids, func(placeholders string) string {
+		return "DELETE FROM " + b.table + " WHERE " + idColumn + " IN (" + placeholders + ")"
+	})
+}
+
+// SoftDeleteWhere sets deletedAtColumn to CURRENT_TIMESTAMP on the rows whose idColumn is in ids,
+// chunked to batchSize. Rows that are already soft-deleted keep their original timestamp.
+// CURRENT_TIMESTAMP (rather than a bound time.Time) keeps the stored format comparable
+// with SQLite's datetime() like the single-row soft deletes.
+//
+// Example:
+//
+//	deleter := sqlcext.NewBulkInserter("notes", nil, 100)
+//	err := deleter.SoftDeleteWhere(ctx, tx, "id", noteIDs, "deleted_at")
+func (b *BulkInserter) SoftDeleteWhere(ctx context.Context, db DBTX, idColumn string, ids []int64, deletedAtColumn string) error {
+	return b.execInChunks(ctx, db, ids, func(placeholders string) string {
+		return "UPDATE " + b.table + " SET " + deletedAtColumn + " = CURRENT_TIMESTAMP WHERE " +
+			idColumn + " IN (" + placeholders + ") AND " + deletedAtColumn + " IS NULL"
+	})
+}
+
+// execInChunks runs the statement built by query once per batchSize IDs,
+// passing the chunk's "?, ?, ..." placeholder list.
+func (b *BulkInserter) execInChunks(ctx context.Context, db DBTX, ids []int64, query func(placeholders string) string) error {
+	for i := 0; i < len(ids); i += b.batchSize {
+		end := i + b.batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		chunk := ids[i:end]
+		args := make([]any, len(chunk))
+		for j, id := range chunk {
+			args[j] = id
+		}
+
+		placeholders := strings.Repeat("?, ", len(chunk)-1) + "?"
+		if _, err := db.ExecContext(ctx, query(placeholders), args...); err != nil {
+			return fmt.Errorf("bulk delete chunk [%d:%d]: %w", i, end, err)
+		}
+	}
+
+	return nil
+}
@@ -460,6 +460,216 @@ func TestBulkInserter_Upsert_EmptyRows(t *testing.T) {
 	}
 }
 
+func TestBulkInserter_UpsertWithExpression_Increments(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE word_counts (word TEXT PRIMARY KEY, count INTEGER NOT NULL)`); err != nil {
+		t.Fatalf("failed to create word_counts table: %v", err)
+	}
+
+	// Batch size 2 so the second call also exercises chunking
+	inserter := NewBulkInserter("word_counts", []string{"word", "count"}, 2)
+	updates := map[string]string{"count": "count + excluded.count"}
+
+	rows := [][]any{{"go", 2}, {"sqlite", 1}, {"notes", 5}}
+	if err := inserter.UpsertWithExpression(context.Background(), db, rows, []string{"word"}, updates); err != nil {
+		t.Fatalf("first UpsertWithExpression failed: %v", err)
+	}
+	rows = [][]any{{"go", 3}, {"sqlite", 1}, {"brain", 4}}
+	if err := inserter.UpsertWithExpression(context.Background(), db, rows, []string{"word"}, updates); err != nil {
+		t.Fatalf("second UpsertWithExpression failed: %v", err)
+	}
+
+	want := map[string]int{"go": 5, "sqlite": 2, "notes": 5, "brain": 4}
+	for word, wantCount := range want {
+		var count int
+		if err := db.QueryRow("SELECT count FROM word_counts WHERE word = ?", word).Scan(&count); err != nil {
+			t.Fatalf("failed to query %q: %v", word, err)
+		}
+		if count != wantCount {
+			t.Errorf("count(%q) = %d, want %d", word, count, wantCount)
+		}
+	}
+}
+
+func TestBulkInserter_UpsertWithExpression_RejectsInvalidExpressions(t *testing.T) {
+	db := setupMetaTestDB(t)
+	defer db.Close()
+
+	inserter := NewBulkInserter("note_meta", []string{"note_id", "key", "value"}, 100)
+	rows := [][]any{{1, "author", "Jane"}}
+
+	tests := []struct {
+		name    string
+		updates map[string]string
+	}{
+		{"no updates", map[string]string{}},
+		{"empty expression", map[string]string{"value": " "}},
+		{"statement separator", map[string]string{"value": "excluded.value; DROP TABLE note_meta"}},
+		{"function call", map[string]string{"value": "lower(excluded.value)"}},
+		{"string literal", map[string]string{"value": "'x'"}},
+		{"comment", map[string]string{"value": "value -- comment"}},
+		{"other qualifier", map[string]string{"value": "note_meta.value"}},
+		{"invalid column", map[string]string{"value = 1, key": "excluded.key"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := inserter.UpsertWithExpression(context.Background(), db, rows, []string{"note_id", "key"}, tt.updates)
+			if err == nil {
+				t.Fatalf("UpsertWithExpression(%v) succeeded, want error", tt.updates)
+			}
+		})
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM note_meta").Scan(&count); err != nil {
+		t.Fatalf("failed to query count: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no rows after rejected upserts, got %d", count)
+	}
+}
+
+func TestBulkInserter_InsertOrIgnore(t *testing.T) {
+	db := setupMetaTestDB(t)
+	defer db.Close()
+
+	inserter := NewBulkInserter("note_meta", []string{"note_id", "key", "value"}, 2)
+	ctx := context.Background()
+
+	if err := inserter.InsertOrIgnore(ctx, db, [][]any{{1, "author", "Jane"}}); err != nil {
+		t.Fatalf("InsertOrIgnore failed: %v", err)
+	}
+
+	// Conflicts within the batch and with existing rows are skipped, spanning chunks
+	rows := [][]any{
+		{1, "author", "John"},
+		{1, "status", "draft"},
+		{1, "status", "final"},
+		{2, "author", "Alex"},
+	}
+	if err := inserter.InsertOrIgnore(ctx, db, rows); err != nil {
+		t.Fatalf("InsertOrIgnore with conflicts failed: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM note_meta").Scan(&count); err != nil {
+		t.Fatalf("failed to count rows: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 rows, got %d", count)
+	}
+
+	var author, status string
+	if err := db.QueryRow("SELECT value FROM note_meta WHERE note_id = 1 AND key = 'author'").Scan(&author); err != nil {
+		t.Fatalf("failed to query author: %v", err)
+	}
+	if err := db.QueryRow("SELECT value FROM note_meta WHERE note_id = 1 AND key = 'status'").Scan(&status); err != nil {
+		t.Fatalf("failed to query status: %v", err)
+	}
+	if author != "Jane" || status != "draft" {
+		t.Errorf("expected first values to win, got author=%q status=%q", author, status)
+	}
+}
+
+// seedBulkRows inserts n rows into test_table and returns their IDs.
+func seedBulkRows(tb testing.TB, db *sql.DB, n int) []int64 {
+	tb.Helper()
+
+	rows := make([][]any, n)
+	for i := range rows {
+		rows[i] = []any{fmt.Sprintf("test%d", i), "value", i}
+	}
+	if err := NewBulkInserter("test_table", []string{"name", "value", "count"}, 100).Insert(context.Background(), db, rows); err != nil {
+		tb.Fatalf("seed failed: %v", err)
+	}
+
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return ids
+}
+
+func countRows(t *testing.T, db *sql.DB, where string) int {
+	t.Helper()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM test_table WHERE " + where).Scan(&count); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	return count
+}
+
+func TestBulkInserter_DeleteWhere_Chunking(t *testing.T) {
+	db := setupBulkTestDB(t)
+	defer db.Close()
+
+	ids := seedBulkRows(t, db, 250)
+	deleter := NewBulkInserter("test_table", nil, 100)
+
+	// 201 IDs span three chunks; 9999 does not exist
+	toDelete := append(ids[:201:201], 9999)
+	if err := deleter.DeleteWhere(context.Background(), db, "id", toDelete); err != nil {
+		t.Fatalf("DeleteWhere failed: %v", err)
+	}
+
+	if got := countRows(t, db, "1 = 1"); got != 49 {
+		t.Errorf("expected 49 remaining rows, got %d", got)
+	}
+	if got := countRows(t, db, "id <= 201"); got != 0 {
+		t.Errorf("expected deleted IDs to be gone, %d remain", got)
+	}
+}
+
+func TestBulkInserter_DeleteWhere_EmptyIDs(t *testing.T) {
+	db := setupBulkTestDB(t)
+	defer db.Close()
+
+	seedBulkRows(t, db, 3)
+	if err := NewBulkInserter("test_table", nil, 100).DeleteWhere(context.Background(), db, "id", nil); err != nil {
+		t.Fatalf("DeleteWhere failed: %v", err)
+	}
+	if got := countRows(t, db, "1 = 1"); got != 3 {
+		t.Errorf("expected no rows deleted, got %d remaining", got)
+	}
+}
+
+func TestBulkInserter_SoftDeleteWhere(t *testing.T) {
+	db := setupBulkTestDB(t)
+	defer db.Close()
+
+	if _, err := db.Exec("ALTER TABLE test_table ADD COLUMN deleted_at TIMESTAMP NULL"); err != nil {
+		t.Fatalf("failed to add deleted_at: %v", err)
+	}
+	ids := seedBulkRows(t, db, 150)
+
+	// Row 1 is already soft-deleted and must keep its timestamp
+	if _, err := db.Exec("UPDATE test_table SET deleted_at = '2020-01-01 00:00:00' WHERE id = 1"); err != nil {
+		t.Fatalf("failed to pre-delete row: %v", err)
+	}
+
+	deleter := NewBulkInserter("test_table", nil, 100)
+	if err := deleter.SoftDeleteWhere(context.Background(), db, "id", ids[:120], "deleted_at"); err != nil {
+		t.Fatalf("SoftDeleteWhere failed: %v", err)
+	}
+
+	if got := countRows(t, db, "deleted_at IS NOT NULL"); got != 120 {
+		t.Errorf("expected 120 soft-deleted rows, got %d", got)
+	}
+	if got := countRows(t, db, "id = 1 AND deleted_at = '2020-01-01 00:00:00'"); got != 1 {
+		t.Error("expected already-deleted row to keep its timestamp")
+	}
+	if got := countRows(t, db, "deleted_at > datetime('now', '-1 minute')"); got != 119 {
+		t.Errorf("expected 119 rows deleted just now in datetime() format, got %d", got)
+	}
+}
+
 // Benchmark tests
 func BenchmarkBulkInserter_Insert_Small(b *testing.B) {
 	db := setupBulkTestDB(&testing.T{})
@@ -555,3 +765,43 @@ func BenchmarkLoopInsert_Large(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkBulkInserter_DeleteWhere_Large(b *testing.B) {
+	db := setupBulkTestDB(&testing.T{})
+	defer db.Close()
+
+	deleter := NewBulkInserter("test_table", nil, 100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db.Exec("DELETE FROM test_table")
+		db.Exec("DELETE FROM sqlite_sequence WHERE name = 'test_table'")
+		ids := seedBulkRows(b, db, 1000)
+		b.StartTimer()
+
+		if err := deleter.DeleteWhere(context.Background(), db, "id", ids); err != nil {
+			b.Fatalf("DeleteWhere failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkLoopDelete_Large(b *testing.B) {
+	db := setupBulkTestDB(&testing.T{})
+	defer db.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		db.Exec("DELETE FROM test_table")
+		db.Exec("DELETE FROM sqlite_sequence WHERE name = 'test_table'")
+		ids := seedBulkRows(b, db, 1000)
+		b.StartTimer()
+
+		for _, id := range ids {
+			if _, err := db.Exec("DELETE FROM test_table WHERE id = ?", id); err != nil {
+				b.Fatalf("Delete failed: %v", err)
+			}
+		}
+	}
+}
@@ -6,9 +6,12 @@ import (
 )
 
 type CTEQuerier struct {
-	db           DB
-	treeQuery    string
-	subtreeQuery string
+	db             DB
+	treeQuery      string
+	subtreeQuery   string
+	reachableQuery string
+	orphanedQuery  string
+	ancestorsQuery string
 }
 
 func NewCTEQuerier(db DB) *CTEQuerier {
@@ -44,9 +47,70 @@ WITH RECURSIVE subtree(id, name, parent_id, path, description, position, is_syst
 )
 SELECT id, name, parent_id, path, description, position, is_system, depth FROM subtree ORDER BY path`
 
+	// BFS over outgoing links. UNION (not UNION ALL) drops repeated (id, distance)
+	// pairs so cycles and diamonds stay bounded by maxDepth. Trashed notes are not traversed.
+	q.reachableQuery = `
+WITH RECURSIVE reachable(id, distance) AS (
+  SELECT ?, 0
+  
+  UNION
+  
+  SELECT l.dest_id, reachable.distance + 1
+  FROM reachable
+  JOIN links l ON l.src_id = reachable.id
+  JOIN notes n ON n.id = l.dest_id AND n.deleted_at IS NULL
+  WHERE reachable.distance < ?
+)
+SELECT id, MIN(distance) AS distance FROM reachable
+WHERE id != ?
+GROUP BY id
+ORDER BY distance, id
+LIMIT ?`
+
+	// Walks the whole tree for depths, then keeps leaves (no child rows) that no note
+	// references. Trashed notes count, since restoring them needs their collection.
+	// System collections are never reported.
+	q.orphanedQuery = `
+WITH RECURSIVE tree(id, name, parent_id, path, description, position, is_system, depth) AS (
+  SELECT c.id, c.name, c.parent_id, c.path, c.description, c.position, c.is_system, 0
+  FROM collections c
+  WHERE c.parent_id IS NULL
+  
+  UNION ALL
+  
+  SELECT c.id, c.name, c.parent_id, c.path, c.description, c.position, c.is_system, tree.depth + 1
+  FROM collections c, tree
+  WHERE c.parent_id = tree.id
+)
+SELECT tree.id, tree.name, tree.parent_id, tree.path, tree.description, tree.position, tree.is_system, tree.depth
+FROM tree
+LEFT JOIN notes n ON n.collection_id = tree.id
+LEFT JOIN collections child ON child.parent_id = tree.id
+WHERE n.id IS NULL AND child.id IS NULL AND tree.is_system = 0
+ORDER BY tree.path`
+
+	// Walks up the parent_id chain from the target. up counts steps from the target, so
+	// ordering by it descending yields root first. The step limit guards against cycles.
+	q.ancestorsQuery = `
+WITH RECURSIVE ancestors(id, name, parent_id, path, up) AS (
+  SELECT c.id, c.name, c.parent_id, c.path, 0
+  FROM collections c
+  WHERE c.id = ?
+  
+  UNION ALL
+  
+  SELECT c.id, c.name, c.parent_id, c.path, ancestors.up + 1
+  FROM collections c, ancestors
+  WHERE c.id = ancestors.parent_id AND ancestors.up < ?
+)
+SELECT id, name, path FROM ancestors ORDER BY up DESC`
+
 	return q
 }
 
+// maxBreadcrumbDepth bounds the ancestor walk of GetCollectionPath.
+const maxBreadcrumbDepth = 256
+
 func (q *CTEQuerier) GetCollectionTree(ctx context.Context, maxDepth int) ([]CollectionTreeRow, error) {
 	rows, err := q.db.QueryContext(ctx, q.treeQuery, maxDepth)
 	if err != nil {
@@ -92,3 +156,79 @@ func (q *CTEQuerier) GetCollectionSubtree(ctx context.Context, collectionID int6
 
 	return results, nil
 }
+
+// GetOrphanedCollections returns non-system collections that have no notes and no children.
+// Removing them can orphan their parents in turn; those are reported on the next call.
+func (q *CTEQuerier) GetOrphanedCollections(ctx context.Context) ([]CollectionTreeRow, error) {
+	rows, err := q.db.QueryContext(ctx, q.orphanedQuery)
+	if err != nil {
+		return nil, fmt.Errorf("orphaned collections query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []CollectionTreeRow
+	for rows.Next() {
+		var r CollectionTreeRow
+		if err := rows.Scan(&r.ID, &r.Name, &r.ParentID, &r.Path, &r.Description, &r.Position, &r.IsSystem, &r.Depth); err != nil {
+			return nil, fmt.Errorf("failed to scan orphaned collection row: %w", err)
+		}
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("orphaned collections iteration failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetReachableNotes returns the notes reachable from noteID by following links
+// up to maxDepth hops, each with its minimum hop distance, nearest first.
+// The start note itself is not included.
+func (q *CTEQuerier) GetReachableNotes(ctx context.Context, noteID int64, maxDepth, limit int) ([]NoteDistanceRow, error) {
+	rows, err := q.db.QueryContext(ctx, q.reachableQuery, noteID, maxDepth, noteID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("reachable notes query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []NoteDistanceRow
+	for rows.Next() {
+		var r NoteDistanceRow
+		if err := rows.Scan(&r.ID, &r.Distance); err != nil {
+			return nil, fmt.Errorf("failed to scan reachable note row: %w", err)
+		}
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reachable notes iteration failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetCollectionPath returns the breadcrumbs from the root collection (depth 0) down to
+// collectionID (deepest). Returns an empty slice if the collection does not exist.
+func (q *CTEQuerier) GetCollectionPath(ctx context.Context, collectionID int64) ([]CollectionBreadcrumb, error) {
+	rows, err := q.db.QueryContext(ctx, q.ancestorsQuery, collectionID, maxBreadcrumbDepth)
+	if err != nil {
+		return nil, fmt.Errorf("collection path query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []CollectionBreadcrumb
+	for rows.Next() {
+		r := CollectionBreadcrumb{Depth: len(results)}
+		if err := rows.Scan(&r.ID, &r.Name, &r.Path); err != nil {
+			return nil, fmt.Errorf("failed to scan collection path row: %w", err)
+		}
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("collection path iteration failed: %w", err)
+	}
+
+	return results, nil
+}
@@ -394,3 +394,213 @@ func TestGetCollectionSubtree_InvalidID(t *testing.T) {
 		t.Errorf("expected empty subtree for invalid ID, got %d items", len(subtree))
 	}
 }
+
+func TestGetCollectionPath_RootToLeaf(t *testing.T) {
+	db := setupCTETestDB(t)
+	defer db.Close()
+
+	ids := createTestCollectionHierarchy(t, db)
+
+	querier := NewCTEQuerier(db)
+	ctx := context.Background()
+
+	crumbs, err := querier.GetCollectionPath(ctx, ids["root1_child1_grandchild1_greatgrandchild1"])
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []CollectionBreadcrumb{
+		{ID: ids["root1"], Name: "root1", Path: "root1", Depth: 0},
+		{ID: ids["root1_child1"], Name: "child1", Path: "root1/child1", Depth: 1},
+		{ID: ids["root1_child1_grandchild1"], Name: "grandchild1", Path: "root1/child1/grandchild1", Depth: 2},
+		{ID: ids["root1_child1_grandchild1_greatgrandchild1"], Name: "greatgrandchild1", Path: "root1/child1/grandchild1/greatgrandchild1", Depth: 3},
+	}
+	if len(crumbs) != len(expected) {
+		t.Fatalf("expected %d breadcrumbs, got %d: %+v", len(expected), len(crumbs), crumbs)
+	}
+	for i, want := range expected {
+		if crumbs[i] != want {
+			t.Errorf("breadcrumb %d: expected %+v, got %+v", i, want, crumbs[i])
+		}
+	}
+
+	root, err := querier.GetCollectionPath(ctx, ids["root2"])
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(root) != 1 || root[0].ID != ids["root2"] || root[0].Depth != 0 {
+		t.Errorf("expected a root collection to be its own only breadcrumb, got %+v", root)
+	}
+
+	missing, err := querier.GetCollectionPath(ctx, 99999)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no breadcrumbs for invalid ID, got %+v", missing)
+	}
+}
+
+func setupLinkGraphTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+
+	schema := `
+		CREATE TABLE notes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			title TEXT NOT NULL,
+			deleted_at TIMESTAMP NULL
+		);
+		CREATE TABLE links (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			src_id INTEGER NOT NULL,
+			dest_id INTEGER
+		);
+	`
+
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	return db
+}
+
+// createTestLinkGraph creates notes A-F with links:
+// A->B->C->D, A->C (shortcut), D->A (cycle), E->A (incoming only), A->unresolved, F trashed with B->F.
+func createTestLinkGraph(t *testing.T, db *sql.DB) map[string]int64 {
+	t.Helper()
+
+	ids := make(map[string]int64)
+	for _, title := range []string{"A", "B", "C", "D", "E", "F"} {
+		result, err := db.Exec("INSERT INTO notes (title) VALUES (?)", title)
+		if err != nil {
+			t.Fatalf("failed to insert note: %v", err)
+		}
+		ids[title], _ = result.LastInsertId()
+	}
+	if _, err := db.Exec("UPDATE notes SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?", ids["F"]); err != nil {
+		t.Fatalf("failed to trash note: %v", err)
+	}
+
+	links := [][2]string{{"A", "B"}, {"B", "C"}, {"C", "D"}, {"A", "C"}, {"D", "A"}, {"E", "A"}, {"B", "F"}}
+	for _, l := range links {
+		if _, err := db.Exec("INSERT INTO links (src_id, dest_id) VALUES (?, ?)", ids[l[0]], ids[l[1]]); err != nil {
+			t.Fatalf("failed to insert link: %v", err)
+		}
+	}
+	if _, err := db.Exec("INSERT INTO links (src_id, dest_id) VALUES (?, NULL)", ids["A"]); err != nil {
+		t.Fatalf("failed to insert unresolved link: %v", err)
+	}
+
+	return ids
+}
+
+func TestGetReachableNotes_MinimumDistance(t *testing.T) {
+	db := setupLinkGraphTestDB(t)
+	defer db.Close()
+
+	ids := createTestLinkGraph(t, db)
+	querier := NewCTEQuerier(db)
+
+	rows, err := querier.GetReachableNotes(context.Background(), ids["A"], 10, 100)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// C is reachable in 2 hops via B but 1 hop directly; A itself (via the cycle), E and F are excluded
+	expected := []NoteDistanceRow{
+		{ID: ids["B"], Distance: 1},
+		{ID: ids["C"], Distance: 1},
+		{ID: ids["D"], Distance: 2},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %d: %+v", len(expected), len(rows), rows)
+	}
+	for i, want := range expected {
+		if rows[i] != want {
+			t.Errorf("row %d: expected %+v, got %+v", i, want, rows[i])
+		}
+	}
+}
+
+func TestGetReachableNotes_DepthAndLimit(t *testing.T) {
+	db := setupLinkGraphTestDB(t)
+	defer db.Close()
+
+	ids := createTestLinkGraph(t, db)
+	querier := NewCTEQuerier(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		maxDepth      int
+		limit         int
+		expectedCount int
+	}{
+		{"depth 0", 0, 100, 0},
+		{"depth 1", 1, 100, 2},
+		{"depth 2", 2, 100, 3},
+		{"limit", 10, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := querier.GetReachableNotes(ctx, ids["A"], tt.maxDepth, tt.limit)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(rows) != tt.expectedCount {
+				t.Errorf("expected %d rows, got %d", tt.expectedCount, len(rows))
+			}
+		})
+	}
+}
+
+func TestGetOrphanedCollections_OnlyEmptyLeaves(t *testing.T) {
+	db := setupCTETestDB(t)
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY AUTOINCREMENT, collection_id INTEGER NOT NULL)`); err != nil {
+		t.Fatalf("failed to create notes table: %v", err)
+	}
+
+	// work/{projects/{alpha, beta}, archive}, inbox (system), empty-root
+	// Notes live in work/projects/alpha only.
+	work := insertTestCollection(t, db, "Work", "work", sql.NullInt64{}, false)
+	projects := insertTestCollection(t, db, "Projects", "work/projects", sql.NullInt64{Int64: work, Valid: true}, false)
+	alpha := insertTestCollection(t, db, "Alpha", "work/projects/alpha", sql.NullInt64{Int64: projects, Valid: true}, false)
+	insertTestCollection(t, db, "Beta", "work/projects/beta", sql.NullInt64{Int64: projects, Valid: true}, false)
+	insertTestCollection(t, db, "Archive", "work/archive", sql.NullInt64{Int64: work, Valid: true}, false)
+	insertTestCollection(t, db, "Inbox", "inbox", sql.NullInt64{}, true)
+	insertTestCollection(t, db, "Empty", "empty", sql.NullInt64{}, false)
+
+	if _, err := db.Exec(`INSERT INTO notes (collection_id) VALUES (?), (?)`, alpha, alpha); err != nil {
+		t.Fatalf("failed to insert notes: %v", err)
+	}
+
+	rows, err := NewCTEQuerier(db).GetOrphanedCollections(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []struct {
+		path  string
+		depth int
+	}{
+		{"empty", 0},
+		{"work/archive", 1},
+		{"work/projects/beta", 2},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d orphaned collections, got %d: %+v", len(expected), len(rows), rows)
+	}
+	for i, want := range expected {
+		if rows[i].Path != want.path || rows[i].Depth != want.depth {
+			t.Errorf("row %d: expected %s at depth %d, got %s at depth %d", i, want.path, want.depth, rows[i].Path, rows[i].Depth)
+		}
+	}
+}
@@ -13,6 +13,7 @@ package sqlcext
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -33,6 +34,10 @@ type FTSQuerier struct {
 	searchQuery        string
 	searchSnippetQuery string
 	countQuery         string
+	// Collection-aware variants, indexed by [withSnippet][inCollection]
+	collectionSearchQueries [2][2]string
+	countInCollectionQuery  string
+	suggestQuery            string
 }
 
 // NewFTSQuerier creates a new FTS querier with the given configuration.
@@ -74,6 +79,13 @@ func NewFTSQuerier(db DB, config FTSConfig) *FTSQuerier {
 	q.searchQuery = q.buildSearchQuery(false)
 	q.searchSnippetQuery = q.buildSearchQuery(true)
 	q.countQuery = q.buildCountQuery()
+	for _, withSnippet := range []bool{false, true} {
+		for _, inCollection := range []bool{false, true} {
+			q.collectionSearchQueries[boolIndex(withSnippet)][boolIndex(inCollection)] = q.buildCollectionSearchQuery(withSnippet, inCollection)
+		}
+	}
+	q.countInCollectionQuery = q.buildCountInCollectionQuery()
+	q.suggestQuery = q.buildSuggestQuery()
 
 	return q
 }
@@ -81,11 +93,45 @@ func NewFTSQuerier(db DB, config FTSConfig) *FTSQuerier {
 // buildSearchQuery constructs the FTS search query string.
 // If withSnippet is true, returns highlighted snippets instead of full body.
 func (q *FTSQuerier) buildSearchQuery(withSnippet bool) string {
-	bodyColumn := "ct.body"
+	return fmt.Sprintf(`
+SELECT 
+    ct.%s,
+    ct.title,
+    %s as body,
+    ct.created_at,
+    -1.0 * rank as score
+FROM %s
+JOIN %s ct ON %s.rowid = ct.%s
+WHERE %s MATCH ?
+ORDER BY rank
+LIMIT ? OFFSET ?`,
+		q.config.IDColumn,
+		q.bodyColumn(withSnippet),
+		q.config.FTSTable,
+		q.config.ContentTable,
+		q.config.FTSTable,
+		q.config.ContentRowID,
+		q.config.FTSTable,
+	)
+}
+
+// bodyColumn returns the body select expression: full body or highlighted snippet.
+func (q *FTSQuerier) bodyColumn(withSnippet bool) string {
 	if withSnippet {
 		// FTS5 snippet function: snippet(table, column_index, before, after, ellipsis, max_tokens)
 		// Column 1 is 'body' (0=title, 1=body in our FTS tables)
-		bodyColumn = fmt.Sprintf("snippet(%s, 1, '<mark>', '</mark>', '...', 32)", q.config.FTSTable)
+		return fmt.Sprintf("snippet(%s, 1, '<mark>', '</mark>', '...', 32)", q.config.FTSTable)
+	}
+	return "ct.body"
+}
+
+// buildCollectionSearchQuery constructs the FTS search query joined with collections.
+// The content table must have a collection_id column referencing collections.id.
+// If inCollection is true, an extra ? parameter restricts results to one collection.
+func (q *FTSQuerier) buildCollectionSearchQuery(withSnippet, inCollection bool) string {
+	collectionFilter := ""
+	if inCollection {
+		collectionFilter = "\n  AND ct.collection_id = ?"
 	}
 
 	return fmt.Sprintf(`
@@ -94,14 +140,58 @@ SELECT
     ct.title,
     %s as body,
     ct.created_at,
-    -1.0 * rank as score
+    -1.0 * rank as score,
+    ct.collection_id,
+    COALESCE(c.path, '') as collection_path
 FROM %s
 JOIN %s ct ON %s.rowid = ct.%s
-WHERE %s MATCH ?
+LEFT JOIN collections c ON ct.collection_id = c.id
+WHERE %s MATCH ?%s
 ORDER BY rank
 LIMIT ? OFFSET ?`,
 		q.config.IDColumn,
-		bodyColumn,
+		q.bodyColumn(withSnippet),
+		q.config.FTSTable,
+		q.config.ContentTable,
+		q.config.FTSTable,
+		q.config.ContentRowID,
+		q.config.FTSTable,
+		collectionFilter,
+	)
+}
+
+// buildCountInCollectionQuery constructs the FTS count query restricted to one collection.
+func (q *FTSQuerier) buildCountInCollectionQuery() string {
+	return fmt.Sprintf(`
+SELECT COUNT(*)
+FROM %s
+JOIN %s ct ON %s.rowid = ct.%s
+WHERE %s MATCH ? AND ct.collection_id = ?`,
+		q.config.FTSTable,
+		q.config.ContentTable,
+		q.config.FTSTable,
+		q.config.ContentRowID,
+		q.config.FTSTable,
+	)
+}
+
+// boolIndex maps false/true to 0/1 for indexing precomputed query variants.
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// buildSuggestQuery constructs the title autocomplete query, best matches first.
+// Duplicate titles are skipped while scanning, so the query has no LIMIT.
+func (q *FTSQuerier) buildSuggestQuery() string {
+	return fmt.Sprintf(`
+SELECT ct.title
+FROM %s
+JOIN %s ct ON %s.rowid = ct.%s
+WHERE %s MATCH ?
+ORDER BY rank`,
 		q.config.FTSTable,
 		q.config.ContentTable,
 		q.config.FTSTable,
@@ -223,3 +313,135 @@ func (q *FTSQuerier) Count(ctx context.Context, query string) (int64, error) {
 
 	return count, nil
 }
+
+// FTSResultWithCollection is an FTS search result with the collection the document belongs to.
+type FTSResultWithCollection struct {
+	FTSSearchResult
+	CollectionID   int64  `json:"collection_id"`
+	CollectionPath string `json:"collection_path"`
+}
+
+// SearchAcrossCollections performs full-text search over all collections and returns each
+// result with its collection ID and path. Body holds a snippet if params.WithSnippet is set.
+// Only valid for content tables with a collection_id column (Mind notes).
+//
+// SECURITY: The query parameter is sanitized via SanitizeFTS5Query() before use,
+// and all parameters are passed via parameterized statements.
+func (q *FTSQuerier) SearchAcrossCollections(ctx context.Context, params FTSSearchParams) ([]FTSResultWithCollection, error) {
+	query := q.collectionSearchQueries[boolIndex(params.WithSnippet)][0]
+	return q.searchWithCollection(ctx, query,
+		SanitizeFTS5Query(params.Query),
+		params.LimitCount,
+		params.OffsetCount,
+	)
+}
+
+// SearchInCollection is SearchAcrossCollections restricted to a single collection.
+// Child collections are not included.
+//
+// SECURITY: The query parameter is sanitized via SanitizeFTS5Query() before use,
+// and all parameters are passed via parameterized statements.
+func (q *FTSQuerier) SearchInCollection(ctx context.Context, params FTSSearchParams, collectionID int64) ([]FTSResultWithCollection, error) {
+	query := q.collectionSearchQueries[boolIndex(params.WithSnippet)][1]
+	return q.searchWithCollection(ctx, query,
+		SanitizeFTS5Query(params.Query),
+		collectionID,
+		params.LimitCount,
+		params.OffsetCount,
+	)
+}
+
+// searchWithCollection runs a collection-aware search query and scans its rows.
+func (q *FTSQuerier) searchWithCollection(ctx context.Context, query string, args ...interface{}) ([]FTSResultWithCollection, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("fts collection search failed: %w", err)
+	}
+	defer rows.Close()
+
+	var results []FTSResultWithCollection
+	for rows.Next() {
+		var r FTSResultWithCollection
+		var body sql.NullString
+		if err := rows.Scan(&r.ID, &r.Title, &body, &r.CreatedAt, &r.Score, &r.CollectionID, &r.CollectionPath); err != nil {
+			return nil, fmt.Errorf("failed to scan fts collection result: %w", err)
+		}
+		if body.Valid {
+			r.Body = body.String
+		}
+		results = append(results, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fts collection search iteration failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// CountInCollection returns the number of documents in a collection matching the search query.
+//
+// SECURITY: The query parameter is sanitized via SanitizeFTS5Query() before use,
+// and passed via parameterized statement.
+func (q *FTSQuerier) CountInCollection(ctx context.Context, query string, collectionID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, q.countInCollectionQuery, SanitizeFTS5Query(query), collectionID)
+
+	var count int64
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("fts count in collection failed: %w", err)
+	}
+
+	return count, nil
+}
+
+// Suggest returns up to limit distinct titles matching prefix (search-as-you-type),
+// best matches first. Only the title column is searched; the last word of prefix
+// may be incomplete. Returns nil if prefix has no letters or digits.
+//
+// SECURITY: The prefix is reduced to a quoted phrase by BuildFTS5PrefixQuery() and
+// passed via parameterized statement.
+func (q *FTSQuerier) Suggest(ctx context.Context, prefix string, limit int) ([]string, error) {
+	match := BuildFTS5PrefixQuery("title", prefix)
+	if match == "" || limit <= 0 {
+		return nil, nil
+	}
+
+	rows, err := q.db.QueryContext(ctx, q.suggestQuery, match)
+	if err != nil {
+		return nil, fmt.Errorf("fts suggest failed: %w", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	var titles []string
+	for len(titles) < limit && rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, fmt.Errorf("failed to scan fts suggestion: %w", err)
+		}
+		if seen[title] {
+			continue
+		}
+		seen[title] = true
+		titles = append(titles, title)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fts suggest iteration failed: %w", err)
+	}
+
+	return titles, nil
+}
+
+// Ping runs a trivial MATCH against the FTS table to check that the index is queryable
+// (e.g., for a health probe). Finding no rows is not an error.
+func (q *FTSQuerier) Ping(ctx context.Context) error {
+	query := fmt.Sprintf(`SELECT rowid FROM %s WHERE %s MATCH 'ping' LIMIT 1`, q.config.FTSTable, q.config.FTSTable)
+
+	var rowid int64
+	err := q.db.QueryRowContext(ctx, query).Scan(&rowid)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("fts ping failed: %w", err)
+	}
+	return nil
+}
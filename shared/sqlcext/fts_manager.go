@@ -0,0 +1,138 @@
+package sqlcext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// FTSManagerDB is the database handle used by FTSManager: it runs DDL and queries.
+// Satisfied by *sql.DB and *sql.Tx.
+type FTSManagerDB interface {
+	DB
+	DBTX
+}
+
+// FTSManager maintains per-collection FTS5 tables over Mind notes.
+//
+// Each table (notes_fts_{collectionID}) has the schema of notes_fts but only indexes the
+// live notes of one collection; triggers on notes keep it in sync as notes are created,
+// edited, moved between collections, trashed, restored, or deleted.
+// Table names are built from int64 IDs only, so no user input reaches the DDL.
+type FTSManager struct {
+	db FTSManagerDB
+}
+
+// NewFTSManager creates a new FTSManager.
+func NewFTSManager(db FTSManagerDB) *FTSManager {
+	return &FTSManager{db: db}
+}
+
+// CollectionFTSTable returns the name of the FTS table of a collection.
+func CollectionFTSTable(collectionID int64) string {
+	return fmt.Sprintf("notes_fts_%d", collectionID)
+}
+
+// CreateCollectionFTSTable creates the FTS table and sync triggers of a collection and
+// indexes the collection's current live notes. It is a no-op if the table already exists.
+func (m *FTSManager) CreateCollectionFTSTable(ctx context.Context, collectionID int64) error {
+	exists, err := m.tableExists(ctx, collectionID)
+	if err != nil || exists {
+		return err
+	}
+
+	table := CollectionFTSTable(collectionID)
+	statements := []string{
+		fmt.Sprintf(`CREATE VIRTUAL TABLE %[1]s USING fts5 (title, body, content = 'notes', content_rowid = 'id')`, table),
+
+		fmt.Sprintf(`CREATE TRIGGER %[1]s_insert AFTER INSERT ON notes
+WHEN new.collection_id = %[2]d AND new.deleted_at IS NULL
+BEGIN
+INSERT INTO %[1]s (rowid, title, body) VALUES (new.id, new.title, COALESCE(new.body, ''));
+END`, table, collectionID),
+
+		fmt.Sprintf(`CREATE TRIGGER %[1]s_update AFTER UPDATE ON notes
+WHEN old.collection_id = %[2]d OR new.collection_id = %[2]d
+BEGIN
+INSERT INTO %[1]s (%[1]s, rowid, title, body)
+SELECT 'delete', old.id, old.title, COALESCE(old.body, '')
+WHERE old.collection_id = %[2]d AND old.deleted_at IS NULL;
+INSERT INTO %[1]s (rowid, title, body)
+SELECT new.id, new.title, COALESCE(new.body, '')
+WHERE new.collection_id = %[2]d AND new.deleted_at IS NULL;
+END`, table, collectionID),
+
+		fmt.Sprintf(`CREATE TRIGGER %[1]s_delete AFTER DELETE ON notes
+WHEN old.collection_id = %[2]d AND old.deleted_at IS NULL
+BEGIN
+INSERT INTO %[1]s (%[1]s, rowid, title, body) VALUES ('delete', old.id, old.title, COALESCE(old.body, ''));
+END`, table, collectionID),
+
+		fmt.Sprintf(`INSERT INTO %[1]s (rowid, title, body)
+SELECT id, title, COALESCE(body, '') FROM notes
+WHERE collection_id = %[2]d AND deleted_at IS NULL`, table, collectionID),
+	}
+
+	for _, stmt := range statements {
+		if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("create collection fts table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// DropCollectionFTSTable drops the FTS table and sync triggers of a collection.
+// It is a no-op if the table does not exist.
+func (m *FTSManager) DropCollectionFTSTable(ctx context.Context, collectionID int64) error {
+	table := CollectionFTSTable(collectionID)
+	statements := []string{
+		fmt.Sprintf(`DROP TRIGGER IF EXISTS %s_insert`, table),
+		fmt.Sprintf(`DROP TRIGGER IF EXISTS %s_update`, table),
+		fmt.Sprintf(`DROP TRIGGER IF EXISTS %s_delete`, table),
+		fmt.Sprintf(`DROP TABLE IF EXISTS %s`, table),
+	}
+
+	for _, stmt := range statements {
+		if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("drop collection fts table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// SearchInCollectionFTS runs a full-text search against the FTS table of one collection.
+// The table is created on first use for collections that predate it (e.g., defaults).
+// Body holds a snippet if params.WithSnippet is set.
+//
+// SECURITY: The query is sanitized by FTSQuerier and passed as a parameter.
+func (m *FTSManager) SearchInCollectionFTS(ctx context.Context, collectionID int64, params FTSSearchParams) ([]FTSSearchResult, error) {
+	if err := m.CreateCollectionFTSTable(ctx, collectionID); err != nil {
+		return nil, err
+	}
+
+	querier := NewFTSQuerier(m.db, FTSConfig{
+		ContentTable: "notes",
+		FTSTable:     CollectionFTSTable(collectionID),
+	})
+	if params.WithSnippet {
+		return querier.SearchWithSnippet(ctx, params)
+	}
+	return querier.Search(ctx, params)
+}
+
+// tableExists reports whether the FTS table of a collection exists.
+func (m *FTSManager) tableExists(ctx context.Context, collectionID int64) (bool, error) {
+	var one int
+	err := m.db.QueryRowContext(ctx,
+		`SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = ?`,
+		CollectionFTSTable(collectionID),
+	).Scan(&one)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check collection fts table: %w", err)
+	}
+	return true, nil
+}
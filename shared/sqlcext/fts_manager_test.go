@@ -0,0 +1,166 @@
+package sqlcext
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// setupCollectionsTestDB creates an in-memory database with a minimal Mind notes schema.
+func setupCollectionsTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:?_fts5=true")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	schema := `
+		CREATE TABLE collections (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL
+		);
+
+		CREATE TABLE notes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			title TEXT NOT NULL,
+			body TEXT,
+			collection_id INTEGER NOT NULL REFERENCES collections(id),
+			created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			deleted_at TIMESTAMP
+		);
+
+		INSERT INTO collections (id, name) VALUES (1, 'Work'), (2, 'Home');
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	return db
+}
+
+// insertCollectionNote inserts a note into a collection and returns its ID.
+func insertCollectionNote(t *testing.T, db *sql.DB, title, body string, collectionID int64) int64 {
+	t.Helper()
+
+	result, err := db.Exec("INSERT INTO notes (title, body, collection_id) VALUES (?, ?, ?)", title, body, collectionID)
+	if err != nil {
+		t.Fatalf("failed to insert note: %v", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatalf("failed to get note id: %v", err)
+	}
+	return id
+}
+
+// searchIDs returns the IDs of the notes of a collection matching query.
+func searchIDs(t *testing.T, m *FTSManager, collectionID int64, query string) []int64 {
+	t.Helper()
+
+	results, err := m.SearchInCollectionFTS(context.Background(), collectionID, FTSSearchParams{Query: query, LimitCount: 10})
+	if err != nil {
+		t.Fatalf("search in collection %d failed: %v", collectionID, err)
+	}
+	ids := make([]int64, len(results))
+	for i, r := range results {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func TestFTSManager_SearchIsolation(t *testing.T) {
+	db := setupCollectionsTestDB(t)
+	ctx := context.Background()
+	m := NewFTSManager(db)
+
+	// Existing notes are backfilled on creation
+	before := insertCollectionNote(t, db, "Golang basics", "goroutines and channels", 1)
+
+	for _, id := range []int64{1, 2} {
+		if err := m.CreateCollectionFTSTable(ctx, id); err != nil {
+			t.Fatalf("failed to create fts table for collection %d: %v", id, err)
+		}
+	}
+	// Creating twice is a no-op and does not duplicate rows
+	if err := m.CreateCollectionFTSTable(ctx, 1); err != nil {
+		t.Fatalf("second create failed: %v", err)
+	}
+
+	work := insertCollectionNote(t, db, "Golang at work", "services", 1)
+	home := insertCollectionNote(t, db, "Golang at home", "hobby projects", 2)
+
+	if ids := searchIDs(t, m, 1, "golang"); len(ids) != 2 || !containsID(ids, before) || !containsID(ids, work) {
+		t.Errorf("collection 1 results = %v, want [%d %d]", ids, before, work)
+	}
+	if ids := searchIDs(t, m, 2, "golang"); len(ids) != 1 || ids[0] != home {
+		t.Errorf("collection 2 results = %v, want [%d]", ids, home)
+	}
+
+	// Moving a note re-indexes it in the target collection only
+	if _, err := db.Exec("UPDATE notes SET collection_id = 2 WHERE id = ?", work); err != nil {
+		t.Fatalf("failed to move note: %v", err)
+	}
+	if ids := searchIDs(t, m, 1, "services"); len(ids) != 0 {
+		t.Errorf("moved note still found in collection 1: %v", ids)
+	}
+	if ids := searchIDs(t, m, 2, "services"); len(ids) != 1 || ids[0] != work {
+		t.Errorf("moved note not found in collection 2: %v", ids)
+	}
+
+	// Trashed notes leave the index, deleted ones too
+	if _, err := db.Exec("UPDATE notes SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?", home); err != nil {
+		t.Fatalf("failed to trash note: %v", err)
+	}
+	if _, err := db.Exec("DELETE FROM notes WHERE id = ?", before); err != nil {
+		t.Fatalf("failed to delete note: %v", err)
+	}
+	if ids := searchIDs(t, m, 1, "golang"); len(ids) != 0 {
+		t.Errorf("collection 1 results after delete = %v, want none", ids)
+	}
+	if ids := searchIDs(t, m, 2, "golang"); len(ids) != 1 || ids[0] != work {
+		t.Errorf("collection 2 results after trash = %v, want [%d]", ids, work)
+	}
+}
+
+func TestFTSManager_DropCollectionFTSTable(t *testing.T) {
+	db := setupCollectionsTestDB(t)
+	ctx := context.Background()
+	m := NewFTSManager(db)
+
+	if err := m.CreateCollectionFTSTable(ctx, 1); err != nil {
+		t.Fatalf("failed to create fts table: %v", err)
+	}
+	if err := m.DropCollectionFTSTable(ctx, 1); err != nil {
+		t.Fatalf("failed to drop fts table: %v", err)
+	}
+	// Dropping a missing table is a no-op
+	if err := m.DropCollectionFTSTable(ctx, 1); err != nil {
+		t.Fatalf("second drop failed: %v", err)
+	}
+
+	var objects int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name LIKE 'notes_fts_1%'`).Scan(&objects); err != nil {
+		t.Fatalf("failed to count schema objects: %v", err)
+	}
+	if objects != 0 {
+		t.Errorf("found %d leftover schema objects after drop", objects)
+	}
+
+	// Triggers are gone, so note writes no longer touch the dropped table
+	insertCollectionNote(t, db, "Golang", "after drop", 1)
+}
+
+// containsID reports whether ids contains id.
+func containsID(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package sqlcext
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -426,6 +427,126 @@ func containsFTS5SyntaxError(err string) bool {
 	return false
 }
 
+// setupCollectionTestDB creates an FTS-indexed notes table whose rows belong to collections.
+// Returns the database and a querier over it.
+func setupCollectionTestDB(t *testing.T) (*sql.DB, *FTSQuerier) {
+	t.Helper()
+
+	db := setupTestDB(t)
+	schema := `
+		CREATE TABLE collections (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			path TEXT NOT NULL
+		);
+		ALTER TABLE test_notes ADD COLUMN collection_id INTEGER NOT NULL DEFAULT 1;
+	`
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatalf("failed to create collection schema: %v", err)
+	}
+
+	return db, NewFTSQuerier(db, FTSConfig{
+		ContentTable: "test_notes",
+		FTSTable:     "test_notes_fts",
+	})
+}
+
+// insertTestCollectionNote inserts a note into a collection, creating the collection by path if needed.
+func insertTestCollectionNote(t *testing.T, db *sql.DB, path, title, body string) {
+	t.Helper()
+
+	var collectionID int64
+	err := db.QueryRow("SELECT id FROM collections WHERE path = ?", path).Scan(&collectionID)
+	if err == sql.ErrNoRows {
+		result, err := db.Exec("INSERT INTO collections (path) VALUES (?)", path)
+		if err != nil {
+			t.Fatalf("failed to insert collection: %v", err)
+		}
+		collectionID, _ = result.LastInsertId()
+	} else if err != nil {
+		t.Fatalf("failed to look up collection: %v", err)
+	}
+
+	if _, err := db.Exec(
+		"INSERT INTO test_notes (title, body, created_at, collection_id) VALUES (?, ?, ?, ?)",
+		title, body, time.Now(), collectionID,
+	); err != nil {
+		t.Fatalf("failed to insert test note: %v", err)
+	}
+}
+
+func TestFTSQuerier_SearchAcrossCollections(t *testing.T) {
+	db, querier := setupCollectionTestDB(t)
+	defer db.Close()
+
+	insertTestCollectionNote(t, db, "work/projects", "Roadmap", "Quarterly roadmap for the search project")
+	insertTestCollectionNote(t, db, "personal", "Reading list", "Books about search engines")
+	insertTestCollectionNote(t, db, "personal", "Groceries", "Milk and bread")
+
+	ctx := context.Background()
+	results, err := querier.SearchAcrossCollections(ctx, FTSSearchParams{Query: "search", LimitCount: 10})
+	if err != nil {
+		t.Fatalf("SearchAcrossCollections() error = %v", err)
+	}
+
+	paths := map[string]string{}
+	for _, r := range results {
+		paths[r.Title] = r.CollectionPath
+		if r.CollectionID == 0 {
+			t.Errorf("result %q has zero collection ID", r.Title)
+		}
+	}
+	expected := map[string]string{"Roadmap": "work/projects", "Reading list": "personal"}
+	if len(paths) != len(expected) {
+		t.Fatalf("expected %d results, got %+v", len(expected), results)
+	}
+	for title, path := range expected {
+		if paths[title] != path {
+			t.Errorf("expected %q in collection %q, got %q", title, path, paths[title])
+		}
+	}
+
+	snippets, err := querier.SearchAcrossCollections(ctx, FTSSearchParams{Query: "roadmap", LimitCount: 10, WithSnippet: true})
+	if err != nil {
+		t.Fatalf("SearchAcrossCollections() with snippet error = %v", err)
+	}
+	if len(snippets) != 1 || !strings.Contains(snippets[0].Body, "<mark>") {
+		t.Errorf("expected one highlighted snippet, got %+v", snippets)
+	}
+}
+
+func TestFTSQuerier_SearchInCollection(t *testing.T) {
+	db, querier := setupCollectionTestDB(t)
+	defer db.Close()
+
+	insertTestCollectionNote(t, db, "work", "Search design", "FTS ranking notes")
+	insertTestCollectionNote(t, db, "personal", "Search engines", "Reading about ranking")
+
+	var personalID int64
+	if err := db.QueryRow("SELECT id FROM collections WHERE path = 'personal'").Scan(&personalID); err != nil {
+		t.Fatalf("failed to look up collection: %v", err)
+	}
+
+	ctx := context.Background()
+	results, err := querier.SearchInCollection(ctx, FTSSearchParams{Query: "ranking", LimitCount: 10}, personalID)
+	if err != nil {
+		t.Fatalf("SearchInCollection() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %+v", results)
+	}
+	if results[0].Title != "Search engines" || results[0].CollectionPath != "personal" || results[0].CollectionID != personalID {
+		t.Errorf("unexpected result: %+v", results[0])
+	}
+
+	count, err := querier.CountInCollection(ctx, "ranking", personalID)
+	if err != nil {
+		t.Fatalf("CountInCollection() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected count 1, got %d", count)
+	}
+}
+
 func BenchmarkFTSQuerier_Search(b *testing.B) {
 	db := setupTestDB(&testing.T{})
 	defer db.Close()
@@ -456,3 +577,77 @@ func BenchmarkFTSQuerier_Search(b *testing.B) {
 		_, _ = querier.Search(ctx, params)
 	}
 }
+
+func TestFTSQuerier_Suggest(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	insertTestNote(t, db, "Golang Basics", "Variables and loops")
+	insertTestNote(t, db, "Golang Advanced", "Generics and goroutines")
+	insertTestNote(t, db, "Python Guide", "Mentions golang in the body only")
+	insertTestNote(t, db, "Golang Basics", "A duplicate title")
+
+	querier := NewFTSQuerier(db, FTSConfig{
+		ContentTable: "test_notes",
+		FTSTable:     "test_notes_fts",
+	})
+	ctx := context.Background()
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"Gol", []string{"Golang Basics", "Golang Advanced"}},
+		{"Py", []string{"Python Guide"}},
+		{"golang ba", []string{"Golang Basics"}},
+		{"Rust", nil},
+		{`"*) OR `, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			got, err := querier.Suggest(ctx, tt.prefix, 5)
+			if err != nil {
+				t.Fatalf("Suggest(%q) failed: %v", tt.prefix, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Suggest(%q) = %v, want %v", tt.prefix, got, tt.want)
+			}
+			for _, title := range tt.want {
+				if !strings.Contains(strings.Join(got, "\n"), title) {
+					t.Errorf("Suggest(%q) = %v, missing %q", tt.prefix, got, title)
+				}
+			}
+		})
+	}
+
+	got, err := querier.Suggest(ctx, "Gol", 1)
+	if err != nil {
+		t.Fatalf("Suggest with limit failed: %v", err)
+	}
+	if len(got) != 1 || !strings.HasPrefix(got[0], "Golang") {
+		t.Errorf("Suggest(%q, 1) = %v, want one Golang title", "Gol", got)
+	}
+}
+
+func TestFTSQuerier_Ping(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	querier := NewFTSQuerier(db, FTSConfig{
+		ContentTable: "test_notes",
+		FTSTable:     "test_notes_fts",
+	})
+	if err := querier.Ping(ctx); err != nil {
+		t.Errorf("Ping on empty index: %v", err)
+	}
+
+	missing := NewFTSQuerier(db, FTSConfig{
+		ContentTable: "test_notes",
+		FTSTable:     "missing_fts",
+	})
+	if err := missing.Ping(ctx); err == nil {
+		t.Error("expected Ping to fail for a missing FTS table")
+	}
+}
@@ -0,0 +1,287 @@
+package sqlcext
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Migrator applies embedded *.sql migrations and records them in schema_migrations.
+//
+// Files are named <version>_<description>.sql and applied in lexicographic order, each in
+// its own transaction. Files may use goose annotations: only the "-- +goose Up" section
+// runs (down migrations are not supported), and "-- +goose StatementBegin/End" mark
+// statements containing semicolons, such as triggers.
+//
+// Databases migrated by goose are adopted on first run: versions recorded as applied in
+// goose_db_version are copied to schema_migrations instead of being applied again.
+type Migrator struct {
+	db     *sql.DB
+	fsys   fs.FS
+	logger *slog.Logger
+}
+
+// MigrationStatus describes one migration file and whether it has been applied.
+type MigrationStatus struct {
+	Version   int64
+	Name      string       // File name, e.g. 20261016090000_add_notes_deleted_at.sql
+	AppliedAt sql.NullTime // Invalid if the migration is pending
+}
+
+// migration is a parsed migration file.
+type migration struct {
+	version    int64
+	name       string
+	statements []string
+}
+
+// NewMigrator creates a Migrator for the *.sql files at the root of fsys (typically an embed.FS).
+func NewMigrator(db *sql.DB, fsys fs.FS, logger *slog.Logger) *Migrator {
+	return &Migrator{db: db, fsys: fsys, logger: logger.With("component", "migrator")}
+}
+
+// Up applies all migrations that are not recorded in schema_migrations, in order.
+// It is a no-op when the database is up to date.
+func (m *Migrator) Up(ctx context.Context) error {
+	migrations, err := m.load()
+	if err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	for _, mig := range migrations {
+		if _, ok := applied[mig.version]; ok {
+			continue
+		}
+		if err := m.apply(ctx, mig); err != nil {
+			return err
+		}
+		m.logger.Info("applied migration", "version", mig.version, "name", mig.name)
+		count++
+	}
+
+	if count > 0 {
+		m.logger.Info("migrations complete", "applied", count, "total", len(migrations))
+	}
+	return nil
+}
+
+// Status lists every migration file in order with the time it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, mig := range migrations {
+		statuses[i] = MigrationStatus{Version: mig.version, Name: mig.name}
+		if at, ok := applied[mig.version]; ok {
+			statuses[i].AppliedAt = sql.NullTime{Time: at, Valid: true}
+		}
+	}
+	return statuses, nil
+}
+
+// apply runs one migration and records it in the same transaction.
+func (m *Migrator) apply(ctx context.Context, mig migration) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin migration %s: %w", mig.name, err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range mig.statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("apply migration %s: %w", mig.name, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+		mig.version, time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("record migration %s: %w", mig.name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %s: %w", mig.name, err)
+	}
+	return nil
+}
+
+// applied creates schema_migrations if needed and returns the applied versions with their times.
+func (m *Migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if _, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL
+)`); err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+	if err := m.adoptGoose(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+		applied[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// adoptGoose copies the versions applied by goose into an empty schema_migrations table.
+func (m *Migrator) adoptGoose(ctx context.Context) error {
+	var tracked int
+	if err := m.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&tracked); err != nil {
+		return fmt.Errorf("count applied migrations: %w", err)
+	}
+	if tracked > 0 {
+		return nil
+	}
+
+	var name string
+	err := m.db.QueryRowContext(ctx,
+		`SELECT name FROM sqlite_master WHERE type = 'table' AND name = 'goose_db_version'`,
+	).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("check goose_db_version: %w", err)
+	}
+
+	// goose appends a row per up/down; the last row of a version decides its state
+	_, err = m.db.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at)
+SELECT g.version_id, g.tstamp
+FROM goose_db_version g
+WHERE g.version_id > 0
+  AND g.is_applied
+  AND g.id = (SELECT MAX(id) FROM goose_db_version WHERE version_id = g.version_id)`)
+	if err != nil {
+		return fmt.Errorf("adopt goose migrations: %w", err)
+	}
+	m.logger.Info("adopted migrations applied by goose")
+	return nil
+}
+
+// load reads and parses the migration files in lexicographic order.
+func (m *Migrator) load() ([]migration, error) {
+	names, err := fs.Glob(m.fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	migrations := make([]migration, 0, len(names))
+	seen := make(map[int64]string, len(names))
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(path.Base(name), "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: file name must start with a positive version number", name)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		seen[version] = name
+
+		data, err := fs.ReadFile(m.fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", name, err)
+		}
+		migrations = append(migrations, migration{
+			version:    version,
+			name:       name,
+			statements: parseMigrationStatements(string(data)),
+		})
+	}
+	return migrations, nil
+}
+
+// parseMigrationStatements returns the statements of the up section of a migration file.
+// Without goose annotations the whole file is the up section. Outside StatementBegin/End
+// blocks, a statement ends at a line ending with a semicolon.
+func parseMigrationStatements(content string) []string {
+	hasUp := strings.Contains(content, "-- +goose Up")
+	inUp := !hasUp
+	inBlock := false
+
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" && !isCommentOnly(stmt) {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "-- +goose Up"):
+			inUp = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose Down"):
+			flush()
+			return statements
+		case strings.HasPrefix(trimmed, "-- +goose StatementBegin"):
+			flush()
+			inBlock = true
+			continue
+		case strings.HasPrefix(trimmed, "-- +goose StatementEnd"):
+			flush()
+			inBlock = false
+			continue
+		}
+		if !inUp {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	flush()
+	return statements
+}
+
+// isCommentOnly reports whether stmt consists only of -- comment lines.
+func isCommentOnly(stmt string) bool {
+	for _, line := range strings.Split(stmt, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package sqlcext
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	_ "modernc.org/sqlite"
+)
+
+// testMigrations holds two goose-style migrations, the second with a trigger.
+var testMigrations = fstest.MapFS{
+	"20250101000000_create_notes.sql": {Data: []byte(`-- +goose Up
+CREATE TABLE notes (
+    id INTEGER PRIMARY KEY,
+    title TEXT NOT NULL
+);
+CREATE INDEX idx_notes_title ON notes (title);
+
+-- +goose Down
+DROP TABLE notes;
+`)},
+	"20250201000000_add_audit.sql": {Data: []byte(`-- +goose Up
+CREATE TABLE audit (note_id INTEGER NOT NULL);
+
+-- +goose StatementBegin
+CREATE TRIGGER notes_audit AFTER INSERT ON notes
+BEGIN
+INSERT INTO audit (note_id) VALUES (new.id);
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER notes_audit;
+DROP TABLE audit;
+`)},
+}
+
+// setupMigratorTestDB opens an empty in-memory database.
+func setupMigratorTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestMigrator(db *sql.DB, fsys fstest.MapFS) *Migrator {
+	return NewMigrator(db, fsys, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestMigrator_UpAndStatus(t *testing.T) {
+	db := setupMigratorTestDB(t)
+	ctx := context.Background()
+	m := newTestMigrator(db, testMigrations)
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if len(statuses) != 2 || statuses[0].AppliedAt.Valid || statuses[1].AppliedAt.Valid {
+		t.Fatalf("expected 2 pending migrations, got %+v", statuses)
+	}
+
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	// Both migrations ran, including the multi-statement trigger
+	if _, err := db.Exec("INSERT INTO notes (title) VALUES ('Hello')"); err != nil {
+		t.Fatalf("insert after migrations failed: %v", err)
+	}
+	var audited int
+	if err := db.QueryRow("SELECT COUNT(*) FROM audit").Scan(&audited); err != nil || audited != 1 {
+		t.Fatalf("expected trigger to audit 1 insert, got %d (err %v)", audited, err)
+	}
+
+	statuses, err = m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	wantVersions := []int64{20250101000000, 20250201000000}
+	for i, s := range statuses {
+		if s.Version != wantVersions[i] || !s.AppliedAt.Valid {
+			t.Errorf("status %d = %+v, want applied version %d", i, s, wantVersions[i])
+		}
+	}
+	if statuses[0].Name != "20250101000000_create_notes.sql" {
+		t.Errorf("unexpected migration name %q", statuses[0].Name)
+	}
+
+	var recorded int
+	if err := db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&recorded); err != nil || recorded != 2 {
+		t.Fatalf("expected 2 rows in schema_migrations, got %d (err %v)", recorded, err)
+	}
+
+	// Running Up again is a no-op
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("second Up failed: %v", err)
+	}
+	again, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !reflect.DeepEqual(statuses, again) {
+		t.Errorf("status changed after idempotent Up:\n%+v\n%+v", statuses, again)
+	}
+}
+
+func TestMigrator_AppliesOnlyNewMigrations(t *testing.T) {
+	db := setupMigratorTestDB(t)
+	ctx := context.Background()
+
+	first := fstest.MapFS{"20250101000000_create_notes.sql": testMigrations["20250101000000_create_notes.sql"]}
+	if err := newTestMigrator(db, first).Up(ctx); err != nil {
+		t.Fatalf("Up with first migration failed: %v", err)
+	}
+	// Re-running the first migration would fail (table exists), so success means it was skipped
+	if err := newTestMigrator(db, testMigrations).Up(ctx); err != nil {
+		t.Fatalf("Up with both migrations failed: %v", err)
+	}
+}
+
+func TestMigrator_FailedMigrationIsNotRecorded(t *testing.T) {
+	db := setupMigratorTestDB(t)
+	ctx := context.Background()
+
+	broken := fstest.MapFS{
+		"20250101000000_broken.sql": {Data: []byte("CREATE TABLE ok (id INTEGER);\nCREATE TABLE;\n")},
+	}
+	m := newTestMigrator(db, broken)
+	if err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "20250101000000_broken.sql") {
+		t.Fatalf("expected error naming the migration, got %v", err)
+	}
+
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'ok'").Scan(&tables); err != nil || tables != 0 {
+		t.Fatalf("failed migration was not rolled back (tables %d, err %v)", tables, err)
+	}
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if statuses[0].AppliedAt.Valid {
+		t.Errorf("failed migration recorded as applied")
+	}
+}
+
+func TestMigrator_AdoptsGooseVersions(t *testing.T) {
+	db := setupMigratorTestDB(t)
+	ctx := context.Background()
+
+	// State left by goose: first migration applied, second applied then rolled back
+	setup := `
+		CREATE TABLE notes (id INTEGER PRIMARY KEY, title TEXT NOT NULL);
+		CREATE INDEX idx_notes_title ON notes (title);
+		CREATE TABLE goose_db_version (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			version_id INTEGER NOT NULL,
+			is_applied INTEGER NOT NULL,
+			tstamp TIMESTAMP DEFAULT (datetime('now'))
+		);
+		INSERT INTO goose_db_version (version_id, is_applied) VALUES
+			(0, 1), (20250101000000, 1), (20250201000000, 1), (20250201000000, 0);
+	`
+	if _, err := db.Exec(setup); err != nil {
+		t.Fatalf("failed to create goose state: %v", err)
+	}
+
+	m := newTestMigrator(db, testMigrations)
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("Up on goose database failed: %v", err)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !statuses[0].AppliedAt.Valid || !statuses[1].AppliedAt.Valid {
+		t.Fatalf("expected both migrations applied, got %+v", statuses)
+	}
+	if _, err := db.Exec("SELECT note_id FROM audit"); err != nil {
+		t.Errorf("rolled-back goose migration was not re-applied: %v", err)
+	}
+}
+
+func TestParseMigrationStatements(t *testing.T) {
+	content := `-- comment before Up is ignored
+-- +goose Up
+-- Leading comment
+CREATE TABLE a (id INTEGER);
+INSERT INTO a (id)
+VALUES (1);
+-- +goose StatementBegin
+CREATE TRIGGER t AFTER INSERT ON a
+BEGIN
+SELECT 1;
+END;
+-- +goose StatementEnd
+-- +goose Down
+DROP TABLE a;
+`
+	got := parseMigrationStatements(content)
+	want := []string{
+		"-- Leading comment\nCREATE TABLE a (id INTEGER);",
+		"INSERT INTO a (id)\nVALUES (1);",
+		"CREATE TRIGGER t AFTER INSERT ON a\nBEGIN\nSELECT 1;\nEND;",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMigrationStatements() = %q, want %q", got, want)
+	}
+
+	// Files without annotations run as a whole
+	if got := parseMigrationStatements("CREATE TABLE b (id INTEGER);\n"); len(got) != 1 {
+		t.Errorf("expected 1 statement without annotations, got %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package sqlcext
 
 import (
 	"strings"
+	"unicode"
 )
 
 // SanitizeFTS5Query escapes special FTS5 characters that could cause syntax errors.
@@ -61,3 +62,24 @@ func isStopWord(word string) bool {
 	}
 	return stopWords[word]
 }
+
+// BuildFTS5PrefixQuery turns typed input into an FTS5 prefix phrase query on one column,
+// e.g. ("title", "golang ba") -> title : "golang ba"*, matching "Golang Basics".
+// Returns "" if the input has no letters or digits.
+//
+// SECURITY: Everything except letters, digits, and spaces is dropped, so the input
+// cannot close the quoted phrase or inject FTS5 operators. column must not be user input.
+func BuildFTS5PrefixQuery(column, input string) string {
+	clean := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return ' '
+	}, input)
+
+	words := strings.Fields(clean)
+	if len(words) == 0 {
+		return ""
+	}
+	return column + ` : "` + strings.Join(words, " ") + `"*`
+}
@@ -214,3 +214,23 @@ func BenchmarkSanitizeFTS5Query(b *testing.B) {
 		})
 	}
 }
+
+func TestBuildFTS5PrefixQuery(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"gol", `title : "gol"*`},
+		{"  Golang   ba ", `title : "Golang ba"*`},
+		{`go" OR body:*`, `title : "go OR body"*`},
+		{"Café", `title : "Café"*`},
+		{`"*()`, ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := BuildFTS5PrefixQuery("title", tt.input); got != tt.want {
+			t.Errorf("BuildFTS5PrefixQuery(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -28,6 +28,8 @@ type FTSSearchParams struct {
 	Query       string `json:"query"`        // Search query text (will be sanitized)
 	LimitCount  int64  `json:"limit_count"`  // Maximum results to return
 	OffsetCount int64  `json:"offset_count"` // Pagination offset
+	// WithSnippet makes collection-aware searches return highlighted snippets instead of the full body
+	WithSnippet bool `json:"with_snippet"`
 }
 
 // FTSResult is a generic interface that FTS result types must implement.
@@ -49,3 +51,17 @@ type CollectionTreeRow struct {
 	IsSystem    bool
 	Depth       int
 }
+
+// CollectionBreadcrumb is one ancestor on the path from the root to a collection.
+type CollectionBreadcrumb struct {
+	ID    int64
+	Name  string
+	Path  string
+	Depth int // 0 for the root
+}
+
+// NoteDistanceRow is a note reachable over links with its minimum hop distance.
+type NoteDistanceRow struct {
+	ID       int64
+	Distance int
+}
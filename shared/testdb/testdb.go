@@ -4,15 +4,18 @@ package testdb
 import (
 	"database/sql"
 	"log/slog"
+	"path/filepath"
 	"testing"
 
-	_ "modernc.org/sqlite"
+	"github.com/nkapatos/mindweaver/shared/database"
 )
 
 // MigrationRunner defines the interface for running service-specific migrations.
 type MigrationRunner func(*sql.DB, *slog.Logger) error
 
-// SetupTestDB creates an in-memory SQLite database with migrations applied.
+// SetupTestDB creates a SQLite database in a temporary directory with migrations applied.
+// It is opened with database.DSN, so every pooled connection has the same pragmas as
+// production (foreign keys enforced, WAL journaling).
 // Returns a configured *sql.DB ready for testing.
 //
 // Usage:
@@ -23,19 +26,13 @@ type MigrationRunner func(*sql.DB, *slog.Logger) error
 func SetupTestDB(t *testing.T, runMigrations MigrationRunner) *sql.DB {
 	t.Helper()
 
-	// Create in-memory database
-	db, err := sql.Open("sqlite", ":memory:")
+	// A file per test: a shared-cache in-memory database would be shared by the whole package
+	db, err := sql.Open("sqlite", database.DSN(filepath.Join(t.TempDir(), "test.db")))
 	if err != nil {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
-
-	// Configure SQLite for testing (fast in-memory mode)
-	if _, err := db.Exec("PRAGMA journal_mode=MEMORY;"); err != nil {
-		t.Fatalf("Failed to set journal mode: %v", err)
-	}
-	if _, err := db.Exec("PRAGMA synchronous=OFF;"); err != nil {
-		t.Fatalf("Failed to set synchronous mode: %v", err)
-	}
+	// Closed before t.TempDir removes the file; closing again in the test is harmless
+	t.Cleanup(func() { db.Close() })
 
 	// Run service-specific migrations
 	logger := NewTestLogger(t)
@@ -0,0 +1,39 @@
+package utils
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"golang guide", "golang guides", 1},
+		{"flaw", "lawn", 2},
+		{"café", "cafe", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Levenshtein(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+		if got := Levenshtein(tt.b, tt.a); got != tt.expected {
+			t.Errorf("Levenshtein(%q, %q) = %d, expected %d", tt.b, tt.a, got, tt.expected)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if got := Similarity("", ""); got != 1 {
+		t.Errorf("Similarity of empty strings = %v, expected 1", got)
+	}
+	if got := Similarity("abc", "xyz"); got != 0 {
+		t.Errorf("Similarity(abc, xyz) = %v, expected 0", got)
+	}
+	if got := Similarity("golang guide", "golang guides"); got < 0.9 {
+		t.Errorf("Similarity(golang guide, golang guides) = %v, expected >= 0.9", got)
+	}
+}
@@ -0,0 +1,32 @@
+-- API keys: management and lookup for ApiKeyMiddleware (SQLite/sqlc)
+-- NOTE: key_hash is the hex sha256 of the key; plaintext keys are never stored
+
+-- name: CreateApiKey :execlastid
+INSERT INTO api_keys (actor_id, key_hash, name, expires_at)
+VALUES (:actor_id, :key_hash, :name, :expires_at);
+
+-- name: GetApiKeyByID :one
+SELECT * FROM api_keys WHERE id = :id;
+
+-- name: GetActiveApiKeyByHash :one
+-- Revoked keys are excluded; expiry is checked by the caller
+SELECT * FROM api_keys WHERE key_hash = :key_hash AND revoked_at IS NULL;
+
+-- name: ListApiKeys :many
+SELECT * FROM api_keys ORDER BY created_at DESC, id DESC;
+
+-- name: ListApiKeysByActor :many
+SELECT * FROM api_keys WHERE actor_id = :actor_id ORDER BY created_at DESC, id DESC;
+
+-- name: RevokeApiKey :execresult
+-- Returns result to check rows affected (0 = not found / already revoked)
+UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = :id AND revoked_at IS NULL;
+
+-- name: TouchApiKeyLastUsed :exec
+UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE id = :id;
+
+-- name: ListKnownActorIDs :many
+-- Filters actor IDs down to actors that have (or had) an API key
+SELECT DISTINCT actor_id FROM api_keys
+WHERE actor_id IN (sqlc.slice('actor_ids'))
+ORDER BY actor_id;
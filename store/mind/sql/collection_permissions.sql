@@ -0,0 +1,23 @@
+-- Collection permissions: per-actor roles checked by PermissionsService (SQLite/sqlc)
+-- NOTE: collections without any permission rows are open to every actor
+
+-- name: UpsertCollectionPermission :exec
+-- Grants role to an actor, replacing any role it already had on the collection
+INSERT INTO collection_permissions (collection_id, actor_id, role)
+VALUES (:collection_id, :actor_id, :role)
+ON CONFLICT (collection_id, actor_id) DO UPDATE SET
+    role = excluded.role,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteCollectionPermission :execresult
+-- Returns result to check rows affected (0 = no grant)
+DELETE FROM collection_permissions WHERE collection_id = :collection_id AND actor_id = :actor_id;
+
+-- name: GetCollectionPermission :one
+SELECT * FROM collection_permissions WHERE collection_id = :collection_id AND actor_id = :actor_id;
+
+-- name: CountCollectionPermissions :one
+SELECT COUNT(*) FROM collection_permissions WHERE collection_id = :collection_id;
+
+-- name: ListCollectionPermissions :many
+SELECT * FROM collection_permissions WHERE collection_id = :collection_id ORDER BY actor_id;
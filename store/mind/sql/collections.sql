@@ -30,6 +30,28 @@ SET name = :name,
     description = :description,
     position = :position,
     is_system = :is_system,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = :id;
+
+-- name: UpdateCollectionPath :exec
+UPDATE collections
+SET path = :path,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = :id;
+
+-- name: UpdateCollectionPosition :exec
+UPDATE collections
+SET position = :position,
+    version = version + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = :id;
+
+-- name: BumpCollectionVersion :exec
+-- Marks a collection as changed when its children are reordered (invalidates its ETag)
+UPDATE collections
+SET version = version + 1,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = :id;
 
@@ -74,7 +96,23 @@ SELECT id, name, parent_id, path, depth FROM descendants WHERE depth > 0 ORDER B
 -- name: CountNotesInCollection :one
 SELECT COUNT(*) as count
 FROM notes
-WHERE collection_id = :collection_id;
+WHERE collection_id = :collection_id AND deleted_at IS NULL;
+
+-- name: CountDescendantNotes :one
+-- Live notes in a collection and all of its descendants
+WITH RECURSIVE descendants AS (
+  SELECT c.id FROM collections c WHERE c.id = :collection_id
+
+  UNION ALL
+
+  SELECT c.id
+  FROM collections c
+  JOIN descendants d ON c.parent_id = d.id
+)
+SELECT COUNT(n.id) as count
+FROM notes n
+JOIN descendants d ON n.collection_id = d.id
+WHERE n.deleted_at IS NULL;
 
 -- name: FindOrCreateCollectionByPath :one
 -- Helper: find existing collection by path (creation in Go)
@@ -95,10 +133,45 @@ SELECT
     c.parent_id,
     COUNT(n.id) as notes_count
 FROM collections c
-LEFT JOIN notes n ON c.id = n.collection_id
+LEFT JOIN notes n ON c.id = n.collection_id AND n.deleted_at IS NULL
 GROUP BY c.id
 ORDER BY c.path;
 
+-- ========================================
+-- Per-Collection Statistics
+-- ========================================
+
+-- name: CountNotesAndTemplatesInCollection :one
+SELECT
+    CAST(COALESCE(SUM(CASE WHEN is_template = 1 THEN 0 ELSE 1 END), 0) AS INTEGER) AS note_count,
+    CAST(COALESCE(SUM(CASE WHEN is_template = 1 THEN 1 ELSE 0 END), 0) AS INTEGER) AS template_count
+FROM notes
+WHERE collection_id = :collection_id AND deleted_at IS NULL;
+
+-- name: GetCollectionLastModified :one
+SELECT updated_at
+FROM notes
+WHERE collection_id = :collection_id AND deleted_at IS NULL
+ORDER BY updated_at DESC
+LIMIT 1;
+
+-- name: ListTopTagsInCollection :many
+SELECT t.name, COUNT(*) AS count
+FROM note_tags nt
+JOIN tags t ON t.id = nt.tag_id
+JOIN notes n ON n.id = nt.note_id
+WHERE n.collection_id = :collection_id AND n.deleted_at IS NULL
+GROUP BY t.id
+ORDER BY count DESC, t.name
+LIMIT :limit;
+
+-- name: CountLinksInCollection :one
+-- Outgoing links from live notes in the collection (resolved or not)
+SELECT COUNT(*)
+FROM links l
+JOIN notes n ON n.id = l.src_id
+WHERE n.collection_id = :collection_id AND n.deleted_at IS NULL;
+
 -- ========================================
 -- Paginated Queries (AIP-158)
 -- ========================================
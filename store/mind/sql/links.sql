@@ -15,10 +15,30 @@ SELECT * FROM links WHERE id = :id;
 SELECT * FROM links ORDER BY id;
 
 -- name: ListLinksBySrcID :many
-SELECT * FROM links WHERE src_id = :src_id;
+-- Outgoing links, skipping those to trashed notes (unresolved links have no destination yet)
+SELECT links.* FROM links
+LEFT JOIN notes ON notes.id = links.dest_id
+WHERE links.src_id = :src_id AND notes.deleted_at IS NULL;
 
 -- name: ListLinksByDestID :many
-SELECT * FROM links WHERE dest_id = :dest_id;
+-- Incoming links (backlinks) from notes that are not trashed
+SELECT links.* FROM links
+JOIN notes ON notes.id = links.src_id
+WHERE links.dest_id = :dest_id AND notes.deleted_at IS NULL;
+
+-- name: ListResolvedLinksBySrcIDs :many
+-- Outgoing links with a known destination (e.g., to draw the link graph of a set of notes)
+SELECT * FROM links
+WHERE src_id IN (sqlc.slice('src_ids')) AND dest_id IS NOT NULL
+ORDER BY src_id, dest_id;
+
+-- name: ListLinkGraphEdges :many
+-- Distinct resolved edges between notes of a set (both ends in the set)
+SELECT DISTINCT src_id, dest_id FROM links
+WHERE
+    src_id IN (sqlc.slice('src_ids'))
+    AND dest_id IN (sqlc.slice('dest_ids'))
+ORDER BY src_id, dest_id;
 
 -- name: SearchLinksByDisplayText :many
 SELECT * FROM links WHERE display_text LIKE :display_text_pattern;
@@ -37,6 +57,13 @@ WHERE resolved IN (0, -1)
 ORDER BY id
 LIMIT :limit;
 
+-- name: ListUnresolvedLinksAfterID :many
+-- Keyset page of pending (0) and broken (-1) links for bulk resolution
+SELECT * FROM links
+WHERE resolved IN (0, -1) AND id > :after_id
+ORDER BY id
+LIMIT :limit;
+
 -- name: FindUnresolvedLinksByDestTitle :many
 -- Unresolved links by destination title (for resolution)
 SELECT * FROM links
@@ -57,6 +84,13 @@ SET dest_id = :dest_id,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = :id;
 
+-- name: UpdateLinkDisplayText :exec
+-- Rewrites a link's display text (e.g., a path-qualified target after its note moved)
+UPDATE links
+SET display_text = :display_text,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = :id;
+
 -- name: MarkLinkBroken :exec
 UPDATE links
 SET resolved = -1,
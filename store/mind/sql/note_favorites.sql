@@ -0,0 +1,39 @@
+-- Note favorites: per-actor favorite notes with a manual order (SQLite/sqlc)
+
+-- name: GetNextFavoritePosition :one
+-- Position that appends a favorite to the end of the actor's list
+SELECT CAST(COALESCE(MAX(position) + 1, 0) AS INTEGER) AS next_position
+FROM note_favorites
+WHERE actor_id = :actor_id;
+
+-- name: CreateNoteFavorite :exec
+-- Favoriting an already favorited note keeps its position
+INSERT OR IGNORE INTO note_favorites (actor_id, note_id, position)
+VALUES (:actor_id, :note_id, :position);
+
+-- name: DeleteNoteFavorite :execresult
+-- Returns result to check rows affected (0 = not favorited).
+DELETE FROM note_favorites WHERE actor_id = :actor_id AND note_id = :note_id;
+
+-- name: UpdateNoteFavoritePosition :execresult
+-- Returns result to check rows affected (0 = not favorited).
+UPDATE note_favorites SET position = :position
+WHERE actor_id = :actor_id AND note_id = :note_id;
+
+-- name: IsNoteFavorited :one
+SELECT EXISTS (
+    SELECT 1 FROM note_favorites WHERE actor_id = :actor_id AND note_id = :note_id
+) AS favorited;
+
+-- name: ListFavoriteNotes :many
+-- Live favorite notes of an actor in their order
+SELECT n.* FROM notes n
+JOIN note_favorites f ON f.note_id = n.id
+WHERE f.actor_id = :actor_id AND n.deleted_at IS NULL
+ORDER BY f.position, f.created_at, n.id
+LIMIT :limit OFFSET :offset;
+
+-- name: CountFavoriteNotes :one
+SELECT COUNT(*) FROM notes n
+JOIN note_favorites f ON f.note_id = n.id
+WHERE f.actor_id = :actor_id AND n.deleted_at IS NULL;
@@ -0,0 +1,15 @@
+-- Note mentions: @handles in note bodies resolved to actors (SQLite/sqlc)
+
+-- name: CreateNoteMention :exec
+INSERT OR IGNORE INTO note_mentions (note_id, actor_id)
+VALUES (:note_id, :actor_id);
+
+-- name: DeleteNoteMentionsByNoteID :exec
+DELETE FROM note_mentions WHERE note_id = :note_id;
+
+-- name: ListNotesMentioningActor :many
+-- Live notes mentioning an actor, most recently updated first
+SELECT n.* FROM notes n
+INNER JOIN note_mentions nm ON nm.note_id = n.id
+WHERE nm.actor_id = :actor_id AND n.deleted_at IS NULL
+ORDER BY n.updated_at DESC, n.id;
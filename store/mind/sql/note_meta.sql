@@ -9,6 +9,12 @@ SELECT * FROM note_meta WHERE key IN (:keys);
 -- name: ListNoteMetaByKeyValuePattern :many
 SELECT * FROM note_meta WHERE key = :key AND value LIKE :value_pattern;
 
+-- name: ListNoteMetaByKeyInCollection :many
+SELECT note_meta.* FROM note_meta
+JOIN notes ON notes.id = note_meta.note_id
+WHERE note_meta.key = :key AND notes.collection_id = :collection_id AND notes.deleted_at IS NULL
+ORDER BY note_meta.note_id;
+
 -- name: ListDistinctNoteMetaKeys :many
 SELECT DISTINCT key FROM note_meta ORDER BY key;
 
@@ -27,6 +33,9 @@ DO UPDATE SET
 -- name: DeleteNoteMetaByNoteID :exec
 DELETE FROM note_meta WHERE note_id = :note_id;
 
+-- name: DeleteNoteMetaByKey :exec
+DELETE FROM note_meta WHERE note_id = :note_id AND key = :key;
+
 -- name: GetNoteMetaByID :one
 SELECT * FROM note_meta WHERE id = :id;
 
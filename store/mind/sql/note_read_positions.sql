@@ -0,0 +1,12 @@
+-- Note read positions: last scroll position per actor and note (SQLite/sqlc)
+
+-- name: UpsertNoteReadPosition :exec
+INSERT INTO note_read_positions (note_id, actor_id, scroll_position, read_at)
+VALUES (:note_id, :actor_id, :scroll_position, CURRENT_TIMESTAMP)
+ON CONFLICT (note_id, actor_id) DO UPDATE SET
+    scroll_position = excluded.scroll_position,
+    read_at = excluded.read_at;
+
+-- name: GetNoteReadPosition :one
+SELECT scroll_position FROM note_read_positions
+WHERE note_id = :note_id AND actor_id = :actor_id;
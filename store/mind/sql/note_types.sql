@@ -39,7 +39,7 @@ SELECT
     nt.*,
     COUNT(n.id) as notes_count
 FROM note_types nt
-LEFT JOIN notes n ON nt.id = n.note_type_id
+LEFT JOIN notes n ON nt.id = n.note_type_id AND n.deleted_at IS NULL
 WHERE nt.id = :id
 GROUP BY nt.id;
 
@@ -48,7 +48,7 @@ SELECT
     nt.*,
     COUNT(n.id) as notes_count
 FROM note_types nt
-LEFT JOIN notes n ON nt.id = n.note_type_id
+LEFT JOIN notes n ON nt.id = n.note_type_id AND n.deleted_at IS NULL
 GROUP BY nt.id
 ORDER BY nt.id;
 
@@ -59,6 +59,17 @@ ORDER BY nt.id;
 -- name: CheckIfSystemType :one
 SELECT is_system FROM note_types WHERE id = :id;
 
+-- ========================================
+-- Frontmatter Schemas
+-- ========================================
+
+-- name: CreateNoteTypeSchema :exec
+INSERT INTO note_type_schema (note_type_id, required_keys, schema_json)
+VALUES (:note_type_id, :required_keys, :schema_json);
+
+-- name: GetNoteTypeSchema :one
+SELECT * FROM note_type_schema WHERE note_type_id = :note_type_id;
+
 -- ========================================
 -- Paginated Queries (AIP-158)
 -- ========================================
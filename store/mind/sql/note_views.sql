@@ -0,0 +1,26 @@
+-- Note views: last view time and view count per note (SQLite/sqlc)
+
+-- name: UpsertNoteView :exec
+INSERT INTO note_views (note_id, last_viewed_at, view_count)
+VALUES (:note_id, CURRENT_TIMESTAMP, 1)
+ON CONFLICT (note_id) DO UPDATE SET
+    last_viewed_at = excluded.last_viewed_at,
+    view_count = note_views.view_count + 1;
+
+-- name: ListRecentlyUpdatedNotes :many
+-- Live notes updated since the cutoff (e.g., cutoff = '-7 days'), most recent first
+SELECT * FROM notes
+WHERE deleted_at IS NULL
+  AND updated_at >= datetime('now', CAST(sqlc.arg(cutoff) AS TEXT))
+ORDER BY updated_at DESC, id
+LIMIT sqlc.arg(limit);
+
+-- name: ListRecentlyUpdatedOrViewedNotes :many
+-- Live notes updated or viewed since the cutoff, ordered by their latest activity
+SELECT n.* FROM notes n
+LEFT JOIN note_views v ON v.note_id = n.id
+WHERE n.deleted_at IS NULL
+  AND (n.updated_at >= datetime('now', CAST(sqlc.arg(cutoff) AS TEXT))
+       OR v.last_viewed_at >= datetime('now', CAST(sqlc.arg(cutoff) AS TEXT)))
+ORDER BY MAX(n.updated_at, COALESCE(v.last_viewed_at, n.updated_at)) DESC, n.id
+LIMIT sqlc.arg(limit);
@@ -5,24 +5,45 @@ INSERT INTO notes (uuid, title, body, description, frontmatter, note_type_id, is
 VALUES (:uuid, :title, :body, :description, :frontmatter, :note_type_id, :is_template, :collection_id);
 
 -- name: GetNoteByID :one
-SELECT * FROM notes WHERE id = :id;
+SELECT * FROM notes WHERE id = :id AND deleted_at IS NULL;
 
 -- name: GetNoteByUUID :one
+SELECT * FROM notes WHERE uuid = :uuid AND deleted_at IS NULL;
+
+-- name: GetNoteByUUIDWithTrashed :one
+-- Includes trashed notes: their UUID stays taken until permanently deleted (e.g., for upserts)
 SELECT * FROM notes WHERE uuid = :uuid;
 
 -- name: GetNoteByTitle :one
+-- Includes trashed notes: they keep their title reserved until permanently deleted
 SELECT * FROM notes WHERE title = :title AND collection_id = :collection_id LIMIT 1;
 
+-- name: GetLiveNoteByTitleInCollection :one
+-- Live notes only, unlike GetNoteByTitle (e.g., to resolve collection-qualified wiki-links)
+SELECT * FROM notes WHERE collection_id = :collection_id AND title = :title AND deleted_at IS NULL LIMIT 1;
+
 -- name: GetNoteByTitleGlobal :one
 -- Global title lookup across collections
-SELECT * FROM notes WHERE title = :title LIMIT 1;
+SELECT * FROM notes WHERE title = :title AND deleted_at IS NULL LIMIT 1;
+
+-- name: ListNoteIDsByUUIDs :many
+-- Maps UUIDs to IDs after a bulk insert (multi-row INSERT has no per-row last id)
+SELECT id, uuid FROM notes WHERE uuid IN (sqlc.slice('uuids'));
+
+-- name: ListNoteCollectionIDs :many
+-- Maps note IDs to collection IDs, trashed notes included (e.g., to group scheduler flushes)
+SELECT id, collection_id FROM notes WHERE id IN (sqlc.slice('ids'));
+
+-- name: ListNotesByIDs :many
+-- Loads live notes for a set of IDs (e.g., from a recursive CTE in sqlcext); order is not preserved
+SELECT * FROM notes WHERE id IN (sqlc.slice('ids')) AND deleted_at IS NULL;
 
 -- name: ListNotes :many
-SELECT * FROM notes ORDER BY uuid;
+SELECT * FROM notes WHERE deleted_at IS NULL ORDER BY uuid;
 
 -- name: UpdateNoteByID :execresult
 -- Updates note including body content. Increments version for optimistic locking.
--- Returns result to check rows affected (0 = version mismatch / stale note).
+-- Returns result to check rows affected (0 = version mismatch / stale or trashed note).
 UPDATE notes
 SET uuid = :uuid,
     title = :title,
@@ -34,11 +55,20 @@ SET uuid = :uuid,
     is_template = :is_template,
     collection_id = :collection_id,
     version = version + 1
-WHERE id = :id AND version = :version;
+WHERE id = :id AND version = :version AND deleted_at IS NULL;
+
+-- name: UpdateNoteBodyByID :exec
+-- Rewrites note body only (e.g., tag rename backfill). Increments version so
+-- clients holding the old ETag must refetch before writing.
+UPDATE notes
+SET body = :body,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = :id;
 
 -- name: UpdateNoteMetadataByID :exec
 -- Updates metadata fields only (title, description, collection_id, etc.)
--- Does NOT increment version or check version - for non-body updates.
+-- Does NOT increment version or check version - for non-body updates. Trashed notes are left alone.
 UPDATE notes
 SET title = :title,
     description = :description,
@@ -46,11 +76,93 @@ SET title = :title,
     is_template = :is_template,
     collection_id = :collection_id,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = :id;
+WHERE id = :id AND deleted_at IS NULL;
+
+-- name: SoftDeleteNoteByID :one
+-- Moves a note to the trash. No row (sql.ErrNoRows) = not found / already trashed.
+UPDATE notes SET deleted_at = CURRENT_TIMESTAMP WHERE id = :id AND deleted_at IS NULL
+RETURNING collection_id;
+
+-- name: RestoreNoteByID :one
+-- Restores a trashed note. No row (sql.ErrNoRows) = not in trash.
+UPDATE notes SET deleted_at = NULL WHERE id = :id AND deleted_at IS NOT NULL
+RETURNING collection_id;
+
+-- name: PinNoteByID :execresult
+-- Pins a live note at pin_position. Does not touch updated_at or version.
+-- Returns result to check rows affected (0 = not found / trashed).
+UPDATE notes SET is_pinned = 1, pin_position = :pin_position WHERE id = :id AND deleted_at IS NULL;
+
+-- name: UnpinNoteByID :execresult
+-- Returns result to check rows affected (0 = not found / trashed).
+UPDATE notes SET is_pinned = 0, pin_position = 0 WHERE id = :id AND deleted_at IS NULL;
+
+-- name: ListInactiveNoteIDs :many
+-- Live, non-template notes last updated before the cutoff (e.g., cutoff = '-365 days'),
+-- excluding notes already in the archive collections
+SELECT n.id FROM notes n
+JOIN collections c ON c.id = n.collection_id
+WHERE n.deleted_at IS NULL
+  AND n.is_template = 0
+  AND n.updated_at < datetime('now', CAST(sqlc.arg(cutoff) AS TEXT))
+  AND c.path != 'archive'
+  AND c.path NOT LIKE 'archive/%'
+ORDER BY n.id;
+
+-- name: ArchiveNoteByID :execresult
+-- Moves a live note to an archive collection. Leaves updated_at and version alone
+-- so archival is not mistaken for activity.
+-- Returns result to check rows affected (0 = not found / trashed).
+UPDATE notes SET collection_id = :collection_id WHERE id = :id AND deleted_at IS NULL;
+
+-- name: UnarchiveNoteByID :execresult
+-- Moves a live note out of the archive. Touches updated_at so the archiver does not
+-- pick it up again on its next run.
+-- Returns result to check rows affected (0 = not found / trashed).
+UPDATE notes
+SET collection_id = :collection_id,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = :id AND deleted_at IS NULL;
 
--- name: DeleteNoteByID :exec
+-- name: MoveNoteToCollection :execresult
+-- Moves a live note to another collection. Increments version so clients holding
+-- the old ETag must refetch before writing.
+-- Returns result to check rows affected (0 = not found / trashed).
+UPDATE notes
+SET collection_id = :collection_id,
+    updated_at = CURRENT_TIMESTAMP,
+    version = version + 1
+WHERE id = :id AND deleted_at IS NULL;
+
+-- name: MoveNotesBetweenCollections :execresult
+-- Moves every note (live and trashed) from one collection to another, e.g., when
+-- merging collections. Trashed notes move too so a restore lands in the new home.
+UPDATE notes
+SET collection_id = sqlc.arg(target_id),
+    updated_at = CURRENT_TIMESTAMP
+WHERE collection_id = sqlc.arg(source_id);
+
+-- name: DeleteNoteByID :execresult
+-- Permanently deletes a note (live or trashed); links, tags and meta cascade.
 DELETE FROM notes WHERE id = :id;
 
+-- name: PurgeDeletedNotes :execresult
+-- Permanently deletes notes trashed before the cutoff, e.g., cutoff = '-30 days'.
+-- Starter notes of templates are kept: templates.starter_note_id is ON DELETE RESTRICT.
+DELETE FROM notes
+WHERE deleted_at IS NOT NULL
+  AND deleted_at < datetime('now', CAST(sqlc.arg(cutoff) AS TEXT))
+  AND id NOT IN (SELECT starter_note_id FROM templates);
+
+-- name: ListDeletedNotesPaginated :many
+SELECT * FROM notes
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC, id
+LIMIT :limit OFFSET :offset;
+
+-- name: CountDeletedNotes :one
+SELECT COUNT(*) FROM notes WHERE deleted_at IS NOT NULL;
+
 -- ========================================
 -- Composite Queries - Notes with Relations
 -- ========================================
@@ -61,7 +173,7 @@ SELECT
     GROUP_CONCAT(nm.key || ':' || nm.value, '|') as meta_pairs
 FROM notes n
 LEFT JOIN note_meta nm ON n.id = nm.note_id
-WHERE n.id = :id
+WHERE n.id = :id AND n.deleted_at IS NULL
 GROUP BY n.id;
 
 -- name: GetNoteWithTypeByID :one
@@ -75,7 +187,7 @@ SELECT
     nt.color as type_color
 FROM notes n
 LEFT JOIN note_types nt ON n.note_type_id = nt.id
-WHERE n.id = :id;
+WHERE n.id = :id AND n.deleted_at IS NULL;
 
 -- ========================================
 -- Filter Queries - Notes by Relations
@@ -84,18 +196,18 @@ WHERE n.id = :id;
 -- name: ListNotesByTagIDs :many
 SELECT DISTINCT n.* FROM notes n
 JOIN note_tags nt ON n.id = nt.note_id
-WHERE nt.tag_id = ?1
+WHERE nt.tag_id = ?1 AND n.deleted_at IS NULL
 ORDER BY n.uuid;
 
 -- name: ListNotesByMetaKeys :many
 SELECT DISTINCT n.* FROM notes n
 JOIN note_meta nm ON n.id = nm.note_id
-WHERE nm.key = ?1
+WHERE nm.key = ?1 AND n.deleted_at IS NULL
 ORDER BY n.uuid;
 
 -- name: ListNotesByNoteTypeID :many
 SELECT * FROM notes 
-WHERE note_type_id = ?1
+WHERE note_type_id = ?1 AND deleted_at IS NULL
 ORDER BY updated_at DESC;
 
 -- ========================================
@@ -125,7 +237,7 @@ SELECT
     ) as tags_json
 FROM notes n
 LEFT JOIN note_types nt ON n.note_type_id = nt.id
-WHERE n.id = :id;
+WHERE n.id = :id AND n.deleted_at IS NULL;
 
 -- ========================================
 -- Collection Queries
@@ -133,18 +245,23 @@ WHERE n.id = :id;
 
 -- name: ListNotesByCollectionID :many
 SELECT * FROM notes 
-WHERE collection_id = :collection_id
+WHERE collection_id = :collection_id AND deleted_at IS NULL
 ORDER BY title;
 
+-- name: ListNotesByCollectionIDs :many
+SELECT * FROM notes
+WHERE collection_id IN (sqlc.slice('collection_ids')) AND deleted_at IS NULL
+ORDER BY title, id;
+
 -- name: ListNotesByCollectionPath :many
 SELECT n.* FROM notes n
 INNER JOIN collections c ON n.collection_id = c.id
-WHERE c.path = :path
+WHERE c.path = :path AND n.deleted_at IS NULL
 ORDER BY n.title;
 
 -- name: CountNotesByCollectionID :one
 SELECT COUNT(*) FROM notes 
-WHERE collection_id = :collection_id;
+WHERE collection_id = :collection_id AND deleted_at IS NULL;
 
 -- ========================================
 -- Multi-Tag Filtering (FR-TAGS-02)
@@ -154,7 +271,7 @@ WHERE collection_id = :collection_id;
 -- Notes having ALL specified tags
 SELECT n.* FROM notes n
 JOIN note_tags nt ON n.id = nt.note_id
-WHERE nt.tag_id IN (sqlc.slice('tag_ids'))
+WHERE nt.tag_id IN (sqlc.slice('tag_ids')) AND n.deleted_at IS NULL
 GROUP BY n.id
 HAVING COUNT(DISTINCT nt.tag_id) = sqlc.arg('tag_count')
 ORDER BY n.uuid;
@@ -163,13 +280,13 @@ ORDER BY n.uuid;
 -- Notes having ANY of the specified tags
 SELECT DISTINCT n.* FROM notes n
 JOIN note_tags nt ON n.id = nt.note_id
-WHERE nt.tag_id IN (sqlc.slice('tag_ids'))
+WHERE nt.tag_id IN (sqlc.slice('tag_ids')) AND n.deleted_at IS NULL
 ORDER BY n.uuid;
 
 -- name: GetNoteByTitleInCollection :one
 -- Lookup note by title within a specific collection (titles unique per collection)
 SELECT * FROM notes 
-WHERE title = :title AND collection_id = :collection_id 
+WHERE title = :title AND collection_id = :collection_id AND deleted_at IS NULL
 LIMIT 1;
 
 -- ========================================
@@ -178,34 +295,55 @@ LIMIT 1;
 
 -- name: ListNotesPaginated :many
 SELECT * FROM notes 
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT :limit OFFSET :offset;
 
 -- name: CountNotes :one
-SELECT COUNT(*) FROM notes;
+SELECT COUNT(*) FROM notes WHERE deleted_at IS NULL;
 
 -- name: ListNotesByCollectionIDPaginated :many
+-- sort_by: '' = insertion order (id), 'updated_at' = most recently updated first,
+-- 'pinned_first' = pinned notes by pin_position, then the rest by updated_at.
+-- id is always the final tie-breaker so offset pagination stays stable.
 SELECT * FROM notes 
-WHERE collection_id = :collection_id
-ORDER BY id
+WHERE collection_id = :collection_id AND deleted_at IS NULL
+ORDER BY
+  CASE WHEN :sort_by = 'pinned_first' THEN is_pinned END DESC,
+  CASE WHEN :sort_by = 'pinned_first' THEN pin_position END ASC,
+  CASE WHEN :sort_by IN ('pinned_first', 'updated_at') THEN updated_at END DESC,
+  id
 LIMIT :limit OFFSET :offset;
 
 -- name: ListNotesByNoteTypeIDPaginated :many
 SELECT * FROM notes 
-WHERE note_type_id = :note_type_id
+WHERE note_type_id = :note_type_id AND deleted_at IS NULL
 ORDER BY id
 LIMIT :limit OFFSET :offset;
 
 -- name: CountNotesByNoteTypeID :one
 SELECT COUNT(*) FROM notes 
-WHERE note_type_id = :note_type_id;
+WHERE note_type_id = :note_type_id AND deleted_at IS NULL;
 
 -- name: ListNotesByIsTemplatePaginated :many
 SELECT * FROM notes 
-WHERE is_template = :is_template
+WHERE is_template = :is_template AND deleted_at IS NULL
 ORDER BY id
 LIMIT :limit OFFSET :offset;
 
 -- name: CountNotesByIsTemplate :one
 SELECT COUNT(*) FROM notes 
-WHERE is_template = :is_template;
+WHERE is_template = :is_template AND deleted_at IS NULL;
+
+-- name: SearchNotesByMeta :many
+-- Live notes with a metadata key whose value matches a LIKE pattern (e.g., author = 'John%')
+SELECT DISTINCT notes.* FROM notes
+JOIN note_meta ON notes.id = note_meta.note_id
+WHERE note_meta.key = :key AND note_meta.value LIKE :value_pattern AND notes.deleted_at IS NULL
+ORDER BY notes.id
+LIMIT :limit OFFSET :offset;
+
+-- name: CountNotesByMeta :one
+SELECT COUNT(DISTINCT notes.id) FROM notes
+JOIN note_meta ON notes.id = note_meta.note_id
+WHERE note_meta.key = :key AND note_meta.value LIKE :value_pattern AND notes.deleted_at IS NULL;
@@ -25,6 +25,7 @@ WHERE
   AND (sqlc.narg(collection_id) IS NULL OR n.collection_id = sqlc.narg(collection_id))
   AND (sqlc.narg(note_type_id) IS NULL OR n.note_type_id = sqlc.narg(note_type_id))
   AND (sqlc.narg(is_template) IS NULL OR n.is_template = sqlc.narg(is_template))
+  AND n.deleted_at IS NULL
 ORDER BY 
   n.updated_at DESC
 LIMIT sqlc.arg(limit) 
@@ -39,4 +40,5 @@ WHERE
   (sqlc.narg(title) IS NULL OR sqlc.narg(title) = '' OR n.title LIKE '%' || sqlc.narg(title) || '%')
   AND (sqlc.narg(collection_id) IS NULL OR n.collection_id = sqlc.narg(collection_id))
   AND (sqlc.narg(note_type_id) IS NULL OR n.note_type_id = sqlc.narg(note_type_id))
-  AND (sqlc.narg(is_template) IS NULL OR n.is_template = sqlc.narg(is_template));
+  AND (sqlc.narg(is_template) IS NULL OR n.is_template = sqlc.narg(is_template))
+  AND n.deleted_at IS NULL;
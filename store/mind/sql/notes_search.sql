@@ -10,7 +10,7 @@ SELECT
     note_type_id,
     created_at
 FROM notes
-WHERE id = :id;
+WHERE id = :id AND deleted_at IS NULL;
 
 -- name: GetRelatedNotesByForwardLinks :many
 -- Notes linked from this note (forward)
@@ -22,7 +22,7 @@ SELECT DISTINCT
     n.created_at
 FROM notes n
 JOIN links nl ON n.id = nl.dest_id
-WHERE nl.src_id = :note_id
+WHERE nl.src_id = :note_id AND n.deleted_at IS NULL
 LIMIT :limit_count;
 
 -- name: GetRelatedNotesByBackwardLinks :many
@@ -35,7 +35,7 @@ SELECT DISTINCT
     n.created_at
 FROM notes n
 JOIN links nl ON n.id = nl.src_id
-WHERE nl.dest_id = :note_id
+WHERE nl.dest_id = :note_id AND n.deleted_at IS NULL
 LIMIT :limit_count;
 
 -- name: GetRelatedNotesByTags :many
@@ -52,6 +52,21 @@ JOIN note_tags nt2 ON n.id = nt2.note_id
 JOIN note_tags nt1 ON nt1.tag_id = nt2.tag_id
 WHERE nt1.note_id = :note_id
 AND n.id != :note_id
+AND n.deleted_at IS NULL
 GROUP BY n.id, n.title, n.body, n.note_type_id, n.created_at
 ORDER BY shared_tags DESC
 LIMIT :limit_count;
+
+-- name: SearchNotesByKeyword :many
+-- LIKE-based fallback for SQLite builds without FTS5.
+-- pattern is '%query%' with LIKE wildcards escaped using '\'.
+SELECT * FROM notes
+WHERE (title LIKE sqlc.arg(pattern) ESCAPE '\' OR body LIKE sqlc.arg(pattern) ESCAPE '\')
+  AND deleted_at IS NULL
+ORDER BY updated_at DESC, id
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
+
+-- name: CountNotesByKeyword :one
+SELECT COUNT(*) FROM notes
+WHERE (title LIKE sqlc.arg(pattern) ESCAPE '\' OR body LIKE sqlc.arg(pattern) ESCAPE '\')
+  AND deleted_at IS NULL;
@@ -0,0 +1,19 @@
+-- Refresh tokens: issuance and one-time rotation for AuthService (SQLite/sqlc)
+-- NOTE: token_hash is the hex sha256 of the token; plaintext tokens are never stored
+
+-- name: CreateRefreshToken :execlastid
+INSERT INTO refresh_tokens (actor_id, token_hash, expires_at)
+VALUES (:actor_id, :token_hash, :expires_at);
+
+-- name: GetRefreshTokenByHash :one
+-- Used and revoked tokens are returned too so the caller can detect reuse
+SELECT * FROM refresh_tokens WHERE token_hash = :token_hash;
+
+-- name: MarkRefreshTokenUsed :execresult
+-- Returns result to check rows affected (0 = already used or revoked, e.g., a concurrent rotation)
+UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP
+WHERE id = :id AND used_at IS NULL AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokensByActor :exec
+UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP
+WHERE actor_id = :actor_id AND revoked_at IS NULL;
@@ -32,11 +32,14 @@ WHERE note_tags.note_id = :note_id;
 -- name: ListNotesForTag :many
 SELECT notes.* FROM notes
 JOIN note_tags ON notes.id = note_tags.note_id
-WHERE note_tags.tag_id = :tag_id;
+WHERE note_tags.tag_id = :tag_id AND notes.deleted_at IS NULL;
 
 -- name: TagUsageCount :many
-SELECT tag_id,
-COUNT(*) as usage_count FROM note_tags GROUP BY tag_id ORDER BY usage_count DESC;
+SELECT note_tags.tag_id,
+COUNT(*) as usage_count FROM note_tags
+JOIN notes ON notes.id = note_tags.note_id
+WHERE notes.deleted_at IS NULL
+GROUP BY note_tags.tag_id ORDER BY usage_count DESC;
 
 -- name: CreateNoteTag :exec
 INSERT INTO note_tags (note_id, tag_id)
@@ -84,14 +87,14 @@ WHERE note_tags.note_id = :note_id;
 -- name: ListNotesForTagPaginated :many
 SELECT notes.* FROM notes
 JOIN note_tags ON notes.id = note_tags.note_id
-WHERE note_tags.tag_id = :tag_id
+WHERE note_tags.tag_id = :tag_id AND notes.deleted_at IS NULL
 ORDER BY notes.id
 LIMIT :limit OFFSET :offset;
 
 -- name: CountNotesForTag :one
 SELECT COUNT(*) FROM notes
 JOIN note_tags ON notes.id = note_tags.note_id
-WHERE note_tags.tag_id = :tag_id;
+WHERE note_tags.tag_id = :tag_id AND notes.deleted_at IS NULL;
 
 -- name: FindTagsPaginated :many
 SELECT * FROM tags
@@ -103,3 +106,7 @@ LIMIT :limit OFFSET :offset;
 SELECT COUNT(*) FROM tags
 WHERE name LIKE :pattern;
 
+
+-- name: ListTagsByNames :many
+-- Resolves tag names to IDs in one query (e.g., bulk tag assignment); missing names are omitted
+SELECT * FROM tags WHERE name IN (sqlc.slice('names'));
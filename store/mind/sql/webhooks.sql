@@ -0,0 +1,16 @@
+-- Webhooks: registrations for outgoing note event deliveries (SQLite/sqlc)
+-- NOTE: events is a JSON array of event names, matched by WebhookService
+
+-- name: CreateWebhook :execlastid
+INSERT INTO webhooks (url, secret, events)
+VALUES (:url, :secret, :events);
+
+-- name: GetWebhookByID :one
+SELECT * FROM webhooks WHERE id = :id;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks ORDER BY id;
+
+-- name: DeleteWebhook :execresult
+-- Returns result to check rows affected (0 = not found)
+DELETE FROM webhooks WHERE id = :id;